@@ -17,6 +17,7 @@
 package abi
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -24,10 +25,43 @@ import (
 	"github.com/DE-labtory/koa/ast"
 )
 
+// ABI describes the methods of contract. Name is the name of contract,
+// and it is empty if contract has no name.
+//
+// ABI is encoded in JSON as below. signature and selector are written
+// by MarshalJSON, and they are checked when they are given to New.
+// enum is written only if the argument is declared as enum.
+//
+//	{
+//	  "name": "Wallet",
+//	  "methods": [
+//	    {
+//	      "name": "deposit",
+//	      "signature": "deposit(int)",
+//	      "selector": "5a74d15b",
+//	      "arguments": [
+//	        { "name": "amount", "type": "int" }
+//	      ],
+//	      "output": {
+//	        "name": "",
+//	        "type": "int",
+//	        "enum": { "name": "State", "members": ["Open", "Closed"] }
+//	      }
+//	    }
+//	  ]
+//	}
+//
+// The array of methods without name of contract is also accepted.
 type ABI struct {
+	Name    string
 	Methods []Method
 }
 
+type abiMarshaling struct {
+	Name    string   `json:"name"`
+	Methods []Method `json:"methods"`
+}
+
 func New(abiJSON string) (ABI, error) {
 	reader := strings.NewReader(abiJSON)
 	dec := json.NewDecoder(reader)
@@ -40,8 +74,32 @@ func New(abiJSON string) (ABI, error) {
 	return abi, nil
 }
 
+// MarshalJSON implements json.Marshaler interface
+func (abi ABI) MarshalJSON() ([]byte, error) {
+	methods := abi.Methods
+	if methods == nil {
+		methods = make([]Method, 0)
+	}
+
+	return json.Marshal(abiMarshaling{
+		Name:    abi.Name,
+		Methods: methods,
+	})
+}
+
 // UnmarshalJSON is implementation of json.Decoder's UnmarshalJSON
 func (abi *ABI) UnmarshalJSON(data []byte) error {
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		var a abiMarshaling
+		if err := json.Unmarshal(data, &a); err != nil {
+			return err
+		}
+
+		abi.Name = a.Name
+		abi.Methods = a.Methods
+		return nil
+	}
+
 	var methods []Method
 
 	if err := json.Unmarshal(data, &methods); err != nil {
@@ -70,6 +128,7 @@ func ExtractAbiFromFunction(f ast.FunctionLiteral) (Method, error) {
 		if err != nil {
 			return Method{}, err
 		}
+		t.Enum = convertAstEnumToAbi(param.Enum)
 
 		arg := Argument{
 			Name: param.Identifier.String(),
@@ -85,6 +144,7 @@ func ExtractAbiFromFunction(f ast.FunctionLiteral) (Method, error) {
 	if err != nil {
 		return Method{}, err
 	}
+	t.Enum = convertAstEnumToAbi(f.ReturnEnum)
 
 	method.Output = Argument{
 		Name: "",
@@ -104,7 +164,27 @@ func convertAstTypeToAbi(p ast.DataStructure) (Type, error) {
 		return NewType("bool")
 	case ast.VoidType:
 		return NewType("void")
+	case ast.EnumType:
+		return NewType("int")
 	default:
 		return Type{}, fmt.Errorf("Unknown paramter type. got=%v", p)
 	}
 }
+
+// convertAstEnumToAbi converts enum declaration to the enum of ABI.
+// If enum is nil, returns nil.
+func convertAstEnumToAbi(e *ast.EnumLiteral) *Enum {
+	if e == nil {
+		return nil
+	}
+
+	members := make([]string, 0)
+	for _, m := range e.Members {
+		members = append(members, m.String())
+	}
+
+	return &Enum{
+		Name:    e.Name.String(),
+		Members: members,
+	}
+}
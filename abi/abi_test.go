@@ -17,6 +17,7 @@
 package abi_test
 
 import (
+	"encoding/json"
 	"reflect"
 	"testing"
 
@@ -81,7 +82,50 @@ func TestNew(t *testing.T) {
 	}
 }
 
+func TestNew_enum(t *testing.T) {
+	abiJSON := `[
+	{
+		"name" : "state",
+		"arguments" : [],
+		"output" : {
+			"name" : "",
+			"type" : "int",
+			"enum" : {
+				"name" : "State",
+				"members" : ["Open", "Closed"]
+			}
+		}
+	}
+]
+`
+
+	ABI, err := abi.New(abiJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := abi.Type{
+		Type: abi.Integer,
+		Enum: &abi.Enum{
+			Name:    "State",
+			Members: []string{"Open", "Closed"},
+		},
+	}
+
+	if !reflect.DeepEqual(ABI.Methods[0].Output.Type, expected) {
+		t.Errorf("Invalid enum type. expected=%v, got=%v", expected, ABI.Methods[0].Output.Type)
+	}
+}
+
 func TestExtractAbiFromFunction(t *testing.T) {
+	state := &ast.EnumLiteral{
+		Name: &ast.Identifier{Name: "State"},
+		Members: []*ast.Identifier{
+			{Name: "Open"},
+			{Name: "Closed"},
+		},
+	}
+
 	tests := []struct {
 		f      ast.FunctionLiteral
 		expect abi.Method
@@ -133,6 +177,51 @@ func TestExtractAbiFromFunction(t *testing.T) {
 			},
 			err: nil,
 		},
+		// test enum parameter and return type
+		{
+			f: ast.FunctionLiteral{
+				Name: &ast.Identifier{
+					Name: "next",
+				},
+				Parameters: []*ast.ParameterLiteral{
+					{
+						Identifier: &ast.Identifier{
+							Name: "s",
+						},
+						Type: ast.EnumType,
+						Enum: state,
+					},
+				},
+				ReturnType: ast.EnumType,
+				ReturnEnum: state,
+			},
+			expect: abi.Method{
+				Name: "next",
+				Arguments: []abi.Argument{
+					{
+						Name: "s",
+						Type: abi.Type{
+							Type: "int",
+							Enum: &abi.Enum{
+								Name:    "State",
+								Members: []string{"Open", "Closed"},
+							},
+						},
+					},
+				},
+				Output: abi.Argument{
+					Name: "",
+					Type: abi.Type{
+						Type: "int",
+						Enum: &abi.Enum{
+							Name:    "State",
+							Members: []string{"Open", "Closed"},
+						},
+					},
+				},
+			},
+			err: nil,
+		},
 		// test void return function
 		{
 			f: ast.FunctionLiteral{
@@ -168,3 +257,77 @@ func TestExtractAbiFromFunction(t *testing.T) {
 		}
 	}
 }
+
+func TestABI_MarshalJSON(t *testing.T) {
+	a := abi.ABI{
+		Name: "Wallet",
+		Methods: []abi.Method{
+			{
+				Name: "deposit",
+				Arguments: abi.Arguments{
+					{Name: "amount", Type: abi.Type{Type: abi.Integer}},
+				},
+				Output: abi.Argument{Name: "", Type: abi.Type{Type: abi.Boolean}},
+			},
+			{
+				Name:      "state",
+				Arguments: abi.Arguments{},
+				Output: abi.Argument{Name: "", Type: abi.Type{
+					Type: abi.Integer,
+					Enum: &abi.Enum{Name: "State", Members: []string{"Open", "Closed"}},
+				}},
+			},
+		},
+	}
+
+	expected := `{"name":"Wallet","methods":[` +
+		`{"name":"deposit","signature":"deposit(int)","selector":"5a74d15b",` +
+		`"arguments":[{"name":"amount","type":"int"}],"output":{"name":"","type":"bool"}},` +
+		`{"name":"state","signature":"state()","selector":"c19d93fb","arguments":[],` +
+		`"output":{"name":"","type":"int","enum":{"name":"State","members":["Open","Closed"]}}}]}`
+
+	b, err := json.Marshal(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(b) != expected {
+		t.Errorf("Invalid JSON. expected=%s, got=%s", expected, b)
+	}
+
+	decoded, err := abi.New(string(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(decoded, a) {
+		t.Errorf("Invalid decoded ABI. expected=%v, got=%v", a, decoded)
+	}
+}
+
+func TestNew_error(t *testing.T) {
+	tests := []struct {
+		abiJSON string
+		err     string
+	}{
+		{
+			abiJSON: `{"name":"a","methods":[{"name":"foo","signature":"foo(int)","arguments":[],"output":{"type":"int"}}]}`,
+			err:     "signature of method foo must be foo(), but got foo(int)",
+		},
+		{
+			abiJSON: `{"name":"a","methods":[{"name":"foo","selector":"00000000","arguments":[],"output":{"type":"int"}}]}`,
+			err:     "selector of method foo() must be c2985578, but got 00000000",
+		},
+		{
+			abiJSON: `{"name":"a","methods":[{"name":"foo","arguments":[{"name":"a","type":"uint"}],"output":{"type":"int"}}]}`,
+			err:     "unsupported arg type: uint",
+		},
+	}
+
+	for i, test := range tests {
+		_, err := abi.New(test.abiJSON)
+		if err == nil || err.Error() != test.err {
+			t.Errorf("test[%d] - New() returns wrong error. expected=%s, got=%v", i, test.err, err)
+		}
+	}
+}
@@ -29,8 +29,18 @@ type Argument struct {
 type Arguments []Argument
 
 type ArgumentMarshaling struct {
-	Name string
-	Type string
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Enum *Enum  `json:"enum,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler interface
+func (argument Argument) MarshalJSON() ([]byte, error) {
+	return json.Marshal(ArgumentMarshaling{
+		Name: argument.Name,
+		Type: string(argument.Type.Type),
+		Enum: argument.Type.Enum,
+	})
 }
 
 // UnmarshalJSON implements json.Unmarshaler interface
@@ -44,6 +54,7 @@ func (argument *Argument) UnmarshalJSON(data []byte) error {
 	if err != nil {
 		return err
 	}
+	argument.Type.Enum = arg.Enum
 	argument.Name = arg.Name
 
 	return nil
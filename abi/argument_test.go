@@ -30,13 +30,13 @@ func TestArguments_Pack(t *testing.T) {
 		{
 			arguments: abi.Arguments{
 				abi.Argument{
-					Type: abi.Type{abi.String},
+					Type: abi.Type{Type: abi.String},
 				},
 				abi.Argument{
-					Type: abi.Type{abi.String},
+					Type: abi.Type{Type: abi.String},
 				},
 				abi.Argument{
-					Type: abi.Type{abi.String},
+					Type: abi.Type{Type: abi.String},
 				},
 			},
 			expectedPack: "string,string,string",
@@ -44,13 +44,13 @@ func TestArguments_Pack(t *testing.T) {
 		{
 			arguments: abi.Arguments{
 				abi.Argument{
-					Type: abi.Type{abi.String},
+					Type: abi.Type{Type: abi.String},
 				},
 				abi.Argument{
-					Type: abi.Type{abi.Boolean},
+					Type: abi.Type{Type: abi.Boolean},
 				},
 				abi.Argument{
-					Type: abi.Type{abi.Integer64},
+					Type: abi.Type{Type: abi.Integer64},
 				},
 			},
 			expectedPack: "string,bool,int64",
@@ -58,13 +58,13 @@ func TestArguments_Pack(t *testing.T) {
 		{
 			arguments: abi.Arguments{
 				abi.Argument{
-					Type: abi.Type{abi.Integer64},
+					Type: abi.Type{Type: abi.Integer64},
 				},
 				abi.Argument{
-					Type: abi.Type{abi.Integer64},
+					Type: abi.Type{Type: abi.Integer64},
 				},
 				abi.Argument{
-					Type: abi.Type{abi.Integer64},
+					Type: abi.Type{Type: abi.Integer64},
 				},
 			},
 			expectedPack: "int64,int64,int64",
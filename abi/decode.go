@@ -0,0 +1,80 @@
+/*
+ * Copyright 2018-2019 De-labtory
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package abi
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// Decode decodes data encoded by Encode to the values of types. int
+// and int64 are decoded to int64, bool to bool and string to string.
+func Decode(types []Type, data []byte) ([]interface{}, error) {
+	if len(data) < len(types)*8 {
+		return nil, fmt.Errorf("data of %d values must be at least %d bytes, but got %d bytes",
+			len(types), len(types)*8, len(data))
+	}
+
+	values := make([]interface{}, len(types))
+
+	for index, t := range types {
+		pointer := binary.BigEndian.Uint64(data[index*8:])
+		if pointer > uint64(len(data)) || uint64(len(data))-pointer < 8 {
+			return nil, fmt.Errorf("pointer of value %d is out of data: %d", index, pointer)
+		}
+
+		size := binary.BigEndian.Uint64(data[pointer:])
+		if uint64(len(data))-pointer-8 < size {
+			return nil, fmt.Errorf("size of value %d is out of data: %d", index, size)
+		}
+
+		value, err := decodeValue(t, data[pointer+8:pointer+8+size])
+		if err != nil {
+			return nil, err
+		}
+
+		values[index] = value
+	}
+
+	return values, nil
+}
+
+// decodeValue decodes the 8 bytes value encoded by encoding.EncodeOperand.
+func decodeValue(t Type, value []byte) (interface{}, error) {
+	if len(value) != 8 {
+		return nil, fmt.Errorf("value of %s must be 8 bytes, but got %d bytes", t.Type, len(value))
+	}
+
+	switch t.Type {
+	case Integer, Integer64:
+		return int64(binary.BigEndian.Uint64(value)), nil
+	case Boolean:
+		switch binary.BigEndian.Uint64(value) {
+		case 0:
+			return false, nil
+		case 1:
+			return true, nil
+		default:
+			return nil, fmt.Errorf("invalid bool value: %x", value)
+		}
+	case String:
+		return string(bytes.TrimRight(value, "\x00")), nil
+	default:
+		return nil, fmt.Errorf("can't decode value of type %s", t.Type)
+	}
+}
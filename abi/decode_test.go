@@ -0,0 +1,138 @@
+/*
+ * Copyright 2018-2019 De-labtory
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package abi_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/DE-labtory/koa/abi"
+)
+
+func TestDecode(t *testing.T) {
+	intType := abi.Type{Type: abi.Integer}
+	int64Type := abi.Type{Type: abi.Integer64}
+	boolType := abi.Type{Type: abi.Boolean}
+	stringType := abi.Type{Type: abi.String}
+
+	tests := []struct {
+		types  []abi.Type
+		values []interface{}
+	}{
+		{
+			types:  []abi.Type{},
+			values: []interface{}{},
+		},
+		{
+			types:  []abi.Type{int64Type, stringType, intType},
+			values: []interface{}{int64(50), "HelloKOA", int64(256)},
+		},
+		{
+			types:  []abi.Type{intType, intType},
+			values: []interface{}{int64(-1), int64(0)},
+		},
+		{
+			types:  []abi.Type{boolType, boolType, stringType, stringType},
+			values: []interface{}{true, false, "koa", ""},
+		},
+	}
+
+	for i, test := range tests {
+		data, err := abi.Encode(test.values...)
+		if err != nil {
+			t.Fatalf("test[%d] - Encode() returns error: %v", i, err)
+		}
+
+		values, err := abi.Decode(test.types, data)
+		if err != nil {
+			t.Fatalf("test[%d] - Decode() returns error: %v", i, err)
+		}
+
+		if !reflect.DeepEqual(values, test.values) {
+			t.Errorf("test[%d] - wrong values. expected=%v, got=%v", i, test.values, values)
+		}
+
+		// encoding the decoded values gives the same data
+		encoded, err := abi.Encode(values...)
+		if err != nil {
+			t.Fatalf("test[%d] - Encode() returns error: %v", i, err)
+		}
+
+		if !reflect.DeepEqual(encoded, data) {
+			t.Errorf("test[%d] - wrong data. expected=%x, got=%x", i, data, encoded)
+		}
+	}
+}
+
+func TestDecode_error(t *testing.T) {
+	intType := abi.Type{Type: abi.Integer}
+
+	valid, err := abi.Encode(1, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		types []abi.Type
+		data  []byte
+		err   string
+	}{
+		{
+			types: []abi.Type{intType, intType},
+			data:  valid[:8],
+			err:   "data of 2 values must be at least 16 bytes, but got 8 bytes",
+		},
+		{
+			types: []abi.Type{intType, intType},
+			data:  valid[:16],
+			err:   "pointer of value 0 is out of data: 16",
+		},
+		{
+			types: []abi.Type{intType, intType},
+			data:  valid[:len(valid)-1],
+			err:   "size of value 1 is out of data: 8",
+		},
+		{
+			types: []abi.Type{intType, {Type: abi.Void}},
+			data:  valid,
+			err:   "can't decode value of type void",
+		},
+		{
+			types: []abi.Type{{Type: abi.Boolean}},
+			data:  append(koaBytes(8, 8), koaBytes(2)...),
+			err:   "invalid bool value: 0000000000000002",
+		},
+	}
+
+	for i, test := range tests {
+		_, err := abi.Decode(test.types, test.data)
+		if err == nil || err.Error() != test.err {
+			t.Errorf("test[%d] - Decode() returns wrong error. expected=%s, got=%v", i, test.err, err)
+		}
+	}
+}
+
+// koaBytes returns the values as 8 bytes big endian integers.
+func koaBytes(values ...int64) []byte {
+	b := make([]byte, 0)
+	for _, v := range values {
+		b = append(b, byte(v>>56), byte(v>>48), byte(v>>40), byte(v>>32),
+			byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+	}
+
+	return b
+}
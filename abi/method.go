@@ -16,12 +16,69 @@
 
 package abi
 
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
 type Method struct {
 	Name      string
 	Arguments Arguments
 	Output    Argument
 }
 
+type methodMarshaling struct {
+	Name      string    `json:"name"`
+	Signature string    `json:"signature,omitempty"`
+	Selector  string    `json:"selector,omitempty"`
+	Arguments Arguments `json:"arguments"`
+	Output    Argument  `json:"output"`
+}
+
+// MarshalJSON implements json.Marshaler interface, signature and
+// selector of method are written with its arguments and output.
+func (method Method) MarshalJSON() ([]byte, error) {
+	args := method.Arguments
+	if args == nil {
+		args = make(Arguments, 0)
+	}
+
+	return json.Marshal(methodMarshaling{
+		Name:      method.Name,
+		Signature: method.Signature(),
+		Selector:  hex.EncodeToString(method.ID()),
+		Arguments: args,
+		Output:    method.Output,
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface. If signature or
+// selector is given, it must be the same as the one of method.
+func (method *Method) UnmarshalJSON(data []byte) error {
+	var m methodMarshaling
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+
+	method.Name = m.Name
+	method.Arguments = m.Arguments
+	method.Output = m.Output
+
+	if m.Signature != "" && m.Signature != method.Signature() {
+		return fmt.Errorf("signature of method %s must be %s, but got %s",
+			m.Name, method.Signature(), m.Signature)
+	}
+
+	if selector := hex.EncodeToString(method.ID()); m.Selector != "" && m.Selector != selector {
+		return fmt.Errorf("selector of method %s must be %s, but got %s",
+			method.Signature(), selector, m.Selector)
+	}
+
+	return nil
+}
+
 // Signature returns function's signature according to the ABI spec.
 //
 // Example
@@ -34,3 +91,49 @@ func (method Method) Signature() string {
 func (method Method) ID() []byte {
 	return Selector(method.Name + "(" + method.Arguments.Pack() + ")")
 }
+
+// DecodeArguments decodes the arguments encoded by Encode.
+func (method Method) DecodeArguments(data []byte) ([]interface{}, error) {
+	types := make([]Type, len(method.Arguments))
+	for i, arg := range method.Arguments {
+		types[i] = arg.Type
+	}
+
+	return Decode(types, data)
+}
+
+// DecodeOutput decodes the result of function, which is the 8 bytes
+// value returned by the VM. Output of void function is nil.
+func (method Method) DecodeOutput(data []byte) (interface{}, error) {
+	if method.Output.Type.Type == Void {
+		return nil, nil
+	}
+
+	return decodeValue(method.Output.Type, data)
+}
+
+// FormatOutput decodes the result of function and formats it to be
+// shown to users. String is quoted, and enum value is shown with the
+// name of member.
+func (method Method) FormatOutput(data []byte) (string, error) {
+	value, err := method.DecodeOutput(data)
+	if err != nil {
+		return "", err
+	}
+
+	t := method.Output.Type
+	switch {
+	case t.Type == Void:
+		return "void", nil
+	case t.Type == String:
+		return strconv.Quote(value.(string)), nil
+	case t.Enum != nil:
+		member, err := t.Enum.Member(value.(int64))
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s.%s (%d)", t.Enum.Name, member, value), nil
+	default:
+		return fmt.Sprint(value), nil
+	}
+}
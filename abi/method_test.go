@@ -18,6 +18,7 @@ package abi_test
 
 import (
 	"bytes"
+	"reflect"
 	"testing"
 
 	"github.com/DE-labtory/koa/abi"
@@ -119,3 +120,139 @@ func TestMethod_ID(t *testing.T) {
 		}
 	}
 }
+
+func TestMethod_DecodeArguments(t *testing.T) {
+	method := makeTestABI().Methods[0]
+
+	data, err := abi.Encode(int64(3), "koa", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args, err := method.DecodeArguments(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []interface{}{int64(3), "koa", true}
+	if !reflect.DeepEqual(args, expected) {
+		t.Errorf("Invalid arguments - expected = %v, got = %v", expected, args)
+	}
+}
+
+func TestMethod_DecodeOutput(t *testing.T) {
+	tests := []struct {
+		output   abi.Type
+		data     []byte
+		expected interface{}
+		err      string
+	}{
+		{
+			output:   abi.Type{Type: abi.Integer},
+			data:     []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xfe},
+			expected: int64(-2),
+		},
+		{
+			output:   abi.Type{Type: abi.Boolean},
+			data:     []byte{0, 0, 0, 0, 0, 0, 0, 1},
+			expected: true,
+		},
+		{
+			output:   abi.Type{Type: abi.String},
+			data:     []byte{'k', 'o', 'a', 0, 0, 0, 0, 0},
+			expected: "koa",
+		},
+		{
+			output:   abi.Type{Type: abi.Void},
+			data:     nil,
+			expected: nil,
+		},
+		{
+			output: abi.Type{Type: abi.Integer},
+			data:   []byte{1},
+			err:    "value of int must be 8 bytes, but got 1 bytes",
+		},
+	}
+
+	for i, test := range tests {
+		method := abi.Method{Name: "foo", Arguments: abi.Arguments{}, Output: abi.Argument{Type: test.output}}
+
+		output, err := method.DecodeOutput(test.data)
+		if test.err != "" {
+			if err == nil || err.Error() != test.err {
+				t.Errorf("test[%d] - wrong error. expected = %s, got = %v", i, test.err, err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("test[%d] - DecodeOutput() returns error: %v", i, err)
+		}
+
+		if output != test.expected {
+			t.Errorf("test[%d] - wrong output. expected = %v, got = %v", i, test.expected, output)
+		}
+	}
+}
+
+func TestMethod_FormatOutput(t *testing.T) {
+	state := &abi.Enum{Name: "State", Members: []string{"Open", "Closed"}}
+
+	tests := []struct {
+		output   abi.Type
+		data     []byte
+		expected string
+		err      string
+	}{
+		{
+			output:   abi.Type{Type: abi.Integer},
+			data:     []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xfe},
+			expected: "-2",
+		},
+		{
+			output:   abi.Type{Type: abi.Boolean},
+			data:     []byte{0, 0, 0, 0, 0, 0, 0, 1},
+			expected: "true",
+		},
+		{
+			output:   abi.Type{Type: abi.String},
+			data:     []byte{'k', 'o', 'a', 0, 0, 0, 0, 0},
+			expected: `"koa"`,
+		},
+		{
+			output:   abi.Type{Type: abi.Void},
+			data:     nil,
+			expected: "void",
+		},
+		{
+			output:   abi.Type{Type: abi.Integer, Enum: state},
+			data:     []byte{0, 0, 0, 0, 0, 0, 0, 1},
+			expected: "State.Closed (1)",
+		},
+		{
+			output: abi.Type{Type: abi.Integer, Enum: state},
+			data:   []byte{0, 0, 0, 0, 0, 0, 0, 2},
+			err:    "enum State has no member of value 2",
+		},
+	}
+
+	for i, test := range tests {
+		method := abi.Method{Name: "foo", Arguments: abi.Arguments{}, Output: abi.Argument{Type: test.output}}
+
+		output, err := method.FormatOutput(test.data)
+		if test.err != "" {
+			if err == nil || err.Error() != test.err {
+				t.Errorf("test[%d] - wrong error. expected = %s, got = %v", i, test.err, err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("test[%d] - FormatOutput() returns error: %v", i, err)
+		}
+
+		if output != test.expected {
+			t.Errorf("test[%d] - wrong output. expected = %s, got = %s", i, test.expected, output)
+		}
+	}
+}
@@ -30,8 +30,29 @@ const (
 	Void      ParamType = "void"
 )
 
+// Type represents type of argument. Enum is set when the argument
+// is declared as enum, then Type is int because enum values are
+// encoded as integer.
 type Type struct {
 	Type ParamType
+	Enum *Enum
+}
+
+// Enum represents enum which is used as type of argument.
+// Members are the names of enum values in order, so that
+// clients can decode integer value to its name.
+type Enum struct {
+	Name    string   `json:"name"`
+	Members []string `json:"members"`
+}
+
+// Member returns the name of enum value.
+func (e Enum) Member(value int64) (string, error) {
+	if value < 0 || value >= int64(len(e.Members)) {
+		return "", fmt.Errorf("enum %s has no member of value %d", e.Name, value)
+	}
+
+	return e.Members[value], nil
 }
 
 func NewType(paramType string) (Type, error) {
@@ -52,3 +52,32 @@ func TestNewType(t *testing.T) {
 		}
 	}
 }
+
+func TestEnum_Member(t *testing.T) {
+	enum := abi.Enum{
+		Name:    "State",
+		Members: []string{"Open", "Closed"},
+	}
+
+	tests := []struct {
+		value    int64
+		expected string
+		err      bool
+	}{
+		{0, "Open", false},
+		{1, "Closed", false},
+		{2, "", true},
+		{-1, "", true},
+	}
+
+	for i, test := range tests {
+		member, err := enum.Member(test.value)
+		if (err != nil) != test.err {
+			t.Errorf("test[%d] - Member() error wrong. expected error=%t, got=%v", i, test.err, err)
+		}
+
+		if member != test.expected {
+			t.Errorf("test[%d] - Member() result wrong. expected=%s, got=%s", i, test.expected, member)
+		}
+	}
+}
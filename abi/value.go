@@ -0,0 +1,131 @@
+/*
+ * Copyright 2018-2019 De-labtory
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package abi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseValue parses s to the value of type t, which can be encoded by
+// Encode. The value of enum can be written as the name of its member.
+func ParseValue(t Type, s string) (interface{}, error) {
+	switch t.Type {
+	case Integer, Integer64:
+		if t.Enum != nil {
+			for i, member := range t.Enum.Members {
+				if member == s {
+					return int64(i), nil
+				}
+			}
+		}
+
+		v, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a value of %s", s, typeName(t))
+		}
+		return v, nil
+	case Boolean:
+		switch s {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		default:
+			return nil, fmt.Errorf("%q is not a value of %s", s, typeName(t))
+		}
+	case String:
+		return s, nil
+	default:
+		return nil, fmt.Errorf("can't parse value of type %s", t.Type)
+	}
+}
+
+// typeName returns the name of enum if t is enum.
+func typeName(t Type) string {
+	if t.Enum != nil {
+		return t.Enum.Name
+	}
+
+	return string(t.Type)
+}
+
+// ParseArguments parses args to the arguments of method.
+func (method Method) ParseArguments(args []string) ([]interface{}, error) {
+	if len(args) != len(method.Arguments) {
+		return nil, fmt.Errorf("method %s needs %d arguments, but got %d",
+			method.Signature(), len(method.Arguments), len(args))
+	}
+
+	values := make([]interface{}, len(args))
+	for i, arg := range method.Arguments {
+		v, err := ParseValue(arg.Type, args[i])
+		if err != nil {
+			return nil, fmt.Errorf("argument %s of method %s: %s", arg.Name, method.Signature(), err)
+		}
+
+		values[i] = v
+	}
+
+	return values, nil
+}
+
+// FindMethod returns the method named name, name can be the signature
+// of method. If the method is overloaded, it is found by the number
+// of arguments.
+func (abi ABI) FindMethod(name string, argc int) (Method, error) {
+	if strings.Contains(name, "(") {
+		for _, method := range abi.Methods {
+			if method.Signature() == name {
+				return method, nil
+			}
+		}
+		return Method{}, fmt.Errorf("there is no method %s", name)
+	}
+
+	methods := make([]Method, 0)
+	for _, method := range abi.Methods {
+		if method.Name == name {
+			methods = append(methods, method)
+		}
+	}
+
+	if len(methods) == 0 {
+		return Method{}, fmt.Errorf("there is no method %s", name)
+	}
+
+	if len(methods) == 1 {
+		return methods[0], nil
+	}
+
+	found := make([]Method, 0)
+	signatures := make([]string, 0)
+	for _, method := range methods {
+		if len(method.Arguments) == argc {
+			found = append(found, method)
+		}
+		signatures = append(signatures, method.Signature())
+	}
+
+	if len(found) != 1 {
+		return Method{}, fmt.Errorf("method %s is overloaded, select one of [%s]",
+			name, strings.Join(signatures, ", "))
+	}
+
+	return found[0], nil
+}
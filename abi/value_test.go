@@ -0,0 +1,153 @@
+/*
+ * Copyright 2018-2019 De-labtory
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package abi_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/DE-labtory/koa/abi"
+)
+
+func TestParseValue(t *testing.T) {
+	state := abi.Type{
+		Type: abi.Integer,
+		Enum: &abi.Enum{Name: "State", Members: []string{"Open", "Closed"}},
+	}
+
+	tests := []struct {
+		t        abi.Type
+		s        string
+		expected interface{}
+		err      string
+	}{
+		{t: abi.Type{Type: abi.Integer}, s: "-12", expected: int64(-12)},
+		{t: abi.Type{Type: abi.Integer64}, s: "7", expected: int64(7)},
+		{t: abi.Type{Type: abi.Boolean}, s: "true", expected: true},
+		{t: abi.Type{Type: abi.Boolean}, s: "false", expected: false},
+		{t: abi.Type{Type: abi.String}, s: "123", expected: "123"},
+		{t: state, s: "Closed", expected: int64(1)},
+		{t: state, s: "0", expected: int64(0)},
+		{t: abi.Type{Type: abi.Integer}, s: "true", err: `"true" is not a value of int`},
+		{t: abi.Type{Type: abi.Boolean}, s: "1", err: `"1" is not a value of bool`},
+		{t: state, s: "Settled", err: `"Settled" is not a value of State`},
+		{t: abi.Type{Type: abi.Void}, s: "", err: "can't parse value of type void"},
+	}
+
+	for i, test := range tests {
+		v, err := abi.ParseValue(test.t, test.s)
+		if test.err != "" {
+			if err == nil || err.Error() != test.err {
+				t.Errorf("test[%d] - ParseValue() returns wrong error. expected=%s, got=%v", i, test.err, err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("test[%d] - ParseValue() returns error: %v", i, err)
+		}
+
+		if v != test.expected {
+			t.Errorf("test[%d] - ParseValue() returns wrong value. expected=%v, got=%v", i, test.expected, v)
+		}
+	}
+}
+
+func TestMethod_ParseArguments(t *testing.T) {
+	method := makeTestABI().Methods[0]
+
+	args, err := method.ParseArguments([]string{"3", "koa", "true"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []interface{}{int64(3), "koa", true}
+	if !reflect.DeepEqual(args, expected) {
+		t.Errorf("Invalid arguments - expected = %v, got = %v", expected, args)
+	}
+
+	tests := []struct {
+		args []string
+		err  string
+	}{
+		{
+			args: []string{"3", "koa"},
+			err:  "method foo(int64,string,bool) needs 3 arguments, but got 2",
+		},
+		{
+			args: []string{"3", "koa", "yes"},
+			err:  `argument true of method foo(int64,string,bool): "yes" is not a value of bool`,
+		},
+	}
+
+	for i, test := range tests {
+		_, err := method.ParseArguments(test.args)
+		if err == nil || err.Error() != test.err {
+			t.Errorf("test[%d] - ParseArguments() returns wrong error. expected=%s, got=%v", i, test.err, err)
+		}
+	}
+}
+
+func TestABI_FindMethod(t *testing.T) {
+	intArg := abi.Argument{Name: "a", Type: abi.Type{Type: abi.Integer}}
+	stringArg := abi.Argument{Name: "a", Type: abi.Type{Type: abi.String}}
+
+	a := abi.ABI{
+		Methods: []abi.Method{
+			{Name: "balance", Arguments: abi.Arguments{}},
+			{Name: "transfer", Arguments: abi.Arguments{intArg}},
+			{Name: "transfer", Arguments: abi.Arguments{stringArg}},
+			{Name: "transfer", Arguments: abi.Arguments{intArg, intArg}},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		argc      int
+		signature string
+		err       string
+	}{
+		{name: "balance", argc: 1, signature: "balance()"},
+		{name: "transfer(string)", argc: 1, signature: "transfer(string)"},
+		{name: "transfer", argc: 2, signature: "transfer(int,int)"},
+		{
+			name: "transfer",
+			argc: 1,
+			err:  "method transfer is overloaded, select one of [transfer(int), transfer(string), transfer(int,int)]",
+		},
+		{name: "deposit", err: "there is no method deposit"},
+		{name: "balance(int)", err: "there is no method balance(int)"},
+	}
+
+	for i, test := range tests {
+		method, err := a.FindMethod(test.name, test.argc)
+		if test.err != "" {
+			if err == nil || err.Error() != test.err {
+				t.Errorf("test[%d] - FindMethod() returns wrong error. expected=%s, got=%v", i, test.err, err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("test[%d] - FindMethod() returns error: %v", i, err)
+		}
+
+		if method.Signature() != test.signature {
+			t.Errorf("test[%d] - FindMethod() returns wrong method. expected=%s, got=%s", i, test.signature, method.Signature())
+		}
+	}
+}
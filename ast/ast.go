@@ -24,8 +24,10 @@ import (
 )
 
 // Node represent ast node
+// Position returns the range of node in source.
 type Node interface {
 	String() string
+	Position() Span
 }
 
 // Represent Statement
@@ -40,19 +42,92 @@ type Expression interface {
 	produce()
 }
 
+// Represent source file.
+// File consists of imports and contracts which share the imports.
+type File struct {
+	Span
+	Imports   []*Import
+	Contracts []*Contract
+}
+
+func (f *File) do() {}
+func (f *File) String() string {
+	var buf bytes.Buffer
+
+	for _, i := range f.Imports {
+		buf.WriteString(i.String() + "\n")
+	}
+
+	for _, c := range f.Contracts {
+		buf.WriteString(c.body() + "\n")
+	}
+
+	return buf.String()
+}
+
+// Contract returns the contract whose name is name. If name is empty,
+// it returns the contract without name. Returns nil if there is no contract.
+func (f *File) Contract(name string) *Contract {
+	for _, c := range f.Contracts {
+		if c.Name == nil && name == "" {
+			return c
+		}
+
+		if c.Name != nil && c.Name.Name == name {
+			return c
+		}
+	}
+
+	return nil
+}
+
 // Represent Contract.
-// Contract consists of multiple functions.
+// Contract consists of multiple enums, modifiers and functions.
+// Contract can have a name. e.g. contract Escrow { ... }
+// Imports are the libraries which functions of contract can call.
+// File is the path of file which contract is declared in, it is empty
+// if contract isn't parsed from file.
 type Contract struct {
+	Span
+	Name      *Identifier
+	Imports   []*Import
+	Enums     []*EnumLiteral
+	Modifiers []*ModifierLiteral
 	Functions []*FunctionLiteral
+	File      string
 }
 
 func (c *Contract) do() {}
 func (c *Contract) String() string {
 	var buf bytes.Buffer
 
+	for _, i := range c.Imports {
+		buf.WriteString(i.String() + "\n")
+	}
+
+	buf.WriteString(c.body())
+
+	return buf.String()
+}
+
+// body returns the string of contract without imports.
+func (c *Contract) body() string {
+	var buf bytes.Buffer
+
 	// start by change line for readability
-	buf.WriteString("\ncontract {\n")
+	if c.Name != nil {
+		buf.WriteString("\ncontract " + c.Name.String() + " {\n")
+	} else {
+		buf.WriteString("\ncontract {\n")
+	}
+
+	for _, e := range c.Enums {
+		buf.WriteString(e.String() + "\n")
+	}
 
+	for _, m := range c.Modifiers {
+		buf.WriteString(m.String() + "\n")
+	}
 	for _, fn := range c.Functions {
 		buf.WriteString(fn.String() + "\n")
 	}
@@ -61,8 +136,79 @@ func (c *Contract) String() string {
 	return buf.String()
 }
 
+// Libraries returns all libraries which contract depends on, including
+// the libraries imported by other libraries. A library comes after
+// the libraries it imports, and appears only once.
+func (c *Contract) Libraries() []*Library {
+	libraries := make([]*Library, 0)
+	visited := make(map[*Library]bool)
+
+	var visit func(imports []*Import)
+	visit = func(imports []*Import) {
+		for _, i := range imports {
+			if visited[i.Library] {
+				continue
+			}
+			visited[i.Library] = true
+
+			visit(i.Library.Imports)
+			libraries = append(libraries, i.Library)
+		}
+	}
+	visit(c.Imports)
+
+	return libraries
+}
+
+// Represent import statement. e.g. import "lib/math.koa"
+// Library is the library loaded from Path.
+type Import struct {
+	Span
+	Path    string
+	Library *Library
+}
+
+func (i *Import) do() {}
+func (i *Import) String() string {
+	return fmt.Sprintf("import \"%s\"", i.Path)
+}
+
+// Represent Library.
+// Library is a file which consists of functions, and its functions are
+// called with the name of library. e.g. math.max(a, b)
+// File is the path of library file, it is empty if library isn't
+// imported from file.
+type Library struct {
+	Span
+	Name      string
+	Imports   []*Import
+	Functions []*FunctionLiteral
+	File      string
+}
+
+// Qualify returns the name of function qualified with the name of library.
+func (l *Library) Qualify(fn *FunctionLiteral) string {
+	return l.Name + "." + fn.Name.String()
+}
+
+func (l *Library) do() {}
+func (l *Library) String() string {
+	var buf bytes.Buffer
+
+	for _, i := range l.Imports {
+		buf.WriteString(i.String() + "\n")
+	}
+
+	for _, fn := range l.Functions {
+		buf.WriteString("\n" + fn.String() + "\n")
+	}
+
+	return buf.String()
+}
+
 // Represent identifier
 type Identifier struct {
+	Span
 	Name string
 }
 
@@ -72,6 +218,20 @@ func (i *Identifier) String() string {
 
 func (i *Identifier) produce() {}
 
+// QualifiedIdentifier represents function of library which is qualified
+// with the name of library. e.g. math.max
+type QualifiedIdentifier struct {
+	Span
+	Library  *Library
+	Function *FunctionLiteral
+}
+
+func (q *QualifiedIdentifier) String() string {
+	return q.Library.Qualify(q.Function)
+}
+
+func (q *QualifiedIdentifier) produce() {}
+
 // Operator represent operator between expression
 type Operator int
 
@@ -91,6 +251,12 @@ const (
 	NOT_EQ            // !=
 	LAND              // &&
 	LOR               // ||
+	BitAnd            // &
+	BitOr             // |
+	BitXor            // ^
+	BitNot            // ~
+	Shl               // <<
+	Shr               // >>
 )
 
 var OperatorMap = map[Operator]string{
@@ -108,6 +274,12 @@ var OperatorMap = map[Operator]string{
 	NOT_EQ:   "!=",
 	LAND:     "&&",
 	LOR:      "||",
+	BitAnd:   "&",
+	BitOr:    "|",
+	BitXor:   "^",
+	BitNot:   "~",
+	Shl:      "<<",
+	Shr:      ">>",
 }
 
 func (o Operator) String() string {
@@ -124,6 +296,7 @@ const (
 	StringType
 	BoolType
 	VoidType
+	EnumType
 )
 
 var DataStructureMap = map[DataStructure]string{
@@ -131,15 +304,28 @@ var DataStructureMap = map[DataStructure]string{
 	StringType: "string",
 	BoolType:   "bool",
 	VoidType:   "void",
+	EnumType:   "enum",
 }
 
 func (ds DataStructure) String() string {
 	return DataStructureMap[ds]
 }
 
+// TypeName returns the name of type which is shown in the source code.
+// Enum types are named by its declaration.
+func TypeName(ds DataStructure, enum *EnumLiteral) string {
+	if ds == EnumType && enum != nil {
+		return enum.Name.String()
+	}
+	return ds.String()
+}
+
 // Represent assign statement
+// Enum is set only when Type is EnumType.
 type AssignStatement struct {
+	Span
 	Type     DataStructure
+	Enum     *EnumLiteral
 	Variable Identifier
 	Value    Expression
 }
@@ -148,7 +334,7 @@ func (a *AssignStatement) do() {}
 
 func (a *AssignStatement) String() string {
 	var out bytes.Buffer
-	out.WriteString(a.Type.String() + " ")
+	out.WriteString(TypeName(a.Type, a.Enum) + " ")
 	out.WriteString(a.Variable.Name + " = ")
 	out.WriteString(a.Value.String())
 	return out.String()
@@ -156,6 +342,7 @@ func (a *AssignStatement) String() string {
 
 // ReassignStatement is used when we want re-assign value to variable
 type ReassignStatement struct {
+	Span
 	Variable *Identifier
 	Value    Expression
 }
@@ -172,6 +359,7 @@ func (r *ReassignStatement) String() string {
 
 // Represent return statement
 type ReturnStatement struct {
+	Span
 	ReturnValue Expression
 }
 
@@ -186,6 +374,7 @@ func (r *ReturnStatement) String() string {
 
 // Represent if statement
 type IfStatement struct {
+	Span
 	Condition   Expression
 	Consequence *BlockStatement
 	Alternative *BlockStatement
@@ -203,11 +392,17 @@ func (i *IfStatement) String() string {
 
 // FunctionLiteral represents function definition
 // e.g. func foo(int a) { ... }
+//
+// ReturnEnum is set only when ReturnType is EnumType.
+// Modifiers wrap the body in the applied order.
 type FunctionLiteral struct {
+	Span
 	Name       *Identifier
 	Parameters []*ParameterLiteral
+	Modifiers  []*ModifierInvocation
 	Body       *BlockStatement
 	ReturnType DataStructure
+	ReturnEnum *EnumLiteral
 }
 
 func (f *FunctionLiteral) do() {}
@@ -224,25 +419,136 @@ func (f *FunctionLiteral) String() string {
 
 	out.WriteString(strings.Join(params, ", "))
 	out.WriteString(") ")
-	out.WriteString(f.ReturnType.String() + " {\n")
+	for _, m := range f.Modifiers {
+		out.WriteString(m.String() + " ")
+	}
+	out.WriteString(TypeName(f.ReturnType, f.ReturnEnum) + " {\n")
 	out.WriteString(f.Body.String() + "\n")
 	out.WriteString("}")
 
 	return out.String()
 }
 
+// Signature returns function's signature. Enum values are passed as
+// integer, so enum parameters are written as int in the signature.
 func (f *FunctionLiteral) Signature() string {
 
 	paramTypes := []string{}
 	for _, p := range f.Parameters {
+		if p.Type == EnumType {
+			paramTypes = append(paramTypes, IntType.String())
+			continue
+		}
 		paramTypes = append(paramTypes, p.Type.String())
 	}
 
 	return fmt.Sprintf("%s(%s)", f.Name.String(), strings.Join(paramTypes, ","))
 }
 
+// ModifierLiteral represents modifier definition, which wraps
+// the body of function. The body of function is placed at "_".
+// e.g. modifier positive(a int) { require(a > 0); _ }
+type ModifierLiteral struct {
+	Span
+	Name       *Identifier
+	Parameters []*ParameterLiteral
+	Body       *BlockStatement
+}
+
+func (m *ModifierLiteral) do() {}
+
+func (m *ModifierLiteral) String() string {
+	var out bytes.Buffer
+
+	params := []string{}
+	for _, p := range m.Parameters {
+		params = append(params, p.String())
+	}
+
+	out.WriteString("modifier " + m.Name.String() + "(")
+	out.WriteString(strings.Join(params, ", "))
+	out.WriteString(") {\n")
+	out.WriteString(m.Body.String() + "\n")
+	out.WriteString("}")
+
+	return out.String()
+}
+
+// ModifierInvocation represents modifier which is applied to function
+// e.g. func withdraw(amount int) positive(amount) { ... }
+type ModifierInvocation struct {
+	Span
+	Modifier  *ModifierLiteral
+	Arguments []Expression
+}
+
+func (m *ModifierInvocation) String() string {
+	args := make([]string, 0)
+	for _, a := range m.Arguments {
+		args = append(args, a.String())
+	}
+	return fmt.Sprintf("%s(%s)", m.Modifier.Name.String(), strings.Join(args, ", "))
+}
+
+// PlaceholderStatement represents "_" in the body of modifier,
+// where the body of function is placed.
+type PlaceholderStatement struct {
+	Span
+}
+
+func (p *PlaceholderStatement) do() {}
+
+func (p *PlaceholderStatement) String() string {
+	return "_"
+}
+
+// RequireStatement stops the execution when the condition is false
+// e.g. require(a > 0)
+type RequireStatement struct {
+	Span
+	Condition Expression
+}
+
+func (r *RequireStatement) do() {}
+
+func (r *RequireStatement) String() string {
+	return fmt.Sprintf("require(%s)", r.Condition.String())
+}
+
+// StoreStatement stores the value of key in the storage of contract
+// e.g. store(1, balance)
+type StoreStatement struct {
+	Span
+	Key   Expression
+	Value Expression
+}
+
+func (s *StoreStatement) do() {}
+
+func (s *StoreStatement) String() string {
+	return fmt.Sprintf("store(%s, %s)", s.Key.String(), s.Value.String())
+}
+
+// EmitStatement records the values as a log of contract
+// e.g. emit(from, amount)
+type EmitStatement struct {
+	Span
+	Values []Expression
+}
+
+func (e *EmitStatement) do() {}
+
+func (e *EmitStatement) String() string {
+	strs := make([]string, 0)
+	for _, v := range e.Values {
+		strs = append(strs, v.String())
+	}
+	return fmt.Sprintf("emit(%s)", strings.Join(strs, ", "))
+}
+
 // Represent block statement
 type BlockStatement struct {
+	Span
 	Statements []Statement
 }
 
@@ -262,6 +568,7 @@ func (b *BlockStatement) String() string {
 
 // Represent function statement
 type ExpressionStatement struct {
+	Span
 	Expr Expression
 }
 
@@ -272,18 +579,52 @@ func (e *ExpressionStatement) String() string {
 }
 
 // Represent string literal
+//
+// Value is the content of the literal, the double quotes around it are
+// removed and escape sequences are decoded. It is what the compiler pushes
+// onto the stack, so "123" is encoded as the three bytes 123 and converted
+// to 123 by int("123").
 type StringLiteral struct {
+	Span
 	Value string
 }
 
 func (s *StringLiteral) produce() {}
 
 func (s *StringLiteral) String() string {
-	return s.Value
+	return Quote(s.Value)
+}
+
+// Quote returns s as a string literal of koa. Characters which can't be
+// written as it is are escaped, e.g. newline is \n and 0x01 is \x01.
+func Quote(s string) string {
+	var buf bytes.Buffer
+	buf.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\n':
+			buf.WriteString(`\n`)
+		case c == '\t':
+			buf.WriteString(`\t`)
+		case c == '\r':
+			buf.WriteString(`\r`)
+		case c == '"' || c == '\\':
+			buf.WriteByte('\\')
+			buf.WriteByte(c)
+		case c < ' ' || c > '~':
+			buf.WriteString(`\x` + strconv.FormatInt(int64(c)|0x100, 16)[1:])
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	buf.WriteByte('"')
+
+	return buf.String()
 }
 
 // Represent integer literal
 type IntegerLiteral struct {
+	Span
 	Value int64
 }
 
@@ -295,6 +636,7 @@ func (i *IntegerLiteral) String() string {
 
 // Represent Boolean expression
 type BooleanLiteral struct {
+	Span
 	Value bool
 }
 
@@ -305,19 +647,69 @@ func (b *BooleanLiteral) String() string {
 }
 
 // Represent Function Parameter expression
+// Enum is set only when Type is EnumType.
 type ParameterLiteral struct {
+	Span
 	Identifier *Identifier
 	Type       DataStructure
+	Enum       *EnumLiteral
 }
 
 func (p *ParameterLiteral) produce() {}
 
 func (p *ParameterLiteral) String() string {
-	return fmt.Sprintf("Parameter : (Identifier: %s, Type: %s)", p.Identifier.String(), p.Type.String())
+	return fmt.Sprintf("Parameter : (Identifier: %s, Type: %s)", p.Identifier.String(), TypeName(p.Type, p.Enum))
+}
+
+// EnumLiteral represents enum declaration
+// e.g. enum State { Open, Closed }
+//
+// Members are numbered from zero in the declared order.
+type EnumLiteral struct {
+	Span
+	Name    *Identifier
+	Members []*Identifier
+}
+
+func (e *EnumLiteral) do() {}
+
+func (e *EnumLiteral) String() string {
+	members := make([]string, 0)
+	for _, m := range e.Members {
+		members = append(members, m.String())
+	}
+	return fmt.Sprintf("enum %s { %s }", e.Name.String(), strings.Join(members, ", "))
+}
+
+// Value returns the integer value of member.
+// If enum doesn't have member, returns false.
+func (e *EnumLiteral) Value(member string) (int64, bool) {
+	for i, m := range e.Members {
+		if m.Name == member {
+			return int64(i), true
+		}
+	}
+	return 0, false
+}
+
+// EnumMemberLiteral represents a member of enum
+// e.g. State.Open
+type EnumMemberLiteral struct {
+	Span
+	Enum   *EnumLiteral
+	Member *Identifier
+	Value  int64
+}
+
+func (e *EnumMemberLiteral) produce() {}
+
+func (e *EnumMemberLiteral) String() string {
+	return fmt.Sprintf("%s.%s", e.Enum.Name.String(), e.Member.String())
 }
 
 // Represent prefix expression
 type PrefixExpression struct {
+	Span
 	Operator
 	Right Expression
 }
@@ -330,6 +722,7 @@ func (p *PrefixExpression) String() string {
 
 // Repersent Infix expression
 type InfixExpression struct {
+	Span
 	Left Expression
 	Operator
 	Right Expression
@@ -341,8 +734,83 @@ func (i *InfixExpression) String() string {
 	return fmt.Sprintf("(%s %s %s)", i.Left.String(), i.Operator.String(), i.Right.String())
 }
 
+// Represent conditional expression
+// e.g. a > b ? a : b
+type ConditionalExpression struct {
+	Span
+	Condition   Expression
+	Consequence Expression
+	Alternative Expression
+}
+
+func (c *ConditionalExpression) produce() {}
+
+func (c *ConditionalExpression) String() string {
+	return fmt.Sprintf("(%s ? %s : %s)", c.Condition.String(), c.Consequence.String(), c.Alternative.String())
+}
+
+// Represent type conversion expression
+// e.g. int(a), string(5), bool("true")
+//
+// ValueType is the type of Value which is resolved by the parser,
+// so that the compiler knows which conversion to generate.
+type ConversionExpression struct {
+	Span
+	Type      DataStructure
+	Value     Expression
+	ValueType DataStructure
+}
+
+func (c *ConversionExpression) produce() {}
+
+func (c *ConversionExpression) String() string {
+	return fmt.Sprintf("%s(%s)", c.Type.String(), c.Value.String())
+}
+
+// ContractCallExpression represents the call of function in other contract
+// at Address. Signature is the function signature to make selector, and
+// ReturnType is the type of value which the function returns.
+// e.g. call(token, "transfer(int,int) bool", to, amount)
+type ContractCallExpression struct {
+	Span
+	Address    Expression
+	Signature  string
+	Arguments  []Expression
+	ReturnType DataStructure
+}
+
+func (c *ContractCallExpression) produce() {}
+
+func (c *ContractCallExpression) String() string {
+	method := c.Signature
+	if c.ReturnType != IntType {
+		method += " " + c.ReturnType.String()
+	}
+
+	strs := []string{c.Address.String(), fmt.Sprintf("\"%s\"", method)}
+	for _, arg := range c.Arguments {
+		strs = append(strs, arg.String())
+	}
+
+	return fmt.Sprintf("call(%s)", strings.Join(strs, ", "))
+}
+
+// LoadExpression represents the value of key in the storage of contract
+// e.g. load(1)
+type LoadExpression struct {
+	Span
+	Key Expression
+}
+
+func (l *LoadExpression) produce() {}
+
+func (l *LoadExpression) String() string {
+	return fmt.Sprintf("load(%s)", l.Key.String())
+}
+
 // Represent Call expression
 type CallExpression struct {
+	Span
 	Function  Expression
 	Arguments []Expression
 }
@@ -24,7 +24,7 @@ func TestAssignStatement_String(t *testing.T) {
 				Value:    &StringLiteral{Value: "hello, world"},
 			},
 			// type mismatch is not considered here
-			expected: "bool asdf = hello, world",
+			expected: `bool asdf = "hello, world"`,
 		},
 		{
 			input: AssignStatement{
@@ -82,24 +82,32 @@ func TestStringLiteral_String(t *testing.T) {
 		expected string
 	}{
 		{
-			StringLiteral{"hello"},
-			"hello",
+			StringLiteral{Value: "hello"},
+			`"hello"`,
 		},
 		{
-			StringLiteral{"hello, world"},
-			"hello, world",
+			StringLiteral{Value: "hello, world"},
+			`"hello, world"`,
 		},
 		{
-			StringLiteral{"123"},
-			"123",
+			StringLiteral{Value: "123"},
+			`"123"`,
+		},
+		{
+			StringLiteral{Value: "123, hello"},
+			`"123, hello"`,
 		},
 		{
-			StringLiteral{"123, hello"},
-			"123, hello",
+			StringLiteral{Value: ""},
+			`""`,
 		},
 		{
-			StringLiteral{""},
-			"",
+			StringLiteral{Value: "say \"hi\"\n"},
+			`"say \"hi\"\n"`,
+		},
+		{
+			StringLiteral{Value: "\x01\xff"},
+			`"\x01\xff"`,
 		},
 	}
 
@@ -136,11 +144,11 @@ func TestBooleanLiteral_String(t *testing.T) {
 		expected string
 	}{
 		{
-			BooleanLiteral{true},
+			BooleanLiteral{Value: true},
 			"true",
 		},
 		{
-			BooleanLiteral{false},
+			BooleanLiteral{Value: false},
 			"false",
 		},
 	}
@@ -239,7 +247,7 @@ func TestReassignStatement_String(t *testing.T) {
 					},
 				},
 			},
-			expected: "foo = (hello + 2)",
+			expected: `foo = ("hello" + 2)`,
 		},
 	}
 
@@ -262,13 +270,13 @@ func TestReturnStatement_String(t *testing.T) {
 		},
 		{
 			input: ReturnStatement{
-				ReturnValue: &StringLiteral{Value: "\"hello, world\""},
+				ReturnValue: &StringLiteral{Value: "hello, world"},
 			},
 			expected: "return \"hello, world\"",
 		},
 		{
 			input: ReturnStatement{
-				ReturnValue: &StringLiteral{Value: "\"hello, world\""},
+				ReturnValue: &StringLiteral{Value: "hello, world"},
 			},
 			expected: "return \"hello, world\"",
 		},
@@ -290,6 +298,137 @@ func TestReturnStatement_String(t *testing.T) {
 	}
 }
 
+func TestConditionalExpression_String(t *testing.T) {
+	tests := []struct {
+		input    ConditionalExpression
+		expected string
+	}{
+		{
+			input: ConditionalExpression{
+				Condition:   &BooleanLiteral{Value: true},
+				Consequence: &IntegerLiteral{Value: 1},
+				Alternative: &IntegerLiteral{Value: 2},
+			},
+			expected: "(true ? 1 : 2)",
+		},
+		{
+			input: ConditionalExpression{
+				Condition: &InfixExpression{
+					Left:     &Identifier{Name: "a"},
+					Operator: GT,
+					Right:    &Identifier{Name: "b"},
+				},
+				Consequence: &Identifier{Name: "a"},
+				Alternative: &Identifier{Name: "b"},
+			},
+			expected: "((a > b) ? a : b)",
+		},
+	}
+
+	for _, tt := range tests {
+		result := tt.input.String()
+		testString(t, result, tt.expected)
+	}
+}
+
+func TestConversionExpression_String(t *testing.T) {
+	tests := []struct {
+		input    ConversionExpression
+		expected string
+	}{
+		{
+			input: ConversionExpression{
+				Type:      StringType,
+				Value:     &IntegerLiteral{Value: 1},
+				ValueType: IntType,
+			},
+			expected: "string(1)",
+		},
+		{
+			input: ConversionExpression{
+				Type:      IntType,
+				Value:     &Identifier{Name: "a"},
+				ValueType: StringType,
+			},
+			expected: "int(a)",
+		},
+		{
+			input: ConversionExpression{
+				Type:      IntType,
+				Value:     &StringLiteral{Value: "abc"},
+				ValueType: StringType,
+			},
+			expected: `int("abc")`,
+		},
+	}
+
+	for _, tt := range tests {
+		result := tt.input.String()
+		testString(t, result, tt.expected)
+	}
+}
+
+func TestContractCallExpression_String(t *testing.T) {
+	tests := []struct {
+		input    ContractCallExpression
+		expected string
+	}{
+		{
+			input: ContractCallExpression{
+				Address:    &Identifier{Name: "token"},
+				Signature:  "transfer(int,int)",
+				Arguments:  []Expression{&Identifier{Name: "to"}, &IntegerLiteral{Value: 5}},
+				ReturnType: IntType,
+			},
+			expected: `call(token, "transfer(int,int)", to, 5)`,
+		},
+		{
+			input: ContractCallExpression{
+				Address:    &IntegerLiteral{Value: 1},
+				Signature:  "opened()",
+				Arguments:  []Expression{},
+				ReturnType: BoolType,
+			},
+			expected: `call(1, "opened() bool")`,
+		},
+	}
+
+	for _, tt := range tests {
+		result := tt.input.String()
+		testString(t, result, tt.expected)
+	}
+}
+
+func TestStorageStatement_String(t *testing.T) {
+	tests := []struct {
+		input    Node
+		expected string
+	}{
+		{
+			input: &StoreStatement{
+				Key:   &IntegerLiteral{Value: 1},
+				Value: &LoadExpression{Key: &IntegerLiteral{Value: 2}},
+			},
+			expected: "store(1, load(2))",
+		},
+		{
+			input: &EmitStatement{
+				Values: []Expression{&Identifier{Name: "a"}, &IntegerLiteral{Value: 3}},
+			},
+			expected: "emit(a, 3)",
+		},
+		{
+			input:    &EmitStatement{},
+			expected: "emit()",
+		},
+	}
+
+	for _, tt := range tests {
+		result := tt.input.String()
+		testString(t, result, tt.expected)
+	}
+}
+
 func TestFunctionLiteral_Signature(t *testing.T) {
 	tests := []struct {
 		input    FunctionLiteral
@@ -329,6 +468,22 @@ func TestFunctionLiteral_Signature(t *testing.T) {
 			},
 			expected: "foo(int,string)",
 		},
+		{
+			input: FunctionLiteral{
+				Name: &Identifier{Name: "foo"},
+				Parameters: []*ParameterLiteral{
+					{
+						Identifier: &Identifier{Name: "s"},
+						Type:       EnumType,
+						Enum: &EnumLiteral{
+							Name:    &Identifier{Name: "State"},
+							Members: []*Identifier{{Name: "Open"}},
+						},
+					},
+				},
+			},
+			expected: "foo(int)",
+		},
 	}
 
 	for _, tt := range tests {
@@ -337,6 +492,211 @@ func TestFunctionLiteral_Signature(t *testing.T) {
 	}
 }
 
+func TestEnumLiteral_String(t *testing.T) {
+	tests := []struct {
+		input    EnumLiteral
+		expected string
+	}{
+		{
+			input: EnumLiteral{
+				Name:    &Identifier{Name: "State"},
+				Members: []*Identifier{{Name: "Open"}},
+			},
+			expected: "enum State { Open }",
+		},
+		{
+			input: EnumLiteral{
+				Name: &Identifier{Name: "State"},
+				Members: []*Identifier{
+					{Name: "Open"},
+					{Name: "Closed"},
+					{Name: "Settled"},
+				},
+			},
+			expected: "enum State { Open, Closed, Settled }",
+		},
+	}
+
+	for _, tt := range tests {
+		result := tt.input.String()
+		testString(t, result, tt.expected)
+	}
+}
+
+func TestEnumLiteral_Value(t *testing.T) {
+	enum := EnumLiteral{
+		Name: &Identifier{Name: "State"},
+		Members: []*Identifier{
+			{Name: "Open"},
+			{Name: "Closed"},
+		},
+	}
+
+	tests := []struct {
+		member        string
+		expectedValue int64
+		expectedOk    bool
+	}{
+		{"Open", 0, true},
+		{"Closed", 1, true},
+		{"Settled", 0, false},
+	}
+
+	for i, tt := range tests {
+		value, ok := enum.Value(tt.member)
+		if value != tt.expectedValue || ok != tt.expectedOk {
+			t.Errorf("test[%d] - Value() wrong result. expected=(%d, %t), got=(%d, %t)",
+				i, tt.expectedValue, tt.expectedOk, value, ok)
+		}
+	}
+}
+
+func TestEnumMemberLiteral_String(t *testing.T) {
+	enum := &EnumLiteral{
+		Name: &Identifier{Name: "State"},
+		Members: []*Identifier{
+			{Name: "Open"},
+			{Name: "Closed"},
+		},
+	}
+
+	input := EnumMemberLiteral{
+		Enum:   enum,
+		Member: &Identifier{Name: "Closed"},
+		Value:  1,
+	}
+
+	testString(t, input.String(), "State.Closed")
+}
+
+func TestModifierLiteral_String(t *testing.T) {
+	modifier := &ModifierLiteral{
+		Name: &Identifier{Name: "positive"},
+		Parameters: []*ParameterLiteral{
+			{
+				Identifier: &Identifier{Name: "a"},
+				Type:       IntType,
+			},
+		},
+		Body: &BlockStatement{
+			Statements: []Statement{
+				&RequireStatement{
+					Condition: &InfixExpression{
+						Left:     &Identifier{Name: "a"},
+						Operator: GT,
+						Right:    &IntegerLiteral{Value: 0},
+					},
+				},
+				&PlaceholderStatement{},
+			},
+		},
+	}
+
+	testString(t, modifier.String(), `modifier positive(Parameter : (Identifier: a, Type: int)) {
+require((a > 0))
+_
+}`)
+
+	invocation := &ModifierInvocation{
+		Modifier:  modifier,
+		Arguments: []Expression{&Identifier{Name: "b"}},
+	}
+
+	testString(t, invocation.String(), "positive(b)")
+
+	fn := &FunctionLiteral{
+		Name:       &Identifier{Name: "foo"},
+		Parameters: []*ParameterLiteral{},
+		Modifiers:  []*ModifierInvocation{invocation},
+		Body:       &BlockStatement{},
+		ReturnType: VoidType,
+	}
+
+	testString(t, fn.String(), "func foo() positive(b) void {\n\n}")
+}
+
+func TestContract_Libraries(t *testing.T) {
+	util := &Library{Name: "util"}
+	math := &Library{
+		Name:    "math",
+		Imports: []*Import{{Path: "util.koa", Library: util}},
+	}
+	str := &Library{
+		Name:    "str",
+		Imports: []*Import{{Path: "util.koa", Library: util}},
+	}
+
+	contract := &Contract{
+		Imports: []*Import{
+			{Path: "lib/math.koa", Library: math},
+			{Path: "lib/str.koa", Library: str},
+		},
+	}
+
+	expected := []*Library{util, math, str}
+	libraries := contract.Libraries()
+	if len(libraries) != len(expected) {
+		t.Fatalf("Libraries() wrong length. expected=%d, got=%d", len(expected), len(libraries))
+	}
+
+	for i, l := range libraries {
+		if l != expected[i] {
+			t.Errorf("Libraries()[%d] wrong library. expected=%s, got=%s", i, expected[i].Name, l.Name)
+		}
+	}
+
+	testString(t, contract.String(), "import \"lib/math.koa\"\nimport \"lib/str.koa\"\n\ncontract {\n}")
+}
+
+func TestQualifiedIdentifier_String(t *testing.T) {
+	max := &FunctionLiteral{
+		Name:       &Identifier{Name: "max"},
+		Parameters: []*ParameterLiteral{},
+		Body:       &BlockStatement{},
+		ReturnType: IntType,
+	}
+	math := &Library{
+		Name:      "math",
+		Imports:   []*Import{{Path: "util.koa", Library: &Library{Name: "util"}}},
+		Functions: []*FunctionLiteral{max},
+	}
+
+	testString(t, (&QualifiedIdentifier{Library: math, Function: max}).String(), "math.max")
+	testString(t, math.String(), "import \"util.koa\"\n\nfunc max() int {\n\n}\n")
+}
+
+func TestFile_Contract(t *testing.T) {
+	escrow := &Contract{Name: &Identifier{Name: "Escrow"}}
+	token := &Contract{Name: &Identifier{Name: "Token"}}
+	file := &File{
+		Imports:   []*Import{{Path: "lib/math.koa", Library: &Library{Name: "math"}}},
+		Contracts: []*Contract{escrow, token},
+	}
+
+	tests := []struct {
+		name     string
+		expected *Contract
+	}{
+		{"Escrow", escrow},
+		{"Token", token},
+		{"Auction", nil},
+		{"", nil},
+	}
+
+	for i, tt := range tests {
+		if result := file.Contract(tt.name); result != tt.expected {
+			t.Errorf("test[%d] - Contract(%s) wrong result. expected=%v, got=%v", i, tt.name, tt.expected, result)
+		}
+	}
+
+	testString(t, file.String(), "import \"lib/math.koa\"\n\ncontract Escrow {\n}\n\ncontract Token {\n}\n")
+
+	anonymous := &Contract{}
+	if result := (&File{Contracts: []*Contract{anonymous}}).Contract(""); result != anonymous {
+		t.Errorf("Contract() should return contract without name. got=%v", result)
+	}
+}
+
 func testString(t *testing.T, got, expected string) {
 	t.Helper()
 	if got != expected {
@@ -0,0 +1,60 @@
+/*
+ * Copyright 2018-2019 De-labtory
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ast
+
+import "fmt"
+
+// Pos is a position in source. Line and Column start from 1, and
+// Column counts bytes. Offset is the byte offset from the start of
+// source, which starts from 0. The zero value means the position is
+// unknown.
+type Pos struct {
+	Line   int
+	Column int
+	Offset int
+}
+
+// IsValid reports whether the position is known.
+func (p Pos) IsValid() bool {
+	return p.Line > 0
+}
+
+func (p Pos) String() string {
+	if !p.IsValid() {
+		return "-"
+	}
+
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// Span is the range of node in source. Start is the position of its
+// first token, and End is the position right after its last token.
+type Span struct {
+	Start Pos
+	End   Pos
+}
+
+// Position returns the range of node.
+func (s *Span) Position() Span {
+	return *s
+}
+
+// SetPosition sets the range of node, it is called by the parser.
+func (s *Span) SetPosition(start Pos, end Pos) {
+	s.Start = start
+	s.End = end
+}
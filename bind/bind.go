@@ -0,0 +1,311 @@
+/*
+ * Copyright 2018-2019 De-labtory
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bind
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/token"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/DE-labtory/koa/abi"
+)
+
+// reserved are the names used in the generated methods, so
+// parameters with these names are renamed.
+var reserved = map[string]bool{
+	"c":    true,
+	"out":  true,
+	"err":  true,
+	"abi":  true,
+	"bind": true,
+}
+
+// tmplData is the data to generate the binding of contract.
+type tmplData struct {
+	Package string
+	Type    string
+	ABI     abi.ABI
+	Code    string
+	Enums   []abi.Enum
+	Methods []tmplMethod
+}
+
+type tmplMethod struct {
+	Index     int
+	Name      string
+	Method    abi.Method
+	Params    []tmplParam
+	Output    string
+	ZeroValue string
+}
+
+type tmplParam struct {
+	Name string
+	Type string
+}
+
+// Bind generates Go source of the binding of contract whose ABI is a.
+// pkg is the package name of source and typeName is the name of binding.
+// If typeName is empty, it is the name of contract. Code is the bytecode
+// of contract, it can be empty.
+func Bind(a abi.ABI, pkg string, typeName string, code []byte) (string, error) {
+	if typeName == "" {
+		typeName = exported(a.Name)
+	}
+
+	if !token.IsIdentifier(typeName) {
+		return "", errors.New("type name of binding is required")
+	}
+
+	if !token.IsIdentifier(pkg) {
+		return "", fmt.Errorf("invalid package name %q", pkg)
+	}
+
+	data := tmplData{
+		Package: pkg,
+		Type:    typeName,
+		ABI:     a,
+		Code:    fmt.Sprintf("%x", code),
+		Enums:   enums(a),
+		Methods: make([]tmplMethod, 0),
+	}
+
+	names := make(map[string]string)
+	overloads := make(map[string]int)
+	for i, method := range a.Methods {
+		m, err := bindMethod(i, method)
+		if err != nil {
+			return "", err
+		}
+
+		// Overloaded methods are numbered in order, e.g. Transfer, Transfer0
+		if n, ok := overloads[method.Name]; ok {
+			m.Name = fmt.Sprintf("%s%d", m.Name, n)
+			overloads[method.Name] = n + 1
+		} else {
+			overloads[method.Name] = 0
+		}
+
+		if other, ok := names[m.Name]; ok {
+			return "", fmt.Errorf("methods %s and %s have the same name %s in Go",
+				other, method.Signature(), m.Name)
+		}
+		names[m.Name] = method.Signature()
+
+		data.Methods = append(data.Methods, m)
+	}
+
+	var buf bytes.Buffer
+	if err := bindTmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return "", err
+	}
+
+	return string(src), nil
+}
+
+func bindMethod(index int, method abi.Method) (tmplMethod, error) {
+	m := tmplMethod{
+		Index:  index,
+		Name:   exported(method.Name),
+		Method: method,
+		Params: make([]tmplParam, 0),
+	}
+
+	for _, arg := range method.Arguments {
+		t, err := goType(arg.Type)
+		if err != nil {
+			return tmplMethod{}, err
+		}
+
+		m.Params = append(m.Params, tmplParam{Name: paramName(arg.Name), Type: t})
+	}
+
+	if method.Output.Type.Type == abi.Void {
+		return m, nil
+	}
+
+	t, err := goType(method.Output.Type)
+	if err != nil {
+		return tmplMethod{}, err
+	}
+
+	m.Output = t
+	m.ZeroValue = zeroValue(t)
+
+	return m, nil
+}
+
+// goType returns the Go type of argument. Enum is int64.
+func goType(t abi.Type) (string, error) {
+	switch t.Type {
+	case abi.Integer, abi.Integer64:
+		return "int64", nil
+	case abi.Boolean:
+		return "bool", nil
+	case abi.String:
+		return "string", nil
+	default:
+		return "", fmt.Errorf("unsupported type %s", t.Type)
+	}
+}
+
+func zeroValue(t string) string {
+	switch t {
+	case "bool":
+		return "false"
+	case "string":
+		return `""`
+	default:
+		return "0"
+	}
+}
+
+// exported converts name to an exported Go identifier, the parts
+// separated by '_' are joined. e.g. check_money is CheckMoney
+func exported(name string) string {
+	parts := strings.Split(name, "_")
+	for i, p := range parts {
+		if p == "" {
+			continue
+		}
+		r := []rune(p)
+		r[0] = unicode.ToUpper(r[0])
+		parts[i] = string(r)
+	}
+
+	return strings.Join(parts, "")
+}
+
+// paramName renames the parameter whose name is keyword of Go
+// or is used in the generated method.
+func paramName(name string) string {
+	if token.IsKeyword(name) || reserved[name] {
+		return name + "_"
+	}
+
+	return name
+}
+
+// enums returns the enums used in the methods in the order they appear.
+func enums(a abi.ABI) []abi.Enum {
+	found := make(map[string]bool)
+	result := make([]abi.Enum, 0)
+
+	add := func(t abi.Type) {
+		if t.Enum == nil || found[t.Enum.Name] {
+			return
+		}
+		found[t.Enum.Name] = true
+		result = append(result, *t.Enum)
+	}
+
+	for _, method := range a.Methods {
+		for _, arg := range method.Arguments {
+			add(arg.Type)
+		}
+		add(method.Output.Type)
+	}
+
+	return result
+}
+
+// literal returns the Go literal of abi.Type.
+func literal(t abi.Type) string {
+	if t.Enum == nil {
+		return fmt.Sprintf("abi.Type{Type: %q}", t.Type)
+	}
+
+	return fmt.Sprintf("abi.Type{Type: %q, Enum: &abi.Enum{Name: %q, Members: %#v}}",
+		t.Type, t.Enum.Name, t.Enum.Members)
+}
+
+var bindTmpl = template.Must(template.New("bind").Funcs(template.FuncMap{
+	"literal":  literal,
+	"exported": exported,
+}).Parse(`// Code generated by koa bind. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"github.com/DE-labtory/koa/abi"
+	"github.com/DE-labtory/koa/bind"
+)
+
+// {{.Type}}ABI is the ABI of contract {{.ABI.Name}}.
+var {{.Type}}ABI = abi.ABI{
+	Name: {{printf "%q" .ABI.Name}},
+	Methods: []abi.Method{
+	{{- range .ABI.Methods}}
+		{
+			Name: {{printf "%q" .Name}},
+			Arguments: abi.Arguments{
+			{{- range .Arguments}}
+				{Name: {{printf "%q" .Name}}, Type: {{literal .Type}}},
+			{{- end}}
+			},
+			Output: abi.Argument{Type: {{literal .Output.Type}}},
+		},
+	{{- end}}
+	},
+}
+{{if .Code}}
+// {{.Type}}Bin is the bytecode of contract {{.ABI.Name}} in hex.
+const {{.Type}}Bin = "{{.Code}}"
+{{end}}
+{{- range .Enums}}
+{{$enum := .}}
+// Members of enum {{.Name}}.
+const (
+{{- range $i, $m := .Members}}
+	{{exported $enum.Name}}{{exported $m}} int64 = {{$i}}
+{{- end}}
+)
+{{end}}
+// {{.Type}} is the binding of contract {{.ABI.Name}}.
+type {{.Type}} struct {
+	contract *bind.Contract
+}
+
+// New{{.Type}} creates the binding of contract {{.ABI.Name}} which is called through backend.
+func New{{.Type}}(backend bind.Backend) *{{.Type}} {
+	return &{{.Type}}{contract: bind.NewContract({{.Type}}ABI, backend)}
+}
+{{range .Methods}}
+// {{.Name}} calls {{.Method.Signature}} of contract.
+func (c *{{$.Type}}) {{.Name}}({{range $i, $p := .Params}}{{if $i}}, {{end}}{{$p.Name}} {{$p.Type}}{{end}}) {{if .Output}}({{.Output}}, error){{else}}error{{end}} {
+	{{- if .Output}}
+	out, err := c.contract.Call({{$.Type}}ABI.Methods[{{.Index}}]{{range .Params}}, {{.Name}}{{end}})
+	if err != nil {
+		return {{.ZeroValue}}, err
+	}
+	return out.({{.Output}}), nil
+	{{- else}}
+	_, err := c.contract.Call({{$.Type}}ABI.Methods[{{.Index}}]{{range .Params}}, {{.Name}}{{end}})
+	return err
+	{{- end}}
+}
+{{end}}`))
@@ -0,0 +1,179 @@
+/*
+ * Copyright 2018-2019 De-labtory
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bind_test
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/DE-labtory/koa"
+	"github.com/DE-labtory/koa/abi"
+	"github.com/DE-labtory/koa/bind"
+)
+
+func TestBind(t *testing.T) {
+	asm, a, err := koa.CompileFile("../test/bind/wallet.koa", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src, err := bind.Bind(a, "bindtest", "", asm.ToRawByteCode())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, err := ioutil.ReadFile("bindtest/wallet.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if src != string(expected) {
+		t.Errorf("binding is different from bindtest/wallet.go, run go generate ./bind/bindtest. got=\n%s", src)
+	}
+}
+
+func TestBind_error(t *testing.T) {
+	intType := abi.Type{Type: abi.Integer}
+
+	tests := []struct {
+		abi      abi.ABI
+		pkg      string
+		typeName string
+		err      string
+	}{
+		{
+			abi: abi.ABI{},
+			pkg: "wallet",
+			err: "type name of binding is required",
+		},
+		{
+			abi: abi.ABI{Name: "Wallet"},
+			pkg: "my-wallet",
+			err: `invalid package name "my-wallet"`,
+		},
+		{
+			abi: abi.ABI{
+				Name: "Wallet",
+				Methods: []abi.Method{
+					{Name: "is_owner", Arguments: abi.Arguments{}, Output: abi.Argument{Type: intType}},
+					{Name: "IsOwner", Arguments: abi.Arguments{}, Output: abi.Argument{Type: intType}},
+				},
+			},
+			pkg: "wallet",
+			err: "methods is_owner() and IsOwner() have the same name IsOwner in Go",
+		},
+	}
+
+	for i, test := range tests {
+		_, err := bind.Bind(test.abi, test.pkg, test.typeName, nil)
+		if err == nil || err.Error() != test.err {
+			t.Errorf("test[%d] - Bind() returns wrong error. expected=%s, got=%v", i, test.err, err)
+		}
+	}
+}
+
+func TestContract_Call(t *testing.T) {
+	asm, a, err := koa.Compile(`
+contract {
+	func add(a int, b int) int {
+		return a + b
+	}
+
+	func positive(a int) bool {
+		return a > 0
+	}
+}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := bind.NewContract(a, bind.CodeBackend{Code: asm.ToRawByteCode()})
+
+	tests := []struct {
+		method abi.Method
+		args   []interface{}
+		output interface{}
+		err    string
+	}{
+		{
+			method: a.Methods[0],
+			args:   []interface{}{1, 2},
+			output: int64(3),
+		},
+		{
+			method: a.Methods[1],
+			args:   []interface{}{-1},
+			output: false,
+		},
+		{
+			method: a.Methods[0],
+			args:   []interface{}{1},
+			err:    "method add(int,int) needs 2 arguments, but got 1",
+		},
+	}
+
+	for i, test := range tests {
+		output, err := c.Call(test.method, test.args...)
+		if test.err != "" {
+			if err == nil || err.Error() != test.err {
+				t.Errorf("test[%d] - Call() returns wrong error. expected=%s, got=%v", i, test.err, err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("test[%d] - Call() returns error: %v", i, err)
+		}
+
+		if output != test.output {
+			t.Errorf("test[%d] - Call() returns wrong output. expected=%v, got=%v", i, test.output, output)
+		}
+	}
+}
+
+func TestBind_overload(t *testing.T) {
+	_, a, err := koa.Compile(`
+contract Token {
+	func transfer(to int) {
+	}
+
+	func transfer(to string) {
+	}
+
+	func transfer(to int, amount int) {
+	}
+}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src, err := bind.Bind(a, "token", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, method := range []string{
+		"func (c *Token) Transfer(to int64) error",
+		"func (c *Token) Transfer0(to string) error",
+		"func (c *Token) Transfer1(to int64, amount int64) error",
+	} {
+		if !strings.Contains(src, method) {
+			t.Errorf("binding has no method %s. got=\n%s", method, src)
+		}
+	}
+}
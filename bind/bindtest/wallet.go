@@ -0,0 +1,122 @@
+// Code generated by koa bind. DO NOT EDIT.
+
+package bindtest
+
+import (
+	"github.com/DE-labtory/koa/abi"
+	"github.com/DE-labtory/koa/bind"
+)
+
+// WalletABI is the ABI of contract Wallet.
+var WalletABI = abi.ABI{
+	Name: "Wallet",
+	Methods: []abi.Method{
+		{
+			Name: "deposit",
+			Arguments: abi.Arguments{
+				{Name: "amount", Type: abi.Type{Type: "int"}},
+			},
+			Output: abi.Argument{Type: abi.Type{Type: "bool"}},
+		},
+		{
+			Name:      "balance",
+			Arguments: abi.Arguments{},
+			Output:    abi.Argument{Type: abi.Type{Type: "int"}},
+		},
+		{
+			Name:      "close",
+			Arguments: abi.Arguments{},
+			Output:    abi.Argument{Type: abi.Type{Type: "int", Enum: &abi.Enum{Name: "State", Members: []string{"Open", "Closed"}}}},
+		},
+		{
+			Name:      "state",
+			Arguments: abi.Arguments{},
+			Output:    abi.Argument{Type: abi.Type{Type: "int", Enum: &abi.Enum{Name: "State", Members: []string{"Open", "Closed"}}}},
+		},
+		{
+			Name:      "owner",
+			Arguments: abi.Arguments{},
+			Output:    abi.Argument{Type: abi.Type{Type: "string"}},
+		},
+		{
+			Name: "is_owner",
+			Arguments: abi.Arguments{
+				{Name: "name", Type: abi.Type{Type: "string"}},
+			},
+			Output: abi.Argument{Type: abi.Type{Type: "bool"}},
+		},
+	},
+}
+
+// WalletBin is the bytecode of contract Wallet in hex.
+const WalletBin = "21000000000000001024210000000000000036253121000000005a74d15b141521000000000000003730312100000000b69ef8a8141521000000000000006e3031210000000043d726d6141521000000000000007230312100000000c19d93fb141521000000000000007a303121000000008da5cb5b14152100000000000000893031210000000026192553141521000000000000008c3033210000000000000000262100000000000000082100000000000000002321000000000000000236210000000000000000141521000000000000004a3034210000000000000008210000000000000000222100000000000000001215210000000000000057303421000000000000000121000000000000000136210000000000000008210000000000000000220137210000000000000008210000000000000000222100000000000000013821000000000000000127210000000000000001362721000000000000000221000000000000000137210000000000000001272100000000000000023621000000000000000114210000000000000086302100000000000000012721000000000000000027216b6f61000000000027210000000000000000262100000000000000082100000000000000082321000000000000000821000000000000000822216b6f6100000000001427"
+
+// Members of enum State.
+const (
+	StateOpen   int64 = 0
+	StateClosed int64 = 1
+)
+
+// Wallet is the binding of contract Wallet.
+type Wallet struct {
+	contract *bind.Contract
+}
+
+// NewWallet creates the binding of contract Wallet which is called through backend.
+func NewWallet(backend bind.Backend) *Wallet {
+	return &Wallet{contract: bind.NewContract(WalletABI, backend)}
+}
+
+// Deposit calls deposit(int) of contract.
+func (c *Wallet) Deposit(amount int64) (bool, error) {
+	out, err := c.contract.Call(WalletABI.Methods[0], amount)
+	if err != nil {
+		return false, err
+	}
+	return out.(bool), nil
+}
+
+// Balance calls balance() of contract.
+func (c *Wallet) Balance() (int64, error) {
+	out, err := c.contract.Call(WalletABI.Methods[1])
+	if err != nil {
+		return 0, err
+	}
+	return out.(int64), nil
+}
+
+// Close calls close() of contract.
+func (c *Wallet) Close() (int64, error) {
+	out, err := c.contract.Call(WalletABI.Methods[2])
+	if err != nil {
+		return 0, err
+	}
+	return out.(int64), nil
+}
+
+// State calls state() of contract.
+func (c *Wallet) State() (int64, error) {
+	out, err := c.contract.Call(WalletABI.Methods[3])
+	if err != nil {
+		return 0, err
+	}
+	return out.(int64), nil
+}
+
+// Owner calls owner() of contract.
+func (c *Wallet) Owner() (string, error) {
+	out, err := c.contract.Call(WalletABI.Methods[4])
+	if err != nil {
+		return "", err
+	}
+	return out.(string), nil
+}
+
+// IsOwner calls is_owner(string) of contract.
+func (c *Wallet) IsOwner(name string) (bool, error) {
+	out, err := c.contract.Call(WalletABI.Methods[5], name)
+	if err != nil {
+		return false, err
+	}
+	return out.(bool), nil
+}
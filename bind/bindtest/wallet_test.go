@@ -0,0 +1,94 @@
+/*
+ * Copyright 2018-2019 De-labtory
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bindtest
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/DE-labtory/koa/bind"
+	"github.com/DE-labtory/koa/sim"
+	"github.com/DE-labtory/koa/vm"
+)
+
+func deployWallet(t *testing.T) *Wallet {
+	t.Helper()
+
+	code, err := hex.DecodeString(WalletBin)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chain := sim.New()
+	alice := chain.NewAccount()
+
+	r, err := chain.Deploy(alice, code)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewWallet(bind.SimBackend{Chain: chain, From: alice, To: r.ContractAddress})
+}
+
+func TestWallet(t *testing.T) {
+	w := deployWallet(t)
+
+	ok, err := w.Deposit(10)
+	if err != nil || !ok {
+		t.Fatalf("Deposit() returns %v, %v", ok, err)
+	}
+
+	if _, err := w.Deposit(-1); err != vm.ErrRevert {
+		t.Errorf("Deposit() with negative amount should return %v, got=%v", vm.ErrRevert, err)
+	}
+
+	balance, err := w.Balance()
+	if err != nil || balance != 10 {
+		t.Errorf("Balance() returns %d, %v. expected=10", balance, err)
+	}
+
+	state, err := w.State()
+	if err != nil || state != StateOpen {
+		t.Errorf("State() returns %d, %v. expected=%d", state, err, StateOpen)
+	}
+
+	state, err = w.Close()
+	if err != nil || state != StateClosed {
+		t.Errorf("Close() returns %d, %v. expected=%d", state, err, StateClosed)
+	}
+
+	state, err = w.State()
+	if err != nil || state != StateClosed {
+		t.Errorf("State() returns %d, %v. expected=%d", state, err, StateClosed)
+	}
+
+	if _, err := w.Deposit(10); err != vm.ErrRevert {
+		t.Errorf("Deposit() to closed wallet should return %v, got=%v", vm.ErrRevert, err)
+	}
+
+	owner, err := w.Owner()
+	if err != nil || owner != "koa" {
+		t.Errorf("Owner() returns %s, %v. expected=koa", owner, err)
+	}
+
+	for name, expected := range map[string]bool{"koa": true, "alice": false} {
+		isOwner, err := w.IsOwner(name)
+		if err != nil || isOwner != expected {
+			t.Errorf("IsOwner(%s) returns %v, %v. expected=%v", name, isOwner, err, expected)
+		}
+	}
+}
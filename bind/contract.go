@@ -0,0 +1,98 @@
+/*
+ * Copyright 2018-2019 De-labtory
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bind
+
+import (
+	"fmt"
+
+	"github.com/DE-labtory/koa"
+	"github.com/DE-labtory/koa/abi"
+	"github.com/DE-labtory/koa/sim"
+)
+
+// Backend executes the function of contract, function is the
+// function selector and args is the arguments encoded with abi.
+type Backend interface {
+	Call(function []byte, args []byte) ([]byte, error)
+}
+
+// CodeBackend executes the bytecode of contract with the VM.
+// The contract can't use storage, because there is no host.
+type CodeBackend struct {
+	Code []byte
+}
+
+func (b CodeBackend) Call(function []byte, args []byte) ([]byte, error) {
+	return koa.Execute(b.Code, function, args)
+}
+
+// SimBackend sends transactions from an account to the contract
+// in the simulator. The failure of transaction is returned as error.
+type SimBackend struct {
+	Chain *sim.Chain
+	From  sim.Address
+	To    sim.Address
+}
+
+func (b SimBackend) Call(function []byte, args []byte) ([]byte, error) {
+	r, err := b.Chain.Send(sim.Transaction{
+		From: b.From,
+		To:   b.To,
+		Func: function,
+		Args: args,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Output, r.Err
+}
+
+// Contract calls the methods of contract through backend. It is
+// used by the generated bindings.
+type Contract struct {
+	ABI     abi.ABI
+	Backend Backend
+}
+
+func NewContract(a abi.ABI, backend Backend) *Contract {
+	return &Contract{
+		ABI:     a,
+		Backend: backend,
+	}
+}
+
+// Call calls the method with arguments, and returns the output decoded
+// by the type of method output. Output of void method is nil.
+func (c *Contract) Call(method abi.Method, args ...interface{}) (interface{}, error) {
+	if len(args) != len(method.Arguments) {
+		return nil, fmt.Errorf("method %s needs %d arguments, but got %d",
+			method.Signature(), len(method.Arguments), len(args))
+	}
+
+	encoded, err := abi.Encode(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := c.Backend.Call(method.ID(), encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	return method.DecodeOutput(output)
+}
@@ -0,0 +1,115 @@
+package bind
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/DE-labtory/koa"
+	"github.com/DE-labtory/koa/abi"
+	binding "github.com/DE-labtory/koa/bind"
+	"github.com/DE-labtory/koa/cmd/compile"
+	"github.com/urfave/cli"
+)
+
+var bindCmd = cli.Command{
+	Name:  "bind",
+	Usage: "koa bind [--pkg name] [--type name] [--out file] [file.koa | compile result]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "pkg",
+			Usage: "package name of the binding, default is the lower case of type name",
+		},
+		cli.StringFlag{
+			Name:  "type",
+			Usage: "type name of the binding, default is the name of contract",
+		},
+		cli.StringFlag{
+			Name:  "contract, n",
+			Usage: "name of the contract to bind, if file has several contracts",
+		},
+		cli.StringFlag{
+			Name:  "out, o",
+			Usage: "file to write the binding, default is stdout",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		if c.Args().Get(0) == "" {
+			return errors.New("you must input koa file or compile result")
+		}
+
+		return bind(c.Args().Get(0), c.String("contract"), c.String("pkg"), c.String("type"), c.String("out"))
+	},
+}
+
+func Cmd() cli.Command {
+	return bindCmd
+}
+
+// bind generates the binding of contract in path, which is a koa
+// source file or the compile result of contract.
+func bind(path string, contract string, pkg string, typeName string, out string) error {
+	a, code, err := load(path, contract)
+	if err != nil {
+		return err
+	}
+
+	if typeName == "" && a.Name == "" {
+		return errors.New("contract has no name, type name of the binding is required")
+	}
+
+	if pkg == "" && typeName != "" {
+		pkg = strings.ToLower(typeName)
+	} else if pkg == "" {
+		pkg = strings.ToLower(a.Name)
+	}
+
+	src, err := binding.Bind(a, pkg, typeName, code)
+	if err != nil {
+		return err
+	}
+
+	if out == "" {
+		fmt.Print(src)
+		return nil
+	}
+
+	return ioutil.WriteFile(out, []byte(src), 0644)
+}
+
+// load returns the ABI and bytecode of contract in path.
+func load(path string, contract string) (abi.ABI, []byte, error) {
+	if filepath.Ext(path) == ".koa" {
+		asm, a, err := koa.CompileFile(path, contract)
+		if err != nil {
+			return abi.ABI{}, nil, err
+		}
+
+		return a, asm.ToRawByteCode(), nil
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return abi.ABI{}, nil, err
+	}
+
+	var r compile.Result
+	if err := json.Unmarshal(b, &r); err != nil {
+		return abi.ABI{}, nil, fmt.Errorf("%s: %s", path, err)
+	}
+
+	if r.Abi == nil {
+		return abi.ABI{}, nil, fmt.Errorf("%s: no abi in compile result", path)
+	}
+
+	code, err := hex.DecodeString(r.RawByte)
+	if err != nil {
+		return abi.ABI{}, nil, fmt.Errorf("%s: %s", path, err)
+	}
+
+	return *r.Abi, code, nil
+}
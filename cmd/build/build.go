@@ -0,0 +1,113 @@
+package build
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/DE-labtory/koa"
+	"github.com/DE-labtory/koa/ast"
+	"github.com/DE-labtory/koa/cmd/compile"
+	parser "github.com/DE-labtory/koa/parse"
+	"github.com/urfave/cli"
+)
+
+var buildCmd = cli.Command{
+	Name:    "build",
+	Aliases: []string{"b"},
+	Usage:   "koa build [project directory]",
+	Action: func(c *cli.Context) error {
+		dir := c.Args().Get(0)
+		if dir == "" {
+			dir = "."
+		}
+		return build(dir)
+	},
+}
+
+func Cmd() cli.Command {
+	return buildCmd
+}
+
+// build compiles every contract in the project directory, and prints the
+// results by file name and contract name. Files which don't declare contract
+// are libraries, they are compiled as a part of the contracts importing them.
+func build(dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.koa"))
+	if err != nil {
+		return err
+	}
+
+	results := make(map[string]compile.Result)
+	for _, file := range files {
+		src, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		if !declaresContract(string(src)) {
+			continue
+		}
+
+		f, err := parser.ParseFile(file)
+		if err != nil {
+			return err
+		}
+
+		for _, contract := range f.Contracts {
+			asm, ab, err := koa.CompileContract(contract)
+			if err != nil {
+				return fmt.Errorf("%s: %s", file, err)
+			}
+
+			r, err := compile.NewResult(asm, &ab)
+			if err != nil {
+				return fmt.Errorf("%s: %s", file, err)
+			}
+
+			results[resultName(file, contract)] = r
+		}
+	}
+
+	if len(results) == 0 {
+		return fmt.Errorf("there is no contract in %s", dir)
+	}
+
+	b, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(b))
+	return nil
+}
+
+// resultName returns the name of compile result. It is the file name,
+// followed by contract name if contract has a name. e.g. escrow.koa:Escrow
+func resultName(file string, contract *ast.Contract) string {
+	if contract.Name == nil {
+		return filepath.Base(file)
+	}
+
+	return filepath.Base(file) + ":" + contract.Name.String()
+}
+
+// declaresContract reports whether source has "contract" keyword.
+// If source has illegal token, it is regarded as contract to report the error.
+func declaresContract(src string) bool {
+	l := parser.NewLexer(src)
+
+	found := false
+	for t := l.NextToken(); t.Type != parser.Eof; t = l.NextToken() {
+		if t.Type == parser.Illegal {
+			return true
+		}
+
+		if t.Type == parser.Contract {
+			found = true
+		}
+	}
+
+	return found
+}
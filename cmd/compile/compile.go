@@ -3,27 +3,36 @@ package compile
 import (
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
 
+	"github.com/DE-labtory/koa"
 	"github.com/DE-labtory/koa/abi"
-
-	parser "github.com/DE-labtory/koa/parse"
 	"github.com/DE-labtory/koa/translate"
 	"github.com/urfave/cli"
 )
 
+// Result is the output of compile, Abi can be read by abi.New.
+// Container is the bytecode packed with ABI, which can be deployed.
+// SourceMap maps the pc of bytecode to the position in source.
 type Result struct {
-	Abi     *abi.ABI
-	Asm     string
-	RawByte string
+	Abi       *abi.ABI            `json:"abi"`
+	Asm       string              `json:"asm"`
+	RawByte   string              `json:"rawByte"`
+	Container string              `json:"container"`
+	SourceMap translate.SourceMap `json:"sourceMap"`
 }
 
 var compileCmd = cli.Command{
 	Name:    "compile",
 	Aliases: []string{"c"},
 	Usage:   "koa compile [filepath]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "contract, n",
+			Usage: "name of the contract to compile, if file has several contracts",
+		},
+	},
 	Action: func(c *cli.Context) error {
-		return compile(c.Args().Get(0))
+		return compile(c.Args().Get(0), c.String("contract"))
 	},
 }
 
@@ -31,49 +40,45 @@ func Cmd() cli.Command {
 	return compileCmd
 }
 
-func compile(path string) error {
-	file, err := ioutil.ReadFile(path)
+func compile(path string, name string) error {
+	asm, ab, err := koa.CompileFile(path, name)
 	if err != nil {
 		return err
 	}
 
-	contract, err := parser.Parse(
-		parser.NewTokenBuffer(
-			parser.NewLexer(string(file))))
-
-	if err != nil {
+	if err := PrintCompileResult(asm, &ab); err != nil {
 		return err
 	}
 
-	asm, err := translate.CompileContract(*contract)
-	if err != nil {
-		return err
-	}
+	return nil
+}
 
-	ab, err := translate.ExtractAbi(*contract)
+func PrintCompileResult(asm translate.Asm, ab *abi.ABI) error {
+	r, err := NewResult(asm, ab)
 	if err != nil {
 		return err
 	}
 
-	if err := PrintCompileResult(asm, ab); err != nil {
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
 		return err
 	}
 
+	fmt.Println(string(b))
 	return nil
 }
 
-func PrintCompileResult(asm translate.Asm, ab *abi.ABI) error {
-	result := Result{
-		Abi:     ab,
-		Asm:     asm.String(),
-		RawByte: fmt.Sprintf("%x", asm.ToRawByteCode()),
-	}
-
-	b, err := json.MarshalIndent(result, "", "  ")
+func NewResult(asm translate.Asm, ab *abi.ABI) (Result, error) {
+	packed, err := koa.Pack(asm, *ab)
 	if err != nil {
-		return err
+		return Result{}, err
 	}
 
-	fmt.Println(string(b))
-	return nil
+	return Result{
+		Abi:       ab,
+		Asm:       asm.String(),
+		RawByte:   fmt.Sprintf("%x", asm.ToRawByteCode()),
+		Container: fmt.Sprintf("%x", packed),
+		SourceMap: asm.SourceMap(),
+	}, nil
 }
@@ -2,27 +2,57 @@ package execute
 
 import (
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"strconv"
 
 	"github.com/DE-labtory/koa"
 	"github.com/DE-labtory/koa/abi"
+	"github.com/DE-labtory/koa/cmd/compile"
+	"github.com/DE-labtory/koa/container"
+	"github.com/DE-labtory/koa/sim"
 	"github.com/urfave/cli"
 )
 
 var executeCmd = cli.Command{
 	Name:    "execute",
 	Aliases: []string{"e"},
-	Usage:   "koa execute [raw byte code] [function name] [args...]",
+	Usage:   "koa execute [--abi file | --src file.koa] [raw byte code | container] [method] [args...]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "abi",
+			Usage: "ABI or compile result of contract, arguments are parsed by the types of method",
+		},
+		cli.StringFlag{
+			Name:  "src",
+			Usage: "koa file to compile and execute, raw byte code is omitted",
+		},
+		cli.StringFlag{
+			Name:  "contract, n",
+			Usage: "name of the contract in src, if file has several contracts",
+		},
+	},
 	Action: func(c *cli.Context) error {
-		if len(c.Args()) < 2 {
+		args := []string(c.Args())
+
+		if path := c.String("src"); path != "" {
+			if len(args) < 1 {
+				return errors.New("you must input method")
+			}
+			return executeSource(path, c.String("contract"), args[0], args[1:])
+		}
+
+		if len(args) < 2 {
 			return errors.New("you must input at least byte code and function name")
 		}
-		if len(c.Args()) == 2 {
-			return execute(c.Args().Get(0), c.Args().Get(1), nil)
+
+		if path := c.String("abi"); path != "" {
+			return executeWithABI(path, args[0], args[1], args[2:])
 		}
-		return execute(c.Args().Get(0), c.Args().Get(1), c.Args()[2:])
+
+		return execute(args[0], args[1], args[2:])
 	},
 }
 
@@ -31,27 +61,149 @@ func Cmd() cli.Command {
 }
 
 func execute(rawByteCode string, functionName string, args []string) error {
+	contractDecoding, err := hex.DecodeString(rawByteCode)
+	if err != nil {
+		return err
+	}
+
+	// Container has the ABI of contract.
+	if container.IsContainer(contractDecoding) {
+		c, err := container.Unpack(contractDecoding)
+		if err != nil {
+			return err
+		}
+
+		if c.ABI != nil {
+			return executeMethod(*c.ABI, contractDecoding, functionName, args)
+		}
+	}
+
 	fnSel := abi.Selector(functionName)
 	params, err := encodeParams(args)
 	if err != nil {
 		return err
 	}
 
-	contractDecoding, err := hex.DecodeString(rawByteCode)
+	result, err := send(contractDecoding, fnSel, params)
 	if err != nil {
 		return err
 	}
 
-	result, err := koa.Execute(contractDecoding, fnSel, params)
+	printExecuteResult(result)
+
+	return nil
+}
+
+// executeWithABI executes the method of contract, and arguments and
+// result are converted by the types of method in ABI.
+func executeWithABI(path string, rawByteCode string, name string, args []string) error {
+	a, err := readABI(path)
 	if err != nil {
 		return err
 	}
 
-	printExecuteResult(result)
+	code, err := hex.DecodeString(rawByteCode)
+	if err != nil {
+		return err
+	}
+
+	return executeMethod(a, code, name, args)
+}
+
+// executeSource compiles the contract in koa file and executes its method.
+func executeSource(path string, contract string, name string, args []string) error {
+	asm, a, err := koa.CompileFile(path, contract)
+	if err != nil {
+		return err
+	}
+
+	return executeMethod(a, asm.ToRawByteCode(), name, args)
+}
+
+func executeMethod(a abi.ABI, code []byte, name string, args []string) error {
+	method, err := a.FindMethod(name, len(args))
+	if err != nil {
+		return err
+	}
+
+	values, err := method.ParseArguments(args)
+	if err != nil {
+		return err
+	}
+
+	params, err := abi.Encode(values...)
+	if err != nil {
+		return err
+	}
+
+	result, err := send(code, method.ID(), params)
+	if err != nil {
+		return err
+	}
+
+	output, err := method.FormatOutput(result)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("execute Result: %s\n", output)
 
 	return nil
 }
 
+// send deploys the contract to the simulator and sends the transaction
+// calling the function, so that the contract can use storage and emit
+// logs. The logs are printed, and the output is returned.
+func send(code []byte, function []byte, args []byte) ([]byte, error) {
+	chain := sim.New()
+	account := chain.NewAccount()
+
+	deployed, err := chain.Deploy(account, code)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := chain.Send(sim.Transaction{
+		From: account,
+		To:   deployed.ContractAddress,
+		Func: function,
+		Args: args,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, l := range r.Logs {
+		fmt.Printf("log: %v\n", l.Data)
+	}
+
+	if r.Failed() {
+		return nil, r.Err
+	}
+
+	return r.Output, nil
+}
+
+// readABI reads ABI in file, which is the ABI or the compile result.
+func readABI(path string) (abi.ABI, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return abi.ABI{}, err
+	}
+
+	var r compile.Result
+	if err := json.Unmarshal(b, &r); err == nil && r.Abi != nil {
+		return *r.Abi, nil
+	}
+
+	a, err := abi.New(string(b))
+	if err != nil {
+		return abi.ABI{}, fmt.Errorf("%s: %s", path, err)
+	}
+
+	return a, nil
+}
+
 func encodeParams(params []string) ([]byte, error) {
 	ps := make([]interface{}, len(params))
 	for idx, oneParam := range params {
@@ -62,11 +214,9 @@ func encodeParams(params []string) ([]byte, error) {
 		}
 
 		// check param is bool
-		if oneParam == "true" {
-			ps[idx] = true
-		}
-		if oneParam == "false" {
-			ps[idx] = false
+		if oneParam == "true" || oneParam == "false" {
+			ps[idx] = oneParam == "true"
+			continue
 		}
 
 		// otherwise string
@@ -80,6 +230,16 @@ func encodeParams(params []string) ([]byte, error) {
 	return result, nil
 }
 
+// printExecuteResult prints the result as int, because the type
+// of result is unknown without ABI.
 func printExecuteResult(result []byte) {
-	fmt.Printf("execute Result: %s\n", string(result))
+	method := abi.Method{Output: abi.Argument{Type: abi.Type{Type: abi.Integer}}}
+
+	value, err := method.DecodeOutput(result)
+	if err != nil {
+		fmt.Printf("execute Result: 0x%x\n", result)
+		return
+	}
+
+	fmt.Printf("execute Result: %d (0x%x)\n", value, result)
 }
@@ -0,0 +1,84 @@
+package execute
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/DE-labtory/koa"
+	capturer "github.com/kami-zh/go-capturer"
+)
+
+func TestExecuteSource(t *testing.T) {
+	tests := []struct {
+		path        string
+		method      string
+		args        []string
+		expected    string
+		expectedErr string
+	}{
+		{
+			path:     "../../test/test_execute.koa",
+			method:   "addArgs",
+			args:     []string{"1", "2"},
+			expected: "execute Result: 3\n",
+		},
+		{
+			path:     "../../test/counter.koa",
+			method:   "increase",
+			args:     []string{"5"},
+			expected: "log: [1 5]\nexecute Result: 5\n",
+		},
+		{
+			path:        "../../test/counter.koa",
+			method:      "decrease",
+			args:        []string{"5"},
+			expectedErr: "there is no method decrease",
+		},
+	}
+
+	for i, test := range tests {
+		var err error
+		out := capturer.CaptureStdout(func() {
+			err = executeSource(test.path, "", test.method, test.args)
+		})
+
+		if test.expectedErr != "" {
+			if err == nil || err.Error() != test.expectedErr {
+				t.Errorf("test[%d] - Invalid error - expected=%s, got=%v", i, test.expectedErr, err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("test[%d] - unexpected error: %s", i, err)
+		}
+
+		if out != test.expected {
+			t.Errorf("test[%d] - wrong output - expected=%q, got=%q", i, test.expected, out)
+		}
+	}
+}
+
+func TestExecute_container(t *testing.T) {
+	asm, a, err := koa.CompileFile("../../test/counter.koa", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	packed, err := koa.Pack(asm, a)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := capturer.CaptureStdout(func() {
+		err = execute(hex.EncodeToString(packed), "increase", []string{"7"})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := "log: [1 7]\nexecute Result: 7\n"
+	if out != expected {
+		t.Errorf("wrong output - expected=%q, got=%q", expected, out)
+	}
+}
@@ -0,0 +1,68 @@
+package format
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+
+	formatter "github.com/DE-labtory/koa/format"
+	"github.com/urfave/cli"
+)
+
+var formatCmd = cli.Command{
+	Name:  "fmt",
+	Usage: "koa fmt [-w] [file.koa...]",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "w",
+			Usage: "write result to the file instead of printing it",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		if c.NArg() == 0 {
+			return errors.New("you must input koa file")
+		}
+
+		for _, path := range c.Args() {
+			if err := format(path, c.Bool("w")); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+func Cmd() cli.Command {
+	return formatCmd
+}
+
+// format formats the koa file in path. The result is printed, or
+// written to the file if write is true and the file is changed.
+func format(path string, write bool) error {
+	out, err := formatter.File(path)
+	if err != nil {
+		return err
+	}
+
+	if !write {
+		_, err := os.Stdout.Write(out)
+		return err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if bytes.Equal(src, out) {
+		return nil
+	}
+
+	return ioutil.WriteFile(path, out, info.Mode().Perm())
+}
@@ -22,12 +22,18 @@ import (
 	"os"
 	"time"
 
+	"github.com/DE-labtory/koa/cmd/bind"
+	"github.com/DE-labtory/koa/cmd/build"
 	"github.com/DE-labtory/koa/cmd/compile"
 
 	"github.com/DE-labtory/koa/cmd/execute"
+	"github.com/DE-labtory/koa/cmd/format"
 	"github.com/DE-labtory/koa/cmd/lex"
+	"github.com/DE-labtory/koa/cmd/lsp"
 	"github.com/DE-labtory/koa/cmd/parse"
 	"github.com/DE-labtory/koa/cmd/repl"
+	"github.com/DE-labtory/koa/cmd/run"
+	parser "github.com/DE-labtory/koa/parse"
 	"github.com/fatih/color"
 	"github.com/urfave/cli"
 )
@@ -85,13 +91,30 @@ func main() {
 	app.Commands = append(app.Commands, parse.Cmd())
 	app.Commands = append(app.Commands, compile.Cmd())
 	app.Commands = append(app.Commands, execute.Cmd())
+	app.Commands = append(app.Commands, build.Cmd())
+	app.Commands = append(app.Commands, bind.Cmd())
+	app.Commands = append(app.Commands, run.Cmd())
+	app.Commands = append(app.Commands, format.Cmd())
+	app.Commands = append(app.Commands, lsp.Cmd())
 
 	app.Action = func(c *cli.Context) error {
 		repl.Run()
 		return nil
 	}
 	err := app.Run(os.Args)
+	if list, ok := err.(parser.ErrorList); ok {
+		printErrorList(list)
+		os.Exit(1)
+	}
 	if err != nil {
 		log.Fatal(err)
 	}
 }
+
+// printErrorList prints the errors in source, sorted by position.
+func printErrorList(list parser.ErrorList) {
+	list.Sort()
+	for _, err := range list {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
@@ -0,0 +1,20 @@
+package lsp
+
+import (
+	"os"
+
+	"github.com/DE-labtory/koa/lsp"
+	"github.com/urfave/cli"
+)
+
+var lspCmd = cli.Command{
+	Name:  "lsp",
+	Usage: "koa lsp",
+	Action: func(c *cli.Context) error {
+		return lsp.NewServer(os.Stdin, os.Stdout).Serve()
+	},
+}
+
+func Cmd() cli.Command {
+	return lspCmd
+}
@@ -17,8 +17,6 @@
 package parse
 
 import (
-	"io/ioutil"
-
 	"fmt"
 
 	"github.com/DE-labtory/koa/ast"
@@ -48,19 +46,14 @@ func Cmd() cli.Command {
 }
 
 func parse(path string) error {
-	file, err := ioutil.ReadFile(path)
+	file, err := parser.ParseFile(path)
 	if err != nil {
 		return err
 	}
 
-	l := parser.NewLexer(string(file))
-	buf := parser.NewTokenBuffer(l)
-	contract, err := parser.Parse(buf)
-	if err != nil {
-		return err
+	for _, contract := range file.Contracts {
+		fmt.Println(PrintContract(contract))
 	}
-
-	fmt.Println(PrintContract(contract))
 	return nil
 }
 
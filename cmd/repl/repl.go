@@ -79,11 +79,12 @@ func run(in io.Reader, out io.Writer) {
 			return
 		}
 
-		l := parse.NewLexer(line)
-		l2 := parse.NewLexer(line)
-
-		buf := parse.NewTokenBuffer(l)
+		buf := parse.NewTokenBuffer(parse.NewLexer(line))
 		contract, err := parse.Parse(buf)
+		if err != nil {
+			color.Red(err.Error())
+			continue
+		}
 
 		asm, err := translate.CompileContract(*contract)
 		if err != nil {
@@ -102,7 +103,7 @@ func run(in io.Reader, out io.Writer) {
 		}
 
 		bold.Println("-->>   LEX RESULT   <<-----------------------------------------------")
-		lex_cmd.PrintTokens(l2)
+		lex_cmd.PrintTokens(parse.NewLexer(line))
 		fmt.Println()
 
 		bold.Println("-->>  PARSE RESULT  <<-----------------------------------------------")
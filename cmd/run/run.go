@@ -0,0 +1,192 @@
+package run
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/DE-labtory/koa"
+	"github.com/DE-labtory/koa/abi"
+	"github.com/DE-labtory/koa/sim"
+	"github.com/DE-labtory/koa/translate"
+	"github.com/DE-labtory/koa/vm"
+	"github.com/urfave/cli"
+)
+
+var runCmd = cli.Command{
+	Name:    "run",
+	Aliases: []string{"r"},
+	Usage:   "koa run [--trace] [--gas] [--gas-limit n] [file.koa] [method] [args...]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "contract, n",
+			Usage: "name of the contract to run, if file has several contracts",
+		},
+		cli.BoolFlag{
+			Name:  "trace, t",
+			Usage: "print each executed instruction with the stack",
+		},
+		cli.BoolFlag{
+			Name:  "gas, g",
+			Usage: "print the gas used by the method",
+		},
+		cli.Uint64Flag{
+			Name:  "gas-limit",
+			Usage: "maximum gas to use, zero means no limit",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		args := []string(c.Args())
+		if len(args) < 2 {
+			return errors.New("you must input koa file and method")
+		}
+
+		opts := options{
+			contract: c.String("contract"),
+			trace:    c.Bool("trace"),
+			gas:      c.Bool("gas"),
+			gasLimit: c.Uint64("gas-limit"),
+		}
+
+		return run(args[0], args[1], args[2:], opts)
+	},
+}
+
+func Cmd() cli.Command {
+	return runCmd
+}
+
+type options struct {
+	contract string
+	trace    bool
+	gas      bool
+	gasLimit uint64
+}
+
+// run compiles the contract in koa file, deploys it to the simulator
+// and sends the transaction calling method with args. If the transaction
+// fails, the error is reported with the position in source where the
+// contract stopped.
+func run(path string, name string, args []string, opts options) error {
+	asm, a, err := koa.CompileFile(path, opts.contract)
+	if err != nil {
+		return err
+	}
+
+	method, err := a.FindMethod(name, len(args))
+	if err != nil {
+		return err
+	}
+
+	values, err := method.ParseArguments(args)
+	if err != nil {
+		return err
+	}
+
+	params, err := abi.Encode(values...)
+	if err != nil {
+		return err
+	}
+
+	chain := sim.New()
+	account := chain.NewAccount()
+
+	deployed, err := chain.Deploy(account, asm.ToRawByteCode())
+	if err != nil {
+		return err
+	}
+
+	tx := sim.Transaction{
+		From:     account,
+		To:       deployed.ContractAddress,
+		Func:     method.ID(),
+		Args:     params,
+		GasLimit: opts.gasLimit,
+	}
+
+	t := &tracer{sourceMap: asm.SourceMap()}
+	if opts.trace {
+		t.w = os.Stdout
+	}
+	tx.Tracer = t
+
+	r, err := chain.Send(tx)
+	if err != nil {
+		return err
+	}
+
+	for _, l := range r.Logs {
+		fmt.Printf("log: %v\n", l.Data)
+	}
+
+	if opts.gas {
+		fmt.Printf("gas used: %d\n", r.GasUsed)
+	}
+
+	if r.Failed() {
+		if pos := t.position(); pos != "" {
+			return fmt.Errorf("%s: %s", pos, r.Err)
+		}
+		return r.Err
+	}
+
+	output, err := method.FormatOutput(r.Output)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("result: %s\n", output)
+
+	return nil
+}
+
+// tracer prints each step of execution in a line, which is indented
+// by the depth of call, if w is set. The steps of contract, of which
+// depth is zero, are annotated with the position in source.
+//
+// tracer remembers the last step of contract to find where it stopped.
+type tracer struct {
+	w         io.Writer
+	sourceMap translate.SourceMap
+	pc        int
+	stepped   bool
+}
+
+func (t *tracer) Step(step vm.Step) {
+	if step.Depth == 0 {
+		t.pc = int(step.PC)
+		t.stepped = true
+	}
+
+	if t.w == nil {
+		return
+	}
+
+	operand := ""
+	if step.Operand != nil {
+		operand = fmt.Sprintf("%x", step.Operand)
+	}
+
+	op, err := step.Op.String()
+	if err != nil {
+		op = fmt.Sprintf("0x%02x", uint8(step.Op))
+	}
+
+	pos := ""
+	if p := t.sourceMap.Position(int(step.PC)); step.Depth == 0 && p != "" {
+		pos = " " + p
+	}
+
+	fmt.Fprintf(t.w, "%*s%4d %-10s %-16s gas=%-4d stack=%v%s\n",
+		step.Depth*2, "", step.PC, op, operand, step.GasUsed, step.Stack, pos)
+}
+
+// position returns the position in source of the last step of contract.
+func (t *tracer) position() string {
+	if !t.stepped {
+		return ""
+	}
+
+	return t.sourceMap.Position(t.pc)
+}
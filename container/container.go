@@ -0,0 +1,203 @@
+/*
+ * Copyright 2018-2019 De-labtory
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package container
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+
+	"github.com/DE-labtory/koa/abi"
+	"github.com/DE-labtory/koa/opcode"
+)
+
+var ErrNotContainer = errors.New("not a container")
+var ErrUnsupportedFormat = errors.New("unsupported container format")
+var ErrIncompatibleOpcode = errors.New("incompatible opcode version")
+var ErrChecksum = errors.New("checksum of container mismatched")
+var ErrNoCode = errors.New("container has no code")
+
+// Magic starts every container. 0x00 isn't an opcode, so the
+// container can't be mistaken for a bare bytecode.
+var Magic = []byte{0x00, 'k', 'o', 'a'}
+
+// FormatVersion is the version of container format written by Pack.
+const FormatVersion uint8 = 1
+
+// Kinds of section
+const (
+	CodeSection     uint8 = 0x01
+	ABISection      uint8 = 0x02
+	MetadataSection uint8 = 0x03
+	DebugSection    uint8 = 0x04
+)
+
+// headerSize is the size of magic, format version and opcode version.
+const headerSize = 6
+
+// checksumSize is the size of CRC-32 checksum at the end.
+const checksumSize = 4
+
+// Container is the deployed artifact of contract. It is encoded as
+//
+//	magic (4 bytes) | format version (1) | opcode version (1) |
+//	sections ... | checksum (4)
+//
+// and each section is encoded as kind (1) | length (4) | data. Code
+// section is required, and ABI, metadata and debug sections are
+// written only if they are set. ABI and metadata are in JSON.
+// Checksum is CRC-32 (IEEE) of all the bytes before it.
+type Container struct {
+	Code     []byte
+	ABI      *abi.ABI
+	Metadata map[string]string
+	Debug    []byte
+}
+
+// IsContainer reports whether b starts with Magic.
+func IsContainer(b []byte) bool {
+	return bytes.HasPrefix(b, Magic)
+}
+
+// Pack encodes container with the current format and opcode version.
+func Pack(c Container) ([]byte, error) {
+	if len(c.Code) == 0 {
+		return nil, ErrNoCode
+	}
+
+	buf := &bytes.Buffer{}
+	buf.Write(Magic)
+	buf.WriteByte(FormatVersion)
+	buf.WriteByte(opcode.Version)
+
+	writeSection(buf, CodeSection, c.Code)
+
+	if c.ABI != nil {
+		b, err := json.Marshal(c.ABI)
+		if err != nil {
+			return nil, err
+		}
+		writeSection(buf, ABISection, b)
+	}
+
+	if c.Metadata != nil {
+		b, err := json.Marshal(c.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		writeSection(buf, MetadataSection, b)
+	}
+
+	if c.Debug != nil {
+		writeSection(buf, DebugSection, c.Debug)
+	}
+
+	checksum := make([]byte, checksumSize)
+	binary.BigEndian.PutUint32(checksum, crc32.ChecksumIEEE(buf.Bytes()))
+	buf.Write(checksum)
+
+	return buf.Bytes(), nil
+}
+
+func writeSection(buf *bytes.Buffer, kind uint8, data []byte) {
+	length := make([]byte, 4)
+	binary.BigEndian.PutUint32(length, uint32(len(data)))
+
+	buf.WriteByte(kind)
+	buf.Write(length)
+	buf.Write(data)
+}
+
+// Unpack decodes container. It fails if the format or opcode version
+// isn't the one of this package, or the checksum is mismatched.
+func Unpack(b []byte) (Container, error) {
+	if !IsContainer(b) || len(b) < headerSize+checksumSize {
+		return Container{}, ErrNotContainer
+	}
+
+	if b[4] != FormatVersion {
+		return Container{}, ErrUnsupportedFormat
+	}
+
+	if b[5] != opcode.Version {
+		return Container{}, ErrIncompatibleOpcode
+	}
+
+	body := b[:len(b)-checksumSize]
+	if crc32.ChecksumIEEE(body) != binary.BigEndian.Uint32(b[len(body):]) {
+		return Container{}, ErrChecksum
+	}
+
+	c := Container{}
+	found := make(map[uint8]bool)
+
+	for rest := body[headerSize:]; len(rest) > 0; {
+		if len(rest) < 5 {
+			return Container{}, errors.New("truncated section header")
+		}
+
+		kind := rest[0]
+		length := binary.BigEndian.Uint32(rest[1:5])
+		if uint64(len(rest)-5) < uint64(length) {
+			return Container{}, fmt.Errorf("section %d is truncated", kind)
+		}
+
+		if found[kind] {
+			return Container{}, fmt.Errorf("section %d appears more than once", kind)
+		}
+		found[kind] = true
+
+		if err := c.setSection(kind, rest[5:5+length]); err != nil {
+			return Container{}, err
+		}
+
+		rest = rest[5+length:]
+	}
+
+	if len(c.Code) == 0 {
+		return Container{}, ErrNoCode
+	}
+
+	return c, nil
+}
+
+// setSection decodes data of section to the field of container.
+func (c *Container) setSection(kind uint8, data []byte) error {
+	switch kind {
+	case CodeSection:
+		c.Code = append([]byte{}, data...)
+	case ABISection:
+		a, err := abi.New(string(data))
+		if err != nil {
+			return fmt.Errorf("invalid ABI section: %s", err)
+		}
+		c.ABI = &a
+	case MetadataSection:
+		if err := json.Unmarshal(data, &c.Metadata); err != nil {
+			return fmt.Errorf("invalid metadata section: %s", err)
+		}
+	case DebugSection:
+		c.Debug = append([]byte{}, data...)
+	default:
+		return fmt.Errorf("unknown section %d", kind)
+	}
+
+	return nil
+}
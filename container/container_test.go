@@ -0,0 +1,173 @@
+/*
+ * Copyright 2018-2019 De-labtory
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package container_test
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"reflect"
+	"testing"
+
+	"github.com/DE-labtory/koa/abi"
+	"github.com/DE-labtory/koa/container"
+)
+
+func TestPack(t *testing.T) {
+	a := &abi.ABI{
+		Name: "Wallet",
+		Methods: []abi.Method{
+			{
+				Name:      "balance",
+				Arguments: abi.Arguments{},
+				Output:    abi.Argument{Type: abi.Type{Type: abi.Integer}},
+			},
+		},
+	}
+
+	tests := []container.Container{
+		{
+			Code: []byte{0x21, 0, 0, 0, 0, 0, 0, 0, 1},
+		},
+		{
+			Code:     []byte{0x21, 0, 0, 0, 0, 0, 0, 0, 1},
+			ABI:      a,
+			Metadata: map[string]string{"compiler": "koa 0.0.1"},
+			Debug:    []byte{1, 2, 3},
+		},
+	}
+
+	for i, test := range tests {
+		b, err := container.Pack(test)
+		if err != nil {
+			t.Fatalf("test[%d] - Pack() returns error: %v", i, err)
+		}
+
+		if !container.IsContainer(b) {
+			t.Errorf("test[%d] - packed bytes should be container", i)
+		}
+
+		c, err := container.Unpack(b)
+		if err != nil {
+			t.Fatalf("test[%d] - Unpack() returns error: %v", i, err)
+		}
+
+		if !reflect.DeepEqual(c, test) {
+			t.Errorf("test[%d] - wrong container. expected=%v, got=%v", i, test, c)
+		}
+	}
+}
+
+func TestPack_layout(t *testing.T) {
+	b, err := container.Pack(container.Container{Code: []byte{0x33}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []byte{
+		0x00, 'k', 'o', 'a', // magic
+		0x01,                         // format version
+		0x01,                         // opcode version
+		0x01, 0x00, 0x00, 0x00, 0x01, // code section of 1 byte
+		0x33,
+	}
+	expected = withChecksum(expected)
+
+	if !reflect.DeepEqual(b, expected) {
+		t.Errorf("wrong layout. expected=%x, got=%x", expected, b)
+	}
+}
+
+// withChecksum appends CRC-32 checksum of b.
+func withChecksum(b []byte) []byte {
+	checksum := make([]byte, 4)
+	binary.BigEndian.PutUint32(checksum, crc32.ChecksumIEEE(b))
+	return append(b, checksum...)
+}
+
+func TestUnpack_error(t *testing.T) {
+	header := []byte{0x00, 'k', 'o', 'a', container.FormatVersion, 0x01}
+	code := []byte{0x01, 0x00, 0x00, 0x00, 0x01, 0x33}
+
+	concat := func(parts ...[]byte) []byte {
+		b := make([]byte, 0)
+		for _, p := range parts {
+			b = append(b, p...)
+		}
+		return b
+	}
+
+	valid := withChecksum(concat(header, code))
+	corrupted := append([]byte{}, valid...)
+	corrupted[len(corrupted)-5] = 0x34
+
+	tests := []struct {
+		b   []byte
+		err string
+	}{
+		{
+			b:   []byte{0x21, 0x00},
+			err: container.ErrNotContainer.Error(),
+		},
+		{
+			b:   withChecksum(concat([]byte{0x00, 'k', 'o', 'a', 0x02, 0x01}, code)),
+			err: container.ErrUnsupportedFormat.Error(),
+		},
+		{
+			b:   withChecksum(concat([]byte{0x00, 'k', 'o', 'a', container.FormatVersion, 0x02}, code)),
+			err: container.ErrIncompatibleOpcode.Error(),
+		},
+		{
+			b:   corrupted,
+			err: container.ErrChecksum.Error(),
+		},
+		{
+			b:   withChecksum(header),
+			err: container.ErrNoCode.Error(),
+		},
+		{
+			b:   withChecksum(concat(header, code[:3])),
+			err: "truncated section header",
+		},
+		{
+			b:   withChecksum(concat(header, []byte{0x01, 0x00, 0x00, 0x00, 0x02, 0x33})),
+			err: "section 1 is truncated",
+		},
+		{
+			b:   withChecksum(concat(header, code, code)),
+			err: "section 1 appears more than once",
+		},
+		{
+			b:   withChecksum(concat(header, code, []byte{0x09, 0x00, 0x00, 0x00, 0x00})),
+			err: "unknown section 9",
+		},
+		{
+			b:   withChecksum(concat(header, code, []byte{0x02, 0x00, 0x00, 0x00, 0x01, '['})),
+			err: "invalid ABI section: unexpected EOF",
+		},
+	}
+
+	for i, test := range tests {
+		_, err := container.Unpack(test.b)
+		if err == nil || err.Error() != test.err {
+			t.Errorf("test[%d] - Unpack() returns wrong error. expected=%s, got=%v", i, test.err, err)
+		}
+	}
+
+	if _, err := container.Pack(container.Container{}); err != container.ErrNoCode {
+		t.Errorf("Pack() without code should return %v, got=%v", container.ErrNoCode, err)
+	}
+}
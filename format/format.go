@@ -0,0 +1,82 @@
+/*
+ * Copyright 2018-2019 De-labtory
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package format prints koa source in the canonical style: statements
+// are indented with tabs, operators are separated by spaces, and
+// declarations are separated by a blank line. Comments are kept where
+// they are written.
+package format
+
+import (
+	"io/ioutil"
+
+	"github.com/DE-labtory/koa/ast"
+	"github.com/DE-labtory/koa/parse"
+)
+
+// Source formats koa source, which is either a file of contracts or a
+// library. Libraries are imported by importer, source must be parsed
+// without errors to be formatted.
+func Source(src []byte, importer parse.Importer) ([]byte, error) {
+	l := parse.NewLexer(string(src))
+	buf := parse.NewTokenBuffer(l)
+
+	var node ast.Node
+	var err error
+	if parse.IsLibrary(string(src)) {
+		node, err = parse.ParseLibrary(buf, "", importer)
+	} else {
+		node, err = parse.ParseSource(buf, importer)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	p := &printer{
+		src:      src,
+		comments: l.Comments(),
+		start:    true,
+	}
+
+	switch n := node.(type) {
+	case *ast.File:
+		p.file(n.Imports, n.Contracts, nil)
+	case *ast.Library:
+		p.file(n.Imports, nil, n.Functions)
+	}
+
+	return p.out.Bytes(), nil
+}
+
+// File formats the source in file whose path is path. Libraries are
+// imported relative to the file.
+func File(path string) ([]byte, error) {
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := Source(src, parse.NewFileImporter(path))
+	if list, ok := err.(parse.ErrorList); ok {
+		for i, e := range list {
+			if _, ok := e.(parse.FileError); !ok {
+				list[i] = parse.FileError{File: path, Err: e}
+			}
+		}
+	}
+
+	return out, err
+}
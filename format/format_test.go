@@ -0,0 +1,230 @@
+/*
+ * Copyright 2018-2019 De-labtory
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package format_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/DE-labtory/koa/format"
+	"github.com/DE-labtory/koa/parse"
+)
+
+func TestSource(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			input: `contract{
+func add(a int,b int) int{
+    int c=a+b*2
+  return c}
+}`,
+			expected: `contract {
+	func add(a int, b int) int {
+		int c = a + b * 2
+		return c
+	}
+}
+`,
+		},
+		{
+			input: `contract {
+	func f(a int, b int, c bool) int {
+		int x = ((a + b)) * ((a)) > b ? 1 : 2
+		int y = (a - (b - 1)) - (a * b)
+		int z = -(-a) + (-b) + ~(a & b) << 2
+		bool d = !(a > b) || (c && (a == b))
+		return (a > b) ? (c ? a : b) : (x == y) ? y : z
+	}
+}`,
+			expected: `contract {
+	func f(a int, b int, c bool) int {
+		int x = (a + b) * a > b ? 1 : 2
+		int y = a - (b - 1) - a * b
+		int z = -(-a) + -b + ~(a & b) << 2
+		bool d = !(a > b) || c && a == b
+		return a > b ? c ? a : b : x == y ? y : z
+	}
+}
+`,
+		},
+		{
+			input: `// header
+
+
+contract Escrow { // escrow
+enum State { Open,
+  Closed }
+	modifier positive(n int) { require(n > 0)
+	_ }
+	func f(n int) positive(n) State {
+
+
+		// first
+		int a = n   // a
+		/* before */ store(1, a)
+		if (a > 1) { return State.Open } else { emit(a, 0x1F, b"\x00") } /* end */
+		return State.Closed
+		// last
+	}
+}
+// eof`,
+			expected: `// header
+
+contract Escrow { // escrow
+	enum State { Open, Closed }
+
+	modifier positive(n int) {
+		require(n > 0)
+		_
+	}
+
+	func f(n int) positive(n) State {
+		// first
+		int a = n // a
+		/* before */
+		store(1, a)
+		if (a > 1) {
+			return State.Open
+		} else {
+			emit(a, 0x1F, b"\x00")
+		} /* end */
+		return State.Closed
+		// last
+	}
+}
+// eof
+`,
+		},
+		{
+			input: `contract A { func f() int { return 1 } }
+contract B {
+	func g(a int) { int b = call(a, "f(int)", 1) + int("2") + load(3)
+	}
+}`,
+			expected: `contract A {
+	func f() int {
+		return 1
+	}
+}
+
+contract B {
+	func g(a int) {
+		int b = call(a, "f(int)", 1) + int("2") + load(3)
+	}
+}
+`,
+		},
+		{
+			input: `func max(a int, b int) int { return a > b ? a : b }
+func min(a int, b int) int { return max(a, b) == a ? b : a }`,
+			expected: `func max(a int, b int) int {
+	return a > b ? a : b
+}
+
+func min(a int, b int) int {
+	return max(a, b) == a ? b : a
+}
+`,
+		},
+	}
+
+	for i, test := range tests {
+		out, err := format.Source([]byte(test.input), nil)
+		if err != nil {
+			t.Errorf("tests[%d] - unexpected error: %s", i, err)
+			continue
+		}
+
+		if string(out) != test.expected {
+			t.Errorf("tests[%d] - wrong result. expected=\n%s\ngot=\n%s", i, test.expected, out)
+		}
+
+		if again, err := format.Source(out, nil); err != nil || string(again) != string(out) {
+			t.Errorf("tests[%d] - formatting is not idempotent. got=\n%s, err=%v", i, again, err)
+		}
+	}
+}
+
+func TestSource_error(t *testing.T) {
+	if _, err := format.Source([]byte("contract { func f( }"), nil); err == nil {
+		t.Error("expected error, but got nil")
+	}
+}
+
+// TestFile checks that formatting the test sources doesn't change
+// their meaning, and formatting again doesn't change the result.
+func TestFile(t *testing.T) {
+	files := make([]string, 0)
+	for _, pattern := range []string{"../test/*.koa", "../test/bind/*.koa", "../test/import/*.koa", "../test/import/lib/*.koa"} {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, matches...)
+	}
+
+	for _, path := range files {
+		out, err := format.File(path)
+		if err != nil {
+			t.Errorf("%s - unexpected error: %s", path, err)
+			continue
+		}
+
+		again, err := format.Source(out, parse.NewFileImporter(path))
+		if err != nil {
+			t.Errorf("%s - can't format the result: %s", path, err)
+			continue
+		}
+
+		if string(again) != string(out) {
+			t.Errorf("%s - formatting is not idempotent. first=\n%s\nsecond=\n%s", path, out, again)
+		}
+
+		src, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if expected, got := parseString(t, path, src), parseString(t, path, out); expected != got {
+			t.Errorf("%s - formatting changes the source. expected=\n%s\ngot=\n%s", path, expected, got)
+		}
+	}
+}
+
+// parseString returns the string of source in path, which is a library
+// if it is in lib directory.
+func parseString(t *testing.T, path string, src []byte) string {
+	buf := parse.NewTokenBuffer(parse.NewLexer(string(src)))
+
+	if filepath.Base(filepath.Dir(path)) == "lib" {
+		library, err := parse.ParseLibrary(buf, "", parse.NewFileImporter(path))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return library.String()
+	}
+
+	file, err := parse.ParseSource(buf, parse.NewFileImporter(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return file.String()
+}
@@ -0,0 +1,472 @@
+/*
+ * Copyright 2018-2019 De-labtory
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package format
+
+import (
+	"bytes"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/DE-labtory/koa/ast"
+	"github.com/DE-labtory/koa/parse"
+)
+
+// precedence of expressions, which is the same as parser's. It decides
+// where parentheses are needed.
+const (
+	_ = iota
+	lowest
+	ternary
+	lor
+	land
+	equals
+	lessGreater
+	bitOr
+	bitXor
+	bitAnd
+	shift
+	sum
+	product
+	prefix
+	primary
+)
+
+var precedences = map[ast.Operator]int{
+	ast.LOR:      lor,
+	ast.LAND:     land,
+	ast.EQ:       equals,
+	ast.NOT_EQ:   equals,
+	ast.LT:       lessGreater,
+	ast.GT:       lessGreater,
+	ast.LTE:      lessGreater,
+	ast.GTE:      lessGreater,
+	ast.BitOr:    bitOr,
+	ast.BitXor:   bitXor,
+	ast.BitAnd:   bitAnd,
+	ast.Shl:      shift,
+	ast.Shr:      shift,
+	ast.Plus:     sum,
+	ast.Minus:    sum,
+	ast.Asterisk: product,
+	ast.Slash:    product,
+	ast.Mod:      product,
+}
+
+// printer prints the nodes of source with the comments between them.
+//
+// Comments before a node are printed on their own lines, and comments
+// on the same line after a node are kept at the end of the line.
+// Literals are printed as they are written in source.
+type printer struct {
+	src      []byte
+	comments []parse.Token
+	out      bytes.Buffer
+	indent   int
+
+	// last is the offset in source where the printed nodes end
+	last int
+
+	// start is true at the beginning of file or block, where no blank
+	// line is printed. sep is true when the next line is separated by
+	// a blank line.
+	start bool
+	sep   bool
+}
+
+// file prints imports, and then contracts or functions of library.
+func (p *printer) file(imports []*ast.Import, contracts []*ast.Contract, functions []*ast.FunctionLiteral) {
+	for _, i := range imports {
+		i := i
+		p.node(i.Span, i.End.Offset, func() {
+			text := p.text(i.Span)
+			p.write("import " + text[strings.IndexByte(text, '"'):])
+		})
+	}
+
+	for _, c := range contracts {
+		p.sep = p.out.Len() > 0
+		p.contract(c)
+	}
+
+	for _, fn := range functions {
+		p.sep = p.out.Len() > 0
+		p.function(fn)
+	}
+
+	p.flush(len(p.src) + 1)
+}
+
+// declaration is enum, modifier or function in contract.
+type declaration struct {
+	offset int
+	print  func()
+}
+
+func (p *printer) contract(c *ast.Contract) {
+	lbrace := c.Start.Offset + bytes.IndexByte(p.src[c.Start.Offset:], '{')
+
+	decls := make([]declaration, 0)
+	for _, e := range c.Enums {
+		e := e
+		decls = append(decls, declaration{e.Start.Offset, func() { p.enum(e) }})
+	}
+	for _, m := range c.Modifiers {
+		m := m
+		decls = append(decls, declaration{m.Start.Offset, func() { p.modifier(m) }})
+	}
+	for _, fn := range c.Functions {
+		fn := fn
+		decls = append(decls, declaration{fn.Start.Offset, func() { p.function(fn) }})
+	}
+	sort.SliceStable(decls, func(i, j int) bool {
+		return decls[i].offset < decls[j].offset
+	})
+
+	p.node(c.Span, lbrace, func() {
+		if c.Name != nil {
+			p.write("contract " + c.Name.Name + " ")
+		} else {
+			p.write("contract ")
+		}
+
+		p.braces(lbrace, c.End.Offset-1, func() {
+			for n, d := range decls {
+				p.sep = n > 0
+				d.print()
+			}
+		})
+	})
+}
+
+func (p *printer) enum(e *ast.EnumLiteral) {
+	members := make([]string, 0)
+	for _, m := range e.Members {
+		members = append(members, m.Name)
+	}
+
+	p.node(e.Span, e.End.Offset, func() {
+		p.write("enum " + e.Name.Name + " { " + strings.Join(members, ", ") + " }")
+	})
+}
+
+func (p *printer) modifier(m *ast.ModifierLiteral) {
+	p.node(m.Span, m.Body.Start.Offset, func() {
+		p.write("modifier " + m.Name.Name + "(" + p.parameters(m.Parameters) + ") ")
+		p.block(m.Body)
+	})
+}
+
+// function prints function with its modifiers. The return type is
+// omitted if function returns nothing.
+func (p *printer) function(fn *ast.FunctionLiteral) {
+	p.node(fn.Span, fn.Body.Start.Offset, func() {
+		p.write("func " + fn.Name.Name + "(" + p.parameters(fn.Parameters) + ") ")
+
+		for _, m := range fn.Modifiers {
+			p.write(m.Modifier.Name.Name)
+			if len(m.Arguments) > 0 {
+				p.write("(" + p.exprs(m.Arguments) + ")")
+			}
+			p.write(" ")
+		}
+
+		if fn.ReturnType != ast.VoidType {
+			p.write(ast.TypeName(fn.ReturnType, fn.ReturnEnum) + " ")
+		}
+
+		p.block(fn.Body)
+	})
+}
+
+func (p *printer) parameters(params []*ast.ParameterLiteral) string {
+	strs := make([]string, 0)
+	for _, param := range params {
+		strs = append(strs, param.Identifier.Name+" "+ast.TypeName(param.Type, param.Enum))
+	}
+
+	return strings.Join(strs, ", ")
+}
+
+func (p *printer) block(b *ast.BlockStatement) {
+	p.braces(b.Start.Offset, b.End.Offset-1, func() {
+		for _, s := range b.Statements {
+			p.statement(s)
+		}
+	})
+}
+
+func (p *printer) statement(s ast.Statement) {
+	if s, ok := s.(*ast.IfStatement); ok {
+		p.node(s.Span, s.Consequence.Start.Offset, func() {
+			p.write("if (" + p.expr(s.Condition) + ") ")
+			p.block(s.Consequence)
+
+			if s.Alternative != nil {
+				p.write(" else ")
+				p.block(s.Alternative)
+			}
+		})
+		return
+	}
+
+	span := s.Position()
+	p.node(span, span.End.Offset, func() {
+		p.write(p.simpleStatement(s))
+	})
+}
+
+// simpleStatement returns the statement which is printed in a line.
+func (p *printer) simpleStatement(s ast.Statement) string {
+	switch s := s.(type) {
+	case *ast.AssignStatement:
+		return ast.TypeName(s.Type, s.Enum) + " " + s.Variable.Name + " = " + p.expr(s.Value)
+	case *ast.ReassignStatement:
+		return s.Variable.Name + " = " + p.expr(s.Value)
+	case *ast.ReturnStatement:
+		if s.ReturnValue == nil {
+			return "return"
+		}
+		return "return " + p.expr(s.ReturnValue)
+	case *ast.RequireStatement:
+		return "require(" + p.expr(s.Condition) + ")"
+	case *ast.StoreStatement:
+		return "store(" + p.exprs([]ast.Expression{s.Key, s.Value}) + ")"
+	case *ast.EmitStatement:
+		return "emit(" + p.exprs(s.Values) + ")"
+	case *ast.PlaceholderStatement:
+		return "_"
+	case *ast.ExpressionStatement:
+		return p.expr(s.Expr)
+	}
+
+	return s.String()
+}
+
+func (p *printer) exprs(exps []ast.Expression) string {
+	strs := make([]string, 0)
+	for _, e := range exps {
+		strs = append(strs, p.expr(e))
+	}
+
+	return strings.Join(strs, ", ")
+}
+
+// expr returns the source of expression with parentheses only where
+// they are needed.
+func (p *printer) expr(e ast.Expression) string {
+	switch e := e.(type) {
+	case *ast.Identifier:
+		return e.Name
+	case *ast.QualifiedIdentifier:
+		if strings.Contains(p.text(e.Span), ".") {
+			return e.Library.Name + "." + e.Function.Name.Name
+		}
+		return e.Function.Name.Name
+	case *ast.IntegerLiteral:
+		if text := p.literal(e.Span); text != "" {
+			return text
+		}
+		return strconv.FormatInt(e.Value, 10)
+	case *ast.StringLiteral:
+		if text := p.literal(e.Span); text != "" {
+			return text
+		}
+		return ast.Quote(e.Value)
+	case *ast.BooleanLiteral:
+		return strconv.FormatBool(e.Value)
+	case *ast.EnumMemberLiteral:
+		return e.Enum.Name.Name + "." + e.Member.Name
+	case *ast.PrefixExpression:
+		right := p.operand(e.Right, prefix)
+		if e.Operator == ast.Minus && strings.HasPrefix(right, "-") {
+			// -(-a) can't be printed as --a, which is decrement
+			right = "(" + right + ")"
+		}
+		return e.Operator.String() + right
+	case *ast.InfixExpression:
+		pre := precedences[e.Operator]
+		return p.operand(e.Left, pre) + " " + e.Operator.String() + " " + p.operand(e.Right, pre+1)
+	case *ast.ConditionalExpression:
+		return p.operand(e.Condition, ternary+1) + " ? " + p.expr(e.Consequence) + " : " + p.expr(e.Alternative)
+	case *ast.ConversionExpression:
+		return e.Type.String() + "(" + p.expr(e.Value) + ")"
+	case *ast.ContractCallExpression:
+		args := append([]ast.Expression{e.Address}, e.Arguments...)
+		strs := []string{p.expr(e.Address), p.signature(e)}
+		if len(args) > 1 {
+			strs = append(strs, p.exprs(args[1:]))
+		}
+		return "call(" + strings.Join(strs, ", ") + ")"
+	case *ast.LoadExpression:
+		return "load(" + p.expr(e.Key) + ")"
+	case *ast.CallExpression:
+		return p.expr(e.Function) + "(" + p.exprs(e.Arguments) + ")"
+	}
+
+	return e.String()
+}
+
+// operand returns the source of expression which is an operand of
+// operator whose precedence is pre. It is in parentheses if it binds
+// more loosely than the operator.
+func (p *printer) operand(e ast.Expression, pre int) string {
+	if precedenceOf(e) < pre {
+		return "(" + p.expr(e) + ")"
+	}
+
+	return p.expr(e)
+}
+
+func precedenceOf(e ast.Expression) int {
+	switch e := e.(type) {
+	case *ast.InfixExpression:
+		return precedences[e.Operator]
+	case *ast.ConditionalExpression:
+		return ternary
+	case *ast.PrefixExpression:
+		return prefix
+	}
+
+	return primary
+}
+
+// signature returns the method of contract call as it is written in
+// source, which is the first string after the address.
+func (p *printer) signature(e *ast.ContractCallExpression) string {
+	text := p.text(e.Span)
+	if text != "" {
+		rest := text[e.Address.Position().End.Offset-e.Start.Offset:]
+		if i := strings.IndexByte(rest, '"'); i >= 0 {
+			if j := strings.IndexByte(rest[i+1:], '"'); j >= 0 {
+				return rest[i : i+j+2]
+			}
+		}
+	}
+
+	method := e.Signature
+	if e.ReturnType != ast.IntType {
+		method += " " + e.ReturnType.String()
+	}
+
+	return ast.Quote(method)
+}
+
+// text returns the source of span, it is empty if span is not in source.
+func (p *printer) text(span ast.Span) string {
+	if span.Start.Offset >= span.End.Offset || span.End.Offset > len(p.src) {
+		return ""
+	}
+
+	return string(p.src[span.Start.Offset:span.End.Offset])
+}
+
+// literal returns the source of literal without the parentheses
+// around it.
+func (p *printer) literal(span ast.Span) string {
+	text := strings.TrimSpace(p.text(span))
+	for strings.HasPrefix(text, "(") && strings.HasSuffix(text, ")") {
+		text = strings.TrimSpace(text[1 : len(text)-1])
+	}
+
+	return text
+}
+
+// node prints a node whose span is span. Comments before head are
+// printed before the node, and comment after the node in the same
+// line is printed at the end of line.
+func (p *printer) node(span ast.Span, head int, print func()) {
+	p.flush(head)
+	p.linebreak(span.Start.Offset)
+	print()
+
+	if span.End.Offset > p.last {
+		p.last = span.End.Offset
+	}
+	p.trailing()
+	p.write("\n")
+}
+
+// braces prints body in braces, which are at lbrace and rbrace in source.
+func (p *printer) braces(lbrace, rbrace int, body func()) {
+	p.write("{")
+	p.last = lbrace + 1
+	p.trailing()
+	p.write("\n")
+
+	p.indent++
+	p.start = true
+	body()
+	p.flush(rbrace)
+	p.indent--
+
+	p.write(strings.Repeat("\t", p.indent) + "}")
+	p.start = false
+	p.last = rbrace + 1
+}
+
+// linebreak starts a line for the node or comment at offset. It is
+// separated by a blank line if there is a blank line before it in source.
+func (p *printer) linebreak(offset int) {
+	if p.sep || (!p.start && p.hasBlank(p.last, offset)) {
+		p.write("\n")
+	}
+	p.sep = false
+	p.start = false
+
+	p.write(strings.Repeat("\t", p.indent))
+}
+
+// flush prints the comments before limit, each in its own line.
+func (p *printer) flush(limit int) {
+	for len(p.comments) > 0 && int(p.comments[0].Offset) < limit {
+		c := p.comments[0]
+		p.comments = p.comments[1:]
+
+		p.linebreak(int(c.Offset))
+		p.write(strings.TrimRight(c.Val, " \t\r") + "\n")
+		p.last = int(c.Offset) + len(c.Val)
+	}
+}
+
+// trailing prints the comments in the same line after the last node.
+func (p *printer) trailing() {
+	for len(p.comments) > 0 {
+		c := p.comments[0]
+		if int(c.Offset) < p.last || len(bytes.Trim(p.src[p.last:c.Offset], " \t\r")) > 0 {
+			return
+		}
+		p.comments = p.comments[1:]
+
+		p.write(" " + strings.TrimRight(c.Val, " \t\r"))
+		p.last = int(c.Offset) + len(c.Val)
+	}
+}
+
+// hasBlank reports whether there is a blank line in source between
+// from and to.
+func (p *printer) hasBlank(from, to int) bool {
+	if from >= to || to > len(p.src) {
+		return false
+	}
+
+	return bytes.Count(p.src[from:to], []byte("\n")) > 1
+}
+
+func (p *printer) write(s string) {
+	p.out.WriteString(s)
+}
@@ -2,15 +2,23 @@ package koa
 
 import (
 	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strings"
 
 	"github.com/DE-labtory/koa/abi"
+	"github.com/DE-labtory/koa/ast"
+	"github.com/DE-labtory/koa/container"
 	"github.com/DE-labtory/koa/parse"
 	"github.com/DE-labtory/koa/translate"
 	"github.com/DE-labtory/koa/vm"
 )
 
+// Version is the version of koa, it is written in the metadata of container.
+const Version = "0.0.1"
+
 func Compile(input string) (translate.Asm, abi.ABI, error) {
-	ast, err := parse.Parse(
+	contract, err := parse.Parse(
 		parse.NewTokenBuffer(
 			parse.NewLexer(input)))
 
@@ -18,12 +26,35 @@ func Compile(input string) (translate.Asm, abi.ABI, error) {
 		return translate.Asm{}, abi.ABI{}, err
 	}
 
-	asm, err := translate.CompileContract(*ast)
+	return CompileContract(contract)
+}
+
+// CompileFile compiles the contract named name in file with the libraries
+// it imports. Import path is relative to the file which imports.
+//
+// If name is empty, file should have only one contract.
+func CompileFile(path string, name string) (translate.Asm, abi.ABI, error) {
+	file, err := parse.ParseFile(path)
+	if err != nil {
+		return translate.Asm{}, abi.ABI{}, err
+	}
+
+	contract, err := selectContract(file, name)
+	if err != nil {
+		return translate.Asm{}, abi.ABI{}, fmt.Errorf("%s: %s", path, err)
+	}
+
+	return CompileContract(contract)
+}
+
+// CompileContract compiles a contract to bytecode and ABI.
+func CompileContract(contract *ast.Contract) (translate.Asm, abi.ABI, error) {
+	asm, err := translate.CompileContract(*contract)
 	if err != nil {
 		return asm, abi.ABI{}, err
 	}
 
-	a, err := translate.ExtractAbi(*ast)
+	a, err := translate.ExtractAbi(*contract)
 	if err != nil {
 		return asm, abi.ABI{}, err
 	}
@@ -31,10 +62,60 @@ func Compile(input string) (translate.Asm, abi.ABI, error) {
 	return asm, *a, nil
 }
 
+// Pack packs the bytecode and ABI of contract into a container, so
+// that the deployed artifact describes itself. The source map of asm
+// is packed as the debug information in JSON.
+func Pack(asm translate.Asm, a abi.ABI) ([]byte, error) {
+	sourceMap, err := json.Marshal(asm.SourceMap())
+	if err != nil {
+		return nil, err
+	}
+
+	return container.Pack(container.Container{
+		Code: asm.ToRawByteCode(),
+		ABI:  &a,
+		Metadata: map[string]string{
+			"compiler": "koa " + Version,
+			"contract": a.Name,
+		},
+		Debug: sourceMap,
+	})
+}
+
+// selectContract returns the contract named name in file. If name is
+// empty, returns the only contract in file.
+func selectContract(file *ast.File, name string) (*ast.Contract, error) {
+	if name == "" && len(file.Contracts) == 1 {
+		return file.Contracts[0], nil
+	}
+
+	if name == "" {
+		names := make([]string, 0)
+		for _, c := range file.Contracts {
+			names = append(names, c.Name.String())
+		}
+		return nil, fmt.Errorf("select one of the contracts [%s]", strings.Join(names, ", "))
+	}
+
+	contract := file.Contract(name)
+	if contract == nil {
+		return nil, fmt.Errorf("there is no contract %s", name)
+	}
+
+	return contract, nil
+}
+
 func Execute(rawByteCode []byte, function []byte, args []byte) ([]byte, error) {
+	return ExecuteWithHost(rawByteCode, function, args, nil)
+}
+
+// ExecuteWithHost executes the function of contract which can call
+// the other contracts. host provides the bytecode of called contracts.
+func ExecuteWithHost(rawByteCode []byte, function []byte, args []byte, host vm.Host) ([]byte, error) {
 	callFunc := &vm.CallFunc{
 		Func: function,
 		Args: args,
+		Host: host,
 	}
 
 	stack, err := vm.Execute(rawByteCode, vm.NewMemory(), callFunc)
@@ -17,15 +17,20 @@
 package koa
 
 import (
+	"encoding/json"
 	"errors"
 	"os"
+	"reflect"
+	"strings"
 	"testing"
 
 	"bytes"
 	"encoding/hex"
 
 	"github.com/DE-labtory/koa/abi"
+	"github.com/DE-labtory/koa/container"
 	"github.com/DE-labtory/koa/translate"
+	"github.com/DE-labtory/koa/vm"
 )
 
 type testData struct {
@@ -458,3 +463,672 @@ func TestExecute(t *testing.T) {
 		}
 	}
 }
+
+// TestExecute_shortCircuit verifies that the right operand of '&&' and '||'
+// is evaluated only when the left operand can't decide the result.
+// The right operand shifts by a negative count which fails the execution
+// with vm.ErrNegativeShift, so it must not be evaluated when skipped.
+func TestExecute_shortCircuit(t *testing.T) {
+	input := `
+contract {
+	func skipAnd(n int) bool {
+		return false && (1 << n) > 0
+	}
+
+	func skipOr(n int) bool {
+		return true || (1 << n) > 0
+	}
+
+	func evalAnd(n int) bool {
+		return true && (1 << n) > 0
+	}
+
+	func evalOr(n int) bool {
+		return false || (1 << n) > 0
+	}
+}
+`
+	asm, _, err := Compile(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		signature   string
+		arg         int
+		output      []byte
+		expectedErr error
+	}{
+		{"skipAnd(int)", -1, Bytes(0), nil},
+		{"skipOr(int)", -1, Bytes(1), nil},
+		{"evalAnd(int)", 2, Bytes(1), nil},
+		{"evalAnd(int)", -1, nil, vm.ErrNegativeShift},
+		{"evalOr(int)", 2, Bytes(1), nil},
+		{"evalOr(int)", -1, nil, vm.ErrNegativeShift},
+	}
+
+	for i, test := range tests {
+		args, err := abi.Encode(test.arg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		output, err := Execute(asm.ToRawByteCode(), abi.Selector(test.signature), args)
+		if err != test.expectedErr {
+			t.Errorf("[test %d] - Execute() returns wrong error.\nexpected=%v\ngot=%v", i, test.expectedErr, err)
+		}
+
+		if !bytes.Equal(test.output, output) {
+			t.Errorf("[test %d] - Invalid output - expected=%x, got=%x ", i, test.output, output)
+		}
+	}
+}
+
+func TestExecute_conditional(t *testing.T) {
+	input := `
+contract {
+	func max(a int, b int) int {
+		return a > b ? a : b
+	}
+
+	func sign(a int) int {
+		return a > 0 ? 1 : a == 0 ? 0 : 0 - 1
+	}
+}
+`
+	asm, _, err := Compile(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		signature string
+		args      []interface{}
+		output    []byte
+	}{
+		{"max(int,int)", []interface{}{3, 7}, Bytes(7)},
+		{"max(int,int)", []interface{}{7, 3}, Bytes(7)},
+		{"sign(int)", []interface{}{5}, Bytes(1)},
+		{"sign(int)", []interface{}{0}, Bytes(0)},
+		{"sign(int)", []interface{}{-5}, Bytes(-1)},
+	}
+
+	for i, test := range tests {
+		args, err := abi.Encode(test.args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		output, err := Execute(asm.ToRawByteCode(), abi.Selector(test.signature), args)
+		if err != nil {
+			t.Errorf("[test %d] - Execute() returns error. %v", i, err)
+		}
+
+		if !bytes.Equal(test.output, output) {
+			t.Errorf("[test %d] - Invalid output - expected=%x, got=%x ", i, test.output, output)
+		}
+	}
+}
+
+func TestExecute_conversion(t *testing.T) {
+	input := `
+contract {
+	func parsed() int {
+		return int("42") + 1
+	}
+
+	func roundTrip(n int) int {
+		return int(string(n))
+	}
+
+	func truthy(n int) bool {
+		return bool(n)
+	}
+
+	func fromBool(n int) int {
+		return int(n > 0) + int(bool(string(true)))
+	}
+
+	func invalid() int {
+		return int("abc")
+	}
+}
+`
+	asm, _, err := Compile(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		signature   string
+		args        []interface{}
+		output      []byte
+		expectedErr error
+	}{
+		{"parsed()", nil, Bytes(43), nil},
+		{"roundTrip(int)", []interface{}{1234}, Bytes(1234), nil},
+		{"roundTrip(int)", []interface{}{-56}, Bytes(-56), nil},
+		{"truthy(int)", []interface{}{3}, Bytes(1), nil},
+		{"truthy(int)", []interface{}{0}, Bytes(0), nil},
+		{"fromBool(int)", []interface{}{5}, Bytes(2), nil},
+		{"fromBool(int)", []interface{}{0}, Bytes(1), nil},
+		{"invalid()", nil, nil, vm.ErrInvalidConversion},
+	}
+
+	for i, test := range tests {
+		args, err := abi.Encode(test.args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		output, err := Execute(asm.ToRawByteCode(), abi.Selector(test.signature), args)
+		if err != test.expectedErr {
+			t.Errorf("[test %d] - Execute() returns wrong error.\nexpected=%v\ngot=%v", i, test.expectedErr, err)
+		}
+
+		if !bytes.Equal(test.output, output) {
+			t.Errorf("[test %d] - Invalid output - expected=%x, got=%x ", i, test.output, output)
+		}
+	}
+}
+
+func TestExecute_literals(t *testing.T) {
+	input := `
+contract {
+	func numbers() int {
+		return 0xff + 0b101 + 1_000
+	}
+
+	func escaped() bool {
+		return "a\"\x41\n" == "a\"A\x0a"
+	}
+
+	func byteString() bool {
+		return hex"4142" == b"\x41B" && hex"4142" == "AB"
+	}
+
+	func key() string {
+		return hex"0102"
+	}
+}
+`
+	asm, _, err := Compile(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		signature string
+		output    []byte
+	}{
+		{"numbers()", Bytes(1260)},
+		{"escaped()", Bytes(1)},
+		{"byteString()", Bytes(1)},
+		{"key()", []byte{1, 2, 0, 0, 0, 0, 0, 0}},
+	}
+
+	for i, test := range tests {
+		output, err := Execute(asm.ToRawByteCode(), abi.Selector(test.signature), nil)
+		if err != nil {
+			t.Errorf("[test %d] - Execute() returns error. %v", i, err)
+		}
+
+		if !bytes.Equal(test.output, output) {
+			t.Errorf("[test %d] - Invalid output - expected=%x, got=%x ", i, test.output, output)
+		}
+	}
+}
+
+func TestExecute_enum(t *testing.T) {
+	input := `
+contract {
+	enum State {
+		Open,
+		Closed,
+		Settled,
+	}
+
+	func next(s State) State {
+		if (s == State.Open) {
+			return State.Closed
+		}
+		State settled = State.Settled
+		return settled
+	}
+
+	func isOpen(s State) bool {
+		return s == State.Open
+	}
+
+	func code() int {
+		return int(State.Settled) * 10
+	}
+}
+`
+	asm, a, err := Compile(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		signature string
+		arg       int
+		output    []byte
+	}{
+		{"next(int)", 0, Bytes(1)},
+		{"next(int)", 1, Bytes(2)},
+		{"isOpen(int)", 0, Bytes(1)},
+		{"isOpen(int)", 2, Bytes(0)},
+		{"code()", 0, Bytes(20)},
+	}
+
+	for i, test := range tests {
+		args, err := abi.Encode(test.arg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		output, err := Execute(asm.ToRawByteCode(), abi.Selector(test.signature), args)
+		if err != nil {
+			t.Errorf("[test %d] - Execute() returns error. %v", i, err)
+		}
+
+		if !bytes.Equal(test.output, output) {
+			t.Errorf("[test %d] - Invalid output - expected=%x, got=%x ", i, test.output, output)
+		}
+	}
+
+	next := a.Methods[0]
+	if next.Signature() != "next(int)" {
+		t.Errorf("Invalid signature of enum method - expected=next(int), got=%s", next.Signature())
+	}
+
+	enum := next.Output.Type.Enum
+	if enum == nil || enum.Name != "State" {
+		t.Fatalf("Output of next() should be enum State, got=%v", enum)
+	}
+
+	member, err := enum.Member(2)
+	if err != nil || member != "Settled" {
+		t.Errorf("Invalid enum member - expected=Settled, got=%s, %v", member, err)
+	}
+}
+
+func TestExecute_overload(t *testing.T) {
+	input := `
+contract {
+	func size(a int) int {
+		return 1
+	}
+
+	func size(a string) int {
+		return 2
+	}
+
+	func size(a int, b int) int {
+		return a + b
+	}
+}
+`
+	asm, _, err := Compile(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		signature string
+		args      []interface{}
+		output    []byte
+	}{
+		{"size(int)", []interface{}{5}, Bytes(1)},
+		{"size(string)", []interface{}{"koa"}, Bytes(2)},
+		{"size(int,int)", []interface{}{3, 4}, Bytes(7)},
+	}
+
+	for i, test := range tests {
+		args, err := abi.Encode(test.args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		output, err := Execute(asm.ToRawByteCode(), abi.Selector(test.signature), args)
+		if err != nil {
+			t.Errorf("[test %d] - Execute() returns error. %v", i, err)
+		}
+
+		if !bytes.Equal(test.output, output) {
+			t.Errorf("[test %d] - Invalid output - expected=%x, got=%x ", i, test.output, output)
+		}
+	}
+}
+
+func TestExecute_modifier(t *testing.T) {
+	input := `
+contract {
+	modifier positive(n int) {
+		require(n > 0)
+		_
+	}
+
+	modifier below(n int, limit int) {
+		int checked = n
+		require(checked < limit)
+		_
+	}
+
+	func double(a int) positive(a) int {
+		int result = a * 2
+		return result
+	}
+
+	func withdraw(amount int) positive(amount) below(amount, 100) int {
+		int n = 1000 - amount
+		return n
+	}
+
+	func check(a int) int {
+		require(a != 0)
+		return a
+	}
+}
+`
+	asm, _, err := Compile(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		signature   string
+		arg         int
+		output      []byte
+		expectedErr error
+	}{
+		{"double(int)", 3, Bytes(6), nil},
+		{"double(int)", 0, nil, vm.ErrRevert},
+		{"withdraw(int)", 10, Bytes(990), nil},
+		{"withdraw(int)", 0, nil, vm.ErrRevert},
+		{"withdraw(int)", 100, nil, vm.ErrRevert},
+		{"check(int)", 7, Bytes(7), nil},
+		{"check(int)", 0, nil, vm.ErrRevert},
+	}
+
+	for i, test := range tests {
+		args, err := abi.Encode(test.arg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		output, err := Execute(asm.ToRawByteCode(), abi.Selector(test.signature), args)
+		if err != test.expectedErr {
+			t.Errorf("[test %d] - Execute() returns wrong error.\nexpected=%v\ngot=%v", i, test.expectedErr, err)
+		}
+
+		if !bytes.Equal(test.output, output) {
+			t.Errorf("[test %d] - Invalid output - expected=%x, got=%x ", i, test.output, output)
+		}
+	}
+}
+
+func TestExecute_import(t *testing.T) {
+	asm, _, err := CompileFile("test/import/token.koa", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		signature string
+		args      []interface{}
+		output    []byte
+	}{
+		{"clamp(int)", []interface{}{50}, Bytes(50)},
+		{"clamp(int)", []interface{}{0 - 5}, Bytes(0)},
+		{"clamp(int)", []interface{}{500}, Bytes(100)},
+		{"biggest(int,int,int)", []interface{}{3, 9, 4}, Bytes(10)},
+		{"biggest(int,int,int)", []interface{}{3, 1, 4}, Bytes(5)},
+	}
+
+	for i, test := range tests {
+		args, err := abi.Encode(test.args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		output, err := Execute(asm.ToRawByteCode(), abi.Selector(test.signature), args)
+		if err != nil {
+			t.Errorf("[test %d] - Execute() returns error: %v", i, err)
+		}
+
+		if !bytes.Equal(test.output, output) {
+			t.Errorf("[test %d] - Invalid output - expected=%x, got=%x ", i, test.output, output)
+		}
+	}
+}
+
+func TestCompileFile_importCycle(t *testing.T) {
+	_, _, err := CompileFile("test/import/cycle/a.koa", "")
+	if err == nil {
+		t.Fatal("CompileFile() should fail with import cycle")
+	}
+
+	expected := "import cycle: test/import/cycle/b.koa -> test/import/cycle/c.koa -> test/import/cycle/b.koa"
+	if !strings.Contains(err.Error(), expected) {
+		t.Fatalf("CompileFile() returns wrong error.\nexpected to contain=%s\ngot=%v", expected, err)
+	}
+}
+
+func TestCompileFile_contracts(t *testing.T) {
+	tests := []struct {
+		name        string
+		signature   string
+		arg         int
+		output      []byte
+		expectedErr string
+	}{
+		{"Escrow", "release(int)", 10, Bytes(9), ""},
+		{"Token", "release(int)", 10, Bytes(20), ""},
+		{"", "", 0, nil, "test/contracts.koa: select one of the contracts [Escrow, Token]"},
+		{"Auction", "", 0, nil, "test/contracts.koa: there is no contract Auction"},
+	}
+
+	for i, test := range tests {
+		asm, a, err := CompileFile("test/contracts.koa", test.name)
+		if test.expectedErr != "" {
+			if err == nil || err.Error() != test.expectedErr {
+				t.Errorf("[test %d] - CompileFile() returns wrong error.\nexpected=%s\ngot=%v", i, test.expectedErr, err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("[test %d] - CompileFile() returns error: %v", i, err)
+		}
+
+		if a.Name != test.name {
+			t.Errorf("[test %d] - ABI has wrong name. expected=%s, got=%s", i, test.name, a.Name)
+		}
+
+		args, err := abi.Encode(test.arg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		output, err := Execute(asm.ToRawByteCode(), abi.Selector(test.signature), args)
+		if err != nil {
+			t.Errorf("[test %d] - Execute() returns error: %v", i, err)
+		}
+
+		if !bytes.Equal(test.output, output) {
+			t.Errorf("[test %d] - Invalid output - expected=%x, got=%x ", i, test.output, output)
+		}
+	}
+}
+
+// contractHost provides the bytecode of contracts by their address
+type contractHost map[int64][]byte
+
+func (h contractHost) Code(address int64) ([]byte, error) {
+	code, ok := h[address]
+	if !ok {
+		return nil, errors.New("no contract at the address")
+	}
+	return code, nil
+}
+
+func TestExecuteWithHost_call(t *testing.T) {
+	bank, _, err := CompileFile("test/call.koa", "Bank")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wallet, _, err := CompileFile("test/call.koa", "Wallet")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	host := contractHost{1: bank.ToRawByteCode()}
+
+	tests := []struct {
+		signature string
+		args      []interface{}
+		output    []byte
+		err       error
+	}{
+		{"deposit(int,int)", []interface{}{1, 10}, Bytes(21), nil},
+		{"deposit(int,int)", []interface{}{1, 0}, nil, vm.ErrRevert},
+		{"withdraw(int,int)", []interface{}{1, 50}, Bytes(50), nil},
+		{"withdraw(int,int)", []interface{}{1, 150}, nil, vm.ErrRevert},
+		{"withdraw(int,int)", []interface{}{2, 50}, nil, errors.New("no contract at the address")},
+	}
+
+	for i, test := range tests {
+		args, err := abi.Encode(test.args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		output, err := ExecuteWithHost(wallet.ToRawByteCode(), abi.Selector(test.signature), args, host)
+		if test.err != nil {
+			if err == nil || err.Error() != test.err.Error() {
+				t.Errorf("[test %d] - ExecuteWithHost() returns wrong error. expected=%v, got=%v", i, test.err, err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("[test %d] - ExecuteWithHost() returns error: %v", i, err)
+		}
+
+		if !bytes.Equal(test.output, output) {
+			t.Errorf("[test %d] - Invalid output - expected=%x, got=%x ", i, test.output, output)
+		}
+	}
+
+	args, err := abi.Encode(1, 50)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Execute(wallet.ToRawByteCode(), abi.Selector("withdraw(int,int)"), args); err != vm.ErrNoHost {
+		t.Errorf("Execute() without host should return %v, got=%v", vm.ErrNoHost, err)
+	}
+}
+
+func TestCompile_abiJSON(t *testing.T) {
+	_, a, err := CompileFile("test/bind/wallet.koa", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := json.Marshal(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := abi.New(string(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(decoded, a) {
+		t.Errorf("ABI is changed by JSON. expected=%v, got=%v", a, decoded)
+	}
+}
+
+func TestCompileFile_sourceMap(t *testing.T) {
+	asm, _, err := CompileFile("test/import/token.koa", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sm := asm.SourceMap()
+
+	positions := make(map[string]bool)
+	for _, entry := range sm.Entries {
+		positions[sm.Position(entry.PC)] = true
+	}
+
+	expected := []string{
+		"test/import/token.koa:5:9",
+		"test/import/token.koa:6:9",
+		"test/import/token.koa:10:9",
+		"test/import/lib/math.koa:4:5",
+		"test/import/lib/math.koa:5:9",
+		"test/import/lib/math.koa:7:5",
+		"test/import/lib/math.koa:11:5",
+	}
+
+	for _, pos := range expected {
+		if !positions[pos] {
+			t.Errorf("source map has no position %s. got=%v", pos, positions)
+		}
+	}
+}
+
+func TestPack(t *testing.T) {
+	asm, a, err := CompileFile("test/bind/wallet.koa", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	packed, err := Pack(asm, a)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := container.Unpack(packed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(c.Code, asm.ToRawByteCode()) || !reflect.DeepEqual(*c.ABI, a) {
+		t.Errorf("container has wrong code or ABI")
+	}
+
+	if c.Metadata["compiler"] != "koa "+Version || c.Metadata["contract"] != "Wallet" {
+		t.Errorf("container has wrong metadata. got=%v", c.Metadata)
+	}
+
+	var sm translate.SourceMap
+	if err := json.Unmarshal(c.Debug, &sm); err != nil {
+		t.Fatalf("container has invalid source map: %v", err)
+	}
+
+	if !reflect.DeepEqual(sm, asm.SourceMap()) {
+		t.Errorf("container has wrong source map. got=%v", sm)
+	}
+
+	// container is executed as the bytecode
+	method, err := c.ABI.FindMethod("owner", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := Execute(packed, method.ID(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if owner, _ := method.DecodeOutput(output); owner != "koa" {
+		t.Errorf("Invalid output - expected=koa, got=%v", owner)
+	}
+}
@@ -0,0 +1,682 @@
+/*
+ * Copyright 2018-2019 De-labtory
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lsp
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/DE-labtory/koa"
+	"github.com/DE-labtory/koa/ast"
+	"github.com/DE-labtory/koa/format"
+	"github.com/DE-labtory/koa/parse"
+	"github.com/DE-labtory/koa/symbol"
+)
+
+// source is a text whose offsets can be converted to positions.
+type source struct {
+	text string
+
+	// lines are the offsets where lines start
+	lines []int
+}
+
+func newSource(text string) *source {
+	lines := []int{0}
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\n' {
+			lines = append(lines, i+1)
+		}
+	}
+
+	return &source{text: text, lines: lines}
+}
+
+// position returns the position of offset in text.
+func (s *source) position(offset int) Position {
+	if offset > len(s.text) {
+		offset = len(s.text)
+	}
+
+	line := sort.Search(len(s.lines), func(i int) bool { return s.lines[i] > offset }) - 1
+	return Position{
+		Line:      line,
+		Character: len(utf16.Encode([]rune(s.text[s.lines[line]:offset]))),
+	}
+}
+
+// offset returns the offset of pos in text. Position after the end of
+// line is the end of line.
+func (s *source) offset(pos Position) int {
+	if pos.Line < 0 {
+		return 0
+	}
+	if pos.Line >= len(s.lines) {
+		return len(s.text)
+	}
+
+	start := s.lines[pos.Line]
+	end := len(s.text)
+	if pos.Line+1 < len(s.lines) {
+		end = s.lines[pos.Line+1] - 1
+	}
+
+	units := 0
+	for i, r := range s.text[start:end] {
+		if units >= pos.Character {
+			return start + i
+		}
+		units += len(utf16.Encode([]rune{r}))
+	}
+
+	return end
+}
+
+func (s *source) rangeOf(span ast.Span) Range {
+	return Range{s.position(span.Start.Offset), s.position(span.End.Offset)}
+}
+
+// document is a koa source opened in editor, with the result of parsing.
+// Document is either a file of contracts or a library.
+type document struct {
+	*source
+	uri string
+
+	// path is the path of file, it is empty if document isn't a file
+	path string
+
+	file    *ast.File
+	library *ast.Library
+	scopes  []parse.Scope
+	errs    []error
+
+	// tokens are the tokens of text except comments
+	tokens []parse.Token
+}
+
+func newDocument(uri string, text string) *document {
+	d := &document{
+		source: newSource(text),
+		uri:    uri,
+		path:   uriPath(uri),
+	}
+
+	l := parse.NewLexer(text)
+	for t := l.NextToken(); t.Type != parse.Eof; t = l.NextToken() {
+		d.tokens = append(d.tokens, t)
+	}
+
+	p := parse.NewParser(parse.NewTokenBuffer(parse.NewLexer(text)), d.importer())
+
+	var err error
+	if parse.IsLibrary(text) {
+		d.library, err = p.ParseLibrary(strings.TrimSuffix(filepath.Base(d.path), filepath.Ext(d.path)))
+	} else {
+		d.file, err = p.ParseSource()
+	}
+	d.scopes = p.Scopes()
+
+	if list, ok := err.(parse.ErrorList); ok {
+		d.errs = list
+	} else if err != nil {
+		d.errs = []error{err}
+	}
+
+	// contracts parsed without errors are compiled, so that the errors
+	// found by compiler are reported too
+	if err == nil && d.file != nil {
+		for _, c := range d.file.Contracts {
+			if _, _, err := koa.CompileContract(c); err != nil {
+				d.errs = append(d.errs, contractError{c, err})
+			}
+		}
+	}
+
+	return d
+}
+
+// contractError is the error of compiling contract.
+type contractError struct {
+	contract *ast.Contract
+	err      error
+}
+
+func (e contractError) Error() string {
+	return e.err.Error()
+}
+
+// importer returns the importer of libraries relative to document,
+// it is nil if document isn't a file.
+func (d *document) importer() parse.Importer {
+	if d.path == "" {
+		return nil
+	}
+
+	return parse.NewFileImporter(d.path)
+}
+
+// diagnostics returns the errors of document. Errors in imported
+// library are reported at its import statement.
+func (d *document) diagnostics() []Diagnostic {
+	diagnostics := make([]Diagnostic, 0)
+
+	for _, err := range d.errs {
+		var r Range
+		switch e := err.(type) {
+		case contractError:
+			r = d.rangeOf(d.contractName(e.contract))
+		case parse.FileError:
+			r = d.importRange(e.File)
+		default:
+			if token, ok := parse.ErrorToken(err); ok {
+				r = Range{d.position(int(token.Offset)), d.position(int(token.Offset) + len(token.Val))}
+			}
+		}
+
+		diagnostics = append(diagnostics, Diagnostic{
+			Range:    r,
+			Severity: SeverityError,
+			Source:   "koa",
+			Message:  err.Error(),
+		})
+	}
+
+	return diagnostics
+}
+
+// importRange returns the range of the path in import statement, which
+// imports file. It is the start of document if there is no such import.
+func (d *document) importRange(file string) Range {
+	for i, t := range d.tokens {
+		if t.Type != parse.Import || i+1 == len(d.tokens) || d.tokens[i+1].Type != parse.String {
+			continue
+		}
+
+		path := d.tokens[i+1]
+		if filepath.Join(filepath.Dir(d.path), strings.Trim(path.Val, `"`)) == file {
+			return Range{d.position(int(path.Offset)), d.position(int(path.Offset) + len(path.Val))}
+		}
+	}
+
+	return Range{}
+}
+
+// contractName returns the span of the name of contract, or its keyword
+// if contract has no name.
+func (d *document) contractName(c *ast.Contract) ast.Span {
+	if c.Name != nil {
+		return c.Name.Span
+	}
+
+	span := c.Span
+	span.End = span.Start
+	span.End.Offset += len("contract")
+
+	return span
+}
+
+// identAt returns the index of identifier token at offset. Offset right
+// after the identifier is in it. It returns -1 if there is no identifier.
+func (d *document) identAt(offset int) int {
+	for i, t := range d.tokens {
+		start := int(t.Offset)
+		if start > offset {
+			break
+		}
+
+		if t.Type == parse.Ident && offset <= start+len(t.Val) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// scopeAt returns the innermost scope at offset, it is nil if offset
+// is out of contracts and library.
+func (d *document) scopeAt(offset int) *symbol.Scope {
+	var inner *parse.Scope
+	for i, s := range d.scopes {
+		if offset < s.Span.Start.Offset || offset > s.Span.End.Offset {
+			continue
+		}
+
+		if inner == nil || s.Span.End.Offset-s.Span.Start.Offset < inner.Span.End.Offset-inner.Span.Start.Offset {
+			inner = &d.scopes[i]
+		}
+	}
+
+	if inner == nil {
+		return nil
+	}
+
+	return inner.Symbols
+}
+
+// definition is the declaration which identifier refers to.
+type definition struct {
+	// detail is the declaration in source, which is shown on hover
+	detail string
+
+	// file declares the identifier, it is empty if it is the document
+	file string
+
+	// span is the name in declaration
+	span ast.Span
+}
+
+// resolve returns the declarations which identifier token at index i
+// refers to. Overloaded functions have several declarations.
+func (d *document) resolve(i int) []definition {
+	t := d.tokens[i]
+	scope := d.scopeAt(int(t.Offset))
+
+	if i >= 2 && d.tokens[i-1].Type == parse.Dot && d.tokens[i-2].Type == parse.Ident {
+		return d.member(scope, d.tokens[i-2].Val, t.Val)
+	}
+
+	if scope != nil {
+		switch sym := scope.Get(t.Val).(type) {
+		case *symbol.Integer:
+			return []definition{{detail: "int " + t.Val, span: sym.Name.Span}}
+		case *symbol.Boolean:
+			return []definition{{detail: "bool " + t.Val, span: sym.Name.Span}}
+		case *symbol.String:
+			return []definition{{detail: "string " + t.Val, span: sym.Name.Span}}
+		case *symbol.EnumValue:
+			return []definition{{detail: sym.Enum.Name.Name + " " + t.Val, span: sym.Name.Span}}
+		case *symbol.Enum:
+			return []definition{{detail: sym.Literal.String(), span: sym.Literal.Name.Span}}
+		case *symbol.Modifier:
+			return []definition{{detail: modifierHeader(sym.Literal), span: sym.Literal.Name.Span}}
+		case *symbol.Library:
+			return []definition{{detail: "library " + sym.Literal.Name, file: sym.Literal.File}}
+		case *symbol.Function:
+			if sym.Literal != nil && sym.Library != nil {
+				return []definition{{detail: functionHeader(sym.Literal), file: sym.Library.File, span: sym.Literal.Name.Span}}
+			}
+		}
+	}
+
+	defs := make([]definition, 0)
+	for _, fn := range d.functions(int(t.Offset)) {
+		if fn.Name.Name == t.Val {
+			defs = append(defs, definition{detail: functionHeader(fn), span: fn.Name.Span})
+		}
+	}
+
+	return defs
+}
+
+// member returns the declarations of member of enum or function of
+// library, which is written as left.name in scope.
+func (d *document) member(scope *symbol.Scope, left string, name string) []definition {
+	if scope == nil {
+		return nil
+	}
+
+	defs := make([]definition, 0)
+	switch sym := scope.Get(left).(type) {
+	case *symbol.Enum:
+		for i, m := range sym.Literal.Members {
+			if m.Name == name {
+				defs = append(defs, definition{detail: fmt.Sprintf("%s.%s = %d", left, name, i), span: m.Span})
+			}
+		}
+	case *symbol.Library:
+		for _, fn := range sym.Literal.Functions {
+			if fn.Name.Name == name {
+				defs = append(defs, definition{detail: functionHeader(fn), file: sym.Literal.File, span: fn.Name.Span})
+			}
+		}
+	}
+
+	return defs
+}
+
+// functions returns the functions of contract or library at offset.
+func (d *document) functions(offset int) []*ast.FunctionLiteral {
+	if d.library != nil {
+		return d.library.Functions
+	}
+
+	if d.file != nil {
+		for _, c := range d.file.Contracts {
+			if c.Start.Offset <= offset && offset <= c.End.Offset {
+				return c.Functions
+			}
+		}
+	}
+
+	return nil
+}
+
+// hover returns the declaration of identifier at offset.
+func (d *document) hover(offset int) (*Hover, bool) {
+	i := d.identAt(offset)
+	if i < 0 {
+		return nil, false
+	}
+
+	defs := d.resolve(i)
+	if len(defs) == 0 {
+		return nil, false
+	}
+
+	details := make([]string, 0)
+	for _, def := range defs {
+		details = append(details, def.detail)
+	}
+
+	t := d.tokens[i]
+	return &Hover{
+		Contents: MarkupContent{
+			Kind:  "markdown",
+			Value: "```koa\n" + strings.Join(details, "\n") + "\n```",
+		},
+		Range: Range{d.position(int(t.Offset)), d.position(int(t.Offset) + len(t.Val))},
+	}, true
+}
+
+// definition returns the locations of declaration which identifier at
+// offset refers to.
+func (d *document) definition(offset int) []Location {
+	locations := make([]Location, 0)
+
+	i := d.identAt(offset)
+	if i < 0 {
+		return locations
+	}
+
+	for _, def := range d.resolve(i) {
+		if def.file == "" {
+			locations = append(locations, Location{URI: d.uri, Range: d.rangeOf(def.span)})
+			continue
+		}
+
+		location := Location{URI: pathURI(def.file)}
+		if text, err := ioutil.ReadFile(def.file); err == nil {
+			location.Range = newSource(string(text)).rangeOf(def.span)
+		}
+		locations = append(locations, location)
+	}
+
+	return locations
+}
+
+// completion returns the names which can be written at offset. After
+// the name of enum or library and dot, they are its members. Otherwise,
+// they are the names in scope, the functions and the keywords.
+func (d *document) completion(offset int) []CompletionItem {
+	items := make([]CompletionItem, 0)
+
+	// k is the last token before the word being written
+	k := -1
+	for i, t := range d.tokens {
+		if int(t.Offset) >= offset {
+			break
+		}
+		k = i
+	}
+	if k >= 0 && d.tokens[k].Type == parse.Ident && int(d.tokens[k].Offset)+len(d.tokens[k].Val) >= offset {
+		k--
+	}
+
+	scope := d.scopeAt(offset)
+	if k >= 1 && d.tokens[k].Type == parse.Dot && d.tokens[k-1].Type == parse.Ident {
+		if scope == nil {
+			return items
+		}
+
+		switch sym := scope.Get(d.tokens[k-1].Val).(type) {
+		case *symbol.Enum:
+			for _, m := range sym.Literal.Members {
+				items = append(items, CompletionItem{Label: m.Name, Kind: CompletionEnumMember})
+			}
+		case *symbol.Library:
+			for _, fn := range sym.Literal.Functions {
+				items = append(items, CompletionItem{Label: fn.Name.Name, Kind: CompletionFunction, Detail: functionHeader(fn)})
+			}
+		}
+		return items
+	}
+
+	names := make(map[string]bool)
+	add := func(item CompletionItem) {
+		if !names[item.Label] {
+			names[item.Label] = true
+			items = append(items, item)
+		}
+	}
+
+	for s := scope; s != nil; s = s.GetOuter() {
+		symbols := s.GetSymbols()
+
+		keys := make([]string, 0)
+		for k := range symbols {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, name := range keys {
+			if item, ok := completionItem(name, symbols[name], offset); ok {
+				add(item)
+			}
+		}
+	}
+
+	for _, fn := range d.functions(offset) {
+		add(CompletionItem{Label: fn.Name.Name, Kind: CompletionFunction, Detail: functionHeader(fn)})
+	}
+
+	for _, k := range parse.Keywords() {
+		add(CompletionItem{Label: k, Kind: CompletionKeyword})
+	}
+
+	return items
+}
+
+// completionItem returns the item of symbol. Variables declared after
+// offset can't be used, so they are omitted.
+func completionItem(name string, sym symbol.Symbol, offset int) (CompletionItem, bool) {
+	var ident *ast.Identifier
+	item := CompletionItem{Label: name, Kind: CompletionVariable}
+
+	switch sym := sym.(type) {
+	case *symbol.Integer:
+		ident, item.Detail = sym.Name, "int"
+	case *symbol.Boolean:
+		ident, item.Detail = sym.Name, "bool"
+	case *symbol.String:
+		ident, item.Detail = sym.Name, "string"
+	case *symbol.EnumValue:
+		ident, item.Detail = sym.Name, sym.Enum.Name.Name
+	case *symbol.Enum:
+		item.Kind, item.Detail = CompletionEnum, sym.Literal.String()
+	case *symbol.Modifier:
+		item.Kind, item.Detail = CompletionFunction, modifierHeader(sym.Literal)
+	case *symbol.Library:
+		item.Kind = CompletionModule
+	case *symbol.Function:
+		if sym.Literal == nil {
+			return item, false
+		}
+		item.Kind, item.Detail = CompletionFunction, functionHeader(sym.Literal)
+	}
+
+	if ident != nil && ident.Start.Offset > offset {
+		return item, false
+	}
+
+	return item, true
+}
+
+// symbols returns the outline of document. Contracts have their enums,
+// modifiers and functions in the order of declaration.
+func (d *document) symbols() []DocumentSymbol {
+	symbols := make([]DocumentSymbol, 0)
+
+	if d.library != nil {
+		for _, fn := range d.library.Functions {
+			symbols = append(symbols, d.functionSymbol(fn, SymbolFunction))
+		}
+		return symbols
+	}
+
+	if d.file == nil {
+		return symbols
+	}
+
+	for _, c := range d.file.Contracts {
+		name := "contract"
+		if c.Name != nil {
+			name = c.Name.Name
+		}
+
+		children := make([]DocumentSymbol, 0)
+		for _, e := range c.Enums {
+			members := make([]DocumentSymbol, 0)
+			for _, m := range e.Members {
+				members = append(members, DocumentSymbol{
+					Name:           m.Name,
+					Kind:           SymbolEnumMember,
+					Range:          d.rangeOf(m.Span),
+					SelectionRange: d.rangeOf(m.Span),
+				})
+			}
+
+			children = append(children, DocumentSymbol{
+				Name:           e.Name.Name,
+				Kind:           SymbolEnum,
+				Range:          d.rangeOf(e.Span),
+				SelectionRange: d.rangeOf(e.Name.Span),
+				Children:       members,
+			})
+		}
+
+		for _, m := range c.Modifiers {
+			children = append(children, DocumentSymbol{
+				Name:           m.Name.Name,
+				Detail:         modifierHeader(m),
+				Kind:           SymbolMethod,
+				Range:          d.rangeOf(m.Span),
+				SelectionRange: d.rangeOf(m.Name.Span),
+			})
+		}
+
+		for _, fn := range c.Functions {
+			children = append(children, d.functionSymbol(fn, SymbolMethod))
+		}
+
+		sort.SliceStable(children, func(i, j int) bool {
+			return before(children[i].Range.Start, children[j].Range.Start)
+		})
+
+		symbols = append(symbols, DocumentSymbol{
+			Name:           name,
+			Kind:           SymbolClass,
+			Range:          d.rangeOf(c.Span),
+			SelectionRange: d.rangeOf(d.contractName(c)),
+			Children:       children,
+		})
+	}
+
+	return symbols
+}
+
+func (d *document) functionSymbol(fn *ast.FunctionLiteral, kind int) DocumentSymbol {
+	return DocumentSymbol{
+		Name:           fn.Name.Name,
+		Detail:         functionHeader(fn),
+		Kind:           kind,
+		Range:          d.rangeOf(fn.Span),
+		SelectionRange: d.rangeOf(fn.Name.Span),
+	}
+}
+
+// format returns the edits which format the document. There is no
+// edit if document has errors or is already formatted.
+func (d *document) format() []TextEdit {
+	edits := make([]TextEdit, 0)
+
+	out, err := format.Source([]byte(d.text), d.importer())
+	if err != nil || string(out) == d.text {
+		return edits
+	}
+
+	return append(edits, TextEdit{
+		Range:   Range{d.position(0), d.position(len(d.text))},
+		NewText: string(out),
+	})
+}
+
+// functionHeader returns the declaration of function without body.
+func functionHeader(fn *ast.FunctionLiteral) string {
+	header := "func " + fn.Name.Name + "(" + parameters(fn.Parameters) + ")"
+	for _, m := range fn.Modifiers {
+		header += " " + m.String()
+	}
+
+	if fn.ReturnType != ast.VoidType {
+		header += " " + ast.TypeName(fn.ReturnType, fn.ReturnEnum)
+	}
+
+	return header
+}
+
+// modifierHeader returns the declaration of modifier without body.
+func modifierHeader(m *ast.ModifierLiteral) string {
+	return "modifier " + m.Name.Name + "(" + parameters(m.Parameters) + ")"
+}
+
+func parameters(params []*ast.ParameterLiteral) string {
+	strs := make([]string, 0)
+	for _, p := range params {
+		strs = append(strs, p.Identifier.Name+" "+ast.TypeName(p.Type, p.Enum))
+	}
+
+	return strings.Join(strs, ", ")
+}
+
+func before(a, b Position) bool {
+	return a.Line < b.Line || a.Line == b.Line && a.Character < b.Character
+}
+
+// uriPath returns the path of file uri. It is empty if uri isn't a file.
+func uriPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return ""
+	}
+
+	return filepath.FromSlash(u.Path)
+}
+
+// pathURI returns the uri of file in path.
+func pathURI(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+
+	u := url.URL{Scheme: "file", Path: filepath.ToSlash(path)}
+	return u.String()
+}
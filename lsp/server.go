@@ -0,0 +1,282 @@
+/*
+ * Copyright 2018-2019 De-labtory
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package lsp implements the language server of koa. Editors talk with
+// the server in language server protocol, and get the diagnostics,
+// hover, definition, document symbols, completion and formatting of
+// koa source.
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Server is the language server which talks with a client through
+// JSON-RPC messages. Client sends the whole text of document when it
+// is changed, and server publishes its diagnostics.
+type Server struct {
+	in  *bufio.Reader
+	out io.Writer
+
+	// docs manage opened documents by their uri
+	docs map[string]*document
+
+	shutdown bool
+}
+
+// NewServer returns the server which reads messages from in and writes
+// messages to out.
+func NewServer(in io.Reader, out io.Writer) *Server {
+	return &Server{
+		in:   bufio.NewReader(in),
+		out:  out,
+		docs: make(map[string]*document),
+	}
+}
+
+// Serve handles the messages from client until client sends exit
+// notification. It returns error if client exits without shutdown or
+// the connection is closed.
+func (s *Server) Serve() error {
+	for {
+		msg, err := readMessage(s.in)
+		if e, ok := err.(*ResponseError); ok {
+			if err := writeMessage(s.out, &message{ID: &null, Error: e}); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if msg.Method == "exit" {
+			if !s.shutdown {
+				return errors.New("exit without shutdown")
+			}
+			return nil
+		}
+
+		result, err := s.handle(msg)
+
+		// notification has no response
+		if msg.ID == nil {
+			continue
+		}
+
+		if err := s.reply(msg.ID, result, err); err != nil {
+			return err
+		}
+	}
+}
+
+var null = json.RawMessage("null")
+
+// reply sends the response of request whose id is id.
+func (s *Server) reply(id *json.RawMessage, result interface{}, err error) error {
+	resp := &message{ID: id}
+
+	if err != nil {
+		e, ok := err.(*ResponseError)
+		if !ok {
+			e = &ResponseError{InternalError, err.Error()}
+		}
+		resp.Error = e
+
+		return writeMessage(s.out, resp)
+	}
+
+	resp.Result = null
+	if result != nil {
+		content, err := json.Marshal(result)
+		if err != nil {
+			return err
+		}
+		resp.Result = content
+	}
+
+	return writeMessage(s.out, resp)
+}
+
+// notify sends the notification of method to client.
+func (s *Server) notify(method string, params interface{}) error {
+	content, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+
+	return writeMessage(s.out, &message{Method: method, Params: content})
+}
+
+// handle handles request or notification, and returns the result.
+// Panic while handling is returned as an internal error, so that
+// server keeps working with incomplete sources.
+func (s *Server) handle(msg *message) (result interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &ResponseError{InternalError, fmt.Sprint(r)}
+		}
+	}()
+
+	if s.shutdown {
+		return nil, &ResponseError{InvalidRequest, "server is shut down"}
+	}
+
+	switch msg.Method {
+	case "initialize":
+		return InitializeResult{
+			Capabilities: ServerCapabilities{
+				TextDocumentSync:           SyncFull,
+				HoverProvider:              true,
+				DefinitionProvider:         true,
+				DocumentSymbolProvider:     true,
+				CompletionProvider:         CompletionOptions{TriggerCharacters: []string{"."}},
+				DocumentFormattingProvider: true,
+			},
+			ServerInfo: ServerInfo{Name: "koa"},
+		}, nil
+
+	case "initialized":
+		return nil, nil
+
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+
+	case "textDocument/didOpen":
+		var params DidOpenTextDocumentParams
+		if err := decode(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		return nil, s.open(params.TextDocument.URI, params.TextDocument.Text)
+
+	case "textDocument/didChange":
+		var params DidChangeTextDocumentParams
+		if err := decode(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		if len(params.ContentChanges) == 0 {
+			return nil, nil
+		}
+		return nil, s.open(params.TextDocument.URI, params.ContentChanges[len(params.ContentChanges)-1].Text)
+
+	case "textDocument/didClose":
+		var params DidCloseTextDocumentParams
+		if err := decode(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		delete(s.docs, params.TextDocument.URI)
+		return nil, s.notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{
+			URI:         params.TextDocument.URI,
+			Diagnostics: []Diagnostic{},
+		})
+
+	case "textDocument/hover":
+		d, offset, err := s.position(msg.Params)
+		if err != nil {
+			return nil, err
+		}
+		if hover, ok := d.hover(offset); ok {
+			return hover, nil
+		}
+		return nil, nil
+
+	case "textDocument/definition":
+		d, offset, err := s.position(msg.Params)
+		if err != nil {
+			return nil, err
+		}
+		return d.definition(offset), nil
+
+	case "textDocument/completion":
+		d, offset, err := s.position(msg.Params)
+		if err != nil {
+			return nil, err
+		}
+		return d.completion(offset), nil
+
+	case "textDocument/documentSymbol":
+		var params DocumentSymbolParams
+		if err := decode(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		d, err := s.document(params.TextDocument.URI)
+		if err != nil {
+			return nil, err
+		}
+		return d.symbols(), nil
+
+	case "textDocument/formatting":
+		var params DocumentFormattingParams
+		if err := decode(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		d, err := s.document(params.TextDocument.URI)
+		if err != nil {
+			return nil, err
+		}
+		return d.format(), nil
+	}
+
+	return nil, &ResponseError{MethodNotFound, "method not found: " + msg.Method}
+}
+
+// open parses the text of document, and publishes its diagnostics.
+func (s *Server) open(uri string, text string) error {
+	d := newDocument(uri, text)
+	s.docs[uri] = d
+
+	return s.notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{
+		URI:         uri,
+		Diagnostics: d.diagnostics(),
+	})
+}
+
+func (s *Server) document(uri string) (*document, error) {
+	d, ok := s.docs[uri]
+	if !ok {
+		return nil, &ResponseError{InvalidParams, "document is not opened: " + uri}
+	}
+
+	return d, nil
+}
+
+// position returns the document and the offset of position in params.
+func (s *Server) position(params json.RawMessage) (*document, int, error) {
+	var p TextDocumentPositionParams
+	if err := decode(params, &p); err != nil {
+		return nil, 0, err
+	}
+
+	d, err := s.document(p.TextDocument.URI)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return d, d.offset(p.Position), nil
+}
+
+func decode(params json.RawMessage, v interface{}) error {
+	if err := json.Unmarshal(params, v); err != nil {
+		return &ResponseError{InvalidParams, err.Error()}
+	}
+
+	return nil
+}
@@ -0,0 +1,443 @@
+/*
+ * Copyright 2018-2019 De-labtory
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// client is a scripted client of language server, which talks with
+// the server in the same process.
+type client struct {
+	t    *testing.T
+	out  io.WriteCloser
+	msgs chan *message
+	id   int
+
+	// done receives the result of Serve
+	done chan error
+}
+
+func newClient(t *testing.T) *client {
+	serverIn, clientOut := io.Pipe()
+	clientIn, serverOut := io.Pipe()
+
+	c := &client{
+		t:    t,
+		out:  clientOut,
+		msgs: make(chan *message, 100),
+		done: make(chan error, 1),
+	}
+
+	go func() {
+		c.done <- NewServer(serverIn, serverOut).Serve()
+		serverOut.Close()
+	}()
+
+	// messages are read in background, because server blocks while
+	// it writes notifications
+	go func() {
+		r := bufio.NewReader(clientIn)
+		for {
+			msg, err := readMessage(r)
+			if err != nil {
+				close(c.msgs)
+				return
+			}
+			c.msgs <- msg
+		}
+	}()
+
+	return c
+}
+
+func (c *client) send(msg *message, params interface{}) {
+	content, err := json.Marshal(params)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	msg.Params = content
+
+	if err := writeMessage(c.out, msg); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+// call sends request and decodes its result into result. It returns
+// the error of response.
+func (c *client) call(method string, params interface{}, result interface{}) *ResponseError {
+	c.id++
+	id := json.RawMessage(strings.Repeat("1", c.id))
+	c.send(&message{ID: &id, Method: method}, params)
+
+	for msg := range c.msgs {
+		if msg.ID == nil || string(*msg.ID) != string(id) {
+			continue
+		}
+
+		if msg.Error != nil {
+			return msg.Error
+		}
+
+		if err := json.Unmarshal(msg.Result, result); err != nil {
+			c.t.Fatalf("%s - invalid result %s: %s", method, msg.Result, err)
+		}
+		return nil
+	}
+
+	c.t.Fatalf("%s - connection is closed", method)
+	return nil
+}
+
+func (c *client) notify(method string, params interface{}) {
+	c.send(&message{Method: method}, params)
+}
+
+// diagnostics waits for the diagnostics of document.
+func (c *client) diagnostics(uri string) []Diagnostic {
+	for msg := range c.msgs {
+		if msg.Method != "textDocument/publishDiagnostics" {
+			continue
+		}
+
+		var params PublishDiagnosticsParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			c.t.Fatal(err)
+		}
+		if params.URI == uri {
+			return params.Diagnostics
+		}
+	}
+
+	c.t.Fatalf("connection is closed before diagnostics of %s", uri)
+	return nil
+}
+
+// at returns the position of n-th(from zero) substr in text, moved
+// right by delta.
+func at(text string, substr string, n int, delta int) Position {
+	offset := -1
+	for i := 0; i <= n; i++ {
+		offset += 1 + strings.Index(text[offset+1:], substr)
+	}
+
+	return newSource(text).position(offset + delta)
+}
+
+func testURI(t *testing.T, name string) string {
+	path, err := filepath.Abs(filepath.Join("../test/import", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pathURI(path)
+}
+
+const wallet = `import "lib/math.koa"
+
+contract Wallet {
+	enum State { Open, Closed }
+
+	modifier positive(n int) {
+		require(n > 0)
+		_
+	}
+
+	func deposit(amount int) positive(amount) int {
+		int total = math.max(amount, 0)
+		State s = State.Open
+		return total
+	}
+
+	func deposit(amount int, bonus int) int {
+		return amount + bonus
+	}
+}
+`
+
+func TestServer(t *testing.T) {
+	c := newClient(t)
+	uri := testURI(t, "wallet.koa")
+	doc := TextDocumentIdentifier{URI: uri}
+
+	var init InitializeResult
+	if err := c.call("initialize", struct{}{}, &init); err != nil {
+		t.Fatal(err)
+	}
+	if caps := init.Capabilities; caps.TextDocumentSync != SyncFull || !caps.HoverProvider || !caps.DefinitionProvider ||
+		!caps.DocumentSymbolProvider || !caps.DocumentFormattingProvider {
+		t.Fatalf("wrong capabilities. got=%+v", caps)
+	}
+	c.notify("initialized", struct{}{})
+
+	// diagnostics
+	c.notify("textDocument/didOpen", DidOpenTextDocumentParams{
+		TextDocument: TextDocumentItem{URI: uri, LanguageID: "koa", Version: 1, Text: "contract {\n\tfunc f() int {\n\t\treturn 1 @ 2\n\t}\n}\n"},
+	})
+	diagnostics := c.diagnostics(uri)
+	if len(diagnostics) != 1 || diagnostics[0].Range.Start != (Position{2, 11}) || diagnostics[0].Severity != SeverityError {
+		t.Fatalf("wrong diagnostics of parse error. got=%+v", diagnostics)
+	}
+
+	c.notify("textDocument/didChange", DidChangeTextDocumentParams{
+		TextDocument:   VersionedTextDocumentIdentifier{URI: uri, Version: 2},
+		ContentChanges: []TextDocumentContentChangeEvent{{Text: "contract {\n\tfunc f() string {\n\t\treturn \"123456789\"\n\t}\n}\n"}},
+	})
+	diagnostics = c.diagnostics(uri)
+	if len(diagnostics) != 1 || !strings.Contains(diagnostics[0].Message, "Length of string") || diagnostics[0].Range.Start != (Position{0, 0}) {
+		t.Fatalf("wrong diagnostics of compile error. got=%+v", diagnostics)
+	}
+
+	c.notify("textDocument/didChange", DidChangeTextDocumentParams{
+		TextDocument:   VersionedTextDocumentIdentifier{URI: uri, Version: 3},
+		ContentChanges: []TextDocumentContentChangeEvent{{Text: wallet}},
+	})
+	if diagnostics := c.diagnostics(uri); len(diagnostics) != 0 {
+		t.Fatalf("wallet should have no diagnostics. got=%+v", diagnostics)
+	}
+
+	// hover
+	hovers := []struct {
+		pos      Position
+		expected string
+	}{
+		{at(wallet, "total", 1, 2), "int total"},
+		{at(wallet, "amount", 1, 0), "int amount"},
+		{at(wallet, "max", 0, 3), "func max(a int, b int) int"},
+		{at(wallet, "Open", 1, 1), "State.Open = 0"},
+		{at(wallet, "State", 1, 0), "enum State { Open, Closed }"},
+		{at(wallet, "s =", 0, 0), "State s"},
+		{at(wallet, "positive", 1, 0), "modifier positive(n int)"},
+		{at(wallet, "math", 1, 0), "library math"},
+		{at(wallet, "deposit", 1, 0), "func deposit(amount int) positive(amount) int\nfunc deposit(amount int, bonus int) int"},
+	}
+
+	for i, test := range hovers {
+		var hover *Hover
+		if err := c.call("textDocument/hover", TextDocumentPositionParams{doc, test.pos}, &hover); err != nil {
+			t.Fatal(err)
+		}
+
+		if expected := "```koa\n" + test.expected + "\n```"; hover == nil || hover.Contents.Value != expected {
+			t.Errorf("hovers[%d] - wrong hover. expected=%q, got=%+v", i, expected, hover)
+		}
+	}
+
+	var hover *Hover
+	if err := c.call("textDocument/hover", TextDocumentPositionParams{doc, Position{1, 0}}, &hover); err != nil || hover != nil {
+		t.Errorf("hover on empty line should be null. got=%+v, %v", hover, err)
+	}
+
+	// definition
+	mathURI := testURI(t, "lib/math.koa")
+	definitions := []struct {
+		pos      Position
+		expected []Location
+	}{
+		{at(wallet, "total", 1, 0), []Location{{uri, Range{at(wallet, "total", 0, 0), at(wallet, "total", 0, 5)}}}},
+		{at(wallet, "max", 0, 0), []Location{{mathURI, Range{Position{2, 5}, Position{2, 8}}}}},
+		{at(wallet, "Open", 1, 0), []Location{{uri, Range{at(wallet, "Open", 0, 0), at(wallet, "Open", 0, 4)}}}},
+		{at(wallet, "deposit", 0, 0), []Location{
+			{uri, Range{at(wallet, "deposit", 0, 0), at(wallet, "deposit", 0, 7)}},
+			{uri, Range{at(wallet, "deposit", 1, 0), at(wallet, "deposit", 1, 7)}},
+		}},
+		{Position{1, 0}, []Location{}},
+	}
+
+	for i, test := range definitions {
+		var locations []Location
+		if err := c.call("textDocument/definition", TextDocumentPositionParams{doc, test.pos}, &locations); err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(locations, test.expected) {
+			t.Errorf("definitions[%d] - wrong locations. expected=%+v, got=%+v", i, test.expected, locations)
+		}
+	}
+
+	// document symbols
+	var symbols []DocumentSymbol
+	if err := c.call("textDocument/documentSymbol", DocumentSymbolParams{doc}, &symbols); err != nil {
+		t.Fatal(err)
+	}
+
+	names := make([]string, 0)
+	for _, s := range symbols {
+		names = append(names, s.Name)
+		for _, child := range s.Children {
+			names = append(names, s.Name+"."+child.Name+": "+child.Detail)
+		}
+	}
+	expectedNames := []string{
+		"Wallet",
+		"Wallet.State: ",
+		"Wallet.positive: modifier positive(n int)",
+		"Wallet.deposit: func deposit(amount int) positive(amount) int",
+		"Wallet.deposit: func deposit(amount int, bonus int) int",
+	}
+	if !reflect.DeepEqual(names, expectedNames) {
+		t.Errorf("wrong document symbols. expected=%v, got=%v", expectedNames, names)
+	}
+
+	// completion
+	completions := []struct {
+		pos      Position
+		included []string
+		excluded []string
+	}{
+		{at(wallet, "return total", 0, 0), []string{"s", "total", "amount", "State", "positive", "math", "deposit", "return", "if"}, nil},
+		{at(wallet, "int total", 0, 0), []string{"amount", "deposit"}, []string{"total", "s", "bonus"}},
+		{at(wallet, "Open", 1, 0), []string{"Open", "Closed"}, []string{"return", "total"}},
+		{at(wallet, "max", 0, 0), []string{"max", "clamp"}, []string{"State"}},
+	}
+
+	for i, test := range completions {
+		var items []CompletionItem
+		if err := c.call("textDocument/completion", TextDocumentPositionParams{doc, test.pos}, &items); err != nil {
+			t.Fatal(err)
+		}
+
+		labels := make(map[string]bool)
+		for _, item := range items {
+			labels[item.Label] = true
+		}
+
+		for _, l := range test.included {
+			if !labels[l] {
+				t.Errorf("completions[%d] - %s should be completed. got=%+v", i, l, items)
+			}
+		}
+		for _, l := range test.excluded {
+			if labels[l] {
+				t.Errorf("completions[%d] - %s should not be completed", i, l)
+			}
+		}
+	}
+
+	// formatting
+	messy := strings.Replace(strings.Replace(wallet, "\t", "  ", -1), "amount, 0", "amount,0", 1)
+	c.notify("textDocument/didChange", DidChangeTextDocumentParams{
+		TextDocument:   VersionedTextDocumentIdentifier{URI: uri, Version: 4},
+		ContentChanges: []TextDocumentContentChangeEvent{{Text: messy}},
+	})
+	c.diagnostics(uri)
+
+	var edits []TextEdit
+	if err := c.call("textDocument/formatting", DocumentFormattingParams{doc}, &edits); err != nil {
+		t.Fatal(err)
+	}
+
+	end := newSource(messy).position(len(messy))
+	if len(edits) != 1 || edits[0].NewText != wallet || edits[0].Range != (Range{Position{}, end}) {
+		t.Errorf("wrong formatting edits. got=%+v", edits)
+	}
+
+	c.notify("textDocument/didChange", DidChangeTextDocumentParams{
+		TextDocument:   VersionedTextDocumentIdentifier{URI: uri, Version: 5},
+		ContentChanges: []TextDocumentContentChangeEvent{{Text: wallet}},
+	})
+	c.diagnostics(uri)
+
+	if err := c.call("textDocument/formatting", DocumentFormattingParams{doc}, &edits); err != nil || len(edits) != 0 {
+		t.Errorf("formatted document should have no edits. got=%+v, %v", edits, err)
+	}
+
+	// errors of requests
+	if err := c.call("textDocument/hover", TextDocumentPositionParams{TextDocumentIdentifier{"file:///none.koa"}, Position{}}, &hover); err == nil || err.Code != InvalidParams {
+		t.Errorf("hover on unknown document should fail with InvalidParams. got=%v", err)
+	}
+	if err := c.call("workspace/symbol", struct{}{}, &hover); err == nil || err.Code != MethodNotFound {
+		t.Errorf("unknown method should fail with MethodNotFound. got=%v", err)
+	}
+
+	c.notify("textDocument/didClose", DidCloseTextDocumentParams{doc})
+	if diagnostics := c.diagnostics(uri); len(diagnostics) != 0 {
+		t.Errorf("diagnostics should be cleared on close. got=%+v", diagnostics)
+	}
+
+	// shutdown and exit
+	var result interface{}
+	if err := c.call("shutdown", nil, &result); err != nil || result != nil {
+		t.Fatalf("shutdown should return null. got=%v, %v", result, err)
+	}
+	if err := c.call("textDocument/hover", TextDocumentPositionParams{doc, Position{}}, &hover); err == nil || err.Code != InvalidRequest {
+		t.Errorf("request after shutdown should fail with InvalidRequest. got=%v", err)
+	}
+
+	c.notify("exit", nil)
+	if err := <-c.done; err != nil {
+		t.Fatalf("Serve() should return nil after shutdown. got=%v", err)
+	}
+}
+
+func TestServer_exitWithoutShutdown(t *testing.T) {
+	c := newClient(t)
+	c.notify("exit", nil)
+
+	if err := <-c.done; err == nil {
+		t.Fatal("Serve() should fail when client exits without shutdown")
+	}
+}
+
+func TestReadMessage(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		err      string
+	}{
+		{
+			input:    "Content-Length: 17\r\n\r\n{\"method\":\"exit\"}",
+			expected: "exit",
+		},
+		{
+			input: "Content-Length: abc\r\n\r\n",
+			err:   `invalid Content-Length "abc"`,
+		},
+		{
+			input: "Content-Length: -1\r\n\r\n",
+			err:   "invalid Content-Length -1",
+		},
+		{
+			input: "Content-Length: 33554433\r\n\r\n",
+			err:   "invalid Content-Length 33554433",
+		},
+	}
+
+	for i, test := range tests {
+		msg, err := readMessage(bufio.NewReader(strings.NewReader(test.input)))
+		if test.err != "" {
+			if err == nil || err.Error() != test.err {
+				t.Errorf("test[%d] - Invalid error - expected=%s, got=%v", i, test.err, err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("test[%d] - readMessage() returns error: %v", i, err)
+		}
+
+		if msg.Method != test.expected {
+			t.Errorf("test[%d] - Invalid method - expected=%s, got=%s", i, test.expected, msg.Method)
+		}
+	}
+}
@@ -18,6 +18,11 @@ package opcode
 
 import "errors"
 
+// Version is the version of opcode set. It is increased when the
+// meaning of an opcode changes, so that the VM can refuse the
+// bytecode compiled for the other opcode set.
+const Version uint8 = 1
+
 type Type uint8
 
 const (
@@ -72,6 +77,28 @@ const (
 	//
 	Mod Type = 0x05
 
+	// Pop the first two items in the stack.
+	// Calculate logical-and popped two items and push to the stack.
+	// It is used for the '&&' operator, see BitAnd for the '&' operator.
+	//
+	// Ex)
+	// [a]
+	// [b]  ==> [a&&b]
+	// [x]      [x]
+	//
+	And Type = 0x06
+
+	// Pop the first two items in the stack.
+	// Calculate logical-or popped two items and push to the stack.
+	// It is used for the '||' operator, see BitOr for the '|' operator.
+	//
+	// Ex)
+	// [a]
+	// [b]  ==> [a||b]
+	// [x]      [x]
+	//
+	Or Type = 0x07
+
 	// Pop the first two items in the stack.
 	// Calculate bit-and popped two items and push to the stack.
 	//
@@ -80,7 +107,7 @@ const (
 	// [b]  ==> [a&b]
 	// [x]      [x]
 	//
-	And Type = 0x06
+	BitAnd Type = 0x08
 
 	// Pop the first two items in the stack.
 	// Calculate bit-or popped two items and push to the stack.
@@ -90,7 +117,50 @@ const (
 	// [b]  ==> [a|b]
 	// [x]      [x]
 	//
-	Or Type = 0x07
+	BitOr Type = 0x09
+
+	// Pop the first two items in the stack.
+	// Calculate bit-xor popped two items and push to the stack.
+	//
+	// Ex)
+	// [a]
+	// [b]  ==> [a^b]
+	// [x]      [x]
+	//
+	BitXor Type = 0x0a
+
+	// Pop the first item in the stack.
+	// Flip every bits of the item and push it to the stack.
+	//
+	// Ex)
+	// [a]       [~a]
+	// [b]  ==>  [b]
+	// [x]       [x]
+	//
+	BitNot Type = 0x0b
+
+	// Pop the first two items in the stack.
+	// Shift the second popped item to the left by the first popped item
+	// and push to the stack. The shift count must not be negative.
+	//
+	// Ex)
+	// [b]
+	// [a]  ==> [a<<b]
+	// [x]      [x]
+	//
+	Shl Type = 0x0c
+
+	// Pop the first two items in the stack.
+	// Shift the second popped item to the right by the first popped item
+	// and push to the stack. The sign bit is preserved (arithmetic shift)
+	// and the shift count must not be negative.
+	//
+	// Ex)
+	// [b]
+	// [a]  ==> [a>>b]
+	// [x]      [x]
+	//
+	Shr Type = 0x0d
 
 	// Pop the first two items in the stack.
 	// Check if the left operand(first popped item) is less than the right operand(second popped item).
@@ -277,6 +347,96 @@ const (
 
 	// Jump to last position (Terminate the contract)
 	Exit Type = 0x33
+
+	// Stop the execution with error, the result of execution is discarded.
+	// It is used when the condition of require is false.
+	Revert Type = 0x34
+
+	// Call the function of another contract and push its return value.
+	// Pop the number of arguments, the arguments, the function selector
+	// and the address of contract. The contract runs with a fresh memory,
+	// and its error stops the execution of caller.
+	//
+	// Ex)
+	// [n]
+	// [arg n]
+	// ...
+	// [arg 1]
+	// [selector]
+	// [address]  ==>  [return value]
+	// [y]             [y]
+	Call Type = 0x35
+
+	// Pop the key and push the value of key in the storage of running
+	// contract. The value of key which is never stored is zero.
+	//
+	// Ex)
+	// [key]  ==>  [value]
+	// [y]         [y]
+	SLoad Type = 0x36
+
+	// Pop the value and key, and store the value of key in the storage
+	// of running contract.
+	//
+	// Ex)
+	// [value]
+	// [key]  ==>  [y]
+	// [y]
+	SStore Type = 0x37
+
+	// Pop the number of data and the data, and record them as a log
+	// of running contract.
+	//
+	// Ex)
+	// [n]
+	// [data n]
+	// ...
+	// [data 1]  ==>  [y]
+	// [y]
+	Log Type = 0x38
+
+	// Pop the first item in the stack.
+	// Convert the integer to its decimal string and push it to the stack.
+	// Fails if the string is longer than 8 bytes.
+	//
+	// Ex)
+	// [123]  ==>  ["123"]
+	// [x]         [x]
+	IntToString Type = 0x40
+
+	// Pop the first item in the stack.
+	// Convert the decimal string to the integer and push it to the stack.
+	// Fails if the string is not a decimal integer.
+	//
+	// Ex)
+	// ["-12"]  ==>  [-12]
+	// [x]           [x]
+	StringToInt Type = 0x41
+
+	// Pop the first item in the stack.
+	// Push false if the integer is zero, otherwise push true.
+	//
+	// Ex)
+	// [5]  ==>  [true]
+	// [x]       [x]
+	IntToBool Type = 0x42
+
+	// Pop the first item in the stack.
+	// Convert the boolean to "true" or "false" and push it to the stack.
+	//
+	// Ex)
+	// [true]  ==>  ["true"]
+	// [x]          [x]
+	BoolToString Type = 0x43
+
+	// Pop the first item in the stack.
+	// Convert "true" or "false" to the boolean and push it to the stack.
+	// Fails if the string is neither of them.
+	//
+	// Ex)
+	// ["false"]  ==>  [false]
+	// [x]             [x]
+	StringToBool Type = 0x44
 )
 
 // Change the bytecode of an opcode to string.
@@ -296,6 +456,18 @@ func (p Type) String() (string, error) {
 		return "And", nil
 	case 0x07:
 		return "Or", nil
+	case 0x08:
+		return "BitAnd", nil
+	case 0x09:
+		return "BitOr", nil
+	case 0x0a:
+		return "BitXor", nil
+	case 0x0b:
+		return "BitNot", nil
+	case 0x0c:
+		return "Shl", nil
+	case 0x0d:
+		return "Shr", nil
 	case 0x10:
 		return "LT", nil
 	case 0x11:
@@ -338,6 +510,26 @@ func (p Type) String() (string, error) {
 		return "SWAP", nil
 	case 0x33:
 		return "Exit", nil
+	case 0x34:
+		return "Revert", nil
+	case 0x35:
+		return "Call", nil
+	case 0x36:
+		return "SLoad", nil
+	case 0x37:
+		return "SStore", nil
+	case 0x38:
+		return "Log", nil
+	case 0x40:
+		return "IntToString", nil
+	case 0x41:
+		return "StringToInt", nil
+	case 0x42:
+		return "IntToBool", nil
+	case 0x43:
+		return "BoolToString", nil
+	case 0x44:
+		return "StringToBool", nil
 
 	default:
 		return "", errors.New("String() error - Not defined opcode")
@@ -55,6 +55,30 @@ func TestType_String(t *testing.T) {
 			opcode.Or,
 			"Or",
 		},
+		{
+			opcode.BitAnd,
+			"BitAnd",
+		},
+		{
+			opcode.BitOr,
+			"BitOr",
+		},
+		{
+			opcode.BitXor,
+			"BitXor",
+		},
+		{
+			opcode.BitNot,
+			"BitNot",
+		},
+		{
+			opcode.Shl,
+			"Shl",
+		},
+		{
+			opcode.Shr,
+			"Shr",
+		},
 		{
 			opcode.LT,
 			"LT",
@@ -139,6 +163,46 @@ func TestType_String(t *testing.T) {
 			opcode.Exit,
 			"Exit",
 		},
+		{
+			opcode.Revert,
+			"Revert",
+		},
+		{
+			opcode.Call,
+			"Call",
+		},
+		{
+			opcode.SLoad,
+			"SLoad",
+		},
+		{
+			opcode.SStore,
+			"SStore",
+		},
+		{
+			opcode.Log,
+			"Log",
+		},
+		{
+			opcode.IntToString,
+			"IntToString",
+		},
+		{
+			opcode.StringToInt,
+			"StringToInt",
+		},
+		{
+			opcode.IntToBool,
+			"IntToBool",
+		},
+		{
+			opcode.BoolToString,
+			"BoolToString",
+		},
+		{
+			opcode.StringToBool,
+			"StringToBool",
+		},
 		{
 			0x97,
 			"String() error - Not defined opcode",
@@ -0,0 +1,75 @@
+/*
+ * Copyright 2018-2019 De-labtory
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package parse_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/DE-labtory/koa/parse"
+)
+
+// largeSource returns the source of contract which has n functions
+// of m statements.
+func largeSource(n int, m int) string {
+	var b strings.Builder
+
+	b.WriteString("contract {\n")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "\n\t// f%d returns the sum of a and b, or its half.\n", i)
+		fmt.Fprintf(&b, "\tfunc f%d(a int, b int) int {\n", i)
+		b.WriteString("\t\tint c = 0\n")
+		for j := 0; j < m; j++ {
+			fmt.Fprintf(&b, "\t\tc = a + b * %d\n", j)
+			fmt.Fprintf(&b, "\t\tif (c > 10 && a != b) {\n\t\t\tstring s%d = \"statement %d\"\n\t\t}\n", j, j)
+		}
+		b.WriteString("\t\treturn c - 1\n\t}\n")
+	}
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+func BenchmarkLexer_NextToken(b *testing.B) {
+	input := largeSource(100, 50)
+
+	b.SetBytes(int64(len(input)))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		l := parse.NewLexer(input)
+		for t := l.NextToken(); t.Type != parse.Eof; t = l.NextToken() {
+		}
+	}
+}
+
+func BenchmarkParseSource(b *testing.B) {
+	input := largeSource(100, 50)
+
+	b.SetBytes(int64(len(input)))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		buf := parse.NewTokenBuffer(parse.NewLexer(input))
+		if _, err := parse.ParseSource(buf, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+/*
+ * Copyright 2018-2019 De-labtory
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package parse
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrorList is the list of errors found while parsing source. Parser
+// recovers from error at the boundary of statement and function, so
+// that all errors in source are reported at once.
+type ErrorList []error
+
+// Add appends err to the list.
+func (l *ErrorList) Add(err error) {
+	*l = append(*l, err)
+}
+
+// Sort sorts the list by the position of errors. Errors in the same file
+// are sorted by offset, and errors without position come last.
+func (l ErrorList) Sort() {
+	sort.SliceStable(l, func(i, j int) bool {
+		fi, oi, pi := errorPos(l[i])
+		fj, oj, pj := errorPos(l[j])
+
+		if fi != fj {
+			return fi < fj
+		}
+		if pi != pj {
+			return pi
+		}
+		return oi < oj
+	})
+}
+
+// Error returns the errors in the list, each error in a line.
+func (l ErrorList) Error() string {
+	errs := make([]string, 0, len(l))
+	for _, err := range l {
+		errs = append(errs, err.Error())
+	}
+
+	return strings.Join(errs, "\n")
+}
+
+// Err returns the sorted list, or nil if the list is empty.
+func (l ErrorList) Err() error {
+	if len(l) == 0 {
+		return nil
+	}
+
+	l.Sort()
+	return l
+}
+
+// inFile returns the list whose errors are in file. The errors already
+// in other file, such as the errors of imported library, are kept.
+func (l ErrorList) inFile(file string) ErrorList {
+	list := make(ErrorList, 0, len(l))
+	for _, err := range l {
+		if _, ok := err.(FileError); !ok {
+			err = FileError{File: file, Err: err}
+		}
+		list = append(list, err)
+	}
+
+	list.Sort()
+	return list
+}
+
+// FileError is the error in file.
+type FileError struct {
+	File string
+	Err  error
+}
+
+func (e FileError) Error() string {
+	return fmt.Sprintf("%s: %s", e.File, e.Err)
+}
+
+// errorPos returns the file and the offset where err happened. It
+// returns false if err has no position.
+func errorPos(err error) (string, int, bool) {
+	file := ""
+	if e, ok := err.(FileError); ok {
+		file = e.File
+	}
+
+	token, ok := ErrorToken(err)
+	return file, int(token.Offset), ok
+}
+
+// ErrorToken returns the token in source where err happened. It returns
+// false if err has no position. The token of FileError is in its file.
+func ErrorToken(err error) (Token, bool) {
+	switch e := err.(type) {
+	case FileError:
+		return ErrorToken(e.Err)
+	case Error:
+		return e.Source, true
+	case ExpectError:
+		return e.Source, true
+	case DupSymError:
+		return e.Source, true
+	case PrefixError:
+		return e.Source, true
+	case NotExistSymError:
+		return e.Source, true
+	default:
+		return Token{}, false
+	}
+}
@@ -0,0 +1,84 @@
+/*
+ * Copyright 2018-2019 De-labtory
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package parse_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DE-labtory/koa/parse"
+)
+
+func TestErrorList_Sort(t *testing.T) {
+	list := parse.ErrorList{
+		errors.New("no position"),
+		parse.FileError{File: "b.koa", Err: parse.Error{Source: parse.Token{Offset: 3}, Reason: "b3"}},
+		parse.ExpectError{Source: parse.Token{Line: 2, Column: 4, Offset: 20}, Expected: parse.Rbrace},
+		parse.FileError{File: "b.koa", Err: parse.Error{Source: parse.Token{Offset: 1}, Reason: "b1"}},
+		parse.DupSymError{Source: parse.Token{Line: 1, Column: 2, Val: "a", Offset: 10}},
+	}
+
+	list.Sort()
+
+	expected := "[line 1, column 2] symbol [a] already exist\n" +
+		"[line 2, column 4] Expected [RBRACE], but got [ILLEGAL]\n" +
+		"no position\n" +
+		"b.koa: [line 0, column 0] [ILLEGAL] b1\n" +
+		"b.koa: [line 0, column 0] [ILLEGAL] b3"
+
+	if list.Error() != expected {
+		t.Fatalf("wrong sorted errors.\nexpected=\n%s\ngot=\n%s", expected, list.Error())
+	}
+}
+
+func TestErrorList_Err(t *testing.T) {
+	if err := (parse.ErrorList{}).Err(); err != nil {
+		t.Fatalf("Err() of empty list should be nil. got=%v", err)
+	}
+
+	list := parse.ErrorList{errors.New("a")}
+	if err := list.Err(); err == nil || err.Error() != "a" {
+		t.Fatalf("Err() returns wrong error. got=%v", err)
+	}
+}
+
+func TestErrorToken(t *testing.T) {
+	token := parse.Token{Type: parse.Ident, Val: "a", Line: 1, Column: 3, Offset: 7}
+
+	tests := []struct {
+		err error
+		ok  bool
+	}{
+		{parse.Error{Source: token, Reason: "error"}, true},
+		{parse.NotExistSymError{Source: token}, true},
+		{parse.FileError{File: "a.koa", Err: parse.DupSymError{Source: token}}, true},
+		{parse.FileError{File: "a.koa", Err: errors.New("no position")}, false},
+		{errors.New("no position"), false},
+	}
+
+	for i, test := range tests {
+		got, ok := parse.ErrorToken(test.err)
+		if ok != test.ok {
+			t.Errorf("tests[%d] - wrong result. expected=%t, got=%t", i, test.ok, ok)
+			continue
+		}
+
+		if ok && got != token {
+			t.Errorf("tests[%d] - wrong token. expected=%v, got=%v", i, token, got)
+		}
+	}
+}
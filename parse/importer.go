@@ -0,0 +1,136 @@
+/*
+ * Copyright 2018-2019 De-labtory
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package parse
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/DE-labtory/koa/ast"
+)
+
+// FileImporter imports libraries from files. Import path is relative
+// to the directory of the importing file, and library is named after
+// its file name without extension. e.g. "lib/math.koa" is math
+//
+// Each library is parsed only once even if it is imported by several
+// files, and importing the file which is being parsed is an import cycle.
+type FileImporter struct {
+	// libraries manage parsed library by its file path
+	libraries map[string]*ast.Library
+
+	// names manage file path of library by its name
+	names map[string]string
+
+	// loading is the stack of files being parsed, the last one
+	// is the file which imports now
+	loading []string
+}
+
+func NewFileImporter(path string) *FileImporter {
+	return &FileImporter{
+		libraries: make(map[string]*ast.Library),
+		names:     make(map[string]string),
+		loading:   []string{filepath.Clean(path)},
+	}
+}
+
+func (i *FileImporter) Import(path string) (*ast.Library, error) {
+	file := filepath.Join(filepath.Dir(i.loading[len(i.loading)-1]), path)
+
+	for n, f := range i.loading {
+		if f == file {
+			cycle := append(append([]string{}, i.loading[n:]...), file)
+			return nil, fmt.Errorf("import cycle: %s", strings.Join(cycle, " -> "))
+		}
+	}
+
+	if library, ok := i.libraries[file]; ok {
+		return library, nil
+	}
+
+	name, err := libraryName(file)
+	if err != nil {
+		return nil, err
+	}
+
+	if f, ok := i.names[name]; ok {
+		return nil, fmt.Errorf("library %s is imported from both %s and %s", name, f, file)
+	}
+
+	src, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	i.loading = append(i.loading, file)
+	library, err := ParseLibrary(NewTokenBuffer(NewLexer(string(src))), name, i)
+	i.loading = i.loading[:len(i.loading)-1]
+
+	if list, ok := err.(ErrorList); ok {
+		return nil, list.inFile(file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", file, err)
+	}
+
+	library.File = file
+	i.libraries[file] = library
+	i.names[name] = file
+
+	return library, nil
+}
+
+// libraryName returns the name of library in file, which is the
+// file name without extension. The name should be an identifier.
+func libraryName(file string) (string, error) {
+	name := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+
+	if !isIdentifier(name) {
+		return "", fmt.Errorf("%s can't be a library name", name)
+	}
+
+	return name, nil
+}
+
+// ParseFile creates an abstract syntax tree of source file.
+// Libraries are imported relative to the file.
+//
+// Errors of the file and its libraries are returned in ErrorList,
+// whose errors are FileError.
+func ParseFile(path string) (*ast.File, error) {
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := ParseSource(NewTokenBuffer(NewLexer(string(src))), NewFileImporter(path))
+	for _, c := range file.Contracts {
+		c.File = path
+	}
+
+	if list, ok := err.(ErrorList); ok {
+		return file, list.inFile(path)
+	}
+	if err != nil {
+		return file, fmt.Errorf("%s: %s", path, err)
+	}
+
+	return file, nil
+}
@@ -0,0 +1,199 @@
+/*
+ * Copyright 2018-2019 De-labtory
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package parse_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/DE-labtory/koa/parse"
+)
+
+func TestParseFile(t *testing.T) {
+	file, err := parse.ParseFile("../test/import/token.koa")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(file.Contracts) != 1 {
+		t.Fatalf("ParseFile() with wrong number of contracts. got=%d", len(file.Contracts))
+	}
+	contract := file.Contracts[0]
+
+	if len(contract.Imports) != 1 || contract.Imports[0].Path != "lib/math.koa" {
+		t.Fatalf("ParseFile() with wrong imports. got=%v", contract.Imports)
+	}
+
+	if start := contract.Imports[0].Position().Start; start.String() != "1:1" {
+		t.Errorf("import starts at wrong position. expected=1:1, got=%s", start)
+	}
+
+	if start := contract.Position().Start; start.String() != "3:1" {
+		t.Errorf("contract starts at wrong position. expected=3:1, got=%s", start)
+	}
+
+	if span := file.Position(); span.Start.Offset != 0 || span.End != contract.Position().End {
+		t.Errorf("file has wrong position. got=%v", span)
+	}
+
+	if start := contract.Imports[0].Library.Functions[0].Position().Start; start.String() != "3:1" {
+		t.Errorf("function of library starts at wrong position. expected=3:1, got=%s", start)
+	}
+
+	expected := []string{"util", "math"}
+	libraries := contract.Libraries()
+	if len(libraries) != len(expected) {
+		t.Fatalf("Libraries() with wrong length. expected=%d, got=%d", len(expected), len(libraries))
+	}
+
+	for i, l := range libraries {
+		if l.Name != expected[i] {
+			t.Fatalf("Libraries()[%d] with wrong name. expected=%s, got=%s", i, expected[i], l.Name)
+		}
+	}
+}
+
+func TestFileImporter_Import(t *testing.T) {
+	tests := []struct {
+		files       map[string]string
+		expectedErr string
+	}{
+		{
+			files: map[string]string{
+				"main.koa":       "import \"lib/a.koa\"\nimport \"b.koa\"\ncontract {\n}\n",
+				"lib/a.koa":      "import \"../b.koa\"\nfunc one() int {\nreturn b.two() - 1\n}\n",
+				"b.koa":          "func two() int {\nreturn 2\n}\n",
+				"lib/unused.koa": "func unused() {\n}\n",
+			},
+			expectedErr: "",
+		},
+		{
+			files: map[string]string{
+				"main.koa":  "import \"lib/a.koa\"\ncontract {\n}\n",
+				"lib/a.koa": "import \"../main.koa\"\nfunc one() int {\nreturn 1\n}\n",
+			},
+			expectedErr: "import cycle: {dir}/main.koa -> {dir}/lib/a.koa -> {dir}/main.koa",
+		},
+		{
+			files: map[string]string{
+				"main.koa":    "import \"lib/a.koa\"\nimport \"other/a.koa\"\ncontract {\n}\n",
+				"lib/a.koa":   "func one() int {\nreturn 1\n}\n",
+				"other/a.koa": "func one() int {\nreturn 1\n}\n",
+			},
+			expectedErr: "library a is imported from both {dir}/lib/a.koa and {dir}/other/a.koa",
+		},
+		{
+			files: map[string]string{
+				"main.koa":    "import \"lib/a-b.koa\"\ncontract {\n}\n",
+				"lib/a-b.koa": "func one() int {\nreturn 1\n}\n",
+			},
+			expectedErr: "a-b can't be a library name",
+		},
+		{
+			files: map[string]string{
+				"main.koa":  "import \"lib/a.koa\"\ncontract {\n}\n",
+				"lib/a.koa": "contract {\n}\n",
+			},
+			expectedErr: "{dir}/lib/a.koa: [line 0, column 8] Expected [FUNCTION], but got [CONTRACT]",
+		},
+	}
+
+	for i, test := range tests {
+		dir, err := ioutil.TempDir("", "koa")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+
+		for name, src := range test.files {
+			path := filepath.Join(dir, name)
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := ioutil.WriteFile(path, []byte(src), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		_, err = parse.ParseFile(filepath.Join(dir, "main.koa"))
+
+		if test.expectedErr == "" && err != nil {
+			t.Fatalf("test[%d] - ParseFile() returns error: %v", i, err)
+		}
+
+		if test.expectedErr != "" {
+			expected := strings.Replace(test.expectedErr, "{dir}", dir, -1)
+
+			if err == nil || !strings.Contains(err.Error(), expected) {
+				t.Fatalf("test[%d] - ParseFile() with wrong error.\nexpected to contain=%s\ngot=%v", i, expected, err)
+			}
+		}
+	}
+}
+
+func TestParseFile_errors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "koa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"main.koa": "import \"a.koa\"\ncontract {\nfunc f() int {\nreturn x\n}\nfunc g() {\ny = 1\n}\n}\n",
+		"a.koa":    "func one() int {\nreturn 1 +\n}\nfunc two( {\n}\n",
+	}
+
+	for name, src := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	file, err := parse.ParseFile(filepath.Join(dir, "main.koa"))
+
+	list, ok := err.(parse.ErrorList)
+	if !ok {
+		t.Fatalf("ParseFile() should return ErrorList. got=%v", err)
+	}
+
+	expected := []string{
+		"{dir}/a.koa: [line 2, column 1] [RBRACE] prefix parse function not defined",
+		"{dir}/a.koa: [line 3, column 11] Expected [IDENT], but got [LBRACE]",
+		"{dir}/main.koa: [line 6, column 1] symbol [y] is not exist",
+	}
+
+	if len(list) != len(expected) {
+		t.Fatalf("ParseFile() with wrong number of errors. expected=%d, got=%d\n%v", len(expected), len(list), list)
+	}
+
+	for i, e := range list {
+		if _, ok := e.(parse.FileError); !ok {
+			t.Errorf("errors[%d] should be FileError. got=%T", i, e)
+		}
+
+		if msg := strings.Replace(expected[i], "{dir}", dir, -1); e.Error() != msg {
+			t.Errorf("errors[%d] is wrong.\nexpected=%s\ngot=%s", i, msg, e)
+		}
+	}
+
+	if file == nil || len(file.Contracts) != 1 || len(file.Contracts[0].Functions) != 2 {
+		t.Fatalf("ParseFile() should return the partial file. got=%v", file)
+	}
+}
@@ -27,43 +27,63 @@ type emitter interface {
 	emit(t Token)
 }
 
+// Lexer produces tokens of input on demand. NextToken runs the state
+// functions until a token is emitted, so lexer doesn't need goroutine
+// and can be dropped at any time, e.g. when parser meets an error.
+//
+// Comments are not returned by NextToken, but kept in the lexer, so
+// that formatter can print them again.
 type Lexer struct {
-	tokench chan Token
-}
+	state   *state
+	stateFn stateFn
 
-func NewLexer(input string) *Lexer {
-
-	l := &Lexer{
-		tokench: make(chan Token, 2),
-	}
+	// tokens are emitted but not returned yet. A state function can emit
+	// more than one token, e.g. semicolon and eof at the end of input.
+	tokens []Token
+	head   int
 
-	go l.run(input)
-	return l
+	comments []Token
 }
 
-// run runs the state machine for the lexer.
-func (l *Lexer) run(input string) {
-
-	state := &state{
-		input: input,
-	}
-
-	for stateFn := defaultStateFn; stateFn != nil; {
-		stateFn = stateFn(state, l)
+func NewLexer(input string) *Lexer {
+	return &Lexer{
+		state: &state{
+			input: input,
+		},
+		stateFn: defaultStateFn,
+		tokens:  make([]Token, 0, 2),
 	}
-
-	close(l.tokench)
 }
 
 // emit passes an token back to the client.
 func (l *Lexer) emit(t Token) {
-	l.tokench <- t
+	l.tokens = append(l.tokens, t)
 }
 
-// NextToken returns the next token from the input.
-// Called by the parser, not in the lexing goroutine.
+// NextToken returns the next token from the input except comments.
+// After the end of input, it keeps returning eof.
 func (l *Lexer) NextToken() Token {
-	return <-l.tokench
+	for {
+		for l.head == len(l.tokens) {
+			l.tokens = l.tokens[:0]
+			l.head = 0
+			l.stateFn = l.stateFn(l.state, l)
+		}
+
+		t := l.tokens[l.head]
+		l.head++
+
+		if t.Type != Comment {
+			return t
+		}
+		l.comments = append(l.comments, t)
+	}
+}
+
+// Comments returns the comments of the input read so far, in the order
+// they appear.
+func (l *Lexer) Comments() []Token {
+	return l.comments
 }
 
 // DefaultTokenBuffer is implementation for TokenBuffer interface
@@ -176,7 +196,7 @@ type Pos int
 
 // Cut return a token and set start position to pos
 func (s *state) cut(t TokenType) Token {
-	token := Token{t, s.input[s.start:s.end], s.column, s.line}
+	token := Token{t, s.input[s.start:s.end], s.column, s.line, s.start}
 	s.start = s.end
 
 	return token
@@ -196,7 +216,8 @@ func (s *state) next() rune {
 
 	if r == '\n' {
 		s.line++
-		s.columnBuf = s.column
+		// backup() restores the column before newline.
+		s.columnBuf = s.column - s.width
 		s.column = 0
 	}
 	return r
@@ -254,7 +275,17 @@ func (s *state) acceptRun(valid string) {
 //	EQ     // ==
 //	NOT_EQ // !=
 //
-//	Comma // ,
+//	And   // &
+//	Or    // |
+//	Xor   // ^
+//	Tilde // ~
+//	Shl   // <<
+//	Shr   // >>
+//
+//	Comma    // ,
+//	Question // ?
+//	Colon    // :
+//	Dot      // .
 //
 //	Lparen // (
 //	Rparen // )
@@ -328,13 +359,17 @@ func defaultStateFn(s *state, e emitter) stateFn {
 			e.emit(s.cut(Mod))
 		}
 	case ch == '<':
-		if s.isNextToken('=') {
+		if s.isNextToken('<') {
+			e.emit(s.cut(Shl))
+		} else if s.isNextToken('=') {
 			e.emit(s.cut(LTE))
 		} else {
 			e.emit(s.cut(LT))
 		}
 	case ch == '>':
-		if s.isNextToken('=') {
+		if s.isNextToken('>') {
+			e.emit(s.cut(Shr))
+		} else if s.isNextToken('=') {
 			e.emit(s.cut(GTE))
 		} else {
 			e.emit(s.cut(GT))
@@ -342,11 +377,19 @@ func defaultStateFn(s *state, e emitter) stateFn {
 	case ch == '&':
 		if s.isNextToken('&') {
 			e.emit(s.cut(Land))
+		} else {
+			e.emit(s.cut(And))
 		}
 	case ch == '|':
 		if s.isNextToken('|') {
 			e.emit(s.cut(Lor))
+		} else {
+			e.emit(s.cut(Or))
 		}
+	case ch == '^':
+		e.emit(s.cut(Xor))
+	case ch == '~':
+		e.emit(s.cut(Tilde))
 	case ch == ')':
 		e.emit(s.cut(Rparen))
 		insertSemi = true
@@ -359,6 +402,12 @@ func defaultStateFn(s *state, e emitter) stateFn {
 		e.emit(s.cut(Lbrace))
 	case ch == ',':
 		e.emit(s.cut(Comma))
+	case ch == '?':
+		e.emit(s.cut(Question))
+	case ch == ':':
+		e.emit(s.cut(Colon))
+	case ch == '.':
+		e.emit(s.cut(Dot))
 	case ch == '"':
 		s.backup()
 		return stringStateFn
@@ -393,7 +442,8 @@ func defaultStateFn(s *state, e emitter) stateFn {
 	return defaultStateFn
 }
 
-// commentStateFn scans a comment line or block
+// commentStateFn scans a comment line or block, and emits it as a
+// comment token which lexer keeps aside from the other tokens.
 // comment format : // or /**/
 func commentStateFn(s *state, e emitter) stateFn {
 	switch second := s.next(); {
@@ -404,7 +454,7 @@ func commentStateFn(s *state, e emitter) stateFn {
 			}
 			s.next()
 		}
-		s.cut(Illegal)
+		e.emit(s.cut(Comment))
 	case second == '*':
 		for s.peek() != eof {
 			if s.next() == '*' && s.peek() == '/' {
@@ -412,7 +462,7 @@ func commentStateFn(s *state, e emitter) stateFn {
 				break
 			}
 		}
-		s.cut(Illegal)
+		e.emit(s.cut(Comment))
 	}
 
 	return defaultStateFn
@@ -420,15 +470,19 @@ func commentStateFn(s *state, e emitter) stateFn {
 
 // stringStateFn scans a string
 // After reading a string, it returns defaultStateFn.
-// string_literal = `"` { unicode_value | byte_value } `"`
+// Escape sequences are kept in the token, and parser decodes them.
+// string_literal = [ "b" | "hex" ] `"` { unicode_value | byte_value | escape } `"`
+// escape = `\` ( "n" | "t" | "r" | `"` | `\` | "x" hex_digit hex_digit )
 func stringStateFn(s *state, e emitter) stateFn {
 	s.insertSemi = true
 	s.next() //accept '"'
 
-	for s.next() != '"' {
-		ch := s.peek()
-		if ch == '\n' || ch == eof {
-			e.emit(Token{Illegal, "String not terminated", s.end, s.line})
+	for ch := s.next(); ch != '"'; ch = s.next() {
+		if ch == '\\' && !isStringEnd(s.peek()) {
+			s.next() //escaped '"' doesn't end string
+		}
+		if isStringEnd(s.peek()) {
+			e.emit(Token{Illegal, "String not terminated", s.end, s.line, s.start})
 			break
 		}
 	}
@@ -437,19 +491,24 @@ func stringStateFn(s *state, e emitter) stateFn {
 	return defaultStateFn
 }
 
-// NumberStateFn scans an alphanumeric. ex) 123, 4001, 232
+// NumberStateFn scans an alphanumeric. ex) 123, 4001, 232, 0xff, 0b101, 1_000
 // After reading Number, it returns DefaultStateFn.
-// number = { decimal_digit }
+// Letters right after number are read together, so that parser reports
+// the invalid number. ex) 0b12, 12ab, 010
+// number = "0" | ( "1" … "9" ) { decimal_digit | "_" }
+//        | "0" ( "x" | "X" ) hex_digit { hex_digit | "_" }
+//        | "0" ( "b" | "B" ) binary_digit { binary_digit | "_" }
 func numberStateFn(s *state, e emitter) stateFn {
 	s.insertSemi = true
 	const digits = "0123456789"
 
 	if !s.accept(digits) {
-		e.emit(Token{Illegal, "Invalid function call: numberStateFn", s.end, s.line})
+		e.emit(Token{Illegal, "Invalid function call: numberStateFn", s.end, s.line, s.start})
 		return defaultStateFn
 	}
 
-	for s.accept(digits) {
+	for isAlphaNumeric(s.peek()) {
+		s.next()
 	}
 
 	e.emit(s.cut(Int))
@@ -466,7 +525,7 @@ func numberStateFn(s *state, e emitter) stateFn {
 func identifierStateFn(s *state, e emitter) stateFn {
 	s.insertSemi = true
 	if !(unicode.IsLetter(s.peek()) || s.peek() == '_') {
-		errToken := Token{Illegal, "Invalid function call: identifierStateFn", s.end, s.line}
+		errToken := Token{Illegal, "Invalid function call: identifierStateFn", s.end, s.line, s.start}
 		e.emit(errToken)
 		return defaultStateFn
 	}
@@ -476,6 +535,11 @@ func identifierStateFn(s *state, e emitter) stateFn {
 		s.next()
 	}
 
+	//b"..." and hex"..." are byte strings, whose prefix is read here
+	if isStringPrefix(s.input[s.start:s.end]) && s.peek() == '"' {
+		return stringStateFn
+	}
+
 	//lookup keywords map and return tokenType
 	tok := LookupIdent(s.input[s.start:s.end])
 	e.emit(s.cut(tok))
@@ -489,7 +553,7 @@ func spaceStateFn(s *state, e emitter) stateFn {
 	const spaceChars = " \t\r"
 
 	if !s.accept(spaceChars) {
-		errToken := Token{Illegal, "Invalid function call: spaceStateFn", s.end, s.line}
+		errToken := Token{Illegal, "Invalid function call: spaceStateFn", s.end, s.line, s.start}
 		e.emit(errToken)
 		return defaultStateFn
 	}
@@ -508,6 +572,14 @@ func isStartingLetter(r rune) bool {
 	return r == '_' || unicode.IsLetter(r)
 }
 
+func isStringPrefix(word string) bool {
+	return word == "b" || word == "hex"
+}
+
+func isStringEnd(r rune) bool {
+	return r == '\n' || r == eof
+}
+
 func isSpace(r rune) bool {
 	return r == ' ' || r == '\t' || r == '\r'
 }
@@ -36,6 +36,11 @@ func TestState_cut(t *testing.T) {
 			inputTokenType: Int,
 			expectedToken:  Token{Line: 0, Val: "5", Type: Int, Column: 1},
 		},
+		{
+			inputState:     state{input: "a\n  bc", start: 4, end: 6, column: 4, line: 1},
+			inputTokenType: Ident,
+			expectedToken:  Token{Line: 1, Val: "bc", Type: Ident, Column: 4, Offset: 4},
+		},
 	}
 
 	for i, test := range tests {
@@ -292,7 +297,17 @@ func TestLex_defaultStateFn(t *testing.T) {
 	//	EQ     // ==
 	//	NOT_EQ // !=
 	//
-	//	Comma // ,
+	//	And   // &
+	//	Or    // |
+	//	Xor   // ^
+	//	Tilde // ~
+	//	Shl   // <<
+	//	Shr   // >>
+	//
+	//	Comma    // ,
+	//	Question // ?
+	//	Colon    // :
+	//	Dot      // .
 	//
 	//	Lparen // (
 	//	Rparen // )
@@ -312,7 +327,16 @@ func TestLex_defaultStateFn(t *testing.T) {
 		{">", GT},
 		{"==", EQ},
 		{"!=", NOT_EQ},
+		{"&", And},
+		{"|", Or},
+		{"^", Xor},
+		{"~", Tilde},
+		{"<<", Shl},
+		{">>", Shr},
 		{",", Comma},
+		{"?", Question},
+		{":", Colon},
+		{".", Dot},
 		{"(", Lparen},
 		{")", Rparen},
 		{"{", Lbrace},
@@ -345,6 +369,9 @@ func TestStringStateFn(t *testing.T) {
 		expectedVal  string
 	}{
 		{"\nsomeString\"", String, "\nsomeString\""},
+		{`"a\"b"`, String, `"a\"b"`},
+		{`"a\\"b`, String, `"a\\"`},
+		{`"\x41\n"`, String, `"\x41\n"`},
 	}
 
 	for i, test := range tests {
@@ -373,6 +400,14 @@ func TestCommentStateFn(t *testing.T) {
 	for i, test := range tests {
 		s := &state{input: test.input}
 		e := MockEmitter{}
+		e.emitFunc = func(tok Token) {
+			if tok.Type != Comment {
+				t.Errorf("tests[%d] - Wrong token type", i)
+			}
+			if tok.Val != test.input {
+				t.Errorf("tests[%d] - comment wrong. Expected=%s, got=%s", i, test.input, tok.Val)
+			}
+		}
 
 		commentStateFn(s, e)
 
@@ -436,6 +471,12 @@ func TestNumberStateFn(t *testing.T) {
 		{"909", Int, "909"},
 		{"909", Int, "909"},
 		{"012", Int, "012"}, //accept 0122
+		{"0xff", Int, "0xff"},
+		{"0XdeadBEEF", Int, "0XdeadBEEF"},
+		{"0b1010", Int, "0b1010"},
+		{"1_000_000", Int, "1_000_000"},
+		{"0b12+3", Int, "0b12"}, //parser reports invalid number
+		{"12ab", Int, "12ab"},
 		{"_121", Illegal, "Invalid function call: numberStateFn"},
 		{"+-121", Illegal, "Invalid function call: numberStateFn"},
 		{"+_11", Illegal, "Invalid function call: numberStateFn"},
@@ -478,12 +519,18 @@ func TestIdentifierStateFn(t *testing.T) {
 		{"return", Return, "return"},
 		{"true", True, "true"},
 		{"false", False, "false"},
+		{`b"ab"`, String, `b"ab"`},
+		{`hex"0a0b" + 1`, String, `hex"0a0b"`},
+		{"hex", Ident, "hex"},
+		{`c"ab"`, Ident, "c"},
 	}
 
 	for i, test := range tests {
 		s := &state{input: test.input}
 		e := MockEmitter{}
+		emitted := false
 		e.emitFunc = func(tok Token) {
+			emitted = true
 			if tok.Type != test.expectedType {
 				t.Errorf("tests[%d] - Wrong token type", i)
 			}
@@ -491,7 +538,11 @@ func TestIdentifierStateFn(t *testing.T) {
 				t.Errorf("tests[%d] - rune wrong. Expected=%s, got=%s", i, test.expectedVal, tok.Val)
 			}
 		}
-		identifierStateFn(s, e)
+
+		// byte string is scanned by stringStateFn after its prefix
+		for fn := stateFn(identifierStateFn); !emitted; {
+			fn = fn(s, e)
+		}
 	}
 }
 
@@ -43,7 +43,7 @@ func TestLexer_NextToken(t *testing.T) {
 			a-- //comment after semicolon
 			
 			string this = "abc"
-			++ -- && || += -= *= /= %= <= >= == != = { } , "string"
+			++ -- && || & | ^ ~ << >> += -= *= /= %= <= >= == != = { } , "string"
 			}
 			return 5
 	}
@@ -104,6 +104,12 @@ func TestLexer_NextToken(t *testing.T) {
 		{parse.Dec, "--"},
 		{parse.Land, "&&"},
 		{parse.Lor, "||"},
+		{parse.And, "&"},
+		{parse.Or, "|"},
+		{parse.Xor, "^"},
+		{parse.Tilde, "~"},
+		{parse.Shl, "<<"},
+		{parse.Shr, ">>"},
 		{parse.PlusAssign, "+="},
 		{parse.MinusAssign, "-="},
 		{parse.AsteriskAssign, "*="},
@@ -138,6 +144,66 @@ func TestLexer_NextToken(t *testing.T) {
 	}
 }
 
+func TestLexer_NextToken_eof(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []parse.TokenType
+	}{
+		{
+			"int a = 1",
+			[]parse.TokenType{parse.IntType, parse.Ident, parse.Assign, parse.Int, parse.Semicolon, parse.Eof, parse.Eof, parse.Eof},
+		},
+		{
+			"a\n\"b",
+			[]parse.TokenType{parse.Ident, parse.Semicolon, parse.Illegal, parse.String, parse.Semicolon, parse.Eof, parse.Eof},
+		},
+		{
+			"",
+			[]parse.TokenType{parse.Eof, parse.Eof},
+		},
+	}
+
+	for i, test := range tests {
+		l := parse.NewLexer(test.input)
+
+		for j, expected := range test.expected {
+			if tok := l.NextToken(); tok.Type != expected {
+				t.Errorf("tests[%d] - tokens[%d] has wrong type. Expected=%s, got=%s",
+					i, j, parse.TokenTypeMap[expected], parse.TokenTypeMap[tok.Type])
+			}
+		}
+	}
+}
+
+func TestLexer_Comments(t *testing.T) {
+	input := `// head
+int a = 1 /* tail */
+/* block
+comment */ return a`
+
+	l := parse.NewLexer(input)
+	for l.NextToken().Type != parse.Eof {
+	}
+
+	expected := []parse.Token{
+		{Type: parse.Comment, Val: "// head", Offset: 0},
+		{Type: parse.Comment, Val: "/* tail */", Offset: 18},
+		{Type: parse.Comment, Val: "/* block\ncomment */", Offset: 29},
+	}
+
+	comments := l.Comments()
+	if len(comments) != len(expected) {
+		t.Fatalf("wrong number of comments. expected=%d, got=%d", len(expected), len(comments))
+	}
+
+	for i, c := range comments {
+		if c.Type != expected[i].Type || c.Val != expected[i].Val || c.Offset != expected[i].Offset {
+			t.Errorf("comments[%d] is wrong. expected=%q at %d, got=%q at %d",
+				i, expected[i].Val, expected[i].Offset, c.Val, c.Offset)
+		}
+	}
+}
+
 func TestTokenBuffer(t *testing.T) {
 	input := `
 	contract { //lexer does not return this comment as token
@@ -154,7 +220,7 @@ func TestTokenBuffer(t *testing.T) {
 			a-- //comment after semicolon
 			
 			string this = "abc"
-			++ -- && || += -= *= /= %= <= >= == != = { } , "string"
+			++ -- && || & | ^ ~ << >> += -= *= /= %= <= >= == != = { } , "string"
 			}
 	}
 	`
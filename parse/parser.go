@@ -17,8 +17,10 @@
 package parse
 
 import (
+	"encoding/hex"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/DE-labtory/koa/symbol"
 
@@ -42,6 +44,12 @@ var operatorMap = map[TokenType]ast.Operator{
 	NOT_EQ:   ast.NOT_EQ,
 	Land:     ast.LAND,
 	Lor:      ast.LOR,
+	And:      ast.BitAnd,
+	Or:       ast.BitOr,
+	Xor:      ast.BitXor,
+	Tilde:    ast.BitNot,
+	Shl:      ast.Shl,
+	Shr:      ast.Shr,
 }
 
 // datastructureMap maps TokenType with Datastructure. By doing this
@@ -63,13 +71,18 @@ type precedence int
 const (
 	_ precedence = iota
 	LOWEST
+	TERNARY     // ? :
 	LOR         // ||
 	LAND        // &&
 	EQUALS      // ==
 	LESSGREATER // > or <
+	BITOR       // |
+	BITXOR      // ^
+	BITAND      // &
+	SHIFT       // << or >>
 	SUM         // +
 	PRODUCT     // *
-	PREFIX      // -X or !X
+	PREFIX      // -X, !X or ~X
 	CALL        // function(X)
 )
 
@@ -90,10 +103,18 @@ var precedenceMap = map[TokenType]precedence{
 	NOT_EQ: EQUALS,
 
 	Lparen: CALL,
-
-	Eol:  LOWEST,
-	Land: LAND,
-	Lor:  LOR,
+	Dot:    CALL,
+
+	Eol:      LOWEST,
+	Question: TERNARY,
+	Land:     LAND,
+	Lor:      LOR,
+
+	Or:  BITOR,
+	Xor: BITXOR,
+	And: BITAND,
+	Shl: SHIFT,
+	Shr: SHIFT,
 }
 
 // PeekNumber restrict peek count from the TokenBuffer
@@ -217,35 +238,79 @@ func (e NotExistSymError) Error() string {
 }
 
 type (
-	prefixParseFn func(TokenBuffer) (ast.Expression, error)
-	infixParseFn  func(TokenBuffer, ast.Expression) (ast.Expression, error)
+	prefixParseFn func() (ast.Expression, error)
+	infixParseFn  func(ast.Expression) (ast.Expression, error)
 )
 
-var prefixParseFnMap = map[TokenType]prefixParseFn{}
-var infixParseFnMap = map[TokenType]infixParseFn{}
+// Parser keeps the state of parsing a source, so that sources can be
+// parsed at the same time with their own parsers. Parser reads tokens
+// from buffer and remembers the last token read, so that it knows
+// where a node ends.
+type Parser struct {
+	buf      TokenBuffer
+	importer Importer
+
+	// scope keeps symbols that shows on tokens, every time scope meet symbol,
+	// trying to check whether symbol with same name already exist, if true
+	// then throw error, if not, add that symbol to scope.
+	scope *symbol.Scope
+
+	// scopes records the scopes of source with the range they cover.
+	scopes []Scope
+
+	// errs collects the errors of source being parsed. If errs is nil,
+	// parser stops at the first error and returns it.
+	errs *ErrorList
+
+	prefixParseFnMap map[TokenType]prefixParseFn
+	infixParseFnMap  map[TokenType]infixParseFn
+
+	last Token
+	read bool
+}
+
+// NewParser returns the parser of tokens in buf. Libraries imported in
+// source are loaded with importer, which can be nil if source has no
+// import statements.
+func NewParser(buf TokenBuffer, importer Importer) *Parser {
+	p := &Parser{
+		buf:              buf,
+		importer:         importer,
+		scope:            symbol.NewScope(),
+		prefixParseFnMap: map[TokenType]prefixParseFn{},
+		infixParseFnMap:  map[TokenType]infixParseFn{},
+	}
+	p.initParseFnMap()
 
-// scope keeps symbols that shows on tokens, every time scope meet symbol,
-// trying to check whether symbol with same name already exist, if true
-// then throw error, if not, add that symbol to scope.
-var scope *symbol.Scope
+	return p
+}
 
 // updateScopeSymbol checks whether token value is exist in scope first,
 // if exist, then throw error, if not, make symbol with token value then add
 // to scope
-func updateScopeSymbol(ident Token, keyword Token) error {
-	if s := scope.Get(ident.Val); s != nil {
+func (p *Parser) updateScopeSymbol(ident Token, keyword Token) error {
+	if s := p.scope.Get(ident.Val); s != nil {
 		return DupSymError{ident}
 	}
 
 	switch keyword.Type {
 	case IntType:
-		scope.Set(ident.Val, &symbol.Integer{Name: &ast.Identifier{Name: ident.Val}})
+		p.scope.Set(ident.Val, &symbol.Integer{Name: newIdentifier(ident)})
 	case BoolType:
-		scope.Set(ident.Val, &symbol.Boolean{Name: &ast.Identifier{Name: ident.Val}})
+		p.scope.Set(ident.Val, &symbol.Boolean{Name: newIdentifier(ident)})
 	case StringType:
-		scope.Set(ident.Val, &symbol.String{Name: &ast.Identifier{Name: ident.Val}})
+		p.scope.Set(ident.Val, &symbol.String{Name: newIdentifier(ident)})
 	case Function:
-		scope.Set(ident.Val, &symbol.Function{Name: ident.Val})
+		p.scope.Set(ident.Val, &symbol.Function{Name: ident.Val})
+	case Ident:
+		enum, ok := p.lookupEnum(keyword)
+		if !ok {
+			return Error{
+				keyword,
+				fmt.Sprintf("unknown type [%s]", keyword.Val),
+			}
+		}
+		p.scope.Set(ident.Val, &symbol.EnumValue{Name: newIdentifier(ident), Enum: enum})
 	default:
 		return Error{
 			keyword,
@@ -256,69 +321,566 @@ func updateScopeSymbol(ident Token, keyword Token) error {
 	return nil
 }
 
+// lookupEnum returns enum declaration which token names.
+// If token isn't the name of enum, returns false.
+func (p *Parser) lookupEnum(token Token) (*ast.EnumLiteral, bool) {
+	if token.Type != Ident {
+		return nil, false
+	}
+
+	enum, ok := p.scope.Get(token.Val).(*symbol.Enum)
+	if !ok {
+		return nil, false
+	}
+
+	return enum.Literal, true
+}
+
+// lookupModifier returns modifier definition which token names.
+// If token isn't the name of modifier, returns false.
+func (p *Parser) lookupModifier(token Token) (*ast.ModifierLiteral, bool) {
+	if token.Type != Ident {
+		return nil, false
+	}
+
+	modifier, ok := p.scope.Get(token.Val).(*symbol.Modifier)
+	if !ok {
+		return nil, false
+	}
+
+	return modifier.Literal, true
+}
+
 // enterScope creates new scope than converts it to existing scope
-func enterScope() {
+func (p *Parser) enterScope() {
 	innerScope := symbol.NewScope()
-	innerScope.SetOuter(scope)
+	innerScope.SetOuter(p.scope)
 
-	scope.AppendInner(innerScope)
-	scope = innerScope
+	p.scope.AppendInner(innerScope)
+	p.scope = innerScope
 }
 
 // leaveScope converts current scope's outer to existing scope
-func leaveScope() {
-	outerScope := scope.GetOuter()
-	scope = outerScope
+func (p *Parser) leaveScope() {
+	outerScope := p.scope.GetOuter()
+	p.scope = outerScope
+}
+
+// Scope is the scope of symbols declared in a range of source, which
+// is a contract, a library, a function, a modifier or a block.
+type Scope struct {
+	Span    ast.Span
+	Symbols *symbol.Scope
+}
+
+// recordScope records current scope with the range of node, which
+// declares the symbols of scope.
+func (p *Parser) recordScope(node ast.Node) {
+	p.scopes = append(p.scopes, Scope{Span: node.Position(), Symbols: p.scope})
 }
 
-// Parse creates an abstract syntax tree
+// Scopes returns the scopes of source parsed, so that tools can find
+// the symbols at a position. Inner scope comes before its outer scope,
+// and the scopes of declarations having errors are omitted.
+func (p *Parser) Scopes() []Scope {
+	return p.scopes
+}
+
+// collectErrors starts to collect the errors of source. It returns the
+// function which ends collecting and returns the errors.
+func (p *Parser) collectErrors() func() error {
+	p.errs = &ErrorList{}
+
+	return func() error {
+		list := *p.errs
+		p.errs = nil
+		return list.Err()
+	}
+}
+
+// report records err, so that parser can go on after err. It returns
+// false if errors aren't collected, then err should be returned.
+func (p *Parser) report(err error) bool {
+	if p.errs == nil {
+		return false
+	}
+
+	if list, ok := err.(ErrorList); ok {
+		*p.errs = append(*p.errs, list...)
+		return true
+	}
+
+	p.errs.Add(err)
+	return true
+}
+
+// skipStatement skips tokens to the end of statement where error
+// happened, so that parsing goes on from the next statement. Block in
+// statement is skipped as a whole, and the end of block is not skipped.
+func skipStatement(buf TokenBuffer) {
+	depth := 0
+	for {
+		switch buf.Peek(CURRENT).Type {
+		case Eof:
+			return
+		case Lbrace:
+			depth++
+		case Rbrace:
+			if depth == 0 {
+				return
+			}
+			depth--
+		case Semicolon:
+			if depth == 0 {
+				buf.Read()
+				return
+			}
+		}
+		buf.Read()
+	}
+}
+
+// skipDeclaration skips tokens to the next declaration of enum, modifier
+// or function, or to the end of contract. Body of declaration is skipped
+// as a whole.
+func skipDeclaration(buf TokenBuffer) {
+	depth := 0
+	for {
+		switch buf.Peek(CURRENT).Type {
+		case Eof:
+			return
+		case Lbrace:
+			depth++
+		case Rbrace:
+			if depth == 0 {
+				return
+			}
+			depth--
+		case Enum, Modifier, Function:
+			if depth == 0 {
+				return
+			}
+		}
+		buf.Read()
+	}
+}
+
+// Importer loads the library of import path. Path is passed as it is
+// written in import statement, so importer decides where it is.
+type Importer interface {
+	Import(path string) (*ast.Library, error)
+}
+
+// Parse creates an abstract syntax tree of the only contract in source
+//
+// If source has errors, Parse returns all of them in ErrorList with the
+// contract parsed except the statements and functions having errors.
 func Parse(buf TokenBuffer) (*ast.Contract, error) {
-	initParseFnMap()
+	file, keywords, err := NewParser(buf, nil).parseSource()
+	if err != nil {
+		if len(file.Contracts) == 0 {
+			return nil, err
+		}
+		return file.Contracts[0], err
+	}
+
+	if len(file.Contracts) != 1 {
+		return nil, Error{
+			keywords[1],
+			fmt.Sprintf("expected one contract, but got %d", len(file.Contracts)),
+		}
+	}
+
+	return file.Contracts[0], nil
+}
+
+// ParseSource creates an abstract syntax tree of source which has
+// import statements and one or more contracts. Libraries are loaded
+// with importer, and importing without importer is an error.
+//
+// Each contract has its own scope, and contracts in source should have
+// different names. Contract without name should be the only one in source.
+//
+// Errors in source are returned in ErrorList sorted by position, and
+// the file has the contracts parsed except the parts having errors.
+func ParseSource(buf TokenBuffer, importer Importer) (*ast.File, error) {
+	return NewParser(buf, importer).ParseSource()
+}
+
+// ParseSource parses source as the package function ParseSource does.
+// Parser should not be used again after parsing.
+func (p *Parser) ParseSource() (*ast.File, error) {
+	file, _, err := p.parseSource()
+	return file, err
+}
+
+// parseSource parse source like ParseSource, and also returns contract
+// keyword tokens of each contract in source.
+func (p *Parser) parseSource() (*ast.File, []Token, error) {
+	done := p.collectErrors()
+
+	file, keywords := p.parseFile()
+
+	return file, keywords, done()
+}
+
+// parseFile parses source, and reports its errors. It returns the file
+// and contract keyword tokens of each contract in file.
+func (p *Parser) parseFile() (*ast.File, []Token) {
+	start := p.Peek(CURRENT)
+	imports, err := p.parseImportList()
+	if err != nil {
+		p.report(err)
+	}
+
+	file := &ast.File{
+		Imports:   imports,
+		Contracts: []*ast.Contract{},
+	}
+	keywords := []Token{}
+
+	for curTokenIs(p, Contract) {
+		keyword, name := p.Peek(CURRENT), p.Peek(NEXT)
+
+		p.scope = symbol.NewScope()
+		p.declareImports(imports)
+
+		contract, err := p.parseContract()
+		if err != nil {
+			p.report(err)
+			return file, keywords
+		}
+		contract.Imports = imports
+		p.recordScope(contract)
+
+		if err := checkContractName(keyword, name, file.Contracts, contract); err != nil {
+			p.report(err)
+			continue
+		}
+
+		file.Contracts = append(file.Contracts, contract)
+		keywords = append(keywords, keyword)
+	}
+
+	if token := p.Peek(CURRENT); token.Type != Eof || len(file.Contracts) == 0 && len(*p.errs) == 0 {
+		p.report(ExpectError{token, Contract})
+		return file, keywords
+	}
+
+	p.setPosition(file, startPos(start))
+
+	return file, keywords
+}
+
+// checkContractName checks that contract can be declared with the
+// contracts declared before.
+func checkContractName(keyword Token, name Token, contracts []*ast.Contract, contract *ast.Contract) error {
+	for _, c := range contracts {
+		if c.Name == nil || contract.Name == nil {
+			return Error{
+				keyword,
+				"contract without name should be the only contract in source",
+			}
+		}
+
+		if c.Name.Name == contract.Name.Name {
+			return DupSymError{name}
+		}
+	}
 
-	scope = symbol.NewScope()
+	return nil
+}
 
+// parseContract parse contract which consists of enums, modifiers
+// and functions. e.g. contract Escrow { ... }
+func (p *Parser) parseContract() (*ast.Contract, error) {
 	contract := &ast.Contract{}
+	contract.Enums = []*ast.EnumLiteral{}
+	contract.Modifiers = []*ast.ModifierLiteral{}
 	contract.Functions = []*ast.FunctionLiteral{}
 
-	if err := parseContractStart(buf); err != nil {
+	start := p.Peek(CURRENT)
+	name, err := p.parseContractStart()
+	if err != nil {
+		return nil, err
+	}
+	contract.Name = name
+
+	for !curTokenIs(p, Rbrace) && !curTokenIs(p, Eof) {
+		if err := p.parseDeclaration(contract); err != nil {
+			if !p.report(err) {
+				return nil, err
+			}
+
+			skipDeclaration(p)
+		}
+	}
+
+	if err := p.parseContractEnd(); err != nil && !p.report(err) {
 		return nil, err
 	}
 
-	for buf.Peek(CURRENT).Type == Function {
-		fn, err := parseFunctionLiteral(buf)
+	p.setPosition(contract, startPos(start))
+
+	return contract, nil
+}
+
+// parseDeclaration parses a declaration of enum, modifier or function
+// in contract, and adds it to contract.
+func (p *Parser) parseDeclaration(contract *ast.Contract) error {
+	outer := p.scope
+	defer func() {
+		p.scope = outer
+	}()
+
+	switch token := p.Peek(CURRENT); token.Type {
+	case Enum:
+		enum, err := p.parseEnumLiteral()
 		if err != nil {
-			return nil, err
+			return err
+		}
+
+		contract.Enums = append(contract.Enums, enum)
+
+	case Modifier:
+		modifier, err := p.parseModifierLiteral()
+		if err != nil {
+			return err
+		}
+
+		contract.Modifiers = append(contract.Modifiers, modifier)
+
+	case Function:
+		fn, err := p.parseFunctionLiteral()
+		if err != nil {
+			return err
+		}
+
+		if err := checkOverload(token, contract.Functions, fn); err != nil {
+			return err
 		}
 
 		contract.Functions = append(contract.Functions, fn)
+
+	default:
+		p.Read()
+		return ExpectError{token, Rbrace}
 	}
 
-	if err := parseContractEnd(buf); err != nil {
-		return nil, err
+	return nil
+}
+
+// checkOverload checks that function can be declared with the functions
+// declared before. Functions of contract can have the same name, when
+// their parameter types are different. Enum parameter is int, so it
+// can't overload the function whose parameter is int.
+func checkOverload(keyword Token, functions []*ast.FunctionLiteral, fn *ast.FunctionLiteral) error {
+	for _, f := range functions {
+		if f.Signature() == fn.Signature() {
+			return Error{
+				keyword,
+				fmt.Sprintf("function %s is already declared", fn.Signature()),
+			}
+		}
 	}
 
-	return contract, nil
+	return nil
+}
+
+// ParseLibrary creates an abstract syntax tree of library, which
+// consists of import statements and functions. Name is the name
+// of library which qualifies its functions.
+//
+// Function of library can call the functions declared before it
+// without the name of library.
+func ParseLibrary(buf TokenBuffer, name string, importer Importer) (*ast.Library, error) {
+	return NewParser(buf, importer).ParseLibrary(name)
+}
+
+// IsLibrary reports whether src is a library, whose functions are
+// declared without contract.
+func IsLibrary(src string) bool {
+	l := NewLexer(src)
+	for {
+		switch t := l.NextToken(); t.Type {
+		case Import, String, Semicolon:
+			continue
+		case Function:
+			return true
+		default:
+			return false
+		}
+	}
+}
+
+// ParseLibrary parses library as the package function ParseLibrary does.
+// Parser should not be used again after parsing.
+func (p *Parser) ParseLibrary(name string) (*ast.Library, error) {
+	done := p.collectErrors()
+
+	library := p.parseLibrary(name)
+
+	return library, done()
+}
+
+// parseLibrary parses library, and reports its errors.
+func (p *Parser) parseLibrary(name string) *ast.Library {
+	start := p.Peek(CURRENT)
+
+	imports, err := p.parseImportList()
+	if err != nil {
+		p.report(err)
+	}
+
+	p.scope = symbol.NewScope()
+	p.declareImports(imports)
+
+	library := &ast.Library{
+		Name:      name,
+		Imports:   imports,
+		Functions: []*ast.FunctionLiteral{},
+	}
+
+	for !curTokenIs(p, Eof) {
+		if !curTokenIs(p, Function) {
+			p.report(ExpectError{p.Read(), Function})
+			skipDeclaration(p)
+			continue
+		}
+
+		outer := p.scope
+		fn, err := p.parseFunctionLiteral()
+		if err != nil {
+			p.scope = outer
+			p.report(err)
+			skipDeclaration(p)
+
+			// Library isn't enclosed in braces, so right brace here is
+			// the end of the function having error.
+			if curTokenIs(p, Rbrace) {
+				p.Read()
+				consumeSemi(p)
+			}
+			continue
+		}
+
+		p.scope.Set(fn.Name.Name, &symbol.Function{
+			Name:    fn.Name.Name,
+			Literal: fn,
+			Library: library,
+		})
+		library.Functions = append(library.Functions, fn)
+	}
+
+	p.setPosition(library, startPos(start))
+	p.recordScope(library)
+
+	return library
+}
+
+// parseImportList parse import statements at the start of file,
+// and loads the libraries with importer of parser. e.g. import "lib/math.koa"
+func (p *Parser) parseImportList() ([]*ast.Import, error) {
+	imports := []*ast.Import{}
+	names := make(map[string]bool)
+
+	for curTokenIs(p, Import) {
+		i, err := p.parseImport(names)
+		if err != nil {
+			if !p.report(err) {
+				return nil, err
+			}
+
+			skipStatement(p)
+			continue
+		}
+
+		imports = append(imports, i)
+		consumeSemi(p)
+	}
+
+	return imports, nil
+}
+
+// parseImport parse an import statement. Names are the names of
+// libraries imported before. Errors of imported library are returned
+// as they are, because they are in the file of library.
+func (p *Parser) parseImport(names map[string]bool) (*ast.Import, error) {
+	keyword := p.Read()
+
+	token := p.Read()
+	if token.Type != String {
+		return nil, ExpectError{token, String}
+	}
+
+	path, err := unquote(token.Val)
+	if err != nil {
+		return nil, Error{token, err.Error()}
+	}
+
+	if p.importer == nil {
+		return nil, Error{
+			token,
+			fmt.Sprintf("can't import %s without importer", path),
+		}
+	}
+
+	library, err := p.importer.Import(path)
+	if list, ok := err.(ErrorList); ok {
+		return nil, list
+	}
+	if err != nil {
+		return nil, Error{token, err.Error()}
+	}
+
+	if names[library.Name] {
+		return nil, Error{
+			token,
+			fmt.Sprintf("library %s is already imported", library.Name),
+		}
+	}
+	names[library.Name] = true
+
+	i := &ast.Import{Path: path, Library: library}
+	p.setPosition(i, startPos(keyword))
+
+	return i, nil
+}
+
+// declareImports declares imported libraries in current scope,
+// so that their functions can be called with the name of library.
+func (p *Parser) declareImports(imports []*ast.Import) {
+	for _, i := range imports {
+		p.scope.Set(i.Library.Name, &symbol.Library{Literal: i.Library})
+	}
 }
 
 // parseContractStart validates whether given token stream is
-// starts with "contract" keyword with left-brace, otherwise throw error
-func parseContractStart(buf TokenBuffer) error {
-	if err := expectNext(buf, Contract); err != nil {
-		return err
+// starts with "contract" keyword and optional name with left-brace,
+// otherwise throw error. Returns nil if contract has no name.
+func (p *Parser) parseContractStart() (*ast.Identifier, error) {
+	if err := expectNext(p, Contract); err != nil {
+		return nil, err
 	}
-	if err := expectNext(buf, Lbrace); err != nil {
-		return err
+
+	var name *ast.Identifier
+	if curTokenIs(p, Ident) {
+		name = newIdentifier(p.Read())
 	}
-	return nil
+
+	if err := expectNext(p, Lbrace); err != nil {
+		return nil, err
+	}
+	return name, nil
 }
 
 // parseContractEnd validates whether contracts finish with
 // Right-brace, otherwise throw error
-func parseContractEnd(buf TokenBuffer) error {
-	if err := expectNext(buf, Rbrace); err != nil {
+func (p *Parser) parseContractEnd() error {
+	if err := expectNext(p, Rbrace); err != nil {
 		return err
 	}
-	if err := expectNext(buf, Semicolon); err != nil {
+	if err := expectNext(p, Semicolon); err != nil {
 		return err
 	}
 	return nil
@@ -330,51 +892,109 @@ func parseContractEnd(buf TokenBuffer) error {
 //   - infix-parsing function
 //   - prefix-parsing function
 //
-func initParseFnMap() {
-	prefixParseFnMap[Ident] = parseIdentifier
-	prefixParseFnMap[Int] = parseIntegerLiteral
-	prefixParseFnMap[String] = parseStringLiteral
-	prefixParseFnMap[Bang] = parsePrefixExpression
-	prefixParseFnMap[Minus] = parsePrefixExpression
-	prefixParseFnMap[True] = parseBooleanLiteral
-	prefixParseFnMap[False] = parseBooleanLiteral
-	prefixParseFnMap[Lparen] = parseGroupedExpression
-
-	infixParseFnMap[Plus] = parseInfixExpression
-	infixParseFnMap[Minus] = parseInfixExpression
-	infixParseFnMap[Asterisk] = parseInfixExpression
-	infixParseFnMap[Slash] = parseInfixExpression
-	infixParseFnMap[Mod] = parseInfixExpression
-	infixParseFnMap[EQ] = parseInfixExpression
-	infixParseFnMap[NOT_EQ] = parseInfixExpression
-	infixParseFnMap[LT] = parseInfixExpression
-	infixParseFnMap[GT] = parseInfixExpression
-	infixParseFnMap[LTE] = parseInfixExpression
-	infixParseFnMap[GTE] = parseInfixExpression
-	infixParseFnMap[Land] = parseInfixExpression
-	infixParseFnMap[Lor] = parseInfixExpression
-	infixParseFnMap[Lparen] = parseCallExpression
-}
-
-// parseStatement parse statement which don't produce value
-func parseStatement(buf TokenBuffer) (ast.Statement, error) {
-	switch tt := buf.Peek(CURRENT).Type; tt {
+func (p *Parser) initParseFnMap() {
+	p.prefixParseFnMap[Ident] = p.parseIdentifier
+	p.prefixParseFnMap[Int] = p.parseIntegerLiteral
+	p.prefixParseFnMap[String] = p.parseStringLiteral
+	p.prefixParseFnMap[Bang] = p.parsePrefixExpression
+	p.prefixParseFnMap[Minus] = p.parsePrefixExpression
+	p.prefixParseFnMap[Tilde] = p.parsePrefixExpression
+	p.prefixParseFnMap[True] = p.parseBooleanLiteral
+	p.prefixParseFnMap[False] = p.parseBooleanLiteral
+	p.prefixParseFnMap[Lparen] = p.parseGroupedExpression
+	p.prefixParseFnMap[IntType] = p.parseConversionExpression
+	p.prefixParseFnMap[StringType] = p.parseConversionExpression
+	p.prefixParseFnMap[BoolType] = p.parseConversionExpression
+	p.prefixParseFnMap[Call] = p.parseContractCallExpression
+	p.prefixParseFnMap[Load] = p.parseLoadExpression
+
+	p.infixParseFnMap[Plus] = p.parseInfixExpression
+	p.infixParseFnMap[Minus] = p.parseInfixExpression
+	p.infixParseFnMap[Asterisk] = p.parseInfixExpression
+	p.infixParseFnMap[Slash] = p.parseInfixExpression
+	p.infixParseFnMap[Mod] = p.parseInfixExpression
+	p.infixParseFnMap[EQ] = p.parseInfixExpression
+	p.infixParseFnMap[NOT_EQ] = p.parseInfixExpression
+	p.infixParseFnMap[LT] = p.parseInfixExpression
+	p.infixParseFnMap[GT] = p.parseInfixExpression
+	p.infixParseFnMap[LTE] = p.parseInfixExpression
+	p.infixParseFnMap[GTE] = p.parseInfixExpression
+	p.infixParseFnMap[Land] = p.parseInfixExpression
+	p.infixParseFnMap[Lor] = p.parseInfixExpression
+	p.infixParseFnMap[And] = p.parseInfixExpression
+	p.infixParseFnMap[Or] = p.parseInfixExpression
+	p.infixParseFnMap[Xor] = p.parseInfixExpression
+	p.infixParseFnMap[Shl] = p.parseInfixExpression
+	p.infixParseFnMap[Shr] = p.parseInfixExpression
+	p.infixParseFnMap[Lparen] = p.parseCallExpression
+	p.infixParseFnMap[Question] = p.parseConditionalExpression
+	p.infixParseFnMap[Dot] = p.parseSelectorExpression
+}
+
+// parseStatement parses statement and sets its position.
+func (p *Parser) parseStatement() (ast.Statement, error) {
+	start := p.Peek(CURRENT)
+
+	stmt, err := p.parseStatementByToken()
+	if err != nil {
+		return nil, err
+	}
+
+	p.setPosition(stmt, startPos(start))
+
+	return stmt, nil
+}
+
+// parseStatementOrSkip parses statement. If statement has error and
+// errors are collected, the error is reported and the statement is
+// skipped, then it returns nil statement without error.
+func (p *Parser) parseStatementOrSkip() (ast.Statement, error) {
+	outer := p.scope
+
+	stmt, err := p.parseStatement()
+	if err == nil {
+		return stmt, nil
+	}
+
+	if !p.report(err) {
+		return nil, err
+	}
+
+	p.scope = outer
+	skipStatement(p)
+
+	return nil, nil
+}
+
+// parseStatementByToken parses statement by its first token.
+func (p *Parser) parseStatementByToken() (ast.Statement, error) {
+	switch tt := p.Peek(CURRENT).Type; tt {
 	case IntType:
-		return parseAssignStatement(buf)
+		return p.parseAssignStatement()
 	case BoolType:
-		return parseAssignStatement(buf)
+		return p.parseAssignStatement()
 	case StringType:
-		return parseAssignStatement(buf)
+		return p.parseAssignStatement()
 	case If:
-		return parseIfStatement(buf)
+		return p.parseIfStatement()
 	case Return:
-		return parseReturnStatement(buf)
+		return p.parseReturnStatement()
+	case Require:
+		return p.parseRequireStatement()
+	case Store:
+		return p.parseStoreStatement()
+	case Emit:
+		return p.parseEmitStatement()
 	default:
-		switch buf.Peek(NEXT).Type {
+		if _, ok := p.lookupEnum(p.Peek(CURRENT)); ok {
+			return p.parseAssignStatement()
+		}
+
+		switch p.Peek(NEXT).Type {
 		case Assign:
-			return parseReassignStatement(buf)
+			return p.parseReassignStatement()
 		default:
-			return parseExpressionStatement(buf)
+			return p.parseExpressionStatement()
 		}
 	}
 }
@@ -385,12 +1005,12 @@ func parseStatement(buf TokenBuffer) (ast.Statement, error) {
 // Parsing expression is done in Pratt Parsing way. So each
 // token has its own parsing function. And each token has its
 // parsing precedence.
-func parseExpression(buf TokenBuffer, pre precedence) (ast.Expression, error) {
-	exp, err := makePrefixExpression(buf)
+func (p *Parser) parseExpression(pre precedence) (ast.Expression, error) {
+	exp, err := p.makePrefixExpression()
 	if err != nil {
 		return exp, err
 	}
-	exp, err = makeInfixExpression(buf, exp, pre)
+	exp, err = p.makeInfixExpression(exp, pre)
 	if err != nil {
 		return exp, err
 	}
@@ -400,10 +1020,10 @@ func parseExpression(buf TokenBuffer, pre precedence) (ast.Expression, error) {
 
 // ParseExpAsPrefix retrieves prefix parse function from
 // map, then parse expression with that function if exist.
-func makePrefixExpression(buf TokenBuffer) (ast.Expression, error) {
-	curTok := buf.Peek(CURRENT)
+func (p *Parser) makePrefixExpression() (ast.Expression, error) {
+	curTok := p.Peek(CURRENT)
 
-	fn := prefixParseFnMap[curTok.Type]
+	fn := p.prefixParseFnMap[curTok.Type]
 
 	if fn == nil {
 		return nil, Error{
@@ -411,22 +1031,24 @@ func makePrefixExpression(buf TokenBuffer) (ast.Expression, error) {
 			"prefix parse function not defined",
 		}
 	}
-	exp, err := fn(buf)
+	exp, err := fn()
 	if err != nil {
 		return nil, err
 	}
 
+	p.setPosition(exp, startPos(curTok))
+
 	return exp, nil
 }
 
 // MakeInfixExpression retrieves infix parse function from map
 // then parse expression with that function if exist.
-func makeInfixExpression(buf TokenBuffer, exp ast.Expression, pre precedence) (ast.Expression, error) {
+func (p *Parser) makeInfixExpression(exp ast.Expression, pre precedence) (ast.Expression, error) {
 	var err error
 	expression := exp
-	for !curTokenIs(buf, Semicolon) && pre < curPrecedence(buf) {
-		token := buf.Peek(CURRENT)
-		fn := infixParseFnMap[token.Type]
+	for !curTokenIs(p, Semicolon) && pre < curPrecedence(p) {
+		token := p.Peek(CURRENT)
+		fn := p.infixParseFnMap[token.Type]
 		if fn == nil {
 			return nil, Error{
 				token,
@@ -434,10 +1056,13 @@ func makeInfixExpression(buf TokenBuffer, exp ast.Expression, pre precedence) (a
 			}
 		}
 
-		expression, err = fn(buf, expression)
+		start := expression.Position().Start
+		expression, err = fn(expression)
 		if err != nil {
 			return nil, err
 		}
+
+		p.setPosition(expression, start)
 	}
 	return expression, nil
 }
@@ -447,170 +1072,959 @@ func makeInfixExpression(buf TokenBuffer, exp ast.Expression, pre precedence) (a
 //
 // Infix parsing is based on a precedence of given token which is defined
 // in precedenceMap
-func parseInfixExpression(buf TokenBuffer, left ast.Expression) (ast.Expression, error) {
+func (p *Parser) parseInfixExpression(left ast.Expression) (ast.Expression, error) {
 	var err error
-	curTok := buf.Read()
+	curTok := p.Read()
 
 	expression := &ast.InfixExpression{
 		Left:     left,
 		Operator: operatorMap[curTok.Type],
 	}
 
-	precedence := precedenceMap[curTok.Type]
-	expression.Right, err = parseExpression(buf, precedence)
-	if err != nil {
-		return nil, err
+	precedence := precedenceMap[curTok.Type]
+	expression.Right, err = p.parseExpression(precedence)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.checkEnumOperands(curTok, expression); err != nil {
+		return nil, err
+	}
+
+	if err := p.checkIntegerOperands(curTok, expression); err != nil {
+		return nil, err
+	}
+
+	return expression, nil
+}
+
+// checkIntegerOperands checks infix expression whose operator is defined
+// only for integer, i.e. arithmetic, bitwise and shift operators. Operands
+// whose type can't be decided at parsing time are not checked.
+func (p *Parser) checkIntegerOperands(token Token, exp *ast.InfixExpression) error {
+	if !isIntegerOperator(exp.Operator) {
+		return nil
+	}
+
+	for _, operand := range []ast.Expression{exp.Left, exp.Right} {
+		if t := p.expressionType(operand); t != 0 && t != ast.IntType {
+			return Error{
+				token,
+				fmt.Sprintf("operator %s is not defined on [%s]", exp.Operator.String(), t),
+			}
+		}
+	}
+
+	return nil
+}
+
+// isIntegerOperator returns whether infix operator takes integer operands
+// and produces integer.
+func isIntegerOperator(op ast.Operator) bool {
+	switch op {
+	case ast.Plus, ast.Minus, ast.Asterisk, ast.Slash, ast.Mod,
+		ast.BitAnd, ast.BitOr, ast.BitXor, ast.Shl, ast.Shr:
+		return true
+	}
+	return false
+}
+
+// checkEnumOperands checks infix expression whose operand is enum.
+// Enum can be compared only with the same enum, using == or !=.
+func (p *Parser) checkEnumOperands(token Token, exp *ast.InfixExpression) error {
+	left := p.expressionEnum(exp.Left)
+	right := p.expressionEnum(exp.Right)
+	if left == nil && right == nil {
+		return nil
+	}
+
+	if exp.Operator != ast.EQ && exp.Operator != ast.NOT_EQ {
+		return Error{
+			token,
+			fmt.Sprintf("operator %s is not defined on enum", exp.Operator.String()),
+		}
+	}
+
+	if left != right && p.expressionType(exp.Left) != 0 && p.expressionType(exp.Right) != 0 {
+		return Error{
+			token,
+			fmt.Sprintf("can't compare %s with %s", exp.Left.String(), exp.Right.String()),
+		}
+	}
+
+	return nil
+}
+
+// parseConditionalExpression parse conditional expression, which chooses
+// one of two expressions by the condition. e.g. a > b ? a : b
+//
+// Conditional expression is right-associative, so a ? b : c ? d : e
+// is grouped as (a ? b : (c ? d : e))
+func (p *Parser) parseConditionalExpression(condition ast.Expression) (ast.Expression, error) {
+	var err error
+	curTok := p.Read()
+
+	expression := &ast.ConditionalExpression{
+		Condition: condition,
+	}
+
+	if expression.Consequence, err = p.parseExpression(LOWEST); err != nil {
+		return nil, err
+	}
+
+	if err = expectNext(p, Colon); err != nil {
+		return nil, err
+	}
+
+	if expression.Alternative, err = p.parseExpression(LOWEST); err != nil {
+		return nil, err
+	}
+
+	if t := p.expressionType(condition); t != 0 && t != ast.BoolType {
+		return nil, Error{
+			curTok,
+			fmt.Sprintf("condition of conditional expression must be [bool], but got [%s]", t),
+		}
+	}
+
+	conseqType := p.expressionType(expression.Consequence)
+	altType := p.expressionType(expression.Alternative)
+	if conseqType != 0 && altType != 0 && conseqType != altType {
+		return nil, Error{
+			curTok,
+			fmt.Sprintf("branches of conditional expression have different types [%s] and [%s]", conseqType, altType),
+		}
+	}
+
+	return expression, nil
+}
+
+// expressionType infers the data structure which expression produces.
+// Identifiers are looked up in the current scope. If the type can't
+// be decided at parsing time, e.g. function call, it returns zero value.
+func (p *Parser) expressionType(exp ast.Expression) ast.DataStructure {
+	switch e := exp.(type) {
+	case *ast.IntegerLiteral:
+		return ast.IntType
+	case *ast.StringLiteral:
+		return ast.StringType
+	case *ast.BooleanLiteral:
+		return ast.BoolType
+	case *ast.Identifier:
+		sym := p.scope.Get(e.Name)
+		if sym == nil {
+			return 0
+		}
+		switch sym.Type() {
+		case symbol.IntegerSymbol:
+			return ast.IntType
+		case symbol.BooleanSymbol:
+			return ast.BoolType
+		case symbol.StringSymbol:
+			return ast.StringType
+		case symbol.EnumValueSymbol:
+			return ast.EnumType
+		}
+	case *ast.PrefixExpression:
+		switch e.Operator {
+		case ast.Bang:
+			return ast.BoolType
+		case ast.Minus, ast.BitNot:
+			return ast.IntType
+		}
+	case *ast.InfixExpression:
+		switch e.Operator {
+		case ast.LT, ast.GT, ast.LTE, ast.GTE, ast.EQ, ast.NOT_EQ, ast.LAND, ast.LOR:
+			return ast.BoolType
+		}
+		if isIntegerOperator(e.Operator) {
+			return ast.IntType
+		}
+	case *ast.ConditionalExpression:
+		if t := p.expressionType(e.Consequence); t != 0 {
+			return t
+		}
+		return p.expressionType(e.Alternative)
+	case *ast.ConversionExpression:
+		return e.Type
+	case *ast.ContractCallExpression:
+		return e.ReturnType
+	case *ast.LoadExpression:
+		return ast.IntType
+	case *ast.EnumMemberLiteral:
+		return ast.EnumType
+	case *ast.CallExpression:
+		if q, ok := e.Function.(*ast.QualifiedIdentifier); ok {
+			return q.Function.ReturnType
+		}
+	}
+
+	return 0
+}
+
+// expressionEnum returns enum declaration of expression whose type is enum.
+// If expression isn't enum, or it can't be decided at parsing time, returns nil.
+func (p *Parser) expressionEnum(exp ast.Expression) *ast.EnumLiteral {
+	switch e := exp.(type) {
+	case *ast.EnumMemberLiteral:
+		return e.Enum
+	case *ast.Identifier:
+		if v, ok := p.scope.Get(e.Name).(*symbol.EnumValue); ok {
+			return v.Enum
+		}
+	case *ast.ConditionalExpression:
+		if enum := p.expressionEnum(e.Consequence); enum != nil {
+			return enum
+		}
+		return p.expressionEnum(e.Alternative)
+	}
+
+	return nil
+}
+
+// parsePrefixExprsesion parse expression when current token in TokenBuffer
+// works as prefix of expression.
+//
+// Prefix parsing is based on a precedence of given token which is defined
+// in precedenceMap.
+func (p *Parser) parsePrefixExpression() (ast.Expression, error) {
+	token := p.Read()
+	op := operatorMap[token.Type]
+
+	right, err := p.parseExpression(PREFIX)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.expressionEnum(right) != nil {
+		return nil, PrefixError{
+			token,
+			right,
+		}
+	}
+
+	switch op {
+	case ast.Bang:
+		switch right.(type) {
+		case *ast.StringLiteral, *ast.IntegerLiteral:
+			return nil, PrefixError{
+				token,
+				right,
+			}
+		}
+	case ast.Minus, ast.BitNot:
+		switch right.(type) {
+		case *ast.BooleanLiteral, *ast.StringLiteral:
+			return nil, PrefixError{
+				token,
+				right,
+			}
+		}
+	}
+
+	exp := &ast.PrefixExpression{
+		Operator: op,
+		Right:    right,
+	}
+	return exp, nil
+}
+
+// parseIdentifier parse identifier.
+func (p *Parser) parseIdentifier() (ast.Expression, error) {
+	token := p.Read()
+	if token.Type != Ident {
+		return nil, ExpectError{token, Ident}
+	}
+
+	return &ast.Identifier{Name: token.Val}, nil
+}
+
+// parseIntegerLiteral parse integer literal.
+func (p *Parser) parseIntegerLiteral() (ast.Expression, error) {
+	token := p.Read()
+	if token.Type != Int {
+		return nil, ExpectError{token, Int}
+	}
+
+	value, err := parseInteger(token.Val)
+	if err, ok := err.(*strconv.NumError); ok && err.Err == strconv.ErrRange {
+		return nil, Error{token, fmt.Sprintf("integer %s out of range", token.Val)}
+	}
+	if err != nil {
+		return nil, Error{token, fmt.Sprintf("invalid integer %s", token.Val)}
+	}
+
+	lit := &ast.IntegerLiteral{Value: value}
+	return lit, nil
+}
+
+// parseInteger converts integer literal to its value. The literal is
+// decimal, hex with 0x or 0X prefix, or binary with 0b or 0B prefix, and
+// "_" can be placed between digits. Decimal can't start with 0, so octal
+// forms of Go such as 010 and 0o17 are rejected.
+func parseInteger(val string) (int64, error) {
+	s, sign := val, ""
+	if strings.HasPrefix(s, "+") || strings.HasPrefix(s, "-") {
+		s, sign = s[1:], s[:1]
+	}
+
+	base := 10
+	switch {
+	case strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X"):
+		s, base = s[2:], 16
+	case strings.HasPrefix(s, "0b") || strings.HasPrefix(s, "0B"):
+		s, base = s[2:], 2
+	case len(s) > 1 && s[0] == '0':
+		return 0, &strconv.NumError{Func: "ParseInt", Num: val, Err: strconv.ErrSyntax}
+	}
+
+	if s == "" || s[0] == '_' || s[len(s)-1] == '_' || strings.Contains(s, "__") {
+		return 0, &strconv.NumError{Func: "ParseInt", Num: val, Err: strconv.ErrSyntax}
+	}
+
+	return strconv.ParseInt(sign+strings.Replace(s, "_", "", -1), base, 64)
+}
+
+// parseBooleanLiteral parse boolean literal.
+func (p *Parser) parseBooleanLiteral() (ast.Expression, error) {
+	token := p.Read()
+	if token.Type != True && token.Type != False {
+		return nil, ExpectError{token, BoolType}
+	}
+
+	val, err := strconv.ParseBool(token.Val)
+	if err != nil {
+		return nil, err
+	}
+
+	lit := &ast.BooleanLiteral{Value: val}
+	return lit, nil
+}
+
+// parseStringLiteral parse string value which is
+// going to be assigned to variable. Surrounding quotes
+// are not part of the value.
+func (p *Parser) parseStringLiteral() (ast.Expression, error) {
+	token := p.Read()
+	if token.Type != String {
+		return nil, ExpectError{token, String}
+	}
+
+	value, err := unquote(token.Val)
+	if err != nil {
+		return nil, Error{token, err.Error()}
+	}
+
+	return &ast.StringLiteral{Value: value}, nil
+}
+
+// unquote returns the value of string token. It removes double quotes
+// around the value and decodes escape sequences. The value of byte
+// string is its bytes, e.g. b"\x01\x02" and hex"0102" are "\x01\x02".
+func unquote(val string) (string, error) {
+	if strings.HasPrefix(val, `hex"`) {
+		b, err := hex.DecodeString(trimQuotes(val[len("hex"):]))
+		if err != nil {
+			return "", fmt.Errorf("invalid hex string %s", val)
+		}
+		return string(b), nil
+	}
+
+	if strings.HasPrefix(val, `b"`) {
+		val = val[len("b"):]
+	}
+
+	return unescape(trimQuotes(val))
+}
+
+// trimQuotes removes double quotes around val.
+func trimQuotes(val string) string {
+	if len(val) >= 2 && val[0] == '"' && val[len(val)-1] == '"' {
+		return val[1 : len(val)-1]
+	}
+
+	return val
+}
+
+// unescape decodes escape sequences of string, which are \n, \t, \r,
+// \", \\ and \x followed by two hex digits.
+func unescape(val string) (string, error) {
+	if !strings.Contains(val, `\`) {
+		return val, nil
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(val); i++ {
+		if val[i] != '\\' {
+			b.WriteByte(val[i])
+			continue
+		}
+
+		i++
+		if i == len(val) {
+			return "", fmt.Errorf("escape sequence not terminated")
+		}
+
+		switch c := val[i]; c {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case 'r':
+			b.WriteByte('\r')
+		case '"', '\\':
+			b.WriteByte(c)
+		case 'x':
+			if i+2 >= len(val) {
+				return "", fmt.Errorf("escape sequence \\x needs two hex digits")
+			}
+			v, err := strconv.ParseUint(val[i+1:i+3], 16, 8)
+			if err != nil {
+				return "", fmt.Errorf("escape sequence \\x needs two hex digits")
+			}
+			b.WriteByte(byte(v))
+			i += 2
+		default:
+			return "", fmt.Errorf("unknown escape sequence \\%c", c)
+		}
+	}
+
+	return b.String(), nil
+}
+
+// parseConversionExpression parse type conversion which converts
+// value to the other type. e.g. int("5"), string(5), bool(1)
+//
+// The type of value must be decided at parsing time, because
+// the conversion is chosen based on it.
+func (p *Parser) parseConversionExpression() (ast.Expression, error) {
+	token := p.Read()
+	ds, ok := datastructureMap[token.Type]
+	if !ok || ds == ast.VoidType {
+		return nil, Error{
+			token,
+			"invalid conversion type",
+		}
+	}
+
+	if err := expectNext(p, Lparen); err != nil {
+		return nil, err
+	}
+
+	value, err := p.parseExpression(LOWEST)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := expectNext(p, Rparen); err != nil {
+		return nil, err
+	}
+
+	valueType := p.expressionType(value)
+	if valueType == 0 || valueType == ast.VoidType {
+		return nil, Error{
+			token,
+			fmt.Sprintf("can't convert %s to [%s], type of value is unknown", value.String(), ds),
+		}
+	}
+
+	return &ast.ConversionExpression{
+		Type:      ds,
+		Value:     value,
+		ValueType: valueType,
+	}, nil
+}
+
+// parseContractCallExpression parse the call of function in other contract.
+// Method is a string literal of function signature, which can be followed
+// by its return type. Return type is int if it is omitted.
+// e.g. call(token, "transfer(int,int) bool", to, amount)
+func (p *Parser) parseContractCallExpression() (ast.Expression, error) {
+	token := p.Read()
+	if token.Type != Call {
+		return nil, ExpectError{token, Call}
+	}
+
+	args, err := p.parseCallArguments()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(args) < 2 {
+		return nil, Error{token, "call needs address and method"}
+	}
+
+	if t := p.expressionType(args[0]); t != 0 && t != ast.IntType {
+		return nil, Error{
+			token,
+			fmt.Sprintf("address of call must be [%s], but got [%s]", ast.IntType, t),
+		}
+	}
+
+	method, ok := args[1].(*ast.StringLiteral)
+	if !ok {
+		return nil, Error{token, "method of call must be a string literal"}
+	}
+
+	signature, params, returnType, err := parseMethodSignature(method.Value)
+	if err != nil {
+		return nil, Error{token, err.Error()}
+	}
+
+	exp := &ast.ContractCallExpression{
+		Address:    args[0],
+		Signature:  signature,
+		Arguments:  args[2:],
+		ReturnType: returnType,
+	}
+
+	if len(exp.Arguments) != len(params) {
+		return nil, Error{
+			token,
+			fmt.Sprintf("method %s needs %d arguments, but got %d",
+				signature, len(params), len(exp.Arguments)),
+		}
+	}
+
+	for i, arg := range exp.Arguments {
+		t := p.expressionType(arg)
+		if t == ast.EnumType {
+			t = ast.IntType
+		}
+
+		if t != 0 && t != params[i] {
+			return nil, Error{
+				token,
+				fmt.Sprintf("argument %d of method %s must be [%s], but got [%s]",
+					i+1, signature, params[i], t),
+			}
+		}
+	}
+
+	return exp, nil
+}
+
+// parseMethodSignature parse method of contract call such as "name(int,bool) string",
+// returns its signature without return type, types of parameters and return type.
+func parseMethodSignature(method string) (string, []ast.DataStructure, ast.DataStructure, error) {
+	invalid := fmt.Errorf(`invalid method "%s", it should be like "name(int,string) bool"`, method)
+
+	lparen, rparen := strings.Index(method, "("), strings.Index(method, ")")
+	if lparen < 0 || rparen < lparen {
+		return "", nil, 0, invalid
+	}
+
+	name := strings.TrimSpace(method[:lparen])
+	if !isIdentifier(name) {
+		return "", nil, 0, invalid
+	}
+
+	params := []ast.DataStructure{}
+	names := []string{}
+	if p := strings.TrimSpace(method[lparen+1 : rparen]); p != "" {
+		for _, param := range strings.Split(p, ",") {
+			t, ok := datastructureMap[LookupIdent(strings.TrimSpace(param))]
+			if !ok || t == ast.VoidType {
+				return "", nil, 0, invalid
+			}
+
+			params = append(params, t)
+			names = append(names, t.String())
+		}
+	}
+
+	returnType := ast.IntType
+	if r := strings.TrimSpace(method[rparen+1:]); r != "" {
+		t, ok := datastructureMap[LookupIdent(r)]
+		if !ok || t == ast.VoidType {
+			return "", nil, 0, invalid
+		}
+
+		returnType = t
+	}
+
+	return fmt.Sprintf("%s(%s)", name, strings.Join(names, ",")), params, returnType, nil
+}
+
+// parseEnumLiteral parse enum declaration whose members are
+// separated by comma. e.g. enum State { Open, Closed }
+//
+// Enum is declared in the contract scope, so it should be declared
+// before it is used.
+func (p *Parser) parseEnumLiteral() (*ast.EnumLiteral, error) {
+	start := p.Peek(CURRENT)
+	if err := expectNext(p, Enum); err != nil {
+		return nil, err
+	}
+
+	token := p.Read()
+	if token.Type != Ident {
+		return nil, ExpectError{token, Ident}
+	}
+
+	if s := p.scope.Get(token.Val); s != nil {
+		return nil, DupSymError{token}
+	}
+
+	lit := &ast.EnumLiteral{
+		Name:    newIdentifier(token),
+		Members: []*ast.Identifier{},
+	}
+
+	if err := expectNext(p, Lbrace); err != nil {
+		return nil, err
+	}
+	consumeSemi(p)
+
+	for !curTokenIs(p, Rbrace) {
+		member := p.Read()
+		if member.Type != Ident {
+			return nil, ExpectError{member, Ident}
+		}
+
+		if _, ok := lit.Value(member.Val); ok {
+			return nil, DupSymError{member}
+		}
+		lit.Members = append(lit.Members, newIdentifier(member))
+
+		consumeSemi(p)
+		if !curTokenIs(p, Comma) {
+			break
+		}
+		p.Read()
+		consumeSemi(p)
+	}
+
+	if err := expectNext(p, Rbrace); err != nil {
+		return nil, err
+	}
+
+	p.setPosition(lit, startPos(start))
+
+	if len(lit.Members) == 0 {
+		return nil, Error{
+			token,
+			"enum should have at least one member",
+		}
+	}
+
+	p.scope.Set(token.Val, &symbol.Enum{Literal: lit})
+	consumeSemi(p)
+
+	return lit, nil
+}
+
+// parseSelectorExpression parse expression which selects a name with dot.
+// If left is the name of library, it is function of the library.
+// e.g. math.max, otherwise it is member of enum. e.g. State.Open
+func (p *Parser) parseSelectorExpression(left ast.Expression) (ast.Expression, error) {
+	if ident, ok := left.(*ast.Identifier); ok {
+		if _, ok := p.scope.Get(ident.Name).(*symbol.Library); ok {
+			return p.parseQualifiedIdentifier(left)
+		}
+	}
+
+	return p.parseEnumMemberLiteral(left)
+}
+
+// parseQualifiedIdentifier parse function of library qualified with
+// the name of library. e.g. math.max
+// Function of library can only be called, so it should be followed by
+// left-paren.
+func (p *Parser) parseQualifiedIdentifier(left ast.Expression) (ast.Expression, error) {
+	dot := p.Read()
+
+	var library *symbol.Library
+	if ident, ok := left.(*ast.Identifier); ok {
+		library, _ = p.scope.Get(ident.Name).(*symbol.Library)
+	}
+
+	if library == nil {
+		return nil, Error{
+			dot,
+			fmt.Sprintf("%s is not a library", left.String()),
+		}
+	}
+
+	token := p.Read()
+	if token.Type != Ident {
+		return nil, ExpectError{token, Ident}
+	}
+
+	var fn *ast.FunctionLiteral
+	for _, f := range library.Literal.Functions {
+		if f.Name.Name == token.Val {
+			fn = f
+			break
+		}
+	}
+
+	if fn == nil {
+		return nil, Error{
+			token,
+			fmt.Sprintf("library %s has no function %s", library.String(), token.Val),
+		}
+	}
+
+	if next := p.Peek(CURRENT); next.Type != Lparen {
+		return nil, ExpectError{next, Lparen}
 	}
 
-	return expression, nil
+	return &ast.QualifiedIdentifier{
+		Library:  library.Literal,
+		Function: fn,
+	}, nil
 }
 
-// parsePrefixExprsesion parse expression when current token in TokenBuffer
-// works as prefix of expression.
-//
-// Prefix parsing is based on a precedence of given token which is defined
-// in precedenceMap.
-func parsePrefixExpression(buf TokenBuffer) (ast.Expression, error) {
-	token := buf.Read()
-	op := operatorMap[token.Type]
+// parseEnumMemberLiteral parse member of enum. e.g. State.Open
+// The value of member is decided at parsing time.
+func (p *Parser) parseEnumMemberLiteral(left ast.Expression) (ast.Expression, error) {
+	dot := p.Read()
 
-	right, err := parseExpression(buf, PREFIX)
-	if err != nil {
-		return nil, err
+	var enum *symbol.Enum
+	if ident, ok := left.(*ast.Identifier); ok {
+		enum, _ = p.scope.Get(ident.Name).(*symbol.Enum)
 	}
 
-	switch op {
-	case ast.Bang:
-		switch right.(type) {
-		case *ast.StringLiteral, *ast.IntegerLiteral:
-			return nil, PrefixError{
-				token,
-				right,
-			}
-		}
-	case ast.Minus:
-		switch right.(type) {
-		case *ast.BooleanLiteral, *ast.StringLiteral:
-			return nil, PrefixError{
-				token,
-				right,
-			}
+	if enum == nil {
+		return nil, Error{
+			dot,
+			fmt.Sprintf("%s is not an enum", left.String()),
 		}
 	}
 
-	exp := &ast.PrefixExpression{
-		Operator: op,
-		Right:    right,
+	token := p.Read()
+	if token.Type != Ident {
+		return nil, ExpectError{token, Ident}
 	}
-	return exp, nil
+
+	value, ok := enum.Literal.Value(token.Val)
+	if !ok {
+		return nil, Error{
+			token,
+			fmt.Sprintf("enum %s has no member %s", enum.String(), token.Val),
+		}
+	}
+
+	return &ast.EnumMemberLiteral{
+		Enum:   enum.Literal,
+		Member: newIdentifier(token),
+		Value:  value,
+	}, nil
 }
 
-// parseIdentifier parse identifier.
-func parseIdentifier(buf TokenBuffer) (ast.Expression, error) {
-	token := buf.Read()
+// parseFunctionLiteral parse functional expression
+// first parse name, and parse parameter, body
+func (p *Parser) parseFunctionLiteral() (*ast.FunctionLiteral, error) {
+	p.enterScope()
+
+	lit := &ast.FunctionLiteral{}
+	var err error
+
+	start := p.Peek(CURRENT)
+	keyword := p.Read()
+	if keyword.Type != Function {
+		return nil, ExpectError{keyword, Function}
+	}
+
+	token := p.Read()
 	if token.Type != Ident {
 		return nil, ExpectError{token, Ident}
 	}
 
-	return &ast.Identifier{Name: token.Val}, nil
-}
+	if err := p.updateScopeSymbol(token, keyword); err != nil {
+		return nil, err
+	}
 
-// parseIntegerLiteral parse integer literal.
-func parseIntegerLiteral(buf TokenBuffer) (ast.Expression, error) {
-	token := buf.Read()
-	if token.Type != Int {
-		return nil, ExpectError{token, Int}
+	lit.Name = newIdentifier(token)
+
+	if err = expectNext(p, Lparen); err != nil {
+		return nil, err
 	}
 
-	value, err := strconv.ParseInt(token.Val, 0, 64)
-	if err != nil {
+	if lit.Parameters, err = p.parseFunctionParameterList(); err != nil {
 		return nil, err
 	}
 
-	lit := &ast.IntegerLiteral{Value: value}
-	return lit, nil
-}
+	if lit.Modifiers, err = p.parseModifierInvocationList(); err != nil {
+		return nil, err
+	}
 
-// parseBooleanLiteral parse boolean literal.
-func parseBooleanLiteral(buf TokenBuffer) (ast.Expression, error) {
-	token := buf.Read()
-	if token.Type != True && token.Type != False {
-		return nil, ExpectError{token, BoolType}
+	if lit.ReturnType, lit.ReturnEnum, err = p.parseFunctionReturnType(); err != nil {
+		return nil, err
 	}
 
-	val, err := strconv.ParseBool(token.Val)
-	if err != nil {
+	if lit.Body, err = p.parseBlockStatement(); err != nil {
 		return nil, err
 	}
 
-	lit := &ast.BooleanLiteral{Value: val}
+	p.setPosition(lit, startPos(start))
+	consumeSemi(p)
+	p.recordScope(lit)
+	p.leaveScope()
+
 	return lit, nil
 }
 
-// parseStringLiteral parse string value which is
-// going to be assigned to variable
-func parseStringLiteral(buf TokenBuffer) (ast.Expression, error) {
-	token := buf.Read()
-	if token.Type != String {
-		return nil, ExpectError{token, String}
+// parseModifierLiteral parse modifier definition. Modifier has
+// parameters like function, and its body should have exactly one
+// placeholder "_" at the top level. e.g. modifier positive(a int) { ... }
+//
+// Modifier is declared in the contract scope, so it should be declared
+// before it is applied to functions.
+func (p *Parser) parseModifierLiteral() (*ast.ModifierLiteral, error) {
+	start := p.Peek(CURRENT)
+	if err := expectNext(p, Modifier); err != nil {
+		return nil, err
 	}
 
-	return &ast.StringLiteral{Value: token.Val}, nil
-}
+	token := p.Read()
+	if token.Type != Ident {
+		return nil, ExpectError{token, Ident}
+	}
 
-// parseFunctionLiteral parse functional expression
-// first parse name, and parse parameter, body
-func parseFunctionLiteral(buf TokenBuffer) (*ast.FunctionLiteral, error) {
-	enterScope()
+	if s := p.scope.Get(token.Val); s != nil {
+		return nil, DupSymError{token}
+	}
 
-	lit := &ast.FunctionLiteral{}
+	lit := &ast.ModifierLiteral{Name: newIdentifier(token)}
 	var err error
 
-	keyword := buf.Read()
-	if keyword.Type != Function {
-		return nil, ExpectError{keyword, Function}
+	p.enterScope()
+
+	if err = expectNext(p, Lparen); err != nil {
+		return nil, err
 	}
 
-	token := buf.Read()
-	if token.Type != Ident {
-		return nil, ExpectError{token, Ident}
+	if lit.Parameters, err = p.parseFunctionParameterList(); err != nil {
+		return nil, err
 	}
 
-	if err := updateScopeSymbol(token, keyword); err != nil {
+	if lit.Body, err = p.parseModifierBody(); err != nil {
 		return nil, err
 	}
 
-	lit.Name = &ast.Identifier{Name: token.Val}
+	p.setPosition(lit, startPos(start))
+	p.recordScope(lit)
+	p.leaveScope()
 
-	if err = expectNext(buf, Lparen); err != nil {
-		return nil, err
+	placeholders := 0
+	for _, stmt := range lit.Body.Statements {
+		if _, ok := stmt.(*ast.PlaceholderStatement); ok {
+			placeholders++
+		}
 	}
 
-	if lit.Parameters, err = parseFunctionParameterList(buf); err != nil {
-		return nil, err
+	if placeholders != 1 {
+		return nil, Error{
+			token,
+			fmt.Sprintf("modifier should have exactly one placeholder \"_\", but got %d", placeholders),
+		}
 	}
 
-	if lit.ReturnType, err = parseFunctionReturnType(buf); err != nil {
+	p.scope.Set(token.Val, &symbol.Modifier{Literal: lit})
+	consumeSemi(p)
+
+	return lit, nil
+}
+
+// parseModifierBody parse the body of modifier. It is the same as
+// parseBlockStatement except that "_" is parsed as placeholder.
+func (p *Parser) parseModifierBody() (*ast.BlockStatement, error) {
+	start := p.Peek(CURRENT)
+	if err := expectNext(p, Lbrace); err != nil {
 		return nil, err
 	}
 
-	if lit.Body, err = parseBlockStatement(buf); err != nil {
+	p.enterScope()
+
+	block := &ast.BlockStatement{}
+	curToken := p.Peek(CURRENT)
+
+	for curToken.Type != Rbrace && curToken.Type != Eof {
+		var stmt ast.Statement
+		var err error
+
+		if curToken.Type == Ident && curToken.Val == "_" {
+			p.Read()
+			consumeSemi(p)
+			placeholder := &ast.PlaceholderStatement{}
+			placeholder.SetPosition(startPos(curToken), endPos(curToken))
+			stmt = placeholder
+		} else if stmt, err = p.parseStatementOrSkip(); err != nil {
+			return nil, err
+		}
+
+		if stmt != nil {
+			block.Statements = append(block.Statements, stmt)
+		}
+		curToken = p.Peek(CURRENT)
+	}
+
+	if err := expectNext(p, Rbrace); err != nil {
 		return nil, err
 	}
 
-	consumeSemi(buf)
-	leaveScope()
+	p.setPosition(block, startPos(start))
+	p.recordScope(block)
+	p.leaveScope()
 
-	return lit, nil
+	return block, nil
+}
+
+// parseModifierInvocationList parse modifiers applied to function which
+// are written after the parameters. Arguments can be omitted with
+// parenthesis when modifier has no parameter. e.g. onlyOwner, positive(a)
+func (p *Parser) parseModifierInvocationList() ([]*ast.ModifierInvocation, error) {
+	modifiers := []*ast.ModifierInvocation{}
+
+	for {
+		token := p.Peek(CURRENT)
+		modifier, ok := p.lookupModifier(token)
+		if !ok {
+			return modifiers, nil
+		}
+		p.Read()
+
+		args := []ast.Expression{}
+		if curTokenIs(p, Lparen) {
+			var err error
+			if args, err = p.parseCallArguments(); err != nil {
+				return nil, err
+			}
+		}
+
+		if len(args) != len(modifier.Parameters) {
+			return nil, Error{
+				token,
+				fmt.Sprintf("modifier %s needs %d arguments, but got %d",
+					token.Val, len(modifier.Parameters), len(args)),
+			}
+		}
+
+		invocation := &ast.ModifierInvocation{
+			Modifier:  modifier,
+			Arguments: args,
+		}
+		p.setPosition(invocation, startPos(token))
+
+		modifiers = append(modifiers, invocation)
+	}
 }
 
-// parseFunctionReturnType parse function's return data structure type
-func parseFunctionReturnType(buf TokenBuffer) (ast.DataStructure, error) {
-	peekTok := buf.Peek(CURRENT)
+// parseFunctionReturnType parse function's return data structure type.
+// If function returns enum, its declaration is also returned.
+func (p *Parser) parseFunctionReturnType() (ast.DataStructure, *ast.EnumLiteral, error) {
+	peekTok := p.Peek(CURRENT)
+
+	if enum, ok := p.lookupEnum(peekTok); ok {
+		p.Read()
+		return ast.EnumType, enum, nil
+	}
 
 	ds, ok := datastructureMap[peekTok.Type]
 	if !ok && peekTok.Type != Lbrace {
-		return 0, Error{
+		return 0, nil, Error{
 			peekTok,
 			"invalid function return type",
 		}
@@ -619,45 +2033,45 @@ func parseFunctionReturnType(buf TokenBuffer) (ast.DataStructure, error) {
 	if !ok {
 		ds = ast.VoidType
 	} else {
-		buf.Read()
+		p.Read()
 	}
 
-	return ds, nil
+	return ds, nil, nil
 }
 
 // parseFunctionParameters parse function's parameters which
 // separated by comma
-func parseFunctionParameterList(buf TokenBuffer) ([]*ast.ParameterLiteral, error) {
+func (p *Parser) parseFunctionParameterList() ([]*ast.ParameterLiteral, error) {
 	identifiers := []*ast.ParameterLiteral{}
-	if err := expectNext(buf, Rparen); err == nil {
+	if err := expectNext(p, Rparen); err == nil {
 		return identifiers, nil
 	}
 
-	ident, err := parseFunctionParameter(buf)
+	ident, err := p.parseFunctionParameter()
 	if err != nil {
 		return nil, err
 	}
 	identifiers = append(identifiers, ident)
 
-	for curTokenIs(buf, Comma) {
-		buf.Read()
+	for curTokenIs(p, Comma) {
+		p.Read()
 
-		ident, err := parseFunctionParameter(buf)
+		ident, err := p.parseFunctionParameter()
 		if err != nil {
 			return nil, err
 		}
 		identifiers = append(identifiers, ident)
 	}
 
-	if err = expectNext(buf, Rparen); err != nil {
+	if err = expectNext(p, Rparen); err != nil {
 		return nil, err
 	}
 
 	return identifiers, nil
 }
 
-func parseFunctionParameter(buf TokenBuffer) (*ast.ParameterLiteral, error) {
-	token := buf.Read()
+func (p *Parser) parseFunctionParameter() (*ast.ParameterLiteral, error) {
+	token := p.Read()
 	if token.Type != Ident {
 		return nil, ExpectError{
 			token,
@@ -666,11 +2080,15 @@ func parseFunctionParameter(buf TokenBuffer) (*ast.ParameterLiteral, error) {
 	}
 
 	ident := &ast.ParameterLiteral{
-		Identifier: &ast.Identifier{Name: token.Val},
+		Identifier: newIdentifier(token),
 	}
 
-	dsToken := buf.Read()
+	dsToken := p.Read()
 	ds, ok := datastructureMap[dsToken.Type]
+	if enum, isEnum := p.lookupEnum(dsToken); isEnum {
+		ds, ok = ast.EnumType, true
+		ident.Enum = enum
+	}
 	if !ok {
 		return nil, Error{
 			dsToken,
@@ -678,8 +2096,9 @@ func parseFunctionParameter(buf TokenBuffer) (*ast.ParameterLiteral, error) {
 		}
 	}
 	ident.Type = ds
+	ident.SetPosition(startPos(token), endPos(dsToken))
 
-	if err := updateScopeSymbol(token, dsToken); err != nil {
+	if err := p.updateScopeSymbol(token, dsToken); err != nil {
 		return nil, err
 	}
 
@@ -687,38 +2106,159 @@ func parseFunctionParameter(buf TokenBuffer) (*ast.ParameterLiteral, error) {
 }
 
 // parseReturnStatement parse "return" keyword with its expression
-func parseReturnStatement(buf TokenBuffer) (ast.Statement, error) {
-	if err := expectNext(buf, Return); err != nil {
+func (p *Parser) parseReturnStatement() (ast.Statement, error) {
+	if err := expectNext(p, Return); err != nil {
 		return nil, err
 	}
 
 	stmt := &ast.ReturnStatement{}
 
-	if curTokenIs(buf, Semicolon) {
-		buf.Read()
+	if curTokenIs(p, Semicolon) {
+		p.Read()
 		return stmt, nil
 	}
 
-	exp, err := parseExpression(buf, LOWEST)
+	exp, err := p.parseExpression(LOWEST)
 	if err != nil {
 		return nil, err
 	}
 	stmt.ReturnValue = exp
 
-	consumeSemi(buf)
+	consumeSemi(p)
 
 	return stmt, nil
 }
 
+// parseRequireStatement parse "require" keyword with its condition.
+// e.g. require(a > 0)
+func (p *Parser) parseRequireStatement() (ast.Statement, error) {
+	token := p.Read()
+	if token.Type != Require {
+		return nil, ExpectError{token, Require}
+	}
+
+	if err := expectNext(p, Lparen); err != nil {
+		return nil, err
+	}
+
+	exp, err := p.parseExpression(LOWEST)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := expectNext(p, Rparen); err != nil {
+		return nil, err
+	}
+
+	if t := p.expressionType(exp); t != 0 && t != ast.BoolType {
+		return nil, Error{
+			token,
+			fmt.Sprintf("condition of require must be [bool], but got [%s]", t),
+		}
+	}
+
+	consumeSemi(p)
+
+	return &ast.RequireStatement{Condition: exp}, nil
+}
+
+// parseStoreStatement parse "store" keyword with key and value to store
+// in the storage of contract. Key is int or enum, and value is int.
+// e.g. store(1, balance)
+func (p *Parser) parseStoreStatement() (ast.Statement, error) {
+	token := p.Read()
+	if token.Type != Store {
+		return nil, ExpectError{token, Store}
+	}
+
+	args, err := p.parseCallArguments()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(args) != 2 {
+		return nil, Error{token, fmt.Sprintf("store needs key and value, but got %d arguments", len(args))}
+	}
+
+	if err := p.checkStorageKey(token, args[0]); err != nil {
+		return nil, err
+	}
+
+	if t := p.expressionType(args[1]); t != 0 && t != ast.IntType {
+		return nil, Error{
+			token,
+			fmt.Sprintf("value of store must be [%s], but got [%s]", ast.IntType, t),
+		}
+	}
+
+	consumeSemi(p)
+
+	return &ast.StoreStatement{Key: args[0], Value: args[1]}, nil
+}
+
+// parseEmitStatement parse "emit" keyword with values to record as a log.
+// e.g. emit(from, amount)
+func (p *Parser) parseEmitStatement() (ast.Statement, error) {
+	token := p.Read()
+	if token.Type != Emit {
+		return nil, ExpectError{token, Emit}
+	}
+
+	args, err := p.parseCallArguments()
+	if err != nil {
+		return nil, err
+	}
+
+	consumeSemi(p)
+
+	return &ast.EmitStatement{Values: args}, nil
+}
+
+// parseLoadExpression parse "load" keyword with key whose value is
+// loaded from the storage of contract. e.g. load(1)
+func (p *Parser) parseLoadExpression() (ast.Expression, error) {
+	token := p.Read()
+	if token.Type != Load {
+		return nil, ExpectError{token, Load}
+	}
+
+	args, err := p.parseCallArguments()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(args) != 1 {
+		return nil, Error{token, fmt.Sprintf("load needs key, but got %d arguments", len(args))}
+	}
+
+	if err := p.checkStorageKey(token, args[0]); err != nil {
+		return nil, err
+	}
+
+	return &ast.LoadExpression{Key: args[0]}, nil
+}
+
+// checkStorageKey checks the type of storage key, which is int or enum.
+func (p *Parser) checkStorageKey(token Token, key ast.Expression) error {
+	if t := p.expressionType(key); t != 0 && t != ast.IntType && t != ast.EnumType {
+		return Error{
+			token,
+			fmt.Sprintf("key of %s must be [%s], but got [%s]", token.Val, ast.IntType, t),
+		}
+	}
+
+	return nil
+}
+
 // parseGroupedExpression parse grouped expression which
 // grouped using parenthesis
-func parseGroupedExpression(buf TokenBuffer) (ast.Expression, error) {
-	buf.Read()
-	exp, err := parseExpression(buf, LOWEST)
+func (p *Parser) parseGroupedExpression() (ast.Expression, error) {
+	p.Read()
+	exp, err := p.parseExpression(LOWEST)
 	if err != nil {
 		return nil, err
 	}
-	if err = expectNext(buf, Rparen); err != nil {
+	if err = expectNext(p, Rparen); err != nil {
 		return nil, err
 	}
 
@@ -727,13 +2267,17 @@ func parseGroupedExpression(buf TokenBuffer) (ast.Expression, error) {
 
 // parseAssignStatement parse assign statements which assign values
 // to its identifier. e.g. int a = 1
-func parseAssignStatement(buf TokenBuffer) (*ast.AssignStatement, error) {
+func (p *Parser) parseAssignStatement() (*ast.AssignStatement, error) {
 	stmt := &ast.AssignStatement{}
 
-	dsToken := buf.Read()
+	dsToken := p.Read()
 	stmt.Type = datastructureMap[dsToken.Type]
+	if enum, ok := p.lookupEnum(dsToken); ok {
+		stmt.Type = ast.EnumType
+		stmt.Enum = enum
+	}
 
-	token := buf.Read()
+	token := p.Read()
 	if token.Type != Ident {
 		return nil, ExpectError{
 			token,
@@ -741,26 +2285,31 @@ func parseAssignStatement(buf TokenBuffer) (*ast.AssignStatement, error) {
 		}
 	}
 
-	if err := updateScopeSymbol(token, dsToken); err != nil {
+	if err := p.updateScopeSymbol(token, dsToken); err != nil {
 		return nil, err
 	}
 
-	stmt.Variable = ast.Identifier{
-		Name: token.Val,
-	}
+	stmt.Variable = *newIdentifier(token)
 
-	if err := expectNext(buf, Assign); err != nil {
+	if err := expectNext(p, Assign); err != nil {
 		return nil, err
 	}
 
-	exp, err := parseExpression(buf, LOWEST)
+	exp, err := p.parseExpression(LOWEST)
 	if err != nil {
 		return nil, err
 	}
 
+	if stmt.Type == ast.EnumType && p.expressionType(exp) != 0 && p.expressionEnum(exp) != stmt.Enum {
+		return nil, Error{
+			token,
+			fmt.Sprintf("can't assign %s to %s of enum %s", exp.String(), token.Val, stmt.Enum.Name.String()),
+		}
+	}
+
 	stmt.Value = exp
 
-	consumeSemi(buf)
+	consumeSemi(p)
 
 	return stmt, nil
 }
@@ -768,81 +2317,140 @@ func parseAssignStatement(buf TokenBuffer) (*ast.AssignStatement, error) {
 // parseReassignStatement parse reassign statement
 // i.e) int a = 1
 // a = 2
-func parseReassignStatement(buf TokenBuffer) (ast.Statement, error) {
+func (p *Parser) parseReassignStatement() (ast.Statement, error) {
 	stmt := &ast.ReassignStatement{}
-	token := buf.Read()
+	token := p.Read()
 	if token.Type != Ident {
 		return nil, ExpectError{Source: token, Expected: Ident}
 	}
 
-	if exist := scope.Get(token.Val); exist == nil {
+	if exist := p.scope.Get(token.Val); exist == nil {
 		return nil, NotExistSymError{token}
 	}
 
-	stmt.Variable = &ast.Identifier{Name: token.Val}
+	stmt.Variable = newIdentifier(token)
 
-	if err := expectNext(buf, Assign); err != nil {
+	if err := expectNext(p, Assign); err != nil {
 		return nil, err
 	}
 
-	exp, err := parseExpression(buf, LOWEST)
+	exp, err := p.parseExpression(LOWEST)
 	if err != nil {
 		return nil, err
 	}
 	stmt.Value = exp
 
-	consumeSemi(buf)
+	consumeSemi(p)
 
 	return stmt, nil
 }
 
 // parseCallExpression parse function call
-func parseCallExpression(buf TokenBuffer, fn ast.Expression) (ast.Expression, error) {
-	exp := &ast.CallExpression{Function: fn}
+//
+// Function of library is resolved at parsing time, so its arguments
+// are checked with the parameters of function.
+func (p *Parser) parseCallExpression(fn ast.Expression) (ast.Expression, error) {
+	lparen := p.Peek(CURRENT)
+	exp := &ast.CallExpression{Function: p.resolveFunction(fn)}
 
 	var err error
-	exp.Arguments, err = parseCallArguments(buf)
+	exp.Arguments, err = p.parseCallArguments()
 	if err != nil {
 		return nil, err
 	}
 
-	consumeSemi(buf)
+	if err := p.checkCallArguments(lparen, exp); err != nil {
+		return nil, err
+	}
+
+	consumeSemi(p)
 
 	return exp, nil
 }
 
+// resolveFunction returns function of library which fn names, when
+// fn is the name of function declared before in the same library.
+// Otherwise, returns fn as it is.
+func (p *Parser) resolveFunction(fn ast.Expression) ast.Expression {
+	ident, ok := fn.(*ast.Identifier)
+	if !ok {
+		return fn
+	}
+
+	sym, ok := p.scope.Get(ident.Name).(*symbol.Function)
+	if !ok || sym.Literal == nil {
+		return fn
+	}
+
+	return &ast.QualifiedIdentifier{
+		Span:     ident.Span,
+		Library:  sym.Library,
+		Function: sym.Literal,
+	}
+}
+
+// checkCallArguments checks the arguments of library function call
+// with its parameters. Other calls can't be checked at parsing time.
+func (p *Parser) checkCallArguments(lparen Token, exp *ast.CallExpression) error {
+	q, ok := exp.Function.(*ast.QualifiedIdentifier)
+	if !ok {
+		return nil
+	}
+
+	params := q.Function.Parameters
+	if len(exp.Arguments) != len(params) {
+		return Error{
+			lparen,
+			fmt.Sprintf("function %s needs %d arguments, but got %d",
+				q.String(), len(params), len(exp.Arguments)),
+		}
+	}
+
+	for i, arg := range exp.Arguments {
+		if t := p.expressionType(arg); t != 0 && t != params[i].Type {
+			return Error{
+				lparen,
+				fmt.Sprintf("argument %s of function %s must be [%s], but got [%s]",
+					params[i].Identifier.String(), q.String(), params[i].Type.String(), t.String()),
+			}
+		}
+	}
+
+	return nil
+}
+
 // parseCallArguments parse arguments of function call
-func parseCallArguments(buf TokenBuffer) ([]ast.Expression, error) {
+func (p *Parser) parseCallArguments() ([]ast.Expression, error) {
 	args := []ast.Expression{}
-	if err := expectNext(buf, Lparen); err != nil {
+	if err := expectNext(p, Lparen); err != nil {
 		return nil, err
 	}
 
-	if curTokenIs(buf, Rparen) {
-		buf.Read()
+	if curTokenIs(p, Rparen) {
+		p.Read()
 		return args, nil
 	}
 
-	exp, err := parseExpression(buf, LOWEST)
+	exp, err := p.parseExpression(LOWEST)
 	if err != nil {
 		return nil, err
 	}
 
 	args = append(args, exp)
 
-	for curTokenIs(buf, Comma) {
-		buf.Read()
+	for curTokenIs(p, Comma) {
+		p.Read()
 
-		exp, err := parseExpression(buf, LOWEST)
+		exp, err := p.parseExpression(LOWEST)
 		if err != nil {
 			return nil, err
 		}
 		args = append(args, exp)
 	}
 
-	consumeSemi(buf)
+	consumeSemi(p)
 
-	if err := expectNext(buf, Rparen); err != nil {
+	if err := expectNext(p, Rparen); err != nil {
 		return nil, err
 	}
 
@@ -850,41 +2458,41 @@ func parseCallArguments(buf TokenBuffer) ([]ast.Expression, error) {
 }
 
 // parseIfStatement parse if-else statement. Else statement is optional
-func parseIfStatement(buf TokenBuffer) (*ast.IfStatement, error) {
-	if err := expectNext(buf, If); err != nil {
+func (p *Parser) parseIfStatement() (*ast.IfStatement, error) {
+	if err := expectNext(p, If); err != nil {
 		return nil, err
 	}
 
-	if err := expectNext(buf, Lparen); err != nil {
+	if err := expectNext(p, Lparen); err != nil {
 		return nil, err
 	}
 
 	expression := &ast.IfStatement{}
 	var err error
-	expression.Condition, err = parseExpression(buf, LOWEST)
+	expression.Condition, err = p.parseExpression(LOWEST)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := expectNext(buf, Rparen); err != nil {
+	if err := expectNext(p, Rparen); err != nil {
 		return nil, err
 	}
 
-	expression.Consequence, err = parseBlockStatement(buf)
+	expression.Consequence, err = p.parseBlockStatement()
 	if err != nil {
 		return nil, err
 	}
 
-	if curTokenIs(buf, Else) {
-		buf.Read()
+	if curTokenIs(p, Else) {
+		p.Read()
 
-		expression.Alternative, err = parseBlockStatement(buf)
+		expression.Alternative, err = p.parseBlockStatement()
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	consumeSemi(buf)
+	consumeSemi(p)
 
 	return expression, nil
 }
@@ -898,39 +2506,42 @@ func parseIfStatement(buf TokenBuffer) (*ast.IfStatement, error) {
 //
 //  parseBlockStatement parse: { ... } <-- left-brace + statements + Right-brace
 //
-func parseBlockStatement(buf TokenBuffer) (*ast.BlockStatement, error) {
-	if err := expectNext(buf, Lbrace); err != nil {
+func (p *Parser) parseBlockStatement() (*ast.BlockStatement, error) {
+	start := p.Peek(CURRENT)
+	if err := expectNext(p, Lbrace); err != nil {
 		return nil, err
 	}
 
-	enterScope()
+	p.enterScope()
 
 	block := &ast.BlockStatement{}
-	curToken := buf.Peek(CURRENT)
+	curToken := p.Peek(CURRENT)
 
 	for curToken.Type != Rbrace && curToken.Type != Eof {
-		stmt, err := parseStatement(buf)
+		stmt, err := p.parseStatementOrSkip()
 		if err != nil {
 			return nil, err
 		}
 		if stmt != nil {
 			block.Statements = append(block.Statements, stmt)
 		}
-		curToken = buf.Peek(CURRENT)
+		curToken = p.Peek(CURRENT)
 	}
 
-	if curTokenIs(buf, Rbrace) {
-		buf.Read()
+	if curTokenIs(p, Rbrace) {
+		p.Read()
 	}
 
-	leaveScope()
+	p.setPosition(block, startPos(start))
+	p.recordScope(block)
+	p.leaveScope()
 
 	return block, nil
 }
 
-func parseExpressionStatement(buf TokenBuffer) (*ast.ExpressionStatement, error) {
+func (p *Parser) parseExpressionStatement() (*ast.ExpressionStatement, error) {
 	stmt := &ast.ExpressionStatement{}
-	token := buf.Read()
+	token := p.Read()
 	if token.Type != Ident {
 		return nil, ExpectError{
 			token,
@@ -938,8 +2549,15 @@ func parseExpressionStatement(buf TokenBuffer) (*ast.ExpressionStatement, error)
 		}
 	}
 
-	ident := &ast.Identifier{Name: token.Val}
-	exp, err := parseCallExpression(buf, ident)
+	var fn ast.Expression = &ast.Identifier{Name: token.Val}
+	if curTokenIs(p, Dot) {
+		var err error
+		if fn, err = p.parseSelectorExpression(fn); err != nil {
+			return nil, err
+		}
+	}
+
+	exp, err := p.parseCallExpression(fn)
 	if err != nil {
 		return nil, err
 	}
@@ -19,6 +19,7 @@ package parse
 import (
 	"errors"
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/DE-labtory/koa/symbol"
@@ -57,6 +58,72 @@ func defaultChkScopeFn(_ *symbol.Scope) bool {
 	return true
 }
 
+// newTestParser returns the parser of buf whose scope is scope
+func newTestParser(buf TokenBuffer, scope *symbol.Scope) *Parser {
+	p := NewParser(buf, nil)
+	p.scope = scope
+
+	return p
+}
+
+var stateEnum = &ast.EnumLiteral{
+	Name: &ast.Identifier{Name: "State"},
+	Members: []*ast.Identifier{
+		{Name: "Open"},
+		{Name: "Closed"},
+		{Name: "Settled"},
+	},
+}
+
+var colorEnum = &ast.EnumLiteral{
+	Name: &ast.Identifier{Name: "Color"},
+	Members: []*ast.Identifier{
+		{Name: "Red"},
+	},
+}
+
+// setupEnumScopeFn builds Scope which has enum State and Color,
+// and variable s whose type is State
+func setupEnumScopeFn() *symbol.Scope {
+	scope := symbol.NewScope()
+	scope.Set("State", &symbol.Enum{Literal: stateEnum})
+	scope.Set("Color", &symbol.Enum{Literal: colorEnum})
+	scope.Set("s", &symbol.EnumValue{Name: &ast.Identifier{Name: "s"}, Enum: stateEnum})
+	return scope
+}
+
+var maxFunction = &ast.FunctionLiteral{
+	Name: &ast.Identifier{Name: "max"},
+	Parameters: []*ast.ParameterLiteral{
+		{Identifier: &ast.Identifier{Name: "a"}, Type: ast.IntType},
+		{Identifier: &ast.Identifier{Name: "b"}, Type: ast.IntType},
+	},
+	Body:       &ast.BlockStatement{},
+	ReturnType: ast.IntType,
+}
+
+var mathLibrary = &ast.Library{
+	Name:      "math",
+	Functions: []*ast.FunctionLiteral{maxFunction},
+}
+
+func setupLibraryScopeFn() *symbol.Scope {
+	scope := symbol.NewScope()
+	scope.Set("math", &symbol.Library{Literal: mathLibrary})
+	return scope
+}
+
+// mockImporter imports libraries by their import path
+type mockImporter map[string]*ast.Library
+
+func (m mockImporter) Import(path string) (*ast.Library, error) {
+	library, ok := m[path]
+	if !ok {
+		return nil, fmt.Errorf("%s is not found", path)
+	}
+	return library, nil
+}
+
 // TestParserOnly tests three things
 //
 // 1. "contract" keyword with its open-brace & close-brace
@@ -95,7 +162,7 @@ contract {
 			},
 			expected: "",
 			expectedErr: ExpectError{
-				Token{Eof, "eof", 0, 0},
+				Token{Eof, "eof", 0, 0, 0},
 				Rbrace,
 			},
 		},
@@ -215,9 +282,196 @@ func bar(Parameter : (Identifier: a, Type: int)) void {
 			},
 			expected: ``,
 			expectedErr: ExpectError{
-				Token{IntType, "int", 0, 0},
+				Token{IntType, "int", 0, 0, 0},
 				Rbrace,
 			},
+		}, {
+			buf: &mockTokenBuffer{
+				[]Token{
+					{Type: Contract, Val: "contract"},
+					{Type: Lbrace, Val: "{"},
+					{Type: Enum, Val: "enum"},
+					{Type: Ident, Val: "State"},
+					{Type: Lbrace, Val: "{"},
+					{Type: Ident, Val: "Open"},
+					{Type: Comma, Val: ","},
+					{Type: Ident, Val: "Closed"},
+					{Type: Rbrace, Val: "}"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Function, Val: "func"},
+					{Type: Ident, Val: "next"},
+					{Type: Lparen, Val: "("},
+					{Type: Ident, Val: "s"},
+					{Type: Ident, Val: "State"},
+					{Type: Rparen, Val: ")"},
+					{Type: Ident, Val: "State"},
+					{Type: Lbrace, Val: "{"},
+					{Type: Ident, Val: "State"},
+					{Type: Ident, Val: "t"},
+					{Type: Assign, Val: "="},
+					{Type: Ident, Val: "State"},
+					{Type: Dot, Val: "."},
+					{Type: Ident, Val: "Closed"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Return, Val: "return"},
+					{Type: Ident, Val: "t"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Rbrace, Val: "}"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Rbrace, Val: "}"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Eof},
+				},
+				0,
+			},
+			expected: `
+contract {
+enum State { Open, Closed }
+func next(Parameter : (Identifier: s, Type: State)) State {
+State t = State.Closed
+return t
+}
+}`,
+		},
+		{
+			buf: &mockTokenBuffer{
+				[]Token{
+					{Type: Contract, Val: "contract"},
+					{Type: Lbrace, Val: "{"},
+					{Type: Enum, Val: "enum"},
+					{Type: Ident, Val: "State"},
+					{Type: Lbrace, Val: "{"},
+					{Type: Ident, Val: "Open"},
+					{Type: Rbrace, Val: "}"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Enum, Val: "enum"},
+					{Type: Ident, Val: "Color"},
+					{Type: Lbrace, Val: "{"},
+					{Type: Ident, Val: "Red"},
+					{Type: Rbrace, Val: "}"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Function, Val: "func"},
+					{Type: Ident, Val: "foo"},
+					{Type: Lparen, Val: "("},
+					{Type: Rparen, Val: ")"},
+					{Type: Lbrace, Val: "{"},
+					{Type: Ident, Val: "State"},
+					{Type: Ident, Val: "t"},
+					{Type: Assign, Val: "="},
+					{Type: Ident, Val: "Color"},
+					{Type: Dot, Val: "."},
+					{Type: Ident, Val: "Red"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Rbrace, Val: "}"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Rbrace, Val: "}"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Eof},
+				},
+				0,
+			},
+			expected: ``,
+			expectedErr: Error{
+				Token{Type: Ident, Val: "t"},
+				"can't assign Color.Red to t of enum State",
+			},
+		}, {
+			buf: &mockTokenBuffer{
+				[]Token{
+					{Type: Contract, Val: "contract"},
+					{Type: Lbrace, Val: "{"},
+					{Type: Modifier, Val: "modifier"},
+					{Type: Ident, Val: "positive"},
+					{Type: Lparen, Val: "("},
+					{Type: Ident, Val: "n"},
+					{Type: IntType, Val: "int"},
+					{Type: Rparen, Val: ")"},
+					{Type: Lbrace, Val: "{"},
+					{Type: Ident, Val: "_"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Rbrace, Val: "}"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Modifier, Val: "modifier"},
+					{Type: Ident, Val: "logged"},
+					{Type: Lparen, Val: "("},
+					{Type: Rparen, Val: ")"},
+					{Type: Lbrace, Val: "{"},
+					{Type: Ident, Val: "_"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Rbrace, Val: "}"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Function, Val: "func"},
+					{Type: Ident, Val: "foo"},
+					{Type: Lparen, Val: "("},
+					{Type: Ident, Val: "a"},
+					{Type: IntType, Val: "int"},
+					{Type: Rparen, Val: ")"},
+					{Type: Ident, Val: "positive"},
+					{Type: Lparen, Val: "("},
+					{Type: Ident, Val: "a"},
+					{Type: Rparen, Val: ")"},
+					{Type: Ident, Val: "logged"},
+					{Type: IntType, Val: "int"},
+					{Type: Lbrace, Val: "{"},
+					{Type: Return, Val: "return"},
+					{Type: Ident, Val: "a"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Rbrace, Val: "}"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Rbrace, Val: "}"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Eof},
+				},
+				0,
+			},
+			expected: `
+contract {
+modifier positive(Parameter : (Identifier: n, Type: int)) {
+_
+}
+modifier logged() {
+_
+}
+func foo(Parameter : (Identifier: a, Type: int)) positive(a) logged() int {
+return a
+}
+}`,
+		},
+		{
+			buf: &mockTokenBuffer{
+				[]Token{
+					{Type: Contract, Val: "contract"},
+					{Type: Lbrace, Val: "{"},
+					{Type: Modifier, Val: "modifier"},
+					{Type: Ident, Val: "positive"},
+					{Type: Lparen, Val: "("},
+					{Type: Ident, Val: "n"},
+					{Type: IntType, Val: "int"},
+					{Type: Rparen, Val: ")"},
+					{Type: Lbrace, Val: "{"},
+					{Type: Ident, Val: "_"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Rbrace, Val: "}"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Function, Val: "func"},
+					{Type: Ident, Val: "foo"},
+					{Type: Lparen, Val: "("},
+					{Type: Rparen, Val: ")"},
+					{Type: Ident, Val: "positive"},
+					{Type: Lbrace, Val: "{"},
+					{Type: Rbrace, Val: "}"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Rbrace, Val: "}"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Eof},
+				},
+				0,
+			},
+			expected: ``,
+			expectedErr: Error{
+				Token{Type: Ident, Val: "positive"},
+				"modifier positive needs 1 arguments, but got 0",
+			},
 		},
 	}
 
@@ -448,7 +702,7 @@ func TestExpectNext(t *testing.T) {
 			token:        Minus,
 			expectedBool: false,
 			expectedError: ExpectError{
-				Token{Ident, "a", 0, 0},
+				Token{Ident, "a", 0, 0, 0},
 				Minus,
 			},
 		},
@@ -461,7 +715,7 @@ func TestExpectNext(t *testing.T) {
 			token:        Rbrace,
 			expectedBool: false,
 			expectedError: ExpectError{
-				Token{Asterisk, "*", 0, 0},
+				Token{Asterisk, "*", 0, 0, 0},
 				Rbrace,
 			},
 		},
@@ -496,6 +750,7 @@ func TestParseIdentifier(t *testing.T) {
 						"1",
 						24,
 						12,
+						0,
 					},
 				},
 				0,
@@ -503,7 +758,7 @@ func TestParseIdentifier(t *testing.T) {
 			setupScope: defaultSetupScopeFn,
 			expected:   nil,
 			expectedErrs: ExpectError{
-				Token{Int, "1", 24, 12},
+				Token{Int, "1", 24, 12, 0},
 				Ident,
 			},
 		},
@@ -515,6 +770,7 @@ func TestParseIdentifier(t *testing.T) {
 						"ADD",
 						125,
 						225,
+						0,
 					},
 				},
 				0,
@@ -537,6 +793,7 @@ func TestParseIdentifier(t *testing.T) {
 						"a",
 						125,
 						225,
+						0,
 					},
 				},
 				0,
@@ -549,6 +806,7 @@ func TestParseIdentifier(t *testing.T) {
 					"a",
 					125,
 					225,
+					0,
 				},
 			},
 		},
@@ -560,6 +818,7 @@ func TestParseIdentifier(t *testing.T) {
 						"+",
 						422,
 						12,
+						0,
 					},
 				},
 				0,
@@ -567,7 +826,7 @@ func TestParseIdentifier(t *testing.T) {
 			setupScope: defaultSetupScopeFn,
 			expected:   nil,
 			expectedErrs: ExpectError{
-				Token{Plus, "+", 422, 12},
+				Token{Plus, "+", 422, 12, 0},
 				Ident,
 			},
 		},
@@ -579,6 +838,7 @@ func TestParseIdentifier(t *testing.T) {
 						"*",
 						12,
 						123,
+						0,
 					},
 				},
 				0,
@@ -586,7 +846,7 @@ func TestParseIdentifier(t *testing.T) {
 			setupScope: defaultSetupScopeFn,
 			expected:   nil,
 			expectedErrs: ExpectError{
-				Token{Asterisk, "*", 12, 123},
+				Token{Asterisk, "*", 12, 123, 0},
 				Ident,
 			},
 		},
@@ -598,6 +858,7 @@ func TestParseIdentifier(t *testing.T) {
 						"(",
 						5,
 						876,
+						0,
 					},
 				},
 				0,
@@ -605,15 +866,15 @@ func TestParseIdentifier(t *testing.T) {
 			setupScope: defaultSetupScopeFn,
 			expected:   nil,
 			expectedErrs: ExpectError{
-				Token{Lparen, "(", 5, 876},
+				Token{Lparen, "(", 5, 876, 0},
 				Ident,
 			},
 		},
 	}
 
 	for i, test := range tests {
-		scope = test.setupScope()
-		exp, err := parseIdentifier(test.buf)
+		scope := test.setupScope()
+		exp, err := newTestParser(test.buf, scope).parseIdentifier()
 
 		if err != nil && err.Error() != test.expectedErrs.Error() {
 			t.Fatalf("test[%d] - wrong error. Expected=%s, got=%s", i, test.expectedErrs, err)
@@ -639,6 +900,19 @@ func TestParseIntegerLiteral(t *testing.T) {
 		{Type: Int, Val: "a"},
 		{Type: String, Val: "abcdefg"},
 		{Type: Int, Val: "-13"},
+		{Type: Int, Val: "0x1F"},
+		{Type: Int, Val: "0b101"},
+		{Type: Int, Val: "1_000_000"},
+		{Type: Int, Val: "0b12"},
+		{Type: Int, Val: "0x8000000000000000"},
+		{Type: Int, Val: "0X1f"},
+		{Type: Int, Val: "0B11"},
+		{Type: Int, Val: "0o17"},
+		{Type: Int, Val: "010"},
+		{Type: Int, Val: "0"},
+		{Type: Int, Val: "1__000"},
+		{Type: Int, Val: "0x_ff"},
+		{Type: Int, Val: "0x"},
 	}
 	tokenBuf := mockTokenBuffer{tokens, 0}
 	tests := []struct {
@@ -655,7 +929,7 @@ func TestParseIntegerLiteral(t *testing.T) {
 		},
 		{
 			expected:    nil,
-			expectedErr: errors.New(`strconv.ParseInt: parsing "a": invalid syntax`),
+			expectedErr: Error{Token{Type: Int, Val: "a"}, "invalid integer a"},
 		},
 		{
 			expected: nil,
@@ -668,12 +942,64 @@ func TestParseIntegerLiteral(t *testing.T) {
 			expected:    &ast.IntegerLiteral{Value: -13},
 			expectedErr: nil,
 		},
+		{
+			expected:    &ast.IntegerLiteral{Value: 31},
+			expectedErr: nil,
+		},
+		{
+			expected:    &ast.IntegerLiteral{Value: 5},
+			expectedErr: nil,
+		},
+		{
+			expected:    &ast.IntegerLiteral{Value: 1000000},
+			expectedErr: nil,
+		},
+		{
+			expected:    nil,
+			expectedErr: Error{Token{Type: Int, Val: "0b12"}, "invalid integer 0b12"},
+		},
+		{
+			expected:    nil,
+			expectedErr: Error{Token{Type: Int, Val: "0x8000000000000000"}, "integer 0x8000000000000000 out of range"},
+		},
+		{
+			expected:    &ast.IntegerLiteral{Value: 31},
+			expectedErr: nil,
+		},
+		{
+			expected:    &ast.IntegerLiteral{Value: 3},
+			expectedErr: nil,
+		},
+		{
+			expected:    nil,
+			expectedErr: Error{Token{Type: Int, Val: "0o17"}, "invalid integer 0o17"},
+		},
+		{
+			expected:    nil,
+			expectedErr: Error{Token{Type: Int, Val: "010"}, "invalid integer 010"},
+		},
+		{
+			expected:    &ast.IntegerLiteral{Value: 0},
+			expectedErr: nil,
+		},
+		{
+			expected:    nil,
+			expectedErr: Error{Token{Type: Int, Val: "1__000"}, "invalid integer 1__000"},
+		},
+		{
+			expected:    nil,
+			expectedErr: Error{Token{Type: Int, Val: "0x_ff"}, "invalid integer 0x_ff"},
+		},
+		{
+			expected:    nil,
+			expectedErr: Error{Token{Type: Int, Val: "0x"}, "invalid integer 0x"},
+		},
 	}
 
 	for i, test := range tests {
 		// For debugging
 		tokenBuf.sp = i
-		exp, err := parseIntegerLiteral(&tokenBuf)
+		exp, err := NewParser(&tokenBuf, nil).parseIntegerLiteral()
 		if err != nil && err.Error() != test.expectedErr.Error() {
 			t.Fatalf("test[%d] - TestParseIntegerLiteral() wrong error. Expected=%s, got=%s",
 				i, test.expectedErr, err.Error())
@@ -699,11 +1025,11 @@ func TestParseBooleanLiteral(t *testing.T) {
 		expectedErr error
 	}{
 		{
-			expected:    &ast.BooleanLiteral{true},
+			expected:    &ast.BooleanLiteral{Value: true},
 			expectedErr: nil,
 		},
 		{
-			expected:    &ast.BooleanLiteral{false},
+			expected:    &ast.BooleanLiteral{Value: false},
 			expectedErr: nil,
 		},
 		{
@@ -720,7 +1046,7 @@ func TestParseBooleanLiteral(t *testing.T) {
 	}
 
 	for i, test := range tests {
-		exp, err := parseBooleanLiteral(&tokenBuf)
+		exp, err := NewParser(&tokenBuf, nil).parseBooleanLiteral()
 
 		if err != nil && err.Error() != test.expectedErr.Error() {
 			t.Fatalf(`test[%d] - TestParseBooleanLiteral() wrong error. Expected="%s", got="%s"`,
@@ -745,6 +1071,12 @@ func TestParseStringLiteral(t *testing.T) {
 		{Type: String, Val: "hihi"},
 		{Type: Int, Val: "3"},
 		{Type: String, Val: "koa zzang"},
+		{Type: String, Val: `"a\n\"b\"\x41\\"`},
+		{Type: String, Val: `b"\x01\xff"`},
+		{Type: String, Val: `hex"deadBEEF"`},
+		{Type: String, Val: `"\q"`},
+		{Type: String, Val: `"\x4"`},
+		{Type: String, Val: `hex"abc"`},
 	}
 	tokenBuf := mockTokenBuffer{tokens, 0}
 	tests := []struct {
@@ -770,12 +1102,36 @@ func TestParseStringLiteral(t *testing.T) {
 			expected:    &ast.StringLiteral{Value: "koa zzang"},
 			expectedErr: nil,
 		},
+		{
+			expected:    &ast.StringLiteral{Value: "a\n\"b\"A\\"},
+			expectedErr: nil,
+		},
+		{
+			expected:    &ast.StringLiteral{Value: "\x01\xff"},
+			expectedErr: nil,
+		},
+		{
+			expected:    &ast.StringLiteral{Value: "\xde\xad\xbe\xef"},
+			expectedErr: nil,
+		},
+		{
+			expected:    nil,
+			expectedErr: Error{Token{Type: String, Val: `"\q"`}, `unknown escape sequence \q`},
+		},
+		{
+			expected:    nil,
+			expectedErr: Error{Token{Type: String, Val: `"\x4"`}, `escape sequence \x needs two hex digits`},
+		},
+		{
+			expected:    nil,
+			expectedErr: Error{Token{Type: String, Val: `hex"abc"`}, `invalid hex string hex"abc"`},
+		},
 	}
 
 	for i, test := range tests {
 		// For debbuging
 		tokenBuf.sp = i
-		exp, err := parseStringLiteral(&tokenBuf)
+		exp, err := NewParser(&tokenBuf, nil).parseStringLiteral()
 
 		switch err != nil {
 		case true:
@@ -795,1312 +1151,1456 @@ func TestParseStringLiteral(t *testing.T) {
 	}
 }
 
-func TestParseFunctionLiteral(t *testing.T) {
-	initParseFnMap()
-
+func TestParseEnumLiteral(t *testing.T) {
 	tests := []struct {
-		buf          TokenBuffer
-		setupScope   setupScopeFn
-		expectedExpr string
-		expectedErr  error
+		buf         TokenBuffer
+		setupScope  setupScopeFn
+		expected    string
+		expectedErr error
 	}{
 		{
 			&mockTokenBuffer{
 				[]Token{
-					// func example (a int, b string) {}
-					{Type: Function, Val: "func"},
-					{Type: Ident, Val: "example"},
-					{Type: Lparen, Val: "("},
-					{Type: Ident, Val: "a"},
-					{Type: IntType, Val: "int"},
-					{Type: Comma, Val: ","},
-					{Type: Ident, Val: "b"},
-					{Type: StringType, Val: "string"},
-					{Type: Rparen, Val: ")"},
+					{Type: Enum, Val: "enum"},
+					{Type: Ident, Val: "State"},
 					{Type: Lbrace, Val: "{"},
+					{Type: Ident, Val: "Open"},
+					{Type: Comma, Val: ","},
+					{Type: Ident, Val: "Closed"},
 					{Type: Rbrace, Val: "}"},
 					{Type: Semicolon, Val: "\n"},
-					{Type: Eof, Val: "eof"},
+					{Type: Eof},
 				},
 				0,
 			},
 			defaultSetupScopeFn,
-			"func example(Parameter : (Identifier: a, Type: int), Parameter : (Identifier: b, Type: string)) void {\n\n}",
+			"enum State { Open, Closed }",
 			nil,
 		},
+		// members written in multiple lines, with trailing comma
 		{
 			&mockTokenBuffer{
 				[]Token{
-					// func name (a int, b string) {
-					//	int c = 5
-					// }
-					{Type: Function, Val: "func"},
-					{Type: Ident, Val: "name"},
-					{Type: Lparen, Val: "("},
-					{Type: Ident, Val: "a"},
-					{Type: IntType, Val: "int"},
-					{Type: Comma, Val: ","},
-					{Type: Ident, Val: "b"},
-					{Type: StringType, Val: "string"},
-					{Type: Rparen, Val: ")"},
+					{Type: Enum, Val: "enum"},
+					{Type: Ident, Val: "State"},
 					{Type: Lbrace, Val: "{"},
-					{Type: IntType, Val: "int"},
-					{Type: Ident, Val: "c"},
-					{Type: Assign, Val: "="},
-					{Type: Int, Val: "5"},
+					{Type: Ident, Val: "Open"},
+					{Type: Comma, Val: ","},
+					{Type: Ident, Val: "Closed"},
+					{Type: Comma, Val: ","},
+					{Type: Ident, Val: "Settled"},
 					{Type: Semicolon, Val: "\n"},
 					{Type: Rbrace, Val: "}"},
 					{Type: Semicolon, Val: "\n"},
-					{Type: Eof, Val: "eof"},
+					{Type: Eof},
 				},
 				0,
 			},
 			defaultSetupScopeFn,
-			"func name(Parameter : (Identifier: a, Type: int), Parameter : (Identifier: b, Type: string)) void {\nint c = 5\n}",
+			"enum State { Open, Closed, Settled }",
 			nil,
 		},
 		{
 			&mockTokenBuffer{
 				[]Token{
-					// error case
+					{Type: Enum, Val: "enum"},
+					{Type: Ident, Val: "State"},
 					{Type: Lbrace, Val: "{"},
-					{Type: Eof, Val: "eof"},
-				},
+					{Type: Ident, Val: "Open"},
+					{Type: Comma, Val: ","},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Rbrace, Val: "}"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Eof},
+				},
 				0,
 			},
 			defaultSetupScopeFn,
-			"",
-			ExpectError{
-				Token{Lbrace, "{", 0, 0},
-				Function,
-			},
+			"enum State { Open }",
+			nil,
 		},
 		{
 			&mockTokenBuffer{
 				[]Token{
-					// func example () string {}
-					{Type: Function, Val: "func"},
-					{Type: Ident, Val: "example"},
-					{Type: Lparen, Val: "("},
-					{Type: Rparen, Val: ")"},
-					{Type: StringType, Val: "string"},
+					{Type: Enum, Val: "enum"},
+					{Type: Ident, Val: "State"},
 					{Type: Lbrace, Val: "{"},
+					{Type: Ident, Val: "Open"},
+					{Type: Comma, Val: ","},
+					{Type: Ident, Val: "Open"},
 					{Type: Rbrace, Val: "}"},
-					{Type: Semicolon, Val: "\n"},
-					{Type: Eof, Val: "eof"},
+					{Type: Eof},
 				},
 				0,
 			},
 			defaultSetupScopeFn,
-			"func example() string {\n\n}",
-			nil,
+			"",
+			DupSymError{Token{Type: Ident, Val: "Open"}},
 		},
 		{
 			&mockTokenBuffer{
 				[]Token{
-					// func example () string {}
-					{Type: Function, Val: "func"},
-					{Type: Ident, Val: "example"},
-					{Type: Lparen, Val: "("},
-					{Type: Rparen, Val: ")"},
-					{Type: StringType, Val: "string"},
+					{Type: Enum, Val: "enum"},
+					{Type: Ident, Val: "State"},
 					{Type: Lbrace, Val: "{"},
-					{Type: If, Val: "if"},
-					{Type: Lparen, Val: "("},
-					{Type: True, Val: "true"},
-					{Type: Rparen, Val: ")"},
+					{Type: Ident, Val: "Open"},
+					{Type: Rbrace, Val: "}"},
+					{Type: Eof},
+				},
+				0,
+			},
+			setupEnumScopeFn,
+			"",
+			DupSymError{Token{Type: Ident, Val: "State"}},
+		},
+		{
+			&mockTokenBuffer{
+				[]Token{
+					{Type: Enum, Val: "enum"},
+					{Type: Ident, Val: "State"},
 					{Type: Lbrace, Val: "{"},
 					{Type: Rbrace, Val: "}"},
-					{Type: Else, Val: "else"},
+					{Type: Eof},
+				},
+				0,
+			},
+			defaultSetupScopeFn,
+			"",
+			Error{
+				Token{Type: Ident, Val: "State"},
+				"enum should have at least one member",
+			},
+		},
+		{
+			&mockTokenBuffer{
+				[]Token{
+					{Type: Enum, Val: "enum"},
+					{Type: Ident, Val: "State"},
 					{Type: Lbrace, Val: "{"},
+					{Type: Ident, Val: "Open"},
+					{Type: Ident, Val: "Closed"},
 					{Type: Rbrace, Val: "}"},
-					//{Type: Semicolon, Val: "\n"},
-					{Type: Semicolon, Val: "\n"},
+					{Type: Eof},
+				},
+				0,
+			},
+			defaultSetupScopeFn,
+			"",
+			ExpectError{
+				Token{Type: Ident, Val: "Closed"},
+				Rbrace,
+			},
+		},
+		{
+			&mockTokenBuffer{
+				[]Token{
+					{Type: Enum, Val: "enum"},
+					{Type: Ident, Val: "State"},
+					{Type: Lbrace, Val: "{"},
+					{Type: Int, Val: "1"},
 					{Type: Rbrace, Val: "}"},
-					{Type: Semicolon, Val: "\n"},
-					{Type: Eof, Val: "eof"},
+					{Type: Eof},
 				},
 				0,
 			},
 			defaultSetupScopeFn,
-			`func example() string {
-if ( true ) {  } else {  }
-}`,
-			nil,
+			"",
+			ExpectError{
+				Token{Type: Int, Val: "1"},
+				Ident,
+			},
 		},
+	}
+
+	for i, test := range tests {
+		scope := test.setupScope()
+		enum, err := newTestParser(test.buf, scope).parseEnumLiteral()
+
+		if err != nil && (test.expectedErr == nil || err.Error() != test.expectedErr.Error()) {
+			t.Fatalf("test[%d] - parseEnumLiteral() with wrong error. Expected=%v, got=%v",
+				i, test.expectedErr, err)
+		}
+
+		if err == nil && test.expectedErr != nil {
+			t.Fatalf("test[%d] - parseEnumLiteral() should return error. Expected=%v",
+				i, test.expectedErr)
+		}
+
+		if err != nil {
+			continue
+		}
+
+		if enum.String() != test.expected {
+			t.Fatalf("test[%d] - parseEnumLiteral() with wrong result. Expected=%s, got=%s",
+				i, test.expected, enum.String())
+		}
+
+		sym, ok := scope.Get(enum.Name.Name).(*symbol.Enum)
+		if !ok || sym.Literal != enum {
+			t.Fatalf("test[%d] - parseEnumLiteral() should declare enum symbol, got=%v",
+				i, scope.Get(enum.Name.Name))
+		}
+	}
+}
+
+func TestParseModifierLiteral(t *testing.T) {
+	tests := []struct {
+		buf         TokenBuffer
+		setupScope  setupScopeFn
+		expected    string
+		expectedErr error
+	}{
 		{
 			&mockTokenBuffer{
 				[]Token{
-					// func example () string {}
-					{Type: Function, Val: "func"},
-					{Type: Ident, Val: "example"},
+					{Type: Modifier, Val: "modifier"},
+					{Type: Ident, Val: "positive"},
 					{Type: Lparen, Val: "("},
+					{Type: Ident, Val: "a"},
+					{Type: IntType, Val: "int"},
 					{Type: Rparen, Val: ")"},
-					{Type: StringType, Val: "string"},
 					{Type: Lbrace, Val: "{"},
-					{Type: If, Val: "if"},
+					{Type: Require, Val: "require"},
 					{Type: Lparen, Val: "("},
-					{Type: True, Val: "true"},
+					{Type: Ident, Val: "a"},
+					{Type: GT, Val: ">"},
+					{Type: Int, Val: "0"},
 					{Type: Rparen, Val: ")"},
-					{Type: Lbrace, Val: "{"},
-					{Type: Rbrace, Val: "}"},
-					{Type: Else, Val: "else"},
-					{Type: Lbrace, Val: "{"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Ident, Val: "_"},
+					{Type: Semicolon, Val: "\n"},
 					{Type: Rbrace, Val: "}"},
 					{Type: Semicolon, Val: "\n"},
-					{Type: If, Val: "if"},
+					{Type: Eof},
+				},
+				0,
+			},
+			defaultSetupScopeFn,
+			`modifier positive(Parameter : (Identifier: a, Type: int)) {
+require((a > 0))
+_
+}`,
+			nil,
+		},
+		{
+			&mockTokenBuffer{
+				[]Token{
+					{Type: Modifier, Val: "modifier"},
+					{Type: Ident, Val: "logged"},
 					{Type: Lparen, Val: "("},
-					{Type: True, Val: "true"},
 					{Type: Rparen, Val: ")"},
 					{Type: Lbrace, Val: "{"},
-					{Type: Rbrace, Val: "}"},
-					{Type: Else, Val: "else"},
-					{Type: Lbrace, Val: "{"},
-					{Type: Rbrace, Val: "}"},
-					{Type: Semicolon, Val: "\n"},
+					{Type: Ident, Val: "_"},
 					{Type: Rbrace, Val: "}"},
 					{Type: Semicolon, Val: "\n"},
-					{Type: Eof, Val: "eof"},
+					{Type: Eof},
 				},
 				0,
 			},
 			defaultSetupScopeFn,
-			`func example() string {
-if ( true ) {  } else {  }
-if ( true ) {  } else {  }
+			`modifier logged() {
+_
 }`,
 			nil,
 		},
 		{
 			&mockTokenBuffer{
 				[]Token{
-					// func example () invalid {}
-					{Type: Function, Val: "func"},
-					{Type: Ident, Val: "example"},
+					{Type: Modifier, Val: "modifier"},
+					{Type: Ident, Val: "empty"},
 					{Type: Lparen, Val: "("},
 					{Type: Rparen, Val: ")"},
-					{Type: Illegal, Val: "invalid"},
 					{Type: Lbrace, Val: "{"},
 					{Type: Rbrace, Val: "}"},
-					{Type: Semicolon, Val: "\n"},
-					{Type: Eof, Val: "eof"},
+					{Type: Eof},
 				},
 				0,
 			},
 			defaultSetupScopeFn,
 			"",
 			Error{
-				Token{Type: Illegal},
-				"invalid function return type",
+				Token{Type: Ident, Val: "empty"},
+				`modifier should have exactly one placeholder "_", but got 0`,
 			},
 		},
 		{
 			&mockTokenBuffer{
 				[]Token{
-					// func example () invalid {}
-					{Type: Function, Val: "func"},
-					{Type: Ident, Val: "example"},
+					{Type: Modifier, Val: "modifier"},
+					{Type: Ident, Val: "twice"},
 					{Type: Lparen, Val: "("},
 					{Type: Rparen, Val: ")"},
-					{Type: Int, Val: "1"},
 					{Type: Lbrace, Val: "{"},
-					{Type: Rbrace, Val: "}"},
+					{Type: Ident, Val: "_"},
 					{Type: Semicolon, Val: "\n"},
-					{Type: Eof, Val: "eof"},
+					{Type: Ident, Val: "_"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Rbrace, Val: "}"},
+					{Type: Eof},
 				},
 				0,
 			},
 			defaultSetupScopeFn,
 			"",
 			Error{
-				Token{Type: Int},
-				"invalid function return type",
+				Token{Type: Ident, Val: "twice"},
+				`modifier should have exactly one placeholder "_", but got 2`,
 			},
 		},
 		{
 			&mockTokenBuffer{
 				[]Token{
-					// func example (a int, b string) {}
-					{Type: Function, Val: "func"},
-					{Type: Ident, Val: "example"},
+					{Type: Modifier, Val: "modifier"},
+					{Type: Ident, Val: "State"},
 					{Type: Lparen, Val: "("},
-					{Type: Ident, Val: "a"},
-					{Type: IntType, Val: "int"},
-					{Type: Comma, Val: ","},
-					{Type: Ident, Val: "b"},
-					{Type: StringType, Val: "string"},
 					{Type: Rparen, Val: ")"},
 					{Type: Lbrace, Val: "{"},
+					{Type: Ident, Val: "_"},
 					{Type: Rbrace, Val: "}"},
-					{Type: Semicolon, Val: "\n"},
-					{Type: Eof, Val: "eof"},
+					{Type: Eof},
 				},
 				0,
 			},
-			func() *symbol.Scope {
-				scope := symbol.NewScope()
-				scope.Set("example", &symbol.Function{Name: "example"})
-				return scope
-			},
+			setupEnumScopeFn,
 			"",
-			DupSymError{Token{Type: Ident, Val: "example"}},
+			DupSymError{Token{Type: Ident, Val: "State"}},
 		},
 	}
 
 	for i, test := range tests {
-		scope = test.setupScope()
+		scope := test.setupScope()
+		modifier, err := newTestParser(test.buf, scope).parseModifierLiteral()
 
-		exp, err := parseFunctionLiteral(test.buf)
+		if err != nil && (test.expectedErr == nil || err.Error() != test.expectedErr.Error()) {
+			t.Fatalf("test[%d] - parseModifierLiteral() with wrong error. Expected=%v, got=%v",
+				i, test.expectedErr, err)
+		}
 
-		if err != nil && err.Error() != test.expectedErr.Error() {
-			t.Fatalf("test[%d] - TestParseFunctionLiteral() wrong error\n"+
-				"Expected: %s\n"+
-				"got: %s", i, test.expectedErr.Error(), err.Error())
+		if err == nil && test.expectedErr != nil {
+			t.Fatalf("test[%d] - parseModifierLiteral() should return error. Expected=%v",
+				i, test.expectedErr)
 		}
 
-		if exp != nil && exp.String() != test.expectedExpr {
-			t.Fatalf("test[%d] - TestParseFunctionLiteral wrong result\n"+
-				"Expected: %s\n"+
-				"got: %s", i, test.expectedExpr, exp.String())
+		if err != nil {
+			continue
+		}
+
+		if modifier.String() != test.expected {
+			t.Fatalf("test[%d] - parseModifierLiteral() with wrong result. Expected=%s, got=%s",
+				i, test.expected, modifier.String())
+		}
+
+		if sym, ok := scope.Get(modifier.Name.Name).(*symbol.Modifier); !ok || sym.Literal != modifier {
+			t.Fatalf("test[%d] - parseModifierLiteral() should declare modifier symbol, got=%v",
+				i, scope.Get(modifier.Name.Name))
 		}
 	}
 }
 
-func TestParseFunctionParameter(t *testing.T) {
-	initParseFnMap()
+func TestParseRequireStatement(t *testing.T) {
 	tests := []struct {
 		buf         TokenBuffer
-		setupScope  setupScopeFn
-		expected    []string
+		expected    string
 		expectedErr error
 	}{
 		{
-			buf: &mockTokenBuffer{
+			&mockTokenBuffer{
 				[]Token{
-					{Type: Ident, Val: "a"},
-					{Type: IntType, Val: "int"},
-					{Type: Comma, Val: ","},
-					{Type: Ident, Val: "b"},
-					{Type: StringType, Val: "string"},
+					{Type: Require, Val: "require"},
+					{Type: Lparen, Val: "("},
+					{Type: Int, Val: "1"},
+					{Type: LT, Val: "<"},
+					{Type: Int, Val: "2"},
 					{Type: Rparen, Val: ")"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Eof},
 				},
 				0,
 			},
-			setupScope: defaultSetupScopeFn,
-			expected: []string{
-				"Parameter : (Identifier: a, Type: int)",
-				"Parameter : (Identifier: b, Type: string)",
-			},
-			expectedErr: nil,
+			"require((1 < 2))",
+			nil,
 		},
 		{
-			buf: &mockTokenBuffer{
+			&mockTokenBuffer{
 				[]Token{
-					{Type: Ident, Val: "arg"},
-					{Type: BoolType, Val: "bool"},
+					{Type: Require, Val: "require"},
+					{Type: Lparen, Val: "("},
+					{Type: Int, Val: "1"},
 					{Type: Rparen, Val: ")"},
+					{Type: Eof},
 				},
 				0,
 			},
-			setupScope: defaultSetupScopeFn,
-			expected: []string{
-				"Parameter : (Identifier: arg, Type: bool)",
+			"",
+			Error{
+				Token{Type: Require, Val: "require"},
+				"condition of require must be [bool], but got [int]",
 			},
-			expectedErr: nil,
 		},
 		{
-			buf: &mockTokenBuffer{
+			&mockTokenBuffer{
 				[]Token{
-					{Type: Ident, Val: "arg"},
-					{Type: IntType, Val: "int"},
-					{Type: Rbrace, Val: "}"},
+					{Type: Require, Val: "require"},
+					{Type: True, Val: "true"},
+					{Type: Eof},
 				},
 				0,
 			},
-			setupScope: defaultSetupScopeFn,
-			expected:   nil,
-			expectedErr: ExpectError{
-				Token{Rbrace, "}", 0, 0},
-				Rparen,
+			"",
+			ExpectError{
+				Token{Type: True, Val: "true"},
+				Lparen,
 			},
 		},
-		{
-			buf: &mockTokenBuffer{
-				[]Token{
-					{Type: Ident, Val: "arg"},
-					{Type: IntType, Val: "int"},
-					{Type: Comma, Val: ","},
-					{Type: Ident, Val: "arg"},
-					{Type: IntType, Val: "int"},
-					{Type: Rparen, Val: ")"},
-				},
-				0,
-			},
-			setupScope:  defaultSetupScopeFn,
-			expected:    nil,
-			expectedErr: DupSymError{Token{Type: Ident, Val: "arg"}},
-		},
 	}
 
 	for i, test := range tests {
-		scope = test.setupScope()
-		identifiers, err := parseFunctionParameterList(test.buf)
-		if err != nil && err.Error() != test.expectedErr.Error() {
-			t.Fatalf("test[%d] - TestParseFunctionParameter() wrong error.\n"+
-				"Expected: %s\n"+
-				"got: %s", i, test.expectedErr.Error(), err.Error())
-		} else {
-			for j, identifier := range identifiers {
-				if identifier.String() != test.expected[j] {
-					t.Fatalf("test[%d-%d] - TestParseFunctionParameter() failed.\n"+
-						"Expected: %s\n"+
-						"got: %s", i, j, test.expected[j], identifier)
-				}
-			}
+		scope := symbol.NewScope()
+		stmt, err := newTestParser(test.buf, scope).parseRequireStatement()
+
+		if err != nil && (test.expectedErr == nil || err.Error() != test.expectedErr.Error()) {
+			t.Fatalf("test[%d] - parseRequireStatement() with wrong error. Expected=%v, got=%v",
+				i, test.expectedErr, err)
+		}
+
+		if err == nil && stmt.String() != test.expected {
+			t.Fatalf("test[%d] - parseRequireStatement() with wrong result. Expected=%s, got=%s",
+				i, test.expected, stmt.String())
 		}
 	}
 }
 
-func TestMakePrefixExpression(t *testing.T) {
-	initParseFnMap()
+func TestParseStoreStatement(t *testing.T) {
 	tests := []struct {
 		buf         TokenBuffer
-		setupScope  setupScopeFn
 		expected    string
 		expectedErr error
 	}{
 		{
-			buf: &mockTokenBuffer{
+			&mockTokenBuffer{
 				[]Token{
-					{Type: Minus, Val: "-"},
+					{Type: Store, Val: "store"},
+					{Type: Lparen, Val: "("},
+					{Type: Ident, Val: "State"},
+					{Type: Dot, Val: "."},
+					{Type: Ident, Val: "Open"},
+					{Type: Comma, Val: ","},
+					{Type: Load, Val: "load"},
+					{Type: Lparen, Val: "("},
 					{Type: Int, Val: "1"},
+					{Type: Rparen, Val: ")"},
+					{Type: Plus, Val: "+"},
+					{Type: Int, Val: "2"},
+					{Type: Rparen, Val: ")"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Eof},
 				},
 				0,
 			},
-			setupScope: defaultSetupScopeFn,
-			expected:   "(-1)",
-		},
-		{
-			buf: &mockTokenBuffer{
-				[]Token{
-					{Type: Minus, Val: "-"},
-					{Type: Ident, Val: "a"},
-				},
-				0,
-			},
-			setupScope: func() *symbol.Scope {
-				scope := symbol.NewScope()
-				scope.Set("a", &symbol.Integer{Name: &ast.Identifier{Name: "a"}})
-				return scope
-			},
-			expected: "(-a)",
+			"store(State.Open, (load(1) + 2))",
+			nil,
 		},
 		{
-			buf: &mockTokenBuffer{
+			&mockTokenBuffer{
 				[]Token{
-					{Type: Bang, Val: "!"},
-					{Type: True, Val: "true"},
+					{Type: Store, Val: "store"},
+					{Type: Lparen, Val: "("},
+					{Type: Int, Val: "1"},
+					{Type: Rparen, Val: ")"},
+					{Type: Eof},
 				},
 				0,
 			},
-			setupScope: defaultSetupScopeFn,
-			expected:   "(!true)",
-		},
-		{
-			buf: &mockTokenBuffer{
-				[]Token{
-					{Type: Bang, Val: "!"},
-					{Type: Bang, Val: "!"},
-					{Type: True, Val: "false"},
-				},
-				0,
+			"",
+			Error{
+				Token{Type: Store, Val: "store"},
+				"store needs key and value, but got 1 arguments",
 			},
-			setupScope: defaultSetupScopeFn,
-			expected:   "(!(!false))",
 		},
 		{
-			buf: &mockTokenBuffer{
+			&mockTokenBuffer{
 				[]Token{
-					{Type: Bang, Val: "!"},
-					{Type: Minus, Val: "-"},
-					{Type: Ident, Val: "foo"},
+					{Type: Store, Val: "store"},
+					{Type: Lparen, Val: "("},
+					{Type: String, Val: "\"a\""},
+					{Type: Comma, Val: ","},
+					{Type: Int, Val: "1"},
+					{Type: Rparen, Val: ")"},
+					{Type: Eof},
 				},
 				0,
 			},
-			setupScope: func() *symbol.Scope {
-				scope := symbol.NewScope()
-				scope.Set("foo", &symbol.Integer{Name: &ast.Identifier{Name: "foo"}})
-				return scope
+			"",
+			Error{
+				Token{Type: Store, Val: "store"},
+				"key of store must be [int], but got [string]",
 			},
-			expected: "(!(-foo))",
 		},
 		{
-			buf: &mockTokenBuffer{
+			&mockTokenBuffer{
 				[]Token{
-					{Type: Minus, Val: "-"},
-					{Type: Bang, Val: "!"},
-					{Type: Ident, Val: "foo"},
+					{Type: Store, Val: "store"},
+					{Type: Lparen, Val: "("},
+					{Type: Int, Val: "1"},
+					{Type: Comma, Val: ","},
+					{Type: True, Val: "true"},
+					{Type: Rparen, Val: ")"},
+					{Type: Eof},
 				},
 				0,
 			},
-			setupScope: func() *symbol.Scope {
-				scope := symbol.NewScope()
-				scope.Set("foo", &symbol.Integer{Name: &ast.Identifier{Name: "foo"}})
-				return scope
+			"",
+			Error{
+				Token{Type: Store, Val: "store"},
+				"value of store must be [int], but got [bool]",
 			},
-			expected: "(-(!foo))",
 		},
+	}
+
+	for i, test := range tests {
+		scope := setupEnumScopeFn()
+		stmt, err := newTestParser(test.buf, scope).parseStoreStatement()
+
+		if err != nil && (test.expectedErr == nil || err.Error() != test.expectedErr.Error()) {
+			t.Fatalf("test[%d] - parseStoreStatement() with wrong error. Expected=%v, got=%v",
+				i, test.expectedErr, err)
+		}
+
+		if err == nil && test.expectedErr != nil {
+			t.Fatalf("test[%d] - parseStoreStatement() should return error. Expected=%v",
+				i, test.expectedErr)
+		}
+
+		if err == nil && stmt.String() != test.expected {
+			t.Fatalf("test[%d] - parseStoreStatement() with wrong result. Expected=%s, got=%s",
+				i, test.expected, stmt.String())
+		}
+	}
+}
+
+func TestParseEmitStatement(t *testing.T) {
+	tests := []struct {
+		buf         TokenBuffer
+		expected    string
+		expectedErr error
+	}{
 		{
-			buf: &mockTokenBuffer{
+			&mockTokenBuffer{
 				[]Token{
-					{Type: Minus, Val: "-"},
-					{Type: True, Val: "true"},
+					{Type: Emit, Val: "emit"},
+					{Type: Lparen, Val: "("},
+					{Type: Int, Val: "1"},
+					{Type: Comma, Val: ","},
+					{Type: String, Val: "\"a\""},
+					{Type: Rparen, Val: ")"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Eof},
 				},
 				0,
 			},
-			setupScope: defaultSetupScopeFn,
-			expectedErr: PrefixError{
-				Token{Type: Minus, Val: "-"},
-				&ast.BooleanLiteral{Value: true},
-			},
+			`emit(1, "a")`,
+			nil,
 		},
 		{
-			buf: &mockTokenBuffer{
+			&mockTokenBuffer{
 				[]Token{
-					{Type: Bang, Val: "!"},
-					{Type: String, Val: "hello"},
+					{Type: Emit, Val: "emit"},
+					{Type: Lparen, Val: "("},
+					{Type: Rparen, Val: ")"},
+					{Type: Eof},
 				},
 				0,
 			},
-			setupScope: defaultSetupScopeFn,
-			expectedErr: PrefixError{
-				Token{Type: Bang, Val: "!"},
-				&ast.StringLiteral{Value: "hello"},
-			},
+			"emit()",
+			nil,
 		},
 		{
-			buf: &mockTokenBuffer{
+			&mockTokenBuffer{
 				[]Token{
-					{Type: Bang, Val: "!"},
-					{Type: Int, Val: "3"},
+					{Type: Emit, Val: "emit"},
+					{Type: Int, Val: "1"},
+					{Type: Eof},
 				},
 				0,
 			},
-			expectedErr: PrefixError{
-				Token{Type: Bang, Val: "!"},
-				&ast.IntegerLiteral{Value: 3},
+			"",
+			ExpectError{
+				Token{Type: Int, Val: "1"},
+				Lparen,
 			},
-			setupScope: defaultSetupScopeFn,
 		},
 	}
 
-	for i, tt := range tests {
-		scope = tt.setupScope()
-		exp, err := makePrefixExpression(tt.buf)
+	for i, test := range tests {
+		scope := symbol.NewScope()
+		stmt, err := newTestParser(test.buf, scope).parseEmitStatement()
 
-		if err != nil && err.Error() != tt.expectedErr.Error() {
-			t.Errorf(`test[%d] - Wrong error returned Expected="%v", got="%v"`,
-				i, tt.expectedErr, err)
-			continue
+		if err != nil && (test.expectedErr == nil || err.Error() != test.expectedErr.Error()) {
+			t.Fatalf("test[%d] - parseEmitStatement() with wrong error. Expected=%v, got=%v",
+				i, test.expectedErr, err)
 		}
 
-		if err == nil && exp.String() != tt.expected {
-			t.Errorf(`test[%d] - Wrong result returned Expected="%s", got="%s"`,
-				i, tt.expected, exp.String())
+		if err == nil && test.expectedErr != nil {
+			t.Fatalf("test[%d] - parseEmitStatement() should return error. Expected=%v",
+				i, test.expectedErr)
+		}
+
+		if err == nil && stmt.String() != test.expected {
+			t.Fatalf("test[%d] - parseEmitStatement() with wrong result. Expected=%s, got=%s",
+				i, test.expected, stmt.String())
 		}
 	}
 }
 
-func TestMakeInfixExpression(t *testing.T) {
-	initParseFnMap()
+func TestParseLoadExpression(t *testing.T) {
 	tests := []struct {
-		prefix      ast.IntegerLiteral
 		buf         TokenBuffer
 		expected    string
 		expectedErr error
 	}{
 		{
-			prefix: ast.IntegerLiteral{
-				Value: 1,
-			},
-			buf: &mockTokenBuffer{
+			&mockTokenBuffer{
 				[]Token{
-					{Type: Plus, Val: "+"},
-					{Type: Int, Val: "2"},
-					{Type: Asterisk, Val: "*"},
-					{Type: Int, Val: "3"},
-					{Type: Eof, Val: ""},
+					{Type: Load, Val: "load"},
+					{Type: Lparen, Val: "("},
+					{Type: Ident, Val: "s"},
+					{Type: Rparen, Val: ")"},
+					{Type: Eof},
 				},
 				0,
 			},
-			expected:    "(1 + (2 * 3))",
-			expectedErr: nil,
+			"load(s)",
+			nil,
 		},
 		{
-			prefix: ast.IntegerLiteral{
-				Value: 121,
-			},
-			buf: &mockTokenBuffer{
+			&mockTokenBuffer{
 				[]Token{
-					{Type: Asterisk, Val: "*"},
-					{Type: Int, Val: "242"},
-					{Type: Plus, Val: "+"},
-					{Type: Int, Val: "312"},
-					{Type: Eof, Val: ""},
+					{Type: Load, Val: "load"},
+					{Type: Lparen, Val: "("},
+					{Type: Rparen, Val: ")"},
+					{Type: Eof},
 				},
 				0,
 			},
-			expected:    "((121 * 242) + 312)",
-			expectedErr: nil,
+			"",
+			Error{
+				Token{Type: Load, Val: "load"},
+				"load needs key, but got 0 arguments",
+			},
 		},
 		{
-			prefix: ast.IntegerLiteral{
-				Value: -10,
-			},
-			buf: &mockTokenBuffer{
+			&mockTokenBuffer{
 				[]Token{
-					{Type: Minus, Val: "-"},
-					{Type: Int, Val: "15"},
-					{Type: Minus, Val: "-"},
-					{Type: Int, Val: "55"},
-					{Type: Eof, Val: ""},
+					{Type: Load, Val: "load"},
+					{Type: Lparen, Val: "("},
+					{Type: False, Val: "false"},
+					{Type: Rparen, Val: ")"},
+					{Type: Eof},
 				},
 				0,
 			},
-			expected:    "((-10 - 15) - 55)",
-			expectedErr: nil,
-		},
-		{
-			prefix: ast.IntegerLiteral{
-				Value: 1,
-			},
-			buf: &mockTokenBuffer{
-				[]Token{
-					{Type: Minus, Val: "-"},
-					{Type: Int, Val: "2"},
-					{Type: Asterisk, Val: "*"},
-					{Type: Int, Val: "3"},
-					{Type: Plus, Val: "+"},
-					{Type: Int, Val: "4"},
-					{Type: Eof, Val: ""},
-				},
-				0,
-			},
-			expected:    "((1 - (2 * 3)) + 4)",
-			expectedErr: nil,
-		},
-		{
-			prefix: ast.IntegerLiteral{
-				Value: 1,
-			},
-			buf: &mockTokenBuffer{
-				[]Token{
-					{Type: Plus, Val: "+"},
-					{Type: Plus, Val: "+"},
-					{Type: Eof, Val: ""},
-				},
-				0,
-			},
-			expected: "",
-			expectedErr: Error{
-				Token{Type: Plus},
-				"prefix parse function not defined",
+			"",
+			Error{
+				Token{Type: Load, Val: "load"},
+				"key of load must be [int], but got [bool]",
 			},
 		},
 	}
 
-	// Expected value is
-	//      +
-	//     / \
-	//    1   *
-	//       / \
-	//      2  3
-	// result String() : 1+(2*3)
-
 	for i, test := range tests {
-		exp, err := makeInfixExpression(test.buf, &test.prefix, LOWEST)
+		p := newTestParser(test.buf, setupEnumScopeFn())
+		exp, err := p.parseExpression(LOWEST)
 
-		if err != nil && test.expectedErr.Error() != err.Error() {
-			t.Fatalf("test[%d] - TestMakeInfixExpression() wrong error. Expected=%s, got=%s",
-				i, test.expected, err.Error())
-		}
-		if err == nil && test.expected != exp.String() {
-			t.Fatalf("test[%d] - TestMakeInfixExpression() wrong result. Expected=%s, got=%s",
-				i, test.expected, exp.String())
+		if err != nil && (test.expectedErr == nil || err.Error() != test.expectedErr.Error()) {
+			t.Fatalf("test[%d] - parseExpression() with wrong error. Expected=%v, got=%v",
+				i, test.expectedErr, err)
 		}
-	}
-}
-
-func TestParseInfixExpression(t *testing.T) {
-	initParseFnMap()
-	tests := []struct {
-		buf         TokenBuffer
-		left        ast.IntegerLiteral
-		expected    string
-		expectedErr error
-	}{
-		{
-			buf: &mockTokenBuffer{
-				[]Token{
-					{Type: Int, Val: "1"},
-					{Type: Plus, Val: "+"},
-					{Type: Int, Val: "2"},
-					{Type: Asterisk, Val: "*"},
-					{Type: Int, Val: "3"},
-					{Type: Eof, Val: ""},
-				},
-				1,
-			},
-			left: ast.IntegerLiteral{
-				Value: 1,
-			},
-			expected:    "(1 + (2 * 3))",
-			expectedErr: nil,
-		},
-		{
-			buf: &mockTokenBuffer{
-				[]Token{
-					{Type: Int, Val: "121"},
-					{Type: Asterisk, Val: "*"},
-					{Type: Int, Val: "242"},
-					{Type: Plus, Val: "+"},
-					{Type: Int, Val: "312"},
-					{Type: Eof, Val: ""},
-				},
-				1,
-			},
-			left: ast.IntegerLiteral{
-				Value: 121,
-			},
-			expected:    "(121 * 242)",
-			expectedErr: nil,
-		},
-		{
-			buf: &mockTokenBuffer{
-				[]Token{
-					{Type: Int, Val: "-10"},
-					{Type: Asterisk, Val: "*"},
-					{Type: Int, Val: "15"},
-					{Type: Plus, Val: "+"},
-					{Type: Int, Val: "55"},
-					{Type: Eof, Val: ""},
-				},
-				1,
-			},
-			left: ast.IntegerLiteral{
-				Value: -10,
-			},
-			expected:    "(-10 * 15)",
-			expectedErr: nil,
-		},
-		{
-			buf: &mockTokenBuffer{
-				[]Token{
-					{Type: Int, Val: "1"},
-					{Type: Plus, Val: "+"},
-					{Type: Plus, Val: "+"},
-					{Type: Eof, Val: ""},
-				},
-				1,
-			},
-			left: ast.IntegerLiteral{
-				Value: 1,
-			},
-			expected: "",
-			expectedErr: Error{
-				Token{Type: Plus},
-				"prefix parse function not defined",
-			},
-		},
-	}
 
-	for i, test := range tests {
-		exp, err := parseInfixExpression(test.buf, &test.left)
+		if err == nil && test.expectedErr != nil {
+			t.Fatalf("test[%d] - parseExpression() should return error. Expected=%v",
+				i, test.expectedErr)
+		}
 
-		if err != nil && test.expectedErr.Error() != err.Error() {
-			t.Fatalf("test[%d] - TestMakeInfixExpression() wrong error. Expected=%s, got=%s",
-				i, test.expectedErr.Error(), err.Error())
+		if err != nil {
+			continue
 		}
 
-		if err == nil && test.expected != exp.String() {
-			t.Fatalf("test[%d] - TestMakeInfixExpression() wrong result. Expected=%s, got=%s",
+		if exp.String() != test.expected {
+			t.Fatalf("test[%d] - parseExpression() with wrong result. Expected=%s, got=%s",
 				i, test.expected, exp.String())
 		}
+
+		if p.expressionType(exp) != ast.IntType {
+			t.Fatalf("test[%d] - p.expressionType() with wrong type. Expected=int, got=%s",
+				i, p.expressionType(exp).String())
+		}
 	}
 }
 
-func TestParseGroupedExpression(t *testing.T) {
-	initParseFnMap()
+func TestParseFunctionLiteral(t *testing.T) {
 	tests := []struct {
-		buf         TokenBuffer
-		setupScope  setupScopeFn
-		expected    string
-		expectedErr error
+		buf          TokenBuffer
+		setupScope   setupScopeFn
+		expectedExpr string
+		expectedErr  error
 	}{
 		{
-			buf: &mockTokenBuffer{
+			&mockTokenBuffer{
 				[]Token{
+					// func example (a int, b string) {}
+					{Type: Function, Val: "func"},
+					{Type: Ident, Val: "example"},
 					{Type: Lparen, Val: "("},
-					{Type: Int, Val: "2"},
-					{Type: Plus, Val: "+"},
-					{Type: Int, Val: "1"},
+					{Type: Ident, Val: "a"},
+					{Type: IntType, Val: "int"},
+					{Type: Comma, Val: ","},
+					{Type: Ident, Val: "b"},
+					{Type: StringType, Val: "string"},
 					{Type: Rparen, Val: ")"},
+					{Type: Lbrace, Val: "{"},
+					{Type: Rbrace, Val: "}"},
 					{Type: Semicolon, Val: "\n"},
+					{Type: Eof, Val: "eof"},
 				},
 				0,
 			},
-			setupScope:  defaultSetupScopeFn,
-			expected:    "(2 + 1)",
-			expectedErr: nil,
+			defaultSetupScopeFn,
+			"func example(Parameter : (Identifier: a, Type: int), Parameter : (Identifier: b, Type: string)) void {\n\n}",
+			nil,
 		},
 		{
-			buf: &mockTokenBuffer{
+			&mockTokenBuffer{
 				[]Token{
+					// func name (a int, b string) {
+					//	int c = 5
+					// }
+					{Type: Function, Val: "func"},
+					{Type: Ident, Val: "name"},
 					{Type: Lparen, Val: "("},
 					{Type: Ident, Val: "a"},
-					{Type: Plus, Val: "+"},
-					{Type: Lparen, Val: "("},
-					{Type: Int, Val: "1"},
-					{Type: Minus, Val: "-"},
-					{Type: Int, Val: "2"},
-					{Type: Rparen, Val: ")"},
+					{Type: IntType, Val: "int"},
+					{Type: Comma, Val: ","},
+					{Type: Ident, Val: "b"},
+					{Type: StringType, Val: "string"},
 					{Type: Rparen, Val: ")"},
+					{Type: Lbrace, Val: "{"},
+					{Type: IntType, Val: "int"},
+					{Type: Ident, Val: "c"},
+					{Type: Assign, Val: "="},
+					{Type: Int, Val: "5"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Rbrace, Val: "}"},
 					{Type: Semicolon, Val: "\n"},
+					{Type: Eof, Val: "eof"},
 				},
 				0,
 			},
-			setupScope: func() *symbol.Scope {
-				scope := symbol.NewScope()
-				scope.Set("a", &symbol.Integer{Name: &ast.Identifier{Name: "a"}})
-				return scope
-			},
-			expected:    "(a + (1 - 2))",
-			expectedErr: nil,
+			defaultSetupScopeFn,
+			"func name(Parameter : (Identifier: a, Type: int), Parameter : (Identifier: b, Type: string)) void {\nint c = 5\n}",
+			nil,
 		},
 		{
-			buf: &mockTokenBuffer{
+			&mockTokenBuffer{
 				[]Token{
-					{Type: Lparen, Val: "("},
-					{Type: Ident, Val: "a"},
-					{Type: Plus, Val: "+"},
-					{Type: Lparen, Val: "("},
-					{Type: Int, Val: "1"},
-					{Type: Minus, Val: "-"},
-					{Type: Int, Val: "2"},
-					{Type: Rparen, Val: ")"},
-					{Type: Plus, Val: "+"},
-					{Type: Int, Val: "3"},
-					{Type: Rparen, Val: ")"},
-					{Type: Semicolon, Val: "\n"},
+					// error case
+					{Type: Lbrace, Val: "{"},
+					{Type: Eof, Val: "eof"},
 				},
 				0,
 			},
-			setupScope: func() *symbol.Scope {
-				scope := symbol.NewScope()
-				scope.Set("a", &symbol.Integer{Name: &ast.Identifier{Name: "a"}})
-				return scope
+			defaultSetupScopeFn,
+			"",
+			ExpectError{
+				Token{Lbrace, "{", 0, 0, 0},
+				Function,
 			},
-			expected:    "((a + (1 - 2)) + 3)",
-			expectedErr: nil,
 		},
 		{
-			buf: &mockTokenBuffer{
+			&mockTokenBuffer{
 				[]Token{
+					// func example () string {}
+					{Type: Function, Val: "func"},
+					{Type: Ident, Val: "example"},
 					{Type: Lparen, Val: "("},
-					{Type: Int, Val: "2"},
-					{Type: Plus, Val: "+"},
-					{Type: Int, Val: "1"},
+					{Type: Rparen, Val: ")"},
+					{Type: StringType, Val: "string"},
+					{Type: Lbrace, Val: "{"},
 					{Type: Rbrace, Val: "}"},
 					{Type: Semicolon, Val: "\n"},
+					{Type: Eof, Val: "eof"},
 				},
 				0,
 			},
-			setupScope: defaultSetupScopeFn,
-			expected:   "",
-			expectedErr: ExpectError{
-				Token{Rbrace, "{", 0, 0},
-				Rparen,
-			},
+			defaultSetupScopeFn,
+			"func example() string {\n\n}",
+			nil,
 		},
-	}
-
-	for i, test := range tests {
-		scope = test.setupScope()
-		exp, err := parseGroupedExpression(test.buf)
-
-		if err != nil && err.Error() != test.expectedErr.Error() {
-			t.Fatalf("test[%d] - TestParseGroupedExpression() wrong error.\n"+
-				"Expected=%s,\n"+
-				"got=%s",
-				i, test.expectedErr.Error(), err.Error())
-		}
-
-		if exp != nil && exp.String() != test.expected {
-			t.Fatalf("test[%d] - TestParseGroupedExpression() wrong answer.\n"+
-				"Expected=%s,\n"+
-				"got=%s",
-				i, test.expected, exp.String())
-		}
-	}
-}
-
-func TestParseReturnStatement(t *testing.T) {
-	initParseFnMap()
-	tests := []struct {
-		buf         TokenBuffer
-		expected    string
-		expectedErr error
-	}{
 		{
-			buf: &mockTokenBuffer{
+			&mockTokenBuffer{
 				[]Token{
-					{Type: Return, Val: "return"},
+					// func example () string {}
+					{Type: Function, Val: "func"},
+					{Type: Ident, Val: "example"},
+					{Type: Lparen, Val: "("},
+					{Type: Rparen, Val: ")"},
+					{Type: StringType, Val: "string"},
+					{Type: Lbrace, Val: "{"},
+					{Type: If, Val: "if"},
+					{Type: Lparen, Val: "("},
 					{Type: True, Val: "true"},
+					{Type: Rparen, Val: ")"},
+					{Type: Lbrace, Val: "{"},
+					{Type: Rbrace, Val: "}"},
+					{Type: Else, Val: "else"},
+					{Type: Lbrace, Val: "{"},
+					{Type: Rbrace, Val: "}"},
+					//{Type: Semicolon, Val: "\n"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Rbrace, Val: "}"},
 					{Type: Semicolon, Val: "\n"},
 					{Type: Eof, Val: "eof"},
 				},
 				0,
 			},
-			expected:    "return true",
-			expectedErr: nil,
+			defaultSetupScopeFn,
+			`func example() string {
+if ( true ) {  } else {  }
+}`,
+			nil,
 		},
 		{
-			buf: &mockTokenBuffer{
+			&mockTokenBuffer{
 				[]Token{
-					{Type: Return, Val: "return"},
-					{Type: Int, Val: "1"},
-					{Type: Plus, Val: "+"},
-					{Type: Int, Val: "2"},
+					// func example () string {}
+					{Type: Function, Val: "func"},
+					{Type: Ident, Val: "example"},
+					{Type: Lparen, Val: "("},
+					{Type: Rparen, Val: ")"},
+					{Type: StringType, Val: "string"},
+					{Type: Lbrace, Val: "{"},
+					{Type: If, Val: "if"},
+					{Type: Lparen, Val: "("},
+					{Type: True, Val: "true"},
+					{Type: Rparen, Val: ")"},
+					{Type: Lbrace, Val: "{"},
+					{Type: Rbrace, Val: "}"},
+					{Type: Else, Val: "else"},
+					{Type: Lbrace, Val: "{"},
+					{Type: Rbrace, Val: "}"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: If, Val: "if"},
+					{Type: Lparen, Val: "("},
+					{Type: True, Val: "true"},
+					{Type: Rparen, Val: ")"},
+					{Type: Lbrace, Val: "{"},
+					{Type: Rbrace, Val: "}"},
+					{Type: Else, Val: "else"},
+					{Type: Lbrace, Val: "{"},
+					{Type: Rbrace, Val: "}"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Rbrace, Val: "}"},
 					{Type: Semicolon, Val: "\n"},
 					{Type: Eof, Val: "eof"},
 				},
 				0,
 			},
-			expected:    "return (1 + 2)",
-			expectedErr: nil,
+			defaultSetupScopeFn,
+			`func example() string {
+if ( true ) {  } else {  }
+if ( true ) {  } else {  }
+}`,
+			nil,
 		},
 		{
-			buf: &mockTokenBuffer{
+			&mockTokenBuffer{
 				[]Token{
-					{Type: Return, Val: "return"},
-					{Type: Int, Val: "1"},
-					{Type: Plus, Val: "+"},
-					{Type: Int, Val: "2"},
-					{Type: Asterisk, Val: "*"},
-					{Type: Int, Val: "3"},
+					// func example () invalid {}
+					{Type: Function, Val: "func"},
+					{Type: Ident, Val: "example"},
+					{Type: Lparen, Val: "("},
+					{Type: Rparen, Val: ")"},
+					{Type: Illegal, Val: "invalid"},
+					{Type: Lbrace, Val: "{"},
+					{Type: Rbrace, Val: "}"},
 					{Type: Semicolon, Val: "\n"},
 					{Type: Eof, Val: "eof"},
 				},
 				0,
 			},
-			expected:    "return (1 + (2 * 3))",
-			expectedErr: nil,
+			defaultSetupScopeFn,
+			"",
+			Error{
+				Token{Type: Illegal},
+				"invalid function return type",
+			},
 		},
-		// test return void
 		{
-			buf: &mockTokenBuffer{
+			&mockTokenBuffer{
 				[]Token{
-					{Type: Return, Val: "return"},
+					// func example () invalid {}
+					{Type: Function, Val: "func"},
+					{Type: Ident, Val: "example"},
+					{Type: Lparen, Val: "("},
+					{Type: Rparen, Val: ")"},
+					{Type: Int, Val: "1"},
+					{Type: Lbrace, Val: "{"},
+					{Type: Rbrace, Val: "}"},
 					{Type: Semicolon, Val: "\n"},
 					{Type: Eof, Val: "eof"},
 				},
 				0,
 			},
-			expected:    "return",
-			expectedErr: nil,
+			defaultSetupScopeFn,
+			"",
+			Error{
+				Token{Type: Int},
+				"invalid function return type",
+			},
 		},
 		{
-			buf: &mockTokenBuffer{
+			&mockTokenBuffer{
 				[]Token{
-					{Type: IntType, Val: "int"},
+					// func example (a int, b string) {}
+					{Type: Function, Val: "func"},
+					{Type: Ident, Val: "example"},
+					{Type: Lparen, Val: "("},
 					{Type: Ident, Val: "a"},
-					{Type: Assign, Val: "="},
-					{Type: Int, Val: "1"},
+					{Type: IntType, Val: "int"},
+					{Type: Comma, Val: ","},
+					{Type: Ident, Val: "b"},
+					{Type: StringType, Val: "string"},
+					{Type: Rparen, Val: ")"},
+					{Type: Lbrace, Val: "{"},
+					{Type: Rbrace, Val: "}"},
 					{Type: Semicolon, Val: "\n"},
 					{Type: Eof, Val: "eof"},
 				},
 				0,
 			},
-			expected: "",
-			expectedErr: ExpectError{
-				Token{IntType, "int", 0, 0},
-				Return,
+			func() *symbol.Scope {
+				scope := symbol.NewScope()
+				scope.Set("example", &symbol.Function{Name: "example"})
+				return scope
 			},
+			"",
+			DupSymError{Token{Type: Ident, Val: "example"}},
 		},
 	}
 
 	for i, test := range tests {
-		exp, err := parseReturnStatement(test.buf)
+		scope := test.setupScope()
+
+		exp, err := newTestParser(test.buf, scope).parseFunctionLiteral()
 
 		if err != nil && err.Error() != test.expectedErr.Error() {
-			t.Fatalf("test[%d] - TestParseReturnStatement() wrong error.\n"+
-				"Expected=%s,\n"+
-				"got=%s",
-				i, test.expectedErr.Error(), err.Error())
+			t.Fatalf("test[%d] - TestParseFunctionLiteral() wrong error\n"+
+				"Expected: %s\n"+
+				"got: %s", i, test.expectedErr.Error(), err.Error())
 		}
 
-		if exp != nil && exp.String() != test.expected {
-			t.Fatalf("test[%d] - TestParseReturnStatement() wrong result.\n"+
-				"Expected=%s,\n"+
-				"got=%s",
-				i, test.expected, exp.String())
+		if exp != nil && exp.String() != test.expectedExpr {
+			t.Fatalf("test[%d] - TestParseFunctionLiteral wrong result\n"+
+				"Expected: %s\n"+
+				"got: %s", i, test.expectedExpr, exp.String())
 		}
 	}
 }
 
-func TestParsePrefixExpression(t *testing.T) {
+func TestParseFunctionParameter(t *testing.T) {
 	tests := []struct {
-		tokenBuffer      TokenBuffer
-		expectedOperator string
-		expectedRight    string
+		buf         TokenBuffer
+		setupScope  setupScopeFn
+		expected    []string
+		expectedErr error
 	}{
 		{
-			&mockTokenBuffer{
-				buf: []Token{
-					{Type: Minus, Val: "-"},
-					{Type: Int, Val: "1"},
-					{Type: Eof}},
-				sp: 0,
+			buf: &mockTokenBuffer{
+				[]Token{
+					{Type: Ident, Val: "a"},
+					{Type: IntType, Val: "int"},
+					{Type: Comma, Val: ","},
+					{Type: Ident, Val: "b"},
+					{Type: StringType, Val: "string"},
+					{Type: Rparen, Val: ")"},
+				},
+				0,
 			},
-			"-", "1",
+			setupScope: defaultSetupScopeFn,
+			expected: []string{
+				"Parameter : (Identifier: a, Type: int)",
+				"Parameter : (Identifier: b, Type: string)",
+			},
+			expectedErr: nil,
 		},
 		{
-			&mockTokenBuffer{
-				buf: []Token{
-					{Type: Minus, Val: "-"},
-					{Type: Int, Val: "3333"},
-					{Type: Eof}},
-				sp: 0,
+			buf: &mockTokenBuffer{
+				[]Token{
+					{Type: Ident, Val: "arg"},
+					{Type: BoolType, Val: "bool"},
+					{Type: Rparen, Val: ")"},
+				},
+				0,
 			},
-			"-", "3333",
+			setupScope: defaultSetupScopeFn,
+			expected: []string{
+				"Parameter : (Identifier: arg, Type: bool)",
+			},
+			expectedErr: nil,
 		},
 		{
-			&mockTokenBuffer{
-				buf: []Token{
-					{Type: Bang, Val: "!"},
-					{Type: True, Val: "true"},
-					{Type: Eof}},
-				sp: 0,
+			buf: &mockTokenBuffer{
+				[]Token{
+					{Type: Ident, Val: "arg"},
+					{Type: IntType, Val: "int"},
+					{Type: Rbrace, Val: "}"},
+				},
+				0,
+			},
+			setupScope: defaultSetupScopeFn,
+			expected:   nil,
+			expectedErr: ExpectError{
+				Token{Rbrace, "}", 0, 0, 0},
+				Rparen,
 			},
-			"!", "true",
 		},
 		{
-			&mockTokenBuffer{
-				buf: []Token{
-					{Type: Bang, Val: "!"},
-					{Type: False, Val: "false"},
-					{Type: Eof}},
-				sp: 0,
-			},
-			"!", "false",
+			buf: &mockTokenBuffer{
+				[]Token{
+					{Type: Ident, Val: "arg"},
+					{Type: IntType, Val: "int"},
+					{Type: Comma, Val: ","},
+					{Type: Ident, Val: "arg"},
+					{Type: IntType, Val: "int"},
+					{Type: Rparen, Val: ")"},
+				},
+				0,
+			},
+			setupScope:  defaultSetupScopeFn,
+			expected:    nil,
+			expectedErr: DupSymError{Token{Type: Ident, Val: "arg"}},
 		},
 	}
 
-	prefixParseFnMap[Int] = parseIntegerLiteral
-	prefixParseFnMap[True] = parseBooleanLiteral
-	prefixParseFnMap[False] = parseBooleanLiteral
-
-	for i, tt := range tests {
-		exp, err := parsePrefixExpression(tt.tokenBuffer)
-		if err != nil {
-			t.Errorf(`tests[%d] - Returned error is "%s"`,
-				i, err)
-		}
-
-		expression, ok := exp.(*ast.PrefixExpression)
-		if !ok {
-			t.Fatalf("exp is not *ast.PrefixExpression. got=%T", exp)
-		}
-
-		if expression.Operator.String() != tt.expectedOperator {
-			t.Errorf("tests[%d] - Type is not %s but got %s",
-				i, tt.expectedOperator, expression.Operator.String())
-		}
-
-		if expression.Right.String() != tt.expectedRight {
-			t.Errorf("tests[%d] - Name is not %s but got %s",
-				i, tt.expectedRight, expression.Right.String())
+	for i, test := range tests {
+		scope := test.setupScope()
+		identifiers, err := newTestParser(test.buf, scope).parseFunctionParameterList()
+		if err != nil && err.Error() != test.expectedErr.Error() {
+			t.Fatalf("test[%d] - TestParseFunctionParameter() wrong error.\n"+
+				"Expected: %s\n"+
+				"got: %s", i, test.expectedErr.Error(), err.Error())
+		} else {
+			for j, identifier := range identifiers {
+				if identifier.String() != test.expected[j] {
+					t.Fatalf("test[%d-%d] - TestParseFunctionParameter() failed.\n"+
+						"Expected: %s\n"+
+						"got: %s", i, j, test.expected[j], identifier)
+				}
+			}
 		}
 	}
 }
 
-func TestParseCallExpression(t *testing.T) {
-	initParseFnMap()
+func TestMakePrefixExpression(t *testing.T) {
 	tests := []struct {
-		setupScope  setupScopeFn
 		buf         TokenBuffer
-		function    ast.Expression
+		setupScope  setupScopeFn
 		expected    string
 		expectedErr error
 	}{
 		{
-			setupScope: defaultSetupScopeFn,
 			buf: &mockTokenBuffer{
 				[]Token{
-					{Type: Lparen, Val: "("},
+					{Type: Minus, Val: "-"},
 					{Type: Int, Val: "1"},
-					{Type: Plus, Val: "+"},
-					{Type: Int, Val: "2"},
-					{Type: Rparen, Val: ")"},
-					{Type: Semicolon, Val: "\n"},
-					{Type: Eof, Val: "eof"},
 				},
 				0,
 			},
-			function:    &ast.Identifier{Name: "add"},
-			expected:    `function add( (1 + 2) )`,
-			expectedErr: nil,
+			setupScope: defaultSetupScopeFn,
+			expected:   "(-1)",
 		},
 		{
-			setupScope: defaultSetupScopeFn,
 			buf: &mockTokenBuffer{
 				[]Token{
-					{Type: Lparen, Val: "("},
-					{Type: String, Val: "a"},
-					{Type: Comma, Val: ","},
-					{Type: String, Val: "b"},
-					{Type: Comma, Val: ","},
-					{Type: Int, Val: "5"},
-					{Type: Rparen, Val: ")"},
-					{Type: Semicolon, Val: "\n"},
-					{Type: Eof, Val: "eof"},
+					{Type: Minus, Val: "-"},
+					{Type: Ident, Val: "a"},
 				},
 				0,
 			},
-			function:    &ast.Identifier{Name: "testFunc"},
-			expected:    `function testFunc( a, b, 5 )`,
-			expectedErr: nil,
+			setupScope: func() *symbol.Scope {
+				scope := symbol.NewScope()
+				scope.Set("a", &symbol.Integer{Name: &ast.Identifier{Name: "a"}})
+				return scope
+			},
+			expected: "(-a)",
 		},
 		{
-			setupScope: defaultSetupScopeFn,
 			buf: &mockTokenBuffer{
 				[]Token{
-					{Type: Lparen, Val: "("},
-					{Type: String, Val: "a"},
-					{Type: Comma, Val: ","},
-					{Type: Asterisk, Val: "*"},
-					{Type: Comma, Val: ","},
-					{Type: Int, Val: "5"},
-					{Type: Rparen, Val: ")"},
-					{Type: Semicolon, Val: "\n"},
-					{Type: Eof, Val: "eof"},
+					{Type: Bang, Val: "!"},
+					{Type: True, Val: "true"},
 				},
 				0,
 			},
-			function: &ast.Identifier{Name: "errorFunc"},
-			expected: "",
-			expectedErr: Error{
-				Token{Type: Asterisk},
-				"prefix parse function not defined",
-			},
+			setupScope: defaultSetupScopeFn,
+			expected:   "(!true)",
 		},
 		{
-			setupScope: defaultSetupScopeFn,
 			buf: &mockTokenBuffer{
 				[]Token{
-					{Type: Lparen, Val: "("},
-					{Type: String, Val: "a"},
-					{Type: Plus, Val: "+"},
-					{Type: String, Val: "b"},
-					{Type: Comma, Val: ","},
-					{Type: Int, Val: "5"},
-					{Type: Asterisk, Val: "*"},
-					{Type: Int, Val: "3"},
-					{Type: Rparen, Val: ")"},
-					{Type: Semicolon, Val: "\n"},
-					{Type: Eof, Val: "eof"},
+					{Type: Bang, Val: "!"},
+					{Type: Bang, Val: "!"},
+					{Type: True, Val: "false"},
 				},
 				0,
 			},
-			function:    &ast.Identifier{Name: "complexFunc"},
-			expected:    `function complexFunc( (a + b), (5 * 3) )`,
-			expectedErr: nil,
+			setupScope: defaultSetupScopeFn,
+			expected:   "(!(!false))",
 		},
 		{
+			buf: &mockTokenBuffer{
+				[]Token{
+					{Type: Bang, Val: "!"},
+					{Type: Minus, Val: "-"},
+					{Type: Ident, Val: "foo"},
+				},
+				0,
+			},
 			setupScope: func() *symbol.Scope {
 				scope := symbol.NewScope()
-				scope.Set("add", &symbol.Integer{Name: &ast.Identifier{Name: "add"}})
+				scope.Set("foo", &symbol.Integer{Name: &ast.Identifier{Name: "foo"}})
 				return scope
 			},
+			expected: "(!(-foo))",
+		},
+		{
 			buf: &mockTokenBuffer{
 				[]Token{
-					{Type: Lparen, Val: "("},
-					{Type: Ident, Val: "add"},
-					{Type: Lparen, Val: "("},
-					{Type: Rparen, Val: ")"},
-					{Type: Rparen, Val: ")"},
-					{Type: Semicolon, Val: "\n"},
-					{Type: Eof, Val: "eof"},
+					{Type: Minus, Val: "-"},
+					{Type: Bang, Val: "!"},
+					{Type: Ident, Val: "foo"},
 				},
 				0,
 			},
-			function:    &ast.Identifier{Name: "complexFunc"},
-			expected:    `function complexFunc( function add(  ) )`,
-			expectedErr: nil,
-		},
-		{
 			setupScope: func() *symbol.Scope {
 				scope := symbol.NewScope()
-				scope.Set("add", &symbol.Integer{Name: &ast.Identifier{Name: "add"}})
+				scope.Set("foo", &symbol.Integer{Name: &ast.Identifier{Name: "foo"}})
 				return scope
 			},
+			expected: "(-(!foo))",
+		},
+		{
 			buf: &mockTokenBuffer{
 				[]Token{
-					{Type: Lparen, Val: "("},
-					{Type: Ident, Val: "add"},
-					{Type: Lparen, Val: "("},
-					{Type: Rparen, Val: ")"},
-					{Type: Comma, Val: ","},
-					{Type: Int, Val: "1"},
-					{Type: Rparen, Val: ")"},
-					{Type: Semicolon, Val: "\n"},
-					{Type: Eof, Val: "eof"},
+					{Type: Minus, Val: "-"},
+					{Type: True, Val: "true"},
 				},
 				0,
 			},
-			function:    &ast.Identifier{Name: "complexFunc"},
-			expected:    `function complexFunc( function add(  ), 1 )`,
-			expectedErr: nil,
+			setupScope: defaultSetupScopeFn,
+			expectedErr: PrefixError{
+				Token{Type: Minus, Val: "-"},
+				&ast.BooleanLiteral{Value: true},
+			},
 		},
-		// test empty arguments function call
 		{
+			buf: &mockTokenBuffer{
+				[]Token{
+					{Type: Bang, Val: "!"},
+					{Type: String, Val: "hello"},
+				},
+				0,
+			},
 			setupScope: defaultSetupScopeFn,
+			expectedErr: PrefixError{
+				Token{Type: Bang, Val: "!"},
+				&ast.StringLiteral{Value: "hello"},
+			},
+		},
+		{
 			buf: &mockTokenBuffer{
 				[]Token{
-					{Type: Lparen, Val: "("},
-					{Type: Rparen, Val: ")"},
-					{Type: Semicolon, Val: "\n"},
-					{Type: Eof, Val: "eof"},
+					{Type: Bang, Val: "!"},
+					{Type: Int, Val: "3"},
 				},
 				0,
 			},
-			function:    &ast.Identifier{Name: "complexFunc"},
-			expected:    `function complexFunc(  )`,
-			expectedErr: nil,
+			expectedErr: PrefixError{
+				Token{Type: Bang, Val: "!"},
+				&ast.IntegerLiteral{Value: 3},
+			},
+			setupScope: defaultSetupScopeFn,
 		},
 	}
 
-	for i, test := range tests {
-		scope = test.setupScope()
-
-		exp, err := parseCallExpression(test.buf, test.function)
+	for i, tt := range tests {
+		scope := tt.setupScope()
+		exp, err := newTestParser(tt.buf, scope).makePrefixExpression()
 
-		if err != nil && err.Error() != test.expectedErr.Error() {
-			t.Fatalf("test[%d] - parseCallExpression() wrong error. Expected=%s, got=%s",
-				i, test.expectedErr.Error(), err.Error())
+		if err != nil && err.Error() != tt.expectedErr.Error() {
+			t.Errorf(`test[%d] - Wrong error returned Expected="%v", got="%v"`,
+				i, tt.expectedErr, err)
+			continue
 		}
-		if exp != nil && exp.String() != test.expected {
-			t.Fatalf("test[%d] - parseCallExpression() wrong answer. Expected=%s, got=%s",
-				i, test.expected, exp.String())
+
+		if err == nil && exp.String() != tt.expected {
+			t.Errorf(`test[%d] - Wrong result returned Expected="%s", got="%s"`,
+				i, tt.expected, exp.String())
 		}
 	}
 }
 
-func TestParseCallArguments(t *testing.T) {
-	initParseFnMap()
+func TestMakeInfixExpression(t *testing.T) {
 	tests := []struct {
+		prefix      ast.IntegerLiteral
 		buf         TokenBuffer
-		setupScope  setupScopeFn
 		expected    string
 		expectedErr error
 	}{
 		{
+			prefix: ast.IntegerLiteral{
+				Value: 1,
+			},
 			buf: &mockTokenBuffer{
 				[]Token{
-					{Type: Lparen, Val: "("},
-					{Type: Rparen, Val: ")"},
+					{Type: Plus, Val: "+"},
+					{Type: Int, Val: "2"},
+					{Type: Asterisk, Val: "*"},
+					{Type: Int, Val: "3"},
+					{Type: Eof, Val: ""},
 				},
 				0,
 			},
-			setupScope:  defaultSetupScopeFn,
-			expected:    "function testFunction(  )",
+			expected:    "(1 + (2 * 3))",
 			expectedErr: nil,
 		},
 		{
+			prefix: ast.IntegerLiteral{
+				Value: 121,
+			},
 			buf: &mockTokenBuffer{
 				[]Token{
-					{Type: Lparen, Val: "("},
-					{Type: Int, Val: "1"},
-					{Type: Rparen, Val: ")"},
+					{Type: Asterisk, Val: "*"},
+					{Type: Int, Val: "242"},
+					{Type: Plus, Val: "+"},
+					{Type: Int, Val: "312"},
+					{Type: Eof, Val: ""},
 				},
 				0,
 			},
-			setupScope:  defaultSetupScopeFn,
-			expected:    "function testFunction( 1 )",
+			expected:    "((121 * 242) + 312)",
 			expectedErr: nil,
 		},
 		{
-			buf: &mockTokenBuffer{
-				[]Token{
-					{Type: Lparen, Val: "("},
-					{Type: String, Val: "a"},
-					{Type: Comma, Val: ","},
-					{Type: String, Val: "b"},
-					{Type: Comma, Val: ","},
-					{Type: Int, Val: "5"},
-					{Type: Rparen, Val: ")"},
+			prefix: ast.IntegerLiteral{
+				Value: -10,
+			},
+			buf: &mockTokenBuffer{
+				[]Token{
+					{Type: Minus, Val: "-"},
+					{Type: Int, Val: "15"},
+					{Type: Minus, Val: "-"},
+					{Type: Int, Val: "55"},
+					{Type: Eof, Val: ""},
 				},
 				0,
 			},
-			setupScope: func() *symbol.Scope {
-				scope := symbol.NewScope()
-				scope.Set("a", &symbol.Integer{Name: &ast.Identifier{Name: "a"}})
-				scope.Set("b", &symbol.Integer{Name: &ast.Identifier{Name: "b"}})
-				return scope
+			expected:    "((-10 - 15) - 55)",
+			expectedErr: nil,
+		},
+		{
+			prefix: ast.IntegerLiteral{
+				Value: 1,
+			},
+			buf: &mockTokenBuffer{
+				[]Token{
+					{Type: Minus, Val: "-"},
+					{Type: Int, Val: "2"},
+					{Type: Asterisk, Val: "*"},
+					{Type: Int, Val: "3"},
+					{Type: Plus, Val: "+"},
+					{Type: Int, Val: "4"},
+					{Type: Eof, Val: ""},
+				},
+				0,
 			},
-			expected:    `function testFunction( a, b, 5 )`,
+			expected:    "((1 - (2 * 3)) + 4)",
 			expectedErr: nil,
 		},
 		{
+			prefix: ast.IntegerLiteral{
+				Value: 1,
+			},
 			buf: &mockTokenBuffer{
 				[]Token{
-					{Type: Lparen, Val: "("},
-					{Type: String, Val: "a"},
 					{Type: Plus, Val: "+"},
-					{Type: String, Val: "b"},
-					{Type: Comma, Val: ","},
-					{Type: Int, Val: "5"},
+					{Type: Plus, Val: "+"},
+					{Type: Eof, Val: ""},
+				},
+				0,
+			},
+			expected: "",
+			expectedErr: Error{
+				Token{Type: Plus},
+				"prefix parse function not defined",
+			},
+		},
+	}
+
+	// Expected value is
+	//      +
+	//     / \
+	//    1   *
+	//       / \
+	//      2  3
+	// result String() : 1+(2*3)
+
+	for i, test := range tests {
+		exp, err := NewParser(test.buf, nil).makeInfixExpression(&test.prefix, LOWEST)
+
+		if err != nil && test.expectedErr.Error() != err.Error() {
+			t.Fatalf("test[%d] - TestMakeInfixExpression() wrong error. Expected=%s, got=%s",
+				i, test.expected, err.Error())
+		}
+		if err == nil && test.expected != exp.String() {
+			t.Fatalf("test[%d] - TestMakeInfixExpression() wrong result. Expected=%s, got=%s",
+				i, test.expected, exp.String())
+		}
+	}
+}
+
+func TestParseInfixExpression(t *testing.T) {
+	tests := []struct {
+		buf         TokenBuffer
+		left        ast.IntegerLiteral
+		expected    string
+		expectedErr error
+	}{
+		{
+			buf: &mockTokenBuffer{
+				[]Token{
+					{Type: Int, Val: "1"},
+					{Type: Plus, Val: "+"},
+					{Type: Int, Val: "2"},
 					{Type: Asterisk, Val: "*"},
 					{Type: Int, Val: "3"},
-					{Type: Rparen, Val: ")"},
+					{Type: Eof, Val: ""},
 				},
-				0,
+				1,
 			},
-			setupScope: func() *symbol.Scope {
-				scope := symbol.NewScope()
-				scope.Set("a", &symbol.Integer{Name: &ast.Identifier{Name: "a"}})
-				scope.Set("b", &symbol.Integer{Name: &ast.Identifier{Name: "b"}})
-				return scope
+			left: ast.IntegerLiteral{
+				Value: 1,
 			},
-			expected:    `function testFunction( (a + b), (5 * 3) )`,
+			expected:    "(1 + (2 * 3))",
 			expectedErr: nil,
 		},
 		{
 			buf: &mockTokenBuffer{
 				[]Token{
-					{Type: Lparen, Val: "("},
+					{Type: Int, Val: "121"},
 					{Type: Asterisk, Val: "*"},
-					{Type: Rparen, Val: ")"},
+					{Type: Int, Val: "242"},
+					{Type: Plus, Val: "+"},
+					{Type: Int, Val: "312"},
+					{Type: Eof, Val: ""},
 				},
-				0,
+				1,
 			},
-			setupScope: defaultSetupScopeFn,
-			expected:   "",
+			left: ast.IntegerLiteral{
+				Value: 121,
+			},
+			expected:    "(121 * 242)",
+			expectedErr: nil,
+		},
+		{
+			buf: &mockTokenBuffer{
+				[]Token{
+					{Type: Int, Val: "-10"},
+					{Type: Asterisk, Val: "*"},
+					{Type: Int, Val: "15"},
+					{Type: Plus, Val: "+"},
+					{Type: Int, Val: "55"},
+					{Type: Eof, Val: ""},
+				},
+				1,
+			},
+			left: ast.IntegerLiteral{
+				Value: -10,
+			},
+			expected:    "(-10 * 15)",
+			expectedErr: nil,
+		},
+		{
+			buf: &mockTokenBuffer{
+				[]Token{
+					{Type: Int, Val: "1"},
+					{Type: Plus, Val: "+"},
+					{Type: Plus, Val: "+"},
+					{Type: Eof, Val: ""},
+				},
+				1,
+			},
+			left: ast.IntegerLiteral{
+				Value: 1,
+			},
+			expected: "",
 			expectedErr: Error{
-				Token{Type: Asterisk},
+				Token{Type: Plus},
 				"prefix parse function not defined",
 			},
 		},
+	}
+
+	for i, test := range tests {
+		exp, err := NewParser(test.buf, nil).parseInfixExpression(&test.left)
+
+		if err != nil && test.expectedErr.Error() != err.Error() {
+			t.Fatalf("test[%d] - TestMakeInfixExpression() wrong error. Expected=%s, got=%s",
+				i, test.expectedErr.Error(), err.Error())
+		}
+
+		if err == nil && test.expected != exp.String() {
+			t.Fatalf("test[%d] - TestMakeInfixExpression() wrong result. Expected=%s, got=%s",
+				i, test.expected, exp.String())
+		}
+	}
+}
+
+func TestParseGroupedExpression(t *testing.T) {
+	tests := []struct {
+		buf         TokenBuffer
+		setupScope  setupScopeFn
+		expected    string
+		expectedErr error
+	}{
 		{
 			buf: &mockTokenBuffer{
 				[]Token{
 					{Type: Lparen, Val: "("},
-					{Type: String, Val: "a"},
-					{Type: Comma, Val: ","},
-					{Type: Asterisk, Val: "*"},
-					{Type: Comma, Val: ","},
-					{Type: Int, Val: "5"},
+					{Type: Int, Val: "2"},
+					{Type: Plus, Val: "+"},
+					{Type: Int, Val: "1"},
+					{Type: Rparen, Val: ")"},
+					{Type: Semicolon, Val: "\n"},
+				},
+				0,
+			},
+			setupScope:  defaultSetupScopeFn,
+			expected:    "(2 + 1)",
+			expectedErr: nil,
+		},
+		{
+			buf: &mockTokenBuffer{
+				[]Token{
+					{Type: Lparen, Val: "("},
+					{Type: Ident, Val: "a"},
+					{Type: Plus, Val: "+"},
+					{Type: Lparen, Val: "("},
+					{Type: Int, Val: "1"},
+					{Type: Minus, Val: "-"},
+					{Type: Int, Val: "2"},
 					{Type: Rparen, Val: ")"},
+					{Type: Rparen, Val: ")"},
+					{Type: Semicolon, Val: "\n"},
 				},
 				0,
 			},
@@ -2109,92 +2609,1946 @@ func TestParseCallArguments(t *testing.T) {
 				scope.Set("a", &symbol.Integer{Name: &ast.Identifier{Name: "a"}})
 				return scope
 			},
-			expected: "",
-			expectedErr: Error{
-				Token{Type: Asterisk},
-				"prefix parse function not defined",
-			},
+			expected:    "(a + (1 - 2))",
+			expectedErr: nil,
 		},
 		{
 			buf: &mockTokenBuffer{
 				[]Token{
 					{Type: Lparen, Val: "("},
-					{Type: Ident, Val: "add"},
+					{Type: Ident, Val: "a"},
+					{Type: Plus, Val: "+"},
 					{Type: Lparen, Val: "("},
-					{Type: Rparen, Val: ")"},
-					{Type: Comma, Val: ","},
 					{Type: Int, Val: "1"},
+					{Type: Minus, Val: "-"},
+					{Type: Int, Val: "2"},
+					{Type: Rparen, Val: ")"},
+					{Type: Plus, Val: "+"},
+					{Type: Int, Val: "3"},
 					{Type: Rparen, Val: ")"},
 					{Type: Semicolon, Val: "\n"},
-					{Type: Eof, Val: "eof"},
 				},
 				0,
 			},
 			setupScope: func() *symbol.Scope {
 				scope := symbol.NewScope()
-				scope.Set("add", &symbol.Integer{Name: &ast.Identifier{Name: "add"}})
+				scope.Set("a", &symbol.Integer{Name: &ast.Identifier{Name: "a"}})
 				return scope
 			},
-			expected:    `function testFunction( function add(  ), 1 )`,
+			expected:    "((a + (1 - 2)) + 3)",
 			expectedErr: nil,
 		},
+		{
+			buf: &mockTokenBuffer{
+				[]Token{
+					{Type: Lparen, Val: "("},
+					{Type: Int, Val: "2"},
+					{Type: Plus, Val: "+"},
+					{Type: Int, Val: "1"},
+					{Type: Rbrace, Val: "}"},
+					{Type: Semicolon, Val: "\n"},
+				},
+				0,
+			},
+			setupScope: defaultSetupScopeFn,
+			expected:   "",
+			expectedErr: ExpectError{
+				Token{Rbrace, "{", 0, 0, 0},
+				Rparen,
+			},
+		},
 	}
 
 	for i, test := range tests {
-		scope = test.setupScope()
-		exp, err := parseCallArguments(test.buf)
+		scope := test.setupScope()
+		exp, err := newTestParser(test.buf, scope).parseGroupedExpression()
 
 		if err != nil && err.Error() != test.expectedErr.Error() {
-			t.Fatalf("test[%d] - TestParseCallArguments() wrong error. Expected=%s, got=%s",
+			t.Fatalf("test[%d] - TestParseGroupedExpression() wrong error.\n"+
+				"Expected=%s,\n"+
+				"got=%s",
+				i, test.expectedErr.Error(), err.Error())
+		}
+
+		if exp != nil && exp.String() != test.expected {
+			t.Fatalf("test[%d] - TestParseGroupedExpression() wrong answer.\n"+
+				"Expected=%s,\n"+
+				"got=%s",
+				i, test.expected, exp.String())
+		}
+	}
+}
+
+func TestParseReturnStatement(t *testing.T) {
+	tests := []struct {
+		buf         TokenBuffer
+		expected    string
+		expectedErr error
+	}{
+		{
+			buf: &mockTokenBuffer{
+				[]Token{
+					{Type: Return, Val: "return"},
+					{Type: True, Val: "true"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Eof, Val: "eof"},
+				},
+				0,
+			},
+			expected:    "return true",
+			expectedErr: nil,
+		},
+		{
+			buf: &mockTokenBuffer{
+				[]Token{
+					{Type: Return, Val: "return"},
+					{Type: Int, Val: "1"},
+					{Type: Plus, Val: "+"},
+					{Type: Int, Val: "2"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Eof, Val: "eof"},
+				},
+				0,
+			},
+			expected:    "return (1 + 2)",
+			expectedErr: nil,
+		},
+		{
+			buf: &mockTokenBuffer{
+				[]Token{
+					{Type: Return, Val: "return"},
+					{Type: Int, Val: "1"},
+					{Type: Plus, Val: "+"},
+					{Type: Int, Val: "2"},
+					{Type: Asterisk, Val: "*"},
+					{Type: Int, Val: "3"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Eof, Val: "eof"},
+				},
+				0,
+			},
+			expected:    "return (1 + (2 * 3))",
+			expectedErr: nil,
+		},
+		// test return void
+		{
+			buf: &mockTokenBuffer{
+				[]Token{
+					{Type: Return, Val: "return"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Eof, Val: "eof"},
+				},
+				0,
+			},
+			expected:    "return",
+			expectedErr: nil,
+		},
+		{
+			buf: &mockTokenBuffer{
+				[]Token{
+					{Type: IntType, Val: "int"},
+					{Type: Ident, Val: "a"},
+					{Type: Assign, Val: "="},
+					{Type: Int, Val: "1"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Eof, Val: "eof"},
+				},
+				0,
+			},
+			expected: "",
+			expectedErr: ExpectError{
+				Token{IntType, "int", 0, 0, 0},
+				Return,
+			},
+		},
+	}
+
+	for i, test := range tests {
+		exp, err := NewParser(test.buf, nil).parseReturnStatement()
+
+		if err != nil && err.Error() != test.expectedErr.Error() {
+			t.Fatalf("test[%d] - TestParseReturnStatement() wrong error.\n"+
+				"Expected=%s,\n"+
+				"got=%s",
+				i, test.expectedErr.Error(), err.Error())
+		}
+
+		if exp != nil && exp.String() != test.expected {
+			t.Fatalf("test[%d] - TestParseReturnStatement() wrong result.\n"+
+				"Expected=%s,\n"+
+				"got=%s",
+				i, test.expected, exp.String())
+		}
+	}
+}
+
+func TestParsePrefixExpression(t *testing.T) {
+	tests := []struct {
+		tokenBuffer      TokenBuffer
+		expectedOperator string
+		expectedRight    string
+	}{
+		{
+			&mockTokenBuffer{
+				buf: []Token{
+					{Type: Minus, Val: "-"},
+					{Type: Int, Val: "1"},
+					{Type: Eof}},
+				sp: 0,
+			},
+			"-", "1",
+		},
+		{
+			&mockTokenBuffer{
+				buf: []Token{
+					{Type: Minus, Val: "-"},
+					{Type: Int, Val: "3333"},
+					{Type: Eof}},
+				sp: 0,
+			},
+			"-", "3333",
+		},
+		{
+			&mockTokenBuffer{
+				buf: []Token{
+					{Type: Bang, Val: "!"},
+					{Type: True, Val: "true"},
+					{Type: Eof}},
+				sp: 0,
+			},
+			"!", "true",
+		},
+		{
+			&mockTokenBuffer{
+				buf: []Token{
+					{Type: Bang, Val: "!"},
+					{Type: False, Val: "false"},
+					{Type: Eof}},
+				sp: 0,
+			},
+			"!", "false",
+		},
+	}
+
+	for i, tt := range tests {
+		exp, err := NewParser(tt.tokenBuffer, nil).parsePrefixExpression()
+		if err != nil {
+			t.Errorf(`tests[%d] - Returned error is "%s"`,
+				i, err)
+		}
+
+		expression, ok := exp.(*ast.PrefixExpression)
+		if !ok {
+			t.Fatalf("exp is not *ast.PrefixExpression. got=%T", exp)
+		}
+
+		if expression.Operator.String() != tt.expectedOperator {
+			t.Errorf("tests[%d] - Type is not %s but got %s",
+				i, tt.expectedOperator, expression.Operator.String())
+		}
+
+		if expression.Right.String() != tt.expectedRight {
+			t.Errorf("tests[%d] - Name is not %s but got %s",
+				i, tt.expectedRight, expression.Right.String())
+		}
+	}
+}
+
+func TestParseCallExpression(t *testing.T) {
+	tests := []struct {
+		setupScope  setupScopeFn
+		buf         TokenBuffer
+		function    ast.Expression
+		expected    string
+		expectedErr error
+	}{
+		{
+			setupScope: defaultSetupScopeFn,
+			buf: &mockTokenBuffer{
+				[]Token{
+					{Type: Lparen, Val: "("},
+					{Type: Int, Val: "1"},
+					{Type: Plus, Val: "+"},
+					{Type: Int, Val: "2"},
+					{Type: Rparen, Val: ")"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Eof, Val: "eof"},
+				},
+				0,
+			},
+			function:    &ast.Identifier{Name: "add"},
+			expected:    `function add( (1 + 2) )`,
+			expectedErr: nil,
+		},
+		{
+			setupScope: defaultSetupScopeFn,
+			buf: &mockTokenBuffer{
+				[]Token{
+					{Type: Lparen, Val: "("},
+					{Type: String, Val: "a"},
+					{Type: Comma, Val: ","},
+					{Type: String, Val: "b"},
+					{Type: Comma, Val: ","},
+					{Type: Int, Val: "5"},
+					{Type: Rparen, Val: ")"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Eof, Val: "eof"},
+				},
+				0,
+			},
+			function:    &ast.Identifier{Name: "testFunc"},
+			expected:    `function testFunc( "a", "b", 5 )`,
+			expectedErr: nil,
+		},
+		{
+			setupScope: defaultSetupScopeFn,
+			buf: &mockTokenBuffer{
+				[]Token{
+					{Type: Lparen, Val: "("},
+					{Type: String, Val: "a"},
+					{Type: Comma, Val: ","},
+					{Type: Asterisk, Val: "*"},
+					{Type: Comma, Val: ","},
+					{Type: Int, Val: "5"},
+					{Type: Rparen, Val: ")"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Eof, Val: "eof"},
+				},
+				0,
+			},
+			function: &ast.Identifier{Name: "errorFunc"},
+			expected: "",
+			expectedErr: Error{
+				Token{Type: Asterisk},
+				"prefix parse function not defined",
+			},
+		},
+		{
+			setupScope: defaultSetupScopeFn,
+			buf: &mockTokenBuffer{
+				[]Token{
+					{Type: Lparen, Val: "("},
+					{Type: Ident, Val: "a"},
+					{Type: Plus, Val: "+"},
+					{Type: Ident, Val: "b"},
+					{Type: Comma, Val: ","},
+					{Type: Int, Val: "5"},
+					{Type: Asterisk, Val: "*"},
+					{Type: Int, Val: "3"},
+					{Type: Rparen, Val: ")"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Eof, Val: "eof"},
+				},
+				0,
+			},
+			function:    &ast.Identifier{Name: "complexFunc"},
+			expected:    `function complexFunc( (a + b), (5 * 3) )`,
+			expectedErr: nil,
+		},
+		{
+			setupScope: func() *symbol.Scope {
+				scope := symbol.NewScope()
+				scope.Set("add", &symbol.Integer{Name: &ast.Identifier{Name: "add"}})
+				return scope
+			},
+			buf: &mockTokenBuffer{
+				[]Token{
+					{Type: Lparen, Val: "("},
+					{Type: Ident, Val: "add"},
+					{Type: Lparen, Val: "("},
+					{Type: Rparen, Val: ")"},
+					{Type: Rparen, Val: ")"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Eof, Val: "eof"},
+				},
+				0,
+			},
+			function:    &ast.Identifier{Name: "complexFunc"},
+			expected:    `function complexFunc( function add(  ) )`,
+			expectedErr: nil,
+		},
+		{
+			setupScope: func() *symbol.Scope {
+				scope := symbol.NewScope()
+				scope.Set("add", &symbol.Integer{Name: &ast.Identifier{Name: "add"}})
+				return scope
+			},
+			buf: &mockTokenBuffer{
+				[]Token{
+					{Type: Lparen, Val: "("},
+					{Type: Ident, Val: "add"},
+					{Type: Lparen, Val: "("},
+					{Type: Rparen, Val: ")"},
+					{Type: Comma, Val: ","},
+					{Type: Int, Val: "1"},
+					{Type: Rparen, Val: ")"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Eof, Val: "eof"},
+				},
+				0,
+			},
+			function:    &ast.Identifier{Name: "complexFunc"},
+			expected:    `function complexFunc( function add(  ), 1 )`,
+			expectedErr: nil,
+		},
+		// test empty arguments function call
+		{
+			setupScope: defaultSetupScopeFn,
+			buf: &mockTokenBuffer{
+				[]Token{
+					{Type: Lparen, Val: "("},
+					{Type: Rparen, Val: ")"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Eof, Val: "eof"},
+				},
+				0,
+			},
+			function:    &ast.Identifier{Name: "complexFunc"},
+			expected:    `function complexFunc(  )`,
+			expectedErr: nil,
+		},
+	}
+
+	for i, test := range tests {
+		scope := test.setupScope()
+
+		exp, err := newTestParser(test.buf, scope).parseCallExpression(test.function)
+
+		if err != nil && err.Error() != test.expectedErr.Error() {
+			t.Fatalf("test[%d] - parseCallExpression() wrong error. Expected=%s, got=%s",
+				i, test.expectedErr.Error(), err.Error())
+		}
+		if exp != nil && exp.String() != test.expected {
+			t.Fatalf("test[%d] - parseCallExpression() wrong answer. Expected=%s, got=%s",
+				i, test.expected, exp.String())
+		}
+	}
+}
+
+func TestParseCallArguments(t *testing.T) {
+	tests := []struct {
+		buf         TokenBuffer
+		setupScope  setupScopeFn
+		expected    string
+		expectedErr error
+	}{
+		{
+			buf: &mockTokenBuffer{
+				[]Token{
+					{Type: Lparen, Val: "("},
+					{Type: Rparen, Val: ")"},
+				},
+				0,
+			},
+			setupScope:  defaultSetupScopeFn,
+			expected:    "function testFunction(  )",
+			expectedErr: nil,
+		},
+		{
+			buf: &mockTokenBuffer{
+				[]Token{
+					{Type: Lparen, Val: "("},
+					{Type: Int, Val: "1"},
+					{Type: Rparen, Val: ")"},
+				},
+				0,
+			},
+			setupScope:  defaultSetupScopeFn,
+			expected:    "function testFunction( 1 )",
+			expectedErr: nil,
+		},
+		{
+			buf: &mockTokenBuffer{
+				[]Token{
+					{Type: Lparen, Val: "("},
+					{Type: String, Val: "a"},
+					{Type: Comma, Val: ","},
+					{Type: String, Val: "b"},
+					{Type: Comma, Val: ","},
+					{Type: Int, Val: "5"},
+					{Type: Rparen, Val: ")"},
+				},
+				0,
+			},
+			setupScope: func() *symbol.Scope {
+				scope := symbol.NewScope()
+				scope.Set("a", &symbol.Integer{Name: &ast.Identifier{Name: "a"}})
+				scope.Set("b", &symbol.Integer{Name: &ast.Identifier{Name: "b"}})
+				return scope
+			},
+			expected:    `function testFunction( "a", "b", 5 )`,
+			expectedErr: nil,
+		},
+		{
+			buf: &mockTokenBuffer{
+				[]Token{
+					{Type: Lparen, Val: "("},
+					{Type: Ident, Val: "a"},
+					{Type: Plus, Val: "+"},
+					{Type: Ident, Val: "b"},
+					{Type: Comma, Val: ","},
+					{Type: Int, Val: "5"},
+					{Type: Asterisk, Val: "*"},
+					{Type: Int, Val: "3"},
+					{Type: Rparen, Val: ")"},
+				},
+				0,
+			},
+			setupScope: func() *symbol.Scope {
+				scope := symbol.NewScope()
+				scope.Set("a", &symbol.Integer{Name: &ast.Identifier{Name: "a"}})
+				scope.Set("b", &symbol.Integer{Name: &ast.Identifier{Name: "b"}})
+				return scope
+			},
+			expected:    `function testFunction( (a + b), (5 * 3) )`,
+			expectedErr: nil,
+		},
+		{
+			buf: &mockTokenBuffer{
+				[]Token{
+					{Type: Lparen, Val: "("},
+					{Type: Asterisk, Val: "*"},
+					{Type: Rparen, Val: ")"},
+				},
+				0,
+			},
+			setupScope: defaultSetupScopeFn,
+			expected:   "",
+			expectedErr: Error{
+				Token{Type: Asterisk},
+				"prefix parse function not defined",
+			},
+		},
+		{
+			buf: &mockTokenBuffer{
+				[]Token{
+					{Type: Lparen, Val: "("},
+					{Type: String, Val: "a"},
+					{Type: Comma, Val: ","},
+					{Type: Asterisk, Val: "*"},
+					{Type: Comma, Val: ","},
+					{Type: Int, Val: "5"},
+					{Type: Rparen, Val: ")"},
+				},
+				0,
+			},
+			setupScope: func() *symbol.Scope {
+				scope := symbol.NewScope()
+				scope.Set("a", &symbol.Integer{Name: &ast.Identifier{Name: "a"}})
+				return scope
+			},
+			expected: "",
+			expectedErr: Error{
+				Token{Type: Asterisk},
+				"prefix parse function not defined",
+			},
+		},
+		{
+			buf: &mockTokenBuffer{
+				[]Token{
+					{Type: Lparen, Val: "("},
+					{Type: Ident, Val: "add"},
+					{Type: Lparen, Val: "("},
+					{Type: Rparen, Val: ")"},
+					{Type: Comma, Val: ","},
+					{Type: Int, Val: "1"},
+					{Type: Rparen, Val: ")"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Eof, Val: "eof"},
+				},
+				0,
+			},
+			setupScope: func() *symbol.Scope {
+				scope := symbol.NewScope()
+				scope.Set("add", &symbol.Integer{Name: &ast.Identifier{Name: "add"}})
+				return scope
+			},
+			expected:    `function testFunction( function add(  ), 1 )`,
+			expectedErr: nil,
+		},
+	}
+
+	for i, test := range tests {
+		scope := test.setupScope()
+		exp, err := newTestParser(test.buf, scope).parseCallArguments()
+
+		if err != nil && err.Error() != test.expectedErr.Error() {
+			t.Fatalf("test[%d] - TestParseCallArguments() wrong error. Expected=%s, got=%s",
 				i, test.expectedErr.Error(), err.Error())
 		}
 
 		mockFn := &ast.CallExpression{
 			Function: &ast.Identifier{Name: "testFunction"},
 		}
-		mockFn.Arguments = exp
-		if exp != nil && mockFn.String() != test.expected {
-			t.Fatalf("test[%d] - TestParseCallArguments() wrong error. Expected=%s, got=%s",
-				i, test.expected, mockFn.String())
+		mockFn.Arguments = exp
+		if exp != nil && mockFn.String() != test.expected {
+			t.Fatalf("test[%d] - TestParseCallArguments() wrong error. Expected=%s, got=%s",
+				i, test.expected, mockFn.String())
+		}
+	}
+}
+
+func TestParseAssignStatement(t *testing.T) {
+	tests := []struct {
+		setupScopeFn
+		tokenBuffer           TokenBuffer
+		expectedDataStructure string
+		expectedIdent         string
+		expectedVal           string
+		expectedErr           error
+		chkScopeFn
+	}{
+		{
+			defaultSetupScopeFn,
+			&mockTokenBuffer{
+				buf: []Token{
+					{Type: StringType, Val: "string"},
+					{Type: Ident, Val: "a"},
+					{Type: Assign, Val: "="},
+					{Type: String, Val: "hello"},
+					{Type: Semicolon},
+					{Type: Eof},
+				},
+				sp: 0,
+			},
+			"string",
+			"a",
+			`"hello"`,
+			nil,
+			func(scope *symbol.Scope) bool {
+				sym := scope.Get("a")
+				if sym == nil {
+					return false
+				}
+
+				if sym.Type() != symbol.StringSymbol {
+					return false
+				}
+
+				if sym.String() != "a" {
+					return false
+				}
+
+				return true
+			},
+		},
+		{
+			defaultSetupScopeFn,
+			&mockTokenBuffer{
+				buf: []Token{
+					{Type: IntType, Val: "int"},
+					{Type: Ident, Val: "myInt"},
+					{Type: Assign, Val: "="},
+					{Type: Int, Val: "1"},
+					{Type: Semicolon},
+					{Type: Eof},
+				},
+				sp: 0,
+			},
+			"int",
+			"myInt",
+			"1",
+			nil,
+			func(scope *symbol.Scope) bool {
+				sym := scope.Get("myInt")
+				if sym == nil {
+					return false
+				}
+
+				if sym.Type() != symbol.IntegerSymbol {
+					return false
+				}
+
+				if sym.String() != "myInt" {
+					return false
+				}
+
+				return true
+			},
+		},
+		{
+			defaultSetupScopeFn,
+			&mockTokenBuffer{
+				buf: []Token{
+					{Type: BoolType, Val: "bool"},
+					{Type: Ident, Val: "ddd"},
+					{Type: Assign, Val: "="},
+					{Type: True, Val: "true"},
+					{Type: Semicolon},
+					{Type: Eof},
+				},
+				sp: 0,
+			},
+			"bool",
+			"ddd",
+			"true",
+			nil,
+			func(scope *symbol.Scope) bool {
+				sym := scope.Get("ddd")
+				if sym == nil {
+					return false
+				}
+
+				if sym.Type() != symbol.BooleanSymbol {
+					return false
+				}
+
+				if sym.String() != "ddd" {
+					return false
+				}
+
+				return true
+			},
+		},
+		{
+			// type mismatch tc - int ddd2 = "iam_string"
+			defaultSetupScopeFn,
+			&mockTokenBuffer{
+				buf: []Token{
+					{Type: IntType, Val: "int"},
+					{Type: Ident, Val: "ddd2"},
+					{Type: Assign, Val: "="},
+					{Type: String, Val: "iam_string"},
+					{Type: Semicolon},
+					{Type: Eof},
+				},
+				sp: 0,
+			},
+			"int",
+			"ddd2",
+			`"iam_string"`,
+			nil,
+			func(scope *symbol.Scope) bool {
+				sym := scope.Get("ddd2")
+				if sym == nil {
+					return false
+				}
+
+				if sym.Type() != symbol.IntegerSymbol {
+					return false
+				}
+
+				if sym.String() != "ddd2" {
+					return false
+				}
+
+				return true
+			},
+		},
+		{
+			// type mismatch tc - bool foo = "iam_string"
+			defaultSetupScopeFn,
+			&mockTokenBuffer{
+				buf: []Token{
+					{Type: BoolType, Val: "bool"},
+					{Type: Ident, Val: "foo"},
+					{Type: Assign, Val: "="},
+					{Type: String, Val: "iam_string"},
+					{Type: Semicolon},
+					{Type: Eof},
+				},
+				sp: 0,
+			},
+			"bool",
+			"foo",
+			`"iam_string"`,
+			nil,
+			func(scope *symbol.Scope) bool {
+				sym := scope.Get("foo")
+				if sym == nil {
+					return false
+				}
+
+				if sym.Type() != symbol.BooleanSymbol {
+					return false
+				}
+
+				if sym.String() != "foo" {
+					return false
+				}
+
+				return true
+			},
+		},
+		{
+			defaultSetupScopeFn,
+			&mockTokenBuffer{
+				buf: []Token{
+					{Type: BoolType, Val: "bool"},
+					{Type: String, Val: "foo"},
+					{Type: Assign, Val: "="},
+					{Type: String, Val: "iam_string"},
+					{Type: Semicolon},
+					{Type: Eof},
+				},
+				sp: 0,
+			},
+			"bool",
+			"foo",
+			`"iam_string"`,
+			ExpectError{
+				Token{Type: String},
+				Ident,
+			},
+			func(scope *symbol.Scope) bool {
+				sym := scope.Get("foo")
+				if sym != nil {
+					return false
+				}
+
+				return true
+			},
+		},
+		{
+			defaultSetupScopeFn,
+			&mockTokenBuffer{
+				buf: []Token{
+					{Type: BoolType, Val: "bool"},
+					{Type: Ident, Val: "foo"},
+					{Type: String, Val: "iam_string"},
+					{Type: Semicolon},
+					{Type: Eof},
+				},
+				sp: 0,
+			},
+			"bool",
+			"foo",
+			`"iam_string"`,
+			ExpectError{
+				Token{Type: String},
+				Assign,
+			},
+			func(scope *symbol.Scope) bool {
+				sym := scope.Get("foo")
+				if sym == nil {
+					return false
+				}
+
+				return true
+			},
+		},
+		// test when identifier already exist
+		{
+			func() *symbol.Scope {
+				scope := symbol.NewScope()
+				scope.Set("ddd", &symbol.String{Name: &ast.Identifier{Name: "ddd"}})
+				return scope
+			},
+			&mockTokenBuffer{
+				buf: []Token{
+					{Type: BoolType, Val: "bool"},
+					{Type: Ident, Val: "ddd"},
+					{Type: Assign, Val: "="},
+					{Type: True, Val: "true"},
+					{Type: Semicolon},
+					{Type: Eof},
+				},
+				sp: 0,
+			},
+			"bool",
+			"ddd",
+			"true",
+			DupSymError{Token{Type: Ident, Val: "ddd"}},
+			func(scope *symbol.Scope) bool {
+				sym := scope.Get("ddd")
+				if sym == nil {
+					return false
+				}
+
+				if sym.Type() != symbol.StringSymbol {
+					return false
+				}
+
+				if sym.String() != "ddd" {
+					return false
+				}
+
+				return true
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		// setup
+		scope := tt.setupScopeFn()
+
+		// exercise
+		exp, err := newTestParser(tt.tokenBuffer, scope).parseAssignStatement()
+
+		// verify
+		if err != nil && err.Error() != tt.expectedErr.Error() {
+			t.Errorf(`tests[%d] - Returned err is not "%s", but got "%s"`,
+				i, tt.expectedErr.Error(), err.Error())
+		}
+
+		if err == nil && exp.Type.String() != tt.expectedDataStructure {
+			t.Errorf("tests[%d] - Type is not %s but got %s",
+				i, tt.expectedDataStructure, exp.Type.String())
+		}
+
+		if err == nil && exp.Variable.String() != tt.expectedIdent {
+			t.Errorf("tests[%d] - Variable is not %s but got %s",
+				i, tt.expectedIdent, exp.Variable.String())
+		}
+
+		if err == nil && exp.Value.String() != tt.expectedVal {
+			t.Errorf("tests[%d] - Name is not %s but got %s",
+				i, tt.expectedVal, exp.Value.String())
+		}
+
+		if !tt.chkScopeFn(scope) {
+			t.Errorf("test[%d] - updateScopeSymbol updates scope incorrectly", i)
+		}
+	}
+}
+
+func TestParseReassignStatement(t *testing.T) {
+	tests := []struct {
+		buf         TokenBuffer
+		setupScope  setupScopeFn
+		expected    string
+		expectedErr error
+	}{
+		{
+			buf: &mockTokenBuffer{
+				buf: []Token{
+					{
+						Type: Ident,
+						Val:  "a",
+					},
+					{
+						Type: Assign,
+						Val:  "=",
+					},
+					{
+						Type: Int,
+						Val:  "1",
+					},
+				},
+				sp: 0,
+			},
+			setupScope: func() *symbol.Scope {
+				scope := symbol.NewScope()
+				scope.Set("a", &symbol.Integer{Name: &ast.Identifier{Name: "a"}})
+				return scope
+			},
+			expected:    "a = 1",
+			expectedErr: nil,
+		},
+		{
+			buf: &mockTokenBuffer{
+				buf: []Token{
+					{
+						Type: Ident,
+						Val:  "b",
+					},
+					{
+						Type: Assign,
+						Val:  "=",
+					},
+					{
+						Type: Int,
+						Val:  "1",
+					},
+				},
+				sp: 0,
+			},
+			setupScope: func() *symbol.Scope {
+				scope := symbol.NewScope()
+				scope.Set("a", &symbol.Integer{Name: &ast.Identifier{Name: "a"}})
+				return scope
+			},
+			expected: "",
+			expectedErr: NotExistSymError{Token{
+				Type: Ident,
+				Val:  "b",
+			}},
+		},
+	}
+
+	for i, test := range tests {
+		scope := test.setupScope()
+		stmt, err := newTestParser(test.buf, scope).parseReassignStatement()
+		if err != nil && err.Error() != test.expectedErr.Error() {
+			t.Fatalf("test[%d] - parseReassignStatement() returns wrong error.\n"+
+				"Expected=%s\n"+
+				"got=%s", i, test.expectedErr.Error(), err.Error())
+		}
+
+		if stmt != nil && stmt.String() != test.expected {
+			t.Fatalf("test[%d] - parseReassignStatement() returns wrong result.\n"+
+				"Expected=%s\n"+
+				"got=%s", i, test.expected, stmt.String())
+		}
+	}
+}
+
+// TestParseExpression tests strings which combine prefix and
+// infix expression
+func TestParseExpression(t *testing.T) {
+	tests := []struct {
+		buf         TokenBuffer
+		setupScope  setupScopeFn
+		expected    string
+		expectedErr error
+	}{
+		{
+			&mockTokenBuffer{
+				[]Token{
+					{Type: Minus, Val: "-"},
+					{Type: Ident, Val: "a"},
+					{Type: Asterisk, Val: "*"},
+					{Type: Ident, Val: "b"},
+					{Type: Eof},
+				},
+				0,
+			},
+			func() *symbol.Scope {
+				scope := symbol.NewScope()
+				scope.Set("a", &symbol.Integer{Name: &ast.Identifier{Name: "a"}})
+				scope.Set("b", &symbol.Integer{Name: &ast.Identifier{Name: "b"}})
+				return scope
+			},
+			"((-a) * b)",
+			nil,
+		},
+		{
+			&mockTokenBuffer{
+				[]Token{
+					{Type: Bang, Val: "!"},
+					{Type: Minus, Val: "-"},
+					{Type: Ident, Val: "b"},
+					{Type: Eof},
+				},
+				0,
+			},
+			func() *symbol.Scope {
+				scope := symbol.NewScope()
+				scope.Set("b", &symbol.Integer{Name: &ast.Identifier{Name: "b"}})
+				return scope
+			},
+			"(!(-b))",
+			nil,
+		},
+		{
+			&mockTokenBuffer{
+				[]Token{
+					{Type: Minus, Val: "-"},
+					{Type: Int, Val: "33"},
+					{Type: Slash, Val: "/"},
+					{Type: Int, Val: "67"},
+					{Type: Plus, Val: "+"},
+					{Type: Ident, Val: "a"},
+					{Type: Eof},
+				},
+				0,
+			},
+			func() *symbol.Scope {
+				scope := symbol.NewScope()
+				scope.Set("a", &symbol.Integer{Name: &ast.Identifier{Name: "a"}})
+				return scope
+			},
+			"(((-33) / 67) + a)",
+			nil,
+		},
+		{
+			&mockTokenBuffer{
+				[]Token{
+					{Type: Int, Val: "33"},
+					{Type: Mod, Val: "%"},
+					{Type: Minus, Val: "-"},
+					{Type: Int, Val: "67"},
+					{Type: Plus, Val: "+"},
+					{Type: Ident, Val: "a"},
+					{Type: Asterisk, Val: "*"},
+					{Type: Ident, Val: "c"},
+					{Type: Eof},
+				},
+				0,
+			},
+			func() *symbol.Scope {
+				scope := symbol.NewScope()
+				scope.Set("a", &symbol.Integer{Name: &ast.Identifier{Name: "a"}})
+				scope.Set("c", &symbol.Integer{Name: &ast.Identifier{Name: "c"}})
+				return scope
+			},
+			"((33 % (-67)) + (a * c))",
+			nil,
+		},
+		{
+			&mockTokenBuffer{
+				[]Token{
+					{Type: Int, Val: "33"},
+					{Type: Mod, Val: "%"},
+					{Type: Lparen, Val: "("},
+					{Type: Minus, Val: "-"},
+					{Type: Int, Val: "67"},
+					{Type: Plus, Val: "+"},
+					{Type: Ident, Val: "a"},
+					{Type: Rparen, Val: ")"},
+					{Type: Asterisk, Val: "*"},
+					{Type: Ident, Val: "c"},
+					{Type: Eof},
+				},
+				0,
+			},
+			func() *symbol.Scope {
+				scope := symbol.NewScope()
+				scope.Set("a", &symbol.Integer{Name: &ast.Identifier{Name: "a"}})
+				scope.Set("c", &symbol.Integer{Name: &ast.Identifier{Name: "c"}})
+				return scope
+			},
+			"((33 % ((-67) + a)) * c)",
+			nil},
+		{
+			&mockTokenBuffer{
+				[]Token{
+					{Type: Minus, Val: "-"},
+					{Type: Int, Val: "33"},
+					{Type: Slash, Val: "/"},
+					{Type: Int, Val: "67"},
+					{Type: LT, Val: "<"},
+					{Type: Ident, Val: "a"},
+					{Type: Asterisk, Val: "*"},
+					{Type: Int, Val: "67"},
+					{Type: Eof},
+				},
+				0,
+			},
+			func() *symbol.Scope {
+				scope := symbol.NewScope()
+				scope.Set("a", &symbol.Integer{Name: &ast.Identifier{Name: "a"}})
+				return scope
+			},
+			"(((-33) / 67) < (a * 67))",
+			nil,
+		},
+		{
+			&mockTokenBuffer{
+				[]Token{
+					{Type: Minus, Val: "-"},
+					{Type: Int, Val: "33"},
+					{Type: Slash, Val: "/"},
+					{Type: Int, Val: "67"},
+					{Type: GTE, Val: ">="},
+					{Type: Ident, Val: "a"},
+					{Type: Plus, Val: "+"},
+					{Type: Int, Val: "67"},
+					{Type: Mod, Val: "%"},
+					{Type: Ident, Val: "z"},
+					{Type: Eof},
+				},
+				0,
+			},
+			func() *symbol.Scope {
+				scope := symbol.NewScope()
+				scope.Set("a", &symbol.Integer{Name: &ast.Identifier{Name: "a"}})
+				scope.Set("z", &symbol.Integer{Name: &ast.Identifier{Name: "z"}})
+				return scope
+			},
+			"(((-33) / 67) >= (a + (67 % z)))",
+			nil},
+		{
+			&mockTokenBuffer{
+				[]Token{
+					{Type: Int, Val: "33"},
+					{Type: EQ, Val: "=="},
+					{Type: Int, Val: "3"},
+					{Type: Land, Val: "&&"},
+					{Type: Int, Val: "44"},
+					{Type: EQ, Val: "=="},
+					{Type: Int, Val: "67"},
+					{Type: Eof},
+				},
+				0,
+			},
+			defaultSetupScopeFn,
+			"((33 == 3) && (44 == 67))",
+			nil,
+		},
+		{
+			&mockTokenBuffer{
+				[]Token{
+					{Type: True, Val: "true"},
+					{Type: Lor, Val: "||"},
+					{Type: False, Val: "false"},
+					{Type: Land, Val: "&&"},
+					{Type: True, Val: "true"},
+					{Type: Eof},
+				},
+				0,
+			},
+			defaultSetupScopeFn,
+			"(true || (false && true))",
+			nil,
+		},
+		{
+			&mockTokenBuffer{
+				[]Token{
+					{Type: Int, Val: "1"},
+					{Type: Or, Val: "|"},
+					{Type: Int, Val: "2"},
+					{Type: Xor, Val: "^"},
+					{Type: Int, Val: "3"},
+					{Type: And, Val: "&"},
+					{Type: Int, Val: "4"},
+					{Type: Eof},
+				},
+				0,
+			},
+			defaultSetupScopeFn,
+			"(1 | (2 ^ (3 & 4)))",
+			nil,
+		},
+		{
+			&mockTokenBuffer{
+				[]Token{
+					{Type: Int, Val: "1"},
+					{Type: Shl, Val: "<<"},
+					{Type: Int, Val: "2"},
+					{Type: Plus, Val: "+"},
+					{Type: Int, Val: "3"},
+					{Type: Shr, Val: ">>"},
+					{Type: Int, Val: "4"},
+					{Type: Eof},
+				},
+				0,
+			},
+			defaultSetupScopeFn,
+			"((1 << (2 + 3)) >> 4)",
+			nil,
+		},
+		{
+			&mockTokenBuffer{
+				[]Token{
+					{Type: Ident, Val: "a"},
+					{Type: And, Val: "&"},
+					{Type: Int, Val: "1"},
+					{Type: EQ, Val: "=="},
+					{Type: Tilde, Val: "~"},
+					{Type: Int, Val: "0"},
+					{Type: Eof},
+				},
+				0,
+			},
+			func() *symbol.Scope {
+				scope := symbol.NewScope()
+				scope.Set("a", &symbol.Integer{Name: &ast.Identifier{Name: "a"}})
+				return scope
+			},
+			"((a & 1) == (~0))",
+			nil,
+		},
+		{
+			&mockTokenBuffer{
+				[]Token{
+					{Type: Tilde, Val: "~"},
+					{Type: True, Val: "true"},
+					{Type: Eof},
+				},
+				0,
+			},
+			defaultSetupScopeFn,
+			"",
+			PrefixError{
+				Token{Type: Tilde, Val: "~"},
+				&ast.BooleanLiteral{Value: true},
+			},
+		}, {
+			&mockTokenBuffer{
+				[]Token{
+					{Type: Ident, Val: "a"},
+					{Type: GT, Val: ">"},
+					{Type: Ident, Val: "b"},
+					{Type: Question, Val: "?"},
+					{Type: Ident, Val: "a"},
+					{Type: Colon, Val: ":"},
+					{Type: Ident, Val: "b"},
+					{Type: Plus, Val: "+"},
+					{Type: Int, Val: "1"},
+					{Type: Eof},
+				},
+				0,
+			},
+			func() *symbol.Scope {
+				scope := symbol.NewScope()
+				scope.Set("a", &symbol.Integer{Name: &ast.Identifier{Name: "a"}})
+				scope.Set("b", &symbol.Integer{Name: &ast.Identifier{Name: "b"}})
+				return scope
+			},
+			"((a > b) ? a : (b + 1))",
+			nil,
+		},
+		{
+			&mockTokenBuffer{
+				[]Token{
+					{Type: Ident, Val: "a"},
+					{Type: Question, Val: "?"},
+					{Type: Int, Val: "1"},
+					{Type: Colon, Val: ":"},
+					{Type: Ident, Val: "b"},
+					{Type: Question, Val: "?"},
+					{Type: Int, Val: "2"},
+					{Type: Colon, Val: ":"},
+					{Type: Int, Val: "3"},
+					{Type: Eof},
+				},
+				0,
+			},
+			func() *symbol.Scope {
+				scope := symbol.NewScope()
+				scope.Set("a", &symbol.Boolean{Name: &ast.Identifier{Name: "a"}})
+				scope.Set("b", &symbol.Boolean{Name: &ast.Identifier{Name: "b"}})
+				return scope
+			},
+			"(a ? 1 : (b ? 2 : 3))",
+			nil,
+		},
+		{
+			&mockTokenBuffer{
+				[]Token{
+					{Type: True, Val: "true"},
+					{Type: Question, Val: "?"},
+					{Type: Int, Val: "1"},
+					{Type: Colon, Val: ":"},
+					{Type: String, Val: "\"a\""},
+					{Type: Eof},
+				},
+				0,
+			},
+			defaultSetupScopeFn,
+			"",
+			Error{
+				Token{Type: Question, Val: "?"},
+				"branches of conditional expression have different types [int] and [string]",
+			},
+		},
+		{
+			&mockTokenBuffer{
+				[]Token{
+					{Type: Ident, Val: "a"},
+					{Type: Question, Val: "?"},
+					{Type: Int, Val: "1"},
+					{Type: Colon, Val: ":"},
+					{Type: Int, Val: "2"},
+					{Type: Eof},
+				},
+				0,
+			},
+			func() *symbol.Scope {
+				scope := symbol.NewScope()
+				scope.Set("a", &symbol.Integer{Name: &ast.Identifier{Name: "a"}})
+				return scope
+			},
+			"",
+			Error{
+				Token{Type: Question, Val: "?"},
+				"condition of conditional expression must be [bool], but got [int]",
+			},
+		},
+		{
+			&mockTokenBuffer{
+				[]Token{
+					{Type: Ident, Val: "a"},
+					{Type: GT, Val: ">"},
+					{Type: Int, Val: "0"},
+					{Type: Question, Val: "?"},
+					{Type: String, Val: "\"ab\""},
+					{Type: Plus, Val: "+"},
+					{Type: String, Val: "\"c\""},
+					{Type: Colon, Val: ":"},
+					{Type: Int, Val: "5"},
+					{Type: Eof},
+				},
+				0,
+			},
+			func() *symbol.Scope {
+				scope := symbol.NewScope()
+				scope.Set("a", &symbol.Integer{Name: &ast.Identifier{Name: "a"}})
+				return scope
+			},
+			"",
+			Error{
+				Token{Type: Plus, Val: "+"},
+				"operator + is not defined on [string]",
+			},
+		},
+		{
+			&mockTokenBuffer{
+				[]Token{
+					{Type: True, Val: "true"},
+					{Type: Question, Val: "?"},
+					{Type: Int, Val: "1"},
+					{Type: Int, Val: "2"},
+					{Type: Eof},
+				},
+				0,
+			},
+			defaultSetupScopeFn,
+			"",
+			ExpectError{
+				Token{Type: Int, Val: "2"},
+				Colon,
+			},
+		}, {
+			&mockTokenBuffer{
+				[]Token{
+					{Type: IntType, Val: "int"},
+					{Type: Lparen, Val: "("},
+					{Type: Ident, Val: "a"},
+					{Type: Rparen, Val: ")"},
+					{Type: Plus, Val: "+"},
+					{Type: Int, Val: "1"},
+					{Type: Eof},
+				},
+				0,
+			},
+			func() *symbol.Scope {
+				scope := symbol.NewScope()
+				scope.Set("a", &symbol.String{Name: &ast.Identifier{Name: "a"}})
+				return scope
+			},
+			"(int(a) + 1)",
+			nil,
+		},
+		{
+			&mockTokenBuffer{
+				[]Token{
+					{Type: StringType, Val: "string"},
+					{Type: Lparen, Val: "("},
+					{Type: BoolType, Val: "bool"},
+					{Type: Lparen, Val: "("},
+					{Type: Int, Val: "1"},
+					{Type: Rparen, Val: ")"},
+					{Type: Rparen, Val: ")"},
+					{Type: Eof},
+				},
+				0,
+			},
+			defaultSetupScopeFn,
+			"string(bool(1))",
+			nil,
+		},
+		{
+			&mockTokenBuffer{
+				[]Token{
+					{Type: BoolType, Val: "bool"},
+					{Type: Lparen, Val: "("},
+					{Type: Ident, Val: "foo"},
+					{Type: Lparen, Val: "("},
+					{Type: Rparen, Val: ")"},
+					{Type: Rparen, Val: ")"},
+					{Type: Eof},
+				},
+				0,
+			},
+			defaultSetupScopeFn,
+			"",
+			Error{
+				Token{Type: BoolType, Val: "bool"},
+				"can't convert function foo(  ) to [bool], type of value is unknown",
+			},
+		},
+		{
+			&mockTokenBuffer{
+				[]Token{
+					{Type: IntType, Val: "int"},
+					{Type: Int, Val: "1"},
+					{Type: Eof},
+				},
+				0,
+			},
+			defaultSetupScopeFn,
+			"",
+			ExpectError{
+				Token{Type: Int, Val: "1"},
+				Lparen,
+			},
+		},
+		{
+			&mockTokenBuffer{
+				[]Token{
+					{Type: String, Val: "\"a\""},
+					{Type: Shl, Val: "<<"},
+					{Type: True, Val: "true"},
+					{Type: Eof},
+				},
+				0,
+			},
+			defaultSetupScopeFn,
+			"",
+			Error{
+				Token{Type: Shl, Val: "<<"},
+				"operator << is not defined on [string]",
+			},
+		},
+		{
+			&mockTokenBuffer{
+				[]Token{
+					{Type: Int, Val: "1"},
+					{Type: Shl, Val: "<<"},
+					{Type: Ident, Val: "a"},
+					{Type: Eof},
+				},
+				0,
+			},
+			func() *symbol.Scope {
+				scope := symbol.NewScope()
+				scope.Set("a", &symbol.Boolean{Name: &ast.Identifier{Name: "a"}})
+				return scope
+			},
+			"",
+			Error{
+				Token{Type: Shl, Val: "<<"},
+				"operator << is not defined on [bool]",
+			},
+		},
+		{
+			&mockTokenBuffer{
+				[]Token{
+					{Type: Ident, Val: "s"},
+					{Type: EQ, Val: "=="},
+					{Type: Ident, Val: "State"},
+					{Type: Dot, Val: "."},
+					{Type: Ident, Val: "Closed"},
+					{Type: Eof},
+				},
+				0,
+			},
+			setupEnumScopeFn,
+			"(s == State.Closed)",
+			nil,
+		},
+		{
+			&mockTokenBuffer{
+				[]Token{
+					{Type: Ident, Val: "State"},
+					{Type: Dot, Val: "."},
+					{Type: Ident, Val: "Open"},
+					{Type: Plus, Val: "+"},
+					{Type: Int, Val: "1"},
+					{Type: Eof},
+				},
+				0,
+			},
+			setupEnumScopeFn,
+			"",
+			Error{
+				Token{Type: Plus, Val: "+"},
+				"operator + is not defined on enum",
+			},
+		},
+		{
+			&mockTokenBuffer{
+				[]Token{
+					{Type: Ident, Val: "State"},
+					{Type: Dot, Val: "."},
+					{Type: Ident, Val: "Open"},
+					{Type: EQ, Val: "=="},
+					{Type: Ident, Val: "Color"},
+					{Type: Dot, Val: "."},
+					{Type: Ident, Val: "Red"},
+					{Type: Eof},
+				},
+				0,
+			},
+			setupEnumScopeFn,
+			"",
+			Error{
+				Token{Type: EQ, Val: "=="},
+				"can't compare State.Open with Color.Red",
+			},
+		},
+		{
+			&mockTokenBuffer{
+				[]Token{
+					{Type: Ident, Val: "State"},
+					{Type: Dot, Val: "."},
+					{Type: Ident, Val: "Open"},
+					{Type: EQ, Val: "=="},
+					{Type: Int, Val: "0"},
+					{Type: Eof},
+				},
+				0,
+			},
+			setupEnumScopeFn,
+			"",
+			Error{
+				Token{Type: EQ, Val: "=="},
+				"can't compare State.Open with 0",
+			},
+		},
+		{
+			&mockTokenBuffer{
+				[]Token{
+					{Type: Ident, Val: "State"},
+					{Type: Dot, Val: "."},
+					{Type: Ident, Val: "Unknown"},
+					{Type: Eof},
+				},
+				0,
+			},
+			setupEnumScopeFn,
+			"",
+			Error{
+				Token{Type: Ident, Val: "Unknown"},
+				"enum State has no member Unknown",
+			},
+		},
+		{
+			&mockTokenBuffer{
+				[]Token{
+					{Type: Ident, Val: "s"},
+					{Type: Dot, Val: "."},
+					{Type: Ident, Val: "Open"},
+					{Type: Eof},
+				},
+				0,
+			},
+			setupEnumScopeFn,
+			"",
+			Error{
+				Token{Type: Dot, Val: "."},
+				"s is not an enum",
+			},
+		},
+		{
+			&mockTokenBuffer{
+				[]Token{
+					{Type: Bang, Val: "!"},
+					{Type: Ident, Val: "State"},
+					{Type: Dot, Val: "."},
+					{Type: Ident, Val: "Open"},
+					{Type: Eof},
+				},
+				0,
+			},
+			setupEnumScopeFn,
+			"",
+			PrefixError{
+				Token{Type: Bang, Val: "!"},
+				&ast.EnumMemberLiteral{
+					Enum:   stateEnum,
+					Member: &ast.Identifier{Name: "Open"},
+				},
+			},
+		},
+		{
+			&mockTokenBuffer{
+				[]Token{
+					{Type: IntType, Val: "int"},
+					{Type: Lparen, Val: "("},
+					{Type: Ident, Val: "State"},
+					{Type: Dot, Val: "."},
+					{Type: Ident, Val: "Settled"},
+					{Type: Rparen, Val: ")"},
+					{Type: Eof},
+				},
+				0,
+			},
+			setupEnumScopeFn,
+			"int(State.Settled)",
+			nil,
+		},
+	}
+
+	for i, test := range tests {
+		p := newTestParser(test.buf, test.setupScope())
+		exp, err := p.parseExpression(LOWEST)
+
+		if err != nil && err.Error() != test.expectedErr.Error() {
+			t.Fatalf("test[%d] - parseExpression() with wrong error. Expected=%s, got=%s",
+				i, test.expectedErr.Error(), err)
+		}
+
+		if err == nil && exp.String() != test.expected {
+			t.Fatalf("test[%d] - parseExpression() with wrong expression. Expected=%s, got=%s",
+				i, test.expected, exp.String())
 		}
 	}
 }
 
-func TestParseAssignStatement(t *testing.T) {
-	initParseFnMap()
+func TestParseIfStatement(t *testing.T) {
 	tests := []struct {
 		setupScopeFn
-		tokenBuffer           TokenBuffer
-		expectedDataStructure string
-		expectedIdent         string
-		expectedVal           string
-		expectedErr           error
+		buf         TokenBuffer
+		expected    string
+		expectedErr error
 		chkScopeFn
 	}{
 		{
 			defaultSetupScopeFn,
 			&mockTokenBuffer{
-				buf: []Token{
+				[]Token{
+					{Type: If, Val: "if"},
+					{Type: Lparen, Val: "("},
+					{Type: True, Val: "true"},
+					{Type: Rparen, Val: ")"},
+					{Type: Lbrace, Val: "{"},
+					{Type: IntType, Val: "int"},
+					{Type: Ident, Val: "a"},
+					{Type: Assign, Val: "="},
+					{Type: Int, Val: "0"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Rbrace, Val: "}"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Eof},
+				},
+				0,
+			},
+			"if ( true ) { int a = 0 }",
+			nil,
+			func(scope *symbol.Scope) bool {
+				sym := scope.GetInner()[0].Get("a")
+				if sym == nil {
+					return false
+				}
+
+				if sym.Type() != symbol.IntegerSymbol {
+					return false
+				}
+
+				if sym.String() != "a" {
+					return false
+				}
+
+				return true
+			},
+		},
+		{
+			func() *symbol.Scope {
+				scope := symbol.NewScope()
+				scope.Set("a", &symbol.Integer{Name: &ast.Identifier{Name: "a"}})
+				return scope
+			},
+			&mockTokenBuffer{
+				[]Token{
+					{Type: If, Val: "if"},
+					{Type: Lparen, Val: "("},
+					{Type: Ident, Val: "a"},
+					{Type: EQ, Val: "=="},
+					{Type: Int, Val: "5"},
+					{Type: Rparen, Val: ")"},
+					{Type: Lbrace, Val: "{"},
+					{Type: IntType, Val: "int"},
+					{Type: Ident, Val: "b"},
+					{Type: Assign, Val: "="},
+					{Type: Int, Val: "1"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Rbrace, Val: "}"},
+					{Type: Else, Val: "else"},
+					{Type: Lbrace, Val: "{"},
 					{Type: StringType, Val: "string"},
+					{Type: Ident, Val: "b"},
+					{Type: Assign, Val: "="},
+					{Type: String, Val: "example"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Rbrace, Val: "}"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Eof},
+				},
+				0,
+			},
+			`if ( (a == 5) ) { int b = 1 } else { string b = "example" }`,
+			nil,
+			func(scope *symbol.Scope) bool {
+				sym := scope.GetInner()[0].Get("a")
+				if sym == nil {
+					return false
+				}
+
+				if sym.Type() != symbol.IntegerSymbol {
+					return false
+				}
+
+				if sym.String() != "a" {
+					return false
+				}
+
+				sym = scope.GetInner()[1].Get("b")
+				if sym == nil {
+					return false
+				}
+
+				if sym.Type() != symbol.StringSymbol {
+					return false
+				}
+
+				if sym.String() != "b" {
+					return false
+				}
+
+				return true
+			},
+		},
+		{
+			defaultSetupScopeFn,
+			&mockTokenBuffer{
+				[]Token{
+					{Type: IntType, Val: "int"},
+					{Type: Ident, Val: "a"},
+					{Type: Assign, Val: "="},
+					{Type: Int, Val: "5"},
+					{Type: Eof},
+				},
+				0,
+			},
+			"",
+			ExpectError{
+				Token{Type: IntType},
+				If,
+			},
+			defaultChkScopeFn,
+		},
+		{
+			defaultSetupScopeFn,
+			&mockTokenBuffer{
+				[]Token{
+					{Type: If, Val: "if"},
+					{Type: Lparen, Val: "("},
+					{Type: True, Val: "true"},
+					{Type: Rparen, Val: ")"},
+					{Type: IntType, Val: "int"},
+					{Type: Ident, Val: "a"},
+					{Type: Assign, Val: "="},
+					{Type: Int, Val: "0"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Rbrace, Val: "}"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Eof},
+				},
+				0,
+			},
+			"",
+			ExpectError{
+				Token{Type: IntType},
+				Lbrace,
+			},
+			defaultChkScopeFn,
+		},
+		{
+			defaultSetupScopeFn,
+			&mockTokenBuffer{
+				[]Token{
+					{Type: If, Val: "if"},
+					{Type: Lparen, Val: "("},
+					{Type: True, Val: "true"},
+					{Type: Rbrace, Val: "}"},
+					{Type: Lbrace, Val: "{"},
+					{Type: IntType, Val: "int"},
+					{Type: Ident, Val: "a"},
+					{Type: Assign, Val: "="},
+					{Type: Int, Val: "0"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Rbrace, Val: "}"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Eof},
+				},
+				0,
+			},
+			"",
+			ExpectError{
+				Token{Type: Rbrace},
+				Rparen,
+			},
+			defaultChkScopeFn,
+		},
+	}
+
+	for i, test := range tests {
+		// setup
+		scope := test.setupScopeFn()
+
+		// exercise
+		stmt, err := newTestParser(test.buf, scope).parseIfStatement()
+
+		// verify
+		if err != nil && err.Error() != test.expectedErr.Error() {
+			t.Fatalf("test[%d] - TestParseIfStatement() wrong error. Expected=%s got=%s",
+				i, test.expectedErr.Error(), err.Error())
+		}
+
+		if stmt != nil && stmt.String() != test.expected {
+			t.Fatalf("test[%d] - TestParseIfStatement() wrong result. Expected=%s, got=%s",
+				i, test.expected, stmt.String())
+		}
+
+		if !test.chkScopeFn(scope) {
+			t.Fatalf("test[%d] - updateScopeSymbol updates scope incorrectly", i)
+		}
+	}
+}
+
+func TestParseBlockStatement(t *testing.T) {
+	tests := []struct {
+		setupScopeFn
+		buf         TokenBuffer
+		expected    string
+		expectedErr error
+		chkScopeFn
+	}{
+		{
+			defaultSetupScopeFn,
+			&mockTokenBuffer{
+				[]Token{
+					{Type: Lbrace, Val: "{"},
+					{Type: IntType, Val: "int"},
+					{Type: Ident, Val: "a"},
+					{Type: Assign, Val: "="},
+					{Type: Int, Val: "0"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Rbrace, Val: "}"},
+				},
+				0,
+			},
+			"int a = 0",
+			nil,
+			func(scope *symbol.Scope) bool {
+				sym := scope.GetInner()[0].Get("a")
+				if sym == nil {
+					return false
+				}
+
+				if sym.Type() != symbol.IntegerSymbol {
+					return false
+				}
+
+				if sym.String() != "a" {
+					return false
+				}
+
+				return true
+			},
+		},
+		{
+			defaultSetupScopeFn,
+			&mockTokenBuffer{
+				[]Token{
+					{Type: Lbrace, Val: "{"},
+					{Type: IntType, Val: "int"},
 					{Type: Ident, Val: "a"},
 					{Type: Assign, Val: "="},
-					{Type: String, Val: "hello"},
-					{Type: Semicolon},
-					{Type: Eof},
+					{Type: Int, Val: "0"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: StringType, Val: "string"},
+					{Type: Ident, Val: "b"},
+					{Type: Assign, Val: "="},
+					{Type: String, Val: "abc"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Rbrace, Val: "}"},
 				},
-				sp: 0,
+				0,
 			},
-			"string",
-			"a",
-			"hello",
+			`int a = 0
+string b = "abc"`,
 			nil,
 			func(scope *symbol.Scope) bool {
-				sym := scope.Get("a")
+				sym := scope.GetInner()[0].Get("a")
 				if sym == nil {
 					return false
 				}
 
-				if sym.Type() != symbol.StringSymbol {
+				if sym.Type() != symbol.IntegerSymbol {
 					return false
 				}
 
@@ -2202,28 +4556,52 @@ func TestParseAssignStatement(t *testing.T) {
 					return false
 				}
 
+				sym = scope.GetInner()[0].Get("b")
+				if sym == nil {
+					return false
+				}
+
+				if sym.Type() != symbol.StringSymbol {
+					return false
+				}
+
+				if sym.String() != "b" {
+					return false
+				}
+
 				return true
 			},
 		},
 		{
 			defaultSetupScopeFn,
 			&mockTokenBuffer{
-				buf: []Token{
+				[]Token{
+					{Type: Lbrace, Val: "{"},
 					{Type: IntType, Val: "int"},
-					{Type: Ident, Val: "myInt"},
+					{Type: Ident, Val: "a"},
 					{Type: Assign, Val: "="},
-					{Type: Int, Val: "1"},
-					{Type: Semicolon},
-					{Type: Eof},
+					{Type: Int, Val: "0"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: StringType, Val: "string"},
+					{Type: Ident, Val: "b"},
+					{Type: Assign, Val: "="},
+					{Type: String, Val: "abc"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: BoolType, Val: "bool"},
+					{Type: Ident, Val: "c"},
+					{Type: Assign, Val: "="},
+					{Type: True, Val: "true"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Rbrace, Val: "}"},
 				},
-				sp: 0,
+				0,
 			},
-			"int",
-			"myInt",
-			"1",
+			`int a = 0
+string b = "abc"
+bool c = true`,
 			nil,
 			func(scope *symbol.Scope) bool {
-				sym := scope.Get("myInt")
+				sym := scope.GetInner()[0].Get("a")
 				if sym == nil {
 					return false
 				}
@@ -2232,7 +4610,33 @@ func TestParseAssignStatement(t *testing.T) {
 					return false
 				}
 
-				if sym.String() != "myInt" {
+				if sym.String() != "a" {
+					return false
+				}
+
+				sym = scope.GetInner()[0].Get("b")
+				if sym == nil {
+					return false
+				}
+
+				if sym.Type() != symbol.StringSymbol {
+					return false
+				}
+
+				if sym.String() != "b" {
+					return false
+				}
+
+				sym = scope.GetInner()[0].Get("c")
+				if sym == nil {
+					return false
+				}
+
+				if sym.Type() != symbol.BooleanSymbol {
+					return false
+				}
+
+				if sym.String() != "c" {
 					return false
 				}
 
@@ -2242,31 +4646,86 @@ func TestParseAssignStatement(t *testing.T) {
 		{
 			defaultSetupScopeFn,
 			&mockTokenBuffer{
-				buf: []Token{
-					{Type: BoolType, Val: "bool"},
-					{Type: Ident, Val: "ddd"},
+				[]Token{
+					{Type: Lbrace, Val: "{"},
+					{Type: IntType, Val: "int"},
+					{Type: Ident, Val: "a"},
 					{Type: Assign, Val: "="},
-					{Type: True, Val: "true"},
-					{Type: Semicolon},
-					{Type: Eof},
+					{Type: Int, Val: "0"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Ident, Val: "a"},
+					{Type: Assign, Val: "="},
+					{Type: Int, Val: "1"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Rbrace, Val: "}"},
 				},
-				sp: 0,
+				0,
 			},
-			"bool",
-			"ddd",
-			"true",
+			"int a = 0\na = 1",
 			nil,
-			func(scope *symbol.Scope) bool {
-				sym := scope.Get("ddd")
+			defaultChkScopeFn,
+		},
+	}
+
+	for i, test := range tests {
+		// setup
+		scope := test.setupScopeFn()
+
+		// exercise
+		exp, err := newTestParser(test.buf, scope).parseBlockStatement()
+
+		// verify
+		if err != nil && err.Error() != test.expectedErr.Error() {
+			t.Fatalf("test[%d] - TestParseBlockStatement() wrong error. Expected=%s, got=%s",
+				i, test.expectedErr.Error(), err.Error())
+		}
+
+		if exp != nil && exp.String() != test.expected {
+			t.Fatalf("test[%d] - TestParseBlockStatement() wrong result. Expected=%s, got=%s",
+				i, test.expected, exp.String())
+		}
+
+		if !test.chkScopeFn(scope) {
+			t.Fatalf("test[%d] - updateScopeSymbol updates scope incorrectly", i)
+		}
+	}
+}
+
+func TestParseStatement(t *testing.T) {
+	tests := []struct {
+		setupScopeFn
+		buf          TokenBuffer
+		expectedErr  error
+		expectedStmt string
+		chkScopeFn
+	}{
+		// tests for IntType
+		{
+			setupScopeFn: defaultSetupScopeFn,
+			buf: &mockTokenBuffer{
+				[]Token{
+					{Type: IntType, Val: "int"},
+					{Type: Ident, Val: "a"},
+					{Type: Assign, Val: "="},
+					{Type: Int, Val: "1"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Eof},
+				},
+				0,
+			},
+			expectedErr:  nil,
+			expectedStmt: "int a = 1",
+			chkScopeFn: func(scope *symbol.Scope) bool {
+				sym := scope.Get("a")
 				if sym == nil {
 					return false
 				}
 
-				if sym.Type() != symbol.BooleanSymbol {
+				if sym.Type() != symbol.IntegerSymbol {
 					return false
 				}
 
-				if sym.String() != "ddd" {
+				if sym.String() != "a" {
 					return false
 				}
 
@@ -2274,25 +4733,24 @@ func TestParseAssignStatement(t *testing.T) {
 			},
 		},
 		{
-			// type mismatch tc - int ddd2 = "iam_string"
-			defaultSetupScopeFn,
-			&mockTokenBuffer{
-				buf: []Token{
+			setupScopeFn: defaultSetupScopeFn,
+			buf: &mockTokenBuffer{
+				[]Token{
 					{Type: IntType, Val: "int"},
-					{Type: Ident, Val: "ddd2"},
+					{Type: Ident, Val: "a"},
 					{Type: Assign, Val: "="},
-					{Type: String, Val: "iam_string"},
-					{Type: Semicolon},
+					{Type: Int, Val: "1"},
+					{Type: Plus, Val: "+"},
+					{Type: Int, Val: "2"},
+					{Type: Semicolon, Val: "\n"},
 					{Type: Eof},
 				},
-				sp: 0,
+				0,
 			},
-			"int",
-			"ddd2",
-			"iam_string",
-			nil,
-			func(scope *symbol.Scope) bool {
-				sym := scope.Get("ddd2")
+			expectedErr:  nil,
+			expectedStmt: "int a = (1 + 2)",
+			chkScopeFn: func(scope *symbol.Scope) bool {
+				sym := scope.Get("a")
 				if sym == nil {
 					return false
 				}
@@ -2301,7 +4759,7 @@ func TestParseAssignStatement(t *testing.T) {
 					return false
 				}
 
-				if sym.String() != "ddd2" {
+				if sym.String() != "a" {
 					return false
 				}
 
@@ -2309,121 +4767,124 @@ func TestParseAssignStatement(t *testing.T) {
 			},
 		},
 		{
-			// type mismatch tc - bool foo = "iam_string"
-			defaultSetupScopeFn,
-			&mockTokenBuffer{
-				buf: []Token{
-					{Type: BoolType, Val: "bool"},
-					{Type: Ident, Val: "foo"},
+			setupScopeFn: defaultSetupScopeFn,
+			buf: &mockTokenBuffer{
+				[]Token{
+					{Type: IntType, Val: "int"},
+					{Type: Ident, Val: "a"},
 					{Type: Assign, Val: "="},
-					{Type: String, Val: "iam_string"},
-					{Type: Semicolon},
+					{Type: Int, Val: "1"},
+					{Type: Plus, Val: "+"},
+					{Type: Int, Val: "2"},
+					{Type: Asterisk, Val: "*"},
+					{Type: Int, Val: "3"},
+					{Type: Semicolon, Val: "\n"},
 					{Type: Eof},
 				},
-				sp: 0,
+				0,
 			},
-			"bool",
-			"foo",
-			"iam_string",
-			nil,
-			func(scope *symbol.Scope) bool {
-				sym := scope.Get("foo")
+			expectedErr:  nil,
+			expectedStmt: "int a = (1 + (2 * 3))",
+			chkScopeFn: func(scope *symbol.Scope) bool {
+				sym := scope.Get("a")
+				if sym == nil {
+					return false
+				}
+
+				if sym.Type() != symbol.IntegerSymbol {
+					return false
+				}
+
+				if sym.String() != "a" {
+					return false
+				}
+
+				return true
+			},
+		},
+		{
+			setupScopeFn: defaultSetupScopeFn,
+			buf: &mockTokenBuffer{
+				[]Token{
+					{Type: IntType, Val: "int"},
+					{Type: Ident, Val: "a"},
+					{Type: Assign, Val: "="},
+					{Type: String, Val: "1"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Eof},
+				},
+				0,
+			},
+			expectedErr:  nil,
+			expectedStmt: `int a = "1"`,
+			chkScopeFn: func(scope *symbol.Scope) bool {
+				sym := scope.Get("a")
 				if sym == nil {
 					return false
 				}
 
-				if sym.Type() != symbol.BooleanSymbol {
+				if sym.Type() != symbol.IntegerSymbol {
 					return false
 				}
 
-				if sym.String() != "foo" {
+				if sym.String() != "a" {
 					return false
 				}
 
 				return true
 			},
 		},
+
+		// tests for StringType
 		{
-			defaultSetupScopeFn,
-			&mockTokenBuffer{
-				buf: []Token{
-					{Type: BoolType, Val: "bool"},
-					{Type: String, Val: "foo"},
+			setupScopeFn: defaultSetupScopeFn,
+			buf: &mockTokenBuffer{
+				[]Token{
+					{Type: StringType, Val: "string"},
+					{Type: Ident, Val: "abb"},
 					{Type: Assign, Val: "="},
-					{Type: String, Val: "iam_string"},
-					{Type: Semicolon},
+					{Type: String, Val: "do not merge, rebase!"},
+					{Type: Semicolon, Val: "\n"},
 					{Type: Eof},
 				},
-				sp: 0,
-			},
-			"bool",
-			"foo",
-			`"iam_string"`,
-			ExpectError{
-				Token{Type: String},
-				Ident,
+				0,
 			},
-			func(scope *symbol.Scope) bool {
-				sym := scope.Get("foo")
-				if sym != nil {
+			expectedErr:  nil,
+			expectedStmt: `string abb = "do not merge, rebase!"`,
+			chkScopeFn: func(scope *symbol.Scope) bool {
+				sym := scope.Get("abb")
+				if sym == nil {
 					return false
 				}
 
-				return true
-			},
-		},
-		{
-			defaultSetupScopeFn,
-			&mockTokenBuffer{
-				buf: []Token{
-					{Type: BoolType, Val: "bool"},
-					{Type: Ident, Val: "foo"},
-					{Type: String, Val: "iam_string"},
-					{Type: Semicolon},
-					{Type: Eof},
-				},
-				sp: 0,
-			},
-			"bool",
-			"foo",
-			"iam_string",
-			ExpectError{
-				Token{Type: String},
-				Assign,
-			},
-			func(scope *symbol.Scope) bool {
-				sym := scope.Get("foo")
-				if sym == nil {
+				if sym.Type() != symbol.StringSymbol {
+					return false
+				}
+
+				if sym.String() != "abb" {
 					return false
 				}
 
 				return true
 			},
 		},
-		// test when identifier already exist
 		{
-			func() *symbol.Scope {
-				scope := symbol.NewScope()
-				scope.Set("ddd", &symbol.String{Name: &ast.Identifier{Name: "ddd"}})
-				return scope
-			},
-			&mockTokenBuffer{
-				buf: []Token{
-					{Type: BoolType, Val: "bool"},
-					{Type: Ident, Val: "ddd"},
+			setupScopeFn: defaultSetupScopeFn,
+			buf: &mockTokenBuffer{
+				[]Token{
+					{Type: StringType, Val: "string"},
+					{Type: Ident, Val: "abb"},
 					{Type: Assign, Val: "="},
-					{Type: True, Val: "true"},
-					{Type: Semicolon},
+					{Type: String, Val: "hello,*+"},
+					{Type: Semicolon, Val: "\n"},
 					{Type: Eof},
 				},
-				sp: 0,
+				0,
 			},
-			"bool",
-			"ddd",
-			"true",
-			DupSymError{Token{Type: Ident, Val: "ddd"}},
-			func(scope *symbol.Scope) bool {
-				sym := scope.Get("ddd")
+			expectedErr:  nil,
+			expectedStmt: `string abb = "hello,*+"`,
+			chkScopeFn: func(scope *symbol.Scope) bool {
+				sym := scope.Get("abb")
 				if sym == nil {
 					return false
 				}
@@ -2432,361 +4893,256 @@ func TestParseAssignStatement(t *testing.T) {
 					return false
 				}
 
-				if sym.String() != "ddd" {
+				if sym.String() != "abb" {
 					return false
 				}
 
 				return true
 			},
 		},
-	}
-
-	for i, tt := range tests {
-		// setup
-		scope = tt.setupScopeFn()
-
-		// exercise
-		exp, err := parseAssignStatement(tt.tokenBuffer)
-
-		// verify
-		if err != nil && err.Error() != tt.expectedErr.Error() {
-			t.Errorf(`tests[%d] - Returned err is not "%s", but got "%s"`,
-				i, tt.expectedErr.Error(), err.Error())
-		}
-
-		if err == nil && exp.Type.String() != tt.expectedDataStructure {
-			t.Errorf("tests[%d] - Type is not %s but got %s",
-				i, tt.expectedDataStructure, exp.Type.String())
-		}
-
-		if err == nil && exp.Variable.String() != tt.expectedIdent {
-			t.Errorf("tests[%d] - Variable is not %s but got %s",
-				i, tt.expectedIdent, exp.Variable.String())
-		}
-
-		if err == nil && exp.Value.String() != tt.expectedVal {
-			t.Errorf("tests[%d] - Name is not %s but got %s",
-				i, tt.expectedVal, exp.Value.String())
-		}
-
-		if !tt.chkScopeFn(scope) {
-			t.Errorf("test[%d] - updateScopeSymbol updates scope incorrectly", i)
-		}
-	}
-}
-
-func TestParseReassignStatement(t *testing.T) {
-	initParseFnMap()
-	tests := []struct {
-		buf         TokenBuffer
-		setupScope  setupScopeFn
-		expected    string
-		expectedErr error
-	}{
-		{
-			buf: &mockTokenBuffer{
-				buf: []Token{
-					{
-						Type: Ident,
-						Val:  "a",
-					},
-					{
-						Type: Assign,
-						Val:  "=",
-					},
-					{
-						Type: Int,
-						Val:  "1",
-					},
-				},
-				sp: 0,
-			},
-			setupScope: func() *symbol.Scope {
-				scope := symbol.NewScope()
-				scope.Set("a", &symbol.Integer{Name: &ast.Identifier{Name: "a"}})
-				return scope
-			},
-			expected:    "a = 1",
-			expectedErr: nil,
-		},
 		{
+			setupScopeFn: defaultSetupScopeFn,
 			buf: &mockTokenBuffer{
-				buf: []Token{
-					{
-						Type: Ident,
-						Val:  "b",
-					},
-					{
-						Type: Assign,
-						Val:  "=",
-					},
-					{
-						Type: Int,
-						Val:  "1",
-					},
-				},
-				sp: 0,
-			},
-			setupScope: func() *symbol.Scope {
-				scope := symbol.NewScope()
-				scope.Set("a", &symbol.Integer{Name: &ast.Identifier{Name: "a"}})
-				return scope
-			},
-			expected: "",
-			expectedErr: NotExistSymError{Token{
-				Type: Ident,
-				Val:  "b",
-			}},
-		},
-	}
-
-	for i, test := range tests {
-		scope = test.setupScope()
-		stmt, err := parseReassignStatement(test.buf)
-		if err != nil && err.Error() != test.expectedErr.Error() {
-			t.Fatalf("test[%d] - parseReassignStatement() returns wrong error.\n"+
-				"Expected=%s\n"+
-				"got=%s", i, test.expectedErr.Error(), err.Error())
-		}
-
-		if stmt != nil && stmt.String() != test.expected {
-			t.Fatalf("test[%d] - parseReassignStatement() returns wrong result.\n"+
-				"Expected=%s\n"+
-				"got=%s", i, test.expected, stmt.String())
-		}
-	}
-}
-
-// TestParseExpression tests strings which combine prefix and
-// infix expression
-func TestParseExpression(t *testing.T) {
-	initParseFnMap()
-	tests := []struct {
-		buf         TokenBuffer
-		setupScope  setupScopeFn
-		expected    string
-		expectedErr error
-	}{
-		{
-			&mockTokenBuffer{
 				[]Token{
-					{Type: Minus, Val: "-"},
-					{Type: Ident, Val: "a"},
-					{Type: Asterisk, Val: "*"},
-					{Type: Ident, Val: "b"},
+					{Type: StringType, Val: "string"},
+					{Type: Ident, Val: "abb"},
+					{Type: Assign, Val: "="},
+					{Type: Int, Val: "1"},
+					{Type: Semicolon, Val: "\n"},
 					{Type: Eof},
 				},
 				0,
 			},
-			func() *symbol.Scope {
-				scope := symbol.NewScope()
-				scope.Set("a", &symbol.Integer{Name: &ast.Identifier{Name: "a"}})
-				scope.Set("b", &symbol.Integer{Name: &ast.Identifier{Name: "b"}})
-				return scope
+			expectedErr:  nil,
+			expectedStmt: `string abb = 1`,
+			chkScopeFn: func(scope *symbol.Scope) bool {
+				sym := scope.Get("abb")
+				if sym == nil {
+					return false
+				}
+
+				if sym.Type() != symbol.StringSymbol {
+					return false
+				}
+
+				if sym.String() != "abb" {
+					return false
+				}
+
+				return true
 			},
-			"((-a) * b)",
-			nil,
 		},
+
+		// tests for BoolType
 		{
-			&mockTokenBuffer{
+			setupScopeFn: defaultSetupScopeFn,
+			buf: &mockTokenBuffer{
 				[]Token{
-					{Type: Bang, Val: "!"},
-					{Type: Minus, Val: "-"},
-					{Type: Ident, Val: "b"},
+					{Type: BoolType, Val: "bool"},
+					{Type: Ident, Val: "asdf"},
+					{Type: Assign, Val: "="},
+					{Type: True, Val: "true"},
+					{Type: Semicolon, Val: "\n"},
 					{Type: Eof},
 				},
 				0,
 			},
-			func() *symbol.Scope {
-				scope := symbol.NewScope()
-				scope.Set("b", &symbol.Integer{Name: &ast.Identifier{Name: "b"}})
-				return scope
+			expectedErr:  nil,
+			expectedStmt: `bool asdf = true`,
+			chkScopeFn: func(scope *symbol.Scope) bool {
+				sym := scope.Get("asdf")
+				if sym == nil {
+					return false
+				}
+
+				if sym.Type() != symbol.BooleanSymbol {
+					return false
+				}
+
+				if sym.String() != "asdf" {
+					return false
+				}
+
+				return true
 			},
-			"(!(-b))",
-			nil,
 		},
 		{
-			&mockTokenBuffer{
+			setupScopeFn: defaultSetupScopeFn,
+			buf: &mockTokenBuffer{
 				[]Token{
-					{Type: Minus, Val: "-"},
-					{Type: Int, Val: "33"},
-					{Type: Slash, Val: "/"},
-					{Type: Int, Val: "67"},
-					{Type: Plus, Val: "+"},
-					{Type: Ident, Val: "a"},
+					{Type: BoolType, Val: "bool"},
+					{Type: Ident, Val: "asdf"},
+					{Type: Assign, Val: "="},
+					{Type: False, Val: "false"},
+					{Type: Semicolon, Val: "\n"},
 					{Type: Eof},
 				},
 				0,
 			},
-			func() *symbol.Scope {
-				scope := symbol.NewScope()
-				scope.Set("a", &symbol.Integer{Name: &ast.Identifier{Name: "a"}})
-				return scope
+			expectedErr:  nil,
+			expectedStmt: `bool asdf = false`,
+			chkScopeFn: func(scope *symbol.Scope) bool {
+				sym := scope.Get("asdf")
+				if sym == nil {
+					return false
+				}
+
+				if sym.Type() != symbol.BooleanSymbol {
+					return false
+				}
+
+				if sym.String() != "asdf" {
+					return false
+				}
+
+				return true
 			},
-			"(((-33) / 67) + a)",
-			nil,
 		},
 		{
-			&mockTokenBuffer{
+			setupScopeFn: defaultSetupScopeFn,
+			buf: &mockTokenBuffer{
 				[]Token{
-					{Type: Int, Val: "33"},
-					{Type: Mod, Val: "%"},
-					{Type: Minus, Val: "-"},
-					{Type: Int, Val: "67"},
-					{Type: Plus, Val: "+"},
-					{Type: Ident, Val: "a"},
-					{Type: Asterisk, Val: "*"},
-					{Type: Ident, Val: "c"},
+					{Type: BoolType, Val: "bool"},
+					{Type: Ident, Val: "asdf"},
+					{Type: Assign, Val: "="},
+					{Type: Int, Val: "1"},
+					{Type: Semicolon, Val: "\n"},
 					{Type: Eof},
 				},
 				0,
 			},
-			func() *symbol.Scope {
-				scope := symbol.NewScope()
-				scope.Set("a", &symbol.Integer{Name: &ast.Identifier{Name: "a"}})
-				scope.Set("c", &symbol.Integer{Name: &ast.Identifier{Name: "c"}})
-				return scope
+			expectedErr:  nil,
+			expectedStmt: `bool asdf = 1`,
+			chkScopeFn: func(scope *symbol.Scope) bool {
+				sym := scope.Get("asdf")
+				if sym == nil {
+					return false
+				}
+
+				if sym.Type() != symbol.BooleanSymbol {
+					return false
+				}
+
+				if sym.String() != "asdf" {
+					return false
+				}
+
+				return true
 			},
-			"((33 % (-67)) + (a * c))",
-			nil,
 		},
+
+		// tests for If statement
 		{
-			&mockTokenBuffer{
+			setupScopeFn: defaultSetupScopeFn,
+			buf: &mockTokenBuffer{
 				[]Token{
-					{Type: Int, Val: "33"},
-					{Type: Mod, Val: "%"},
+					{Type: If, Val: "if"},
 					{Type: Lparen, Val: "("},
-					{Type: Minus, Val: "-"},
-					{Type: Int, Val: "67"},
-					{Type: Plus, Val: "+"},
-					{Type: Ident, Val: "a"},
+					{Type: True, Val: "true"},
 					{Type: Rparen, Val: ")"},
-					{Type: Asterisk, Val: "*"},
-					{Type: Ident, Val: "c"},
-					{Type: Eof},
-				},
-				0,
-			},
-			func() *symbol.Scope {
-				scope := symbol.NewScope()
-				scope.Set("a", &symbol.Integer{Name: &ast.Identifier{Name: "a"}})
-				scope.Set("c", &symbol.Integer{Name: &ast.Identifier{Name: "c"}})
-				return scope
-			},
-			"((33 % ((-67) + a)) * c)",
-			nil},
-		{
-			&mockTokenBuffer{
-				[]Token{
-					{Type: Minus, Val: "-"},
-					{Type: Int, Val: "33"},
-					{Type: Slash, Val: "/"},
-					{Type: Int, Val: "67"},
-					{Type: LT, Val: "<"},
-					{Type: Ident, Val: "a"},
-					{Type: Asterisk, Val: "*"},
-					{Type: Int, Val: "67"},
+					{Type: Lbrace, Val: "{"},
+					{Type: Rbrace, Val: "}"},
+					{Type: Semicolon, Val: "\n"},
 					{Type: Eof},
 				},
 				0,
 			},
-			func() *symbol.Scope {
-				scope := symbol.NewScope()
-				scope.Set("a", &symbol.Integer{Name: &ast.Identifier{Name: "a"}})
-				return scope
-			},
-			"(((-33) / 67) < (a * 67))",
-			nil,
+			expectedErr:  nil,
+			expectedStmt: `if ( true ) {  }`,
+			chkScopeFn:   defaultChkScopeFn,
 		},
 		{
-			&mockTokenBuffer{
+			setupScopeFn: defaultSetupScopeFn,
+			buf: &mockTokenBuffer{
 				[]Token{
-					{Type: Minus, Val: "-"},
-					{Type: Int, Val: "33"},
-					{Type: Slash, Val: "/"},
-					{Type: Int, Val: "67"},
-					{Type: GTE, Val: ">="},
-					{Type: Ident, Val: "a"},
+					{Type: If, Val: "if"},
+					{Type: Lparen, Val: "("},
+					{Type: Int, Val: "1"},
 					{Type: Plus, Val: "+"},
-					{Type: Int, Val: "67"},
-					{Type: Mod, Val: "%"},
-					{Type: Ident, Val: "z"},
+					{Type: Int, Val: "2"},
+					{Type: EQ, Val: "=="},
+					{Type: Int, Val: "3"},
+					{Type: Rparen, Val: ")"},
+					{Type: Lbrace, Val: "{"},
+					{Type: Rbrace, Val: "}"},
+					{Type: Semicolon, Val: "\n"},
 					{Type: Eof},
 				},
 				0,
 			},
-			func() *symbol.Scope {
-				scope := symbol.NewScope()
-				scope.Set("a", &symbol.Integer{Name: &ast.Identifier{Name: "a"}})
-				scope.Set("z", &symbol.Integer{Name: &ast.Identifier{Name: "z"}})
-				return scope
-			},
-			"(((-33) / 67) >= (a + (67 % z)))",
-			nil},
+			expectedErr:  nil,
+			expectedStmt: `if ( ((1 + 2) == 3) ) {  }`,
+			chkScopeFn:   defaultChkScopeFn,
+		},
 		{
-			&mockTokenBuffer{
+			setupScopeFn: defaultSetupScopeFn,
+			buf: &mockTokenBuffer{
 				[]Token{
-					{Type: Int, Val: "33"},
+					{Type: If, Val: "if"},
+					{Type: Lparen, Val: "("},
+					{Type: True, Val: "true"},
+					{Type: Rparen, Val: ")"},
+					{Type: Lbrace, Val: "{"},
+					{Type: Int, Val: "1"},
+					{Type: Plus, Val: "+"},
+					{Type: Int, Val: "2"},
 					{Type: EQ, Val: "=="},
 					{Type: Int, Val: "3"},
-					{Type: Land, Val: "&&"},
-					{Type: Int, Val: "44"},
-					{Type: EQ, Val: "=="},
-					{Type: Int, Val: "67"},
+					{Type: Rbrace, Val: "}"},
+					{Type: Semicolon, Val: "\n"},
 					{Type: Eof},
 				},
 				0,
 			},
-			defaultSetupScopeFn,
-			"((33 == 3) && (44 == 67))",
-			nil,
+			expectedErr: ExpectError{
+				Token{Type: Int},
+				Ident,
+			},
+			expectedStmt: ``,
+			chkScopeFn:   defaultChkScopeFn,
 		},
 		{
-			&mockTokenBuffer{
+			setupScopeFn: defaultSetupScopeFn,
+			buf: &mockTokenBuffer{
 				[]Token{
+					{Type: If, Val: "if"},
+					{Type: Lparen, Val: "("},
 					{Type: True, Val: "true"},
-					{Type: Lor, Val: "||"},
-					{Type: False, Val: "false"},
-					{Type: Land, Val: "&&"},
-					{Type: True, Val: "true"},
+					{Type: Rparen, Val: ")"},
+					{Type: Lbrace, Val: "{"},
+					{Type: IntType, Val: "int"},
+					{Type: Ident, Val: "a"},
+					{Type: Assign, Val: "="},
+					{Type: Int, Val: "2"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Rbrace, Val: "}"},
+					{Type: Semicolon, Val: "\n"},
 					{Type: Eof},
-				},
-				0,
-			},
-			defaultSetupScopeFn,
-			"(true || (false && true))",
-			nil,
-		},
-	}
-
-	for i, test := range tests {
-		scope = test.setupScope()
-		exp, err := parseExpression(test.buf, LOWEST)
+				},
+				0,
+			},
+			expectedErr:  nil,
+			expectedStmt: `if ( true ) { int a = 2 }`,
+			chkScopeFn: func(scope *symbol.Scope) bool {
+				sym := scope.GetInner()[0].Get("a")
+				if sym == nil {
+					return false
+				}
 
-		if err != nil && err.Error() != test.expectedErr.Error() {
-			t.Fatalf("test[%d] - parseExpression() with wrong error. Expected=%s, got=%s",
-				i, test.expectedErr.Error(), err)
-		}
+				if sym.Type() != symbol.IntegerSymbol {
+					return false
+				}
 
-		if err == nil && exp.String() != test.expected {
-			t.Fatalf("test[%d] - parseExpression() with wrong expression. Expected=%s, got=%s",
-				i, test.expected, exp.String())
-		}
-	}
-}
+				if sym.String() != "a" {
+					return false
+				}
 
-func TestParseIfStatement(t *testing.T) {
-	initParseFnMap()
-	tests := []struct {
-		setupScopeFn
-		buf         TokenBuffer
-		expected    string
-		expectedErr error
-		chkScopeFn
-	}{
+				return true
+			},
+		},
 		{
-			defaultSetupScopeFn,
-			&mockTokenBuffer{
+			setupScopeFn: defaultSetupScopeFn,
+			buf: &mockTokenBuffer{
 				[]Token{
 					{Type: If, Val: "if"},
 					{Type: Lparen, Val: "("},
@@ -2796,17 +5152,20 @@ func TestParseIfStatement(t *testing.T) {
 					{Type: IntType, Val: "int"},
 					{Type: Ident, Val: "a"},
 					{Type: Assign, Val: "="},
-					{Type: Int, Val: "0"},
+					{Type: Int, Val: "2"},
 					{Type: Semicolon, Val: "\n"},
 					{Type: Rbrace, Val: "}"},
+					{Type: Else, Val: "else"},
+					{Type: Lbrace, Val: "{"},
+					{Type: Rbrace, Val: "}"},
 					{Type: Semicolon, Val: "\n"},
 					{Type: Eof},
 				},
 				0,
 			},
-			"if ( true ) { int a = 0 }",
-			nil,
-			func(scope *symbol.Scope) bool {
+			expectedErr:  nil,
+			expectedStmt: `if ( true ) { int a = 2 } else {  }`,
+			chkScopeFn: func(scope *symbol.Scope) bool {
 				sym := scope.GetInner()[0].Get("a")
 				if sym == nil {
 					return false
@@ -2824,24 +5183,18 @@ func TestParseIfStatement(t *testing.T) {
 			},
 		},
 		{
-			func() *symbol.Scope {
-				scope := symbol.NewScope()
-				scope.Set("a", &symbol.Integer{Name: &ast.Identifier{Name: "a"}})
-				return scope
-			},
-			&mockTokenBuffer{
+			setupScopeFn: defaultSetupScopeFn,
+			buf: &mockTokenBuffer{
 				[]Token{
 					{Type: If, Val: "if"},
 					{Type: Lparen, Val: "("},
-					{Type: Ident, Val: "a"},
-					{Type: EQ, Val: "=="},
-					{Type: Int, Val: "5"},
+					{Type: True, Val: "true"},
 					{Type: Rparen, Val: ")"},
 					{Type: Lbrace, Val: "{"},
 					{Type: IntType, Val: "int"},
-					{Type: Ident, Val: "b"},
+					{Type: Ident, Val: "a"},
 					{Type: Assign, Val: "="},
-					{Type: Int, Val: "1"},
+					{Type: Int, Val: "2"},
 					{Type: Semicolon, Val: "\n"},
 					{Type: Rbrace, Val: "}"},
 					{Type: Else, Val: "else"},
@@ -2849,7 +5202,7 @@ func TestParseIfStatement(t *testing.T) {
 					{Type: StringType, Val: "string"},
 					{Type: Ident, Val: "b"},
 					{Type: Assign, Val: "="},
-					{Type: String, Val: "example"},
+					{Type: String, Val: "hello"},
 					{Type: Semicolon, Val: "\n"},
 					{Type: Rbrace, Val: "}"},
 					{Type: Semicolon, Val: "\n"},
@@ -2857,9 +5210,9 @@ func TestParseIfStatement(t *testing.T) {
 				},
 				0,
 			},
-			`if ( (a == 5) ) { int b = 1 } else { string b = example }`,
-			nil,
-			func(scope *symbol.Scope) bool {
+			expectedErr:  nil,
+			expectedStmt: `if ( true ) { int a = 2 } else { string b = "hello" }`,
+			chkScopeFn: func(scope *symbol.Scope) bool {
 				sym := scope.GetInner()[0].Get("a")
 				if sym == nil {
 					return false
@@ -2889,413 +5242,387 @@ func TestParseIfStatement(t *testing.T) {
 				return true
 			},
 		},
+
+		// tests for Return statement
 		{
-			defaultSetupScopeFn,
-			&mockTokenBuffer{
+			setupScopeFn: func() *symbol.Scope {
+				scope := symbol.NewScope()
+				scope.Set("asdf", &symbol.Integer{Name: &ast.Identifier{Name: "asdf"}})
+				return scope
+			},
+			buf: &mockTokenBuffer{
 				[]Token{
-					{Type: IntType, Val: "int"},
-					{Type: Ident, Val: "a"},
-					{Type: Assign, Val: "="},
-					{Type: Int, Val: "5"},
-					{Type: Eof},
+					{Type: Return, Val: "return"},
+					{Type: Ident, Val: "asdf"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Eof, Val: "eof"},
 				},
 				0,
 			},
-			"",
-			ExpectError{
-				Token{Type: IntType},
-				If,
-			},
-			defaultChkScopeFn,
+			expectedErr:  nil,
+			expectedStmt: `return asdf`,
+			chkScopeFn:   defaultChkScopeFn,
 		},
 		{
-			defaultSetupScopeFn,
-			&mockTokenBuffer{
+			setupScopeFn: defaultSetupScopeFn,
+			buf: &mockTokenBuffer{
 				[]Token{
-					{Type: If, Val: "if"},
+					{Type: Return, Val: "return"},
 					{Type: Lparen, Val: "("},
-					{Type: True, Val: "true"},
+					{Type: Int, Val: "1"},
+					{Type: Plus, Val: "+"},
+					{Type: Int, Val: "2"},
+					{Type: Asterisk, Val: "*"},
+					{Type: Int, Val: "3"},
 					{Type: Rparen, Val: ")"},
-					{Type: IntType, Val: "int"},
-					{Type: Ident, Val: "a"},
-					{Type: Assign, Val: "="},
-					{Type: Int, Val: "0"},
-					{Type: Semicolon, Val: "\n"},
-					{Type: Rbrace, Val: "}"},
 					{Type: Semicolon, Val: "\n"},
-					{Type: Eof},
+					{Type: Eof, Val: "eof"},
 				},
 				0,
 			},
-			"",
-			ExpectError{
-				Token{Type: IntType},
-				Lbrace,
+			expectedErr:  nil,
+			expectedStmt: `return (1 + (2 * 3))`,
+			chkScopeFn:   defaultChkScopeFn,
+		},
+		// test call expression
+		{
+			setupScopeFn: func() *symbol.Scope {
+				scope := symbol.NewScope()
+				scope.Set("add", &symbol.Function{Name: "add"})
+				return scope
 			},
-			defaultChkScopeFn,
+			buf: &mockTokenBuffer{
+				[]Token{
+					{Type: Ident, Val: "add"},
+					{Type: Lparen, Val: "("},
+					{Type: Int, Val: "1"},
+					{Type: Comma, Val: ","},
+					{Type: Int, Val: "2"},
+					{Type: Rparen, Val: ")"},
+				},
+				0,
+			},
+			expectedErr:  nil,
+			expectedStmt: `function add( 1, 2 )`,
+			chkScopeFn:   defaultChkScopeFn,
 		},
 		{
-			defaultSetupScopeFn,
-			&mockTokenBuffer{
+			setupScopeFn: func() *symbol.Scope {
+				scope := symbol.NewScope()
+				scope.Set("add", &symbol.Function{Name: "add"})
+				scope.Set("foo", &symbol.Function{Name: "foo"})
+				return scope
+			},
+			buf: &mockTokenBuffer{
 				[]Token{
-					{Type: If, Val: "if"},
+					{Type: Ident, Val: "add"},
 					{Type: Lparen, Val: "("},
-					{Type: True, Val: "true"},
-					{Type: Rbrace, Val: "}"},
-					{Type: Lbrace, Val: "{"},
-					{Type: IntType, Val: "int"},
-					{Type: Ident, Val: "a"},
-					{Type: Assign, Val: "="},
-					{Type: Int, Val: "0"},
-					{Type: Semicolon, Val: "\n"},
-					{Type: Rbrace, Val: "}"},
+					{Type: Ident, Val: "foo"},
+					{Type: Lparen, Val: "("},
+					{Type: Rparen, Val: ")"},
+					{Type: Comma, Val: ","},
+					{Type: Int, Val: "2"},
+					{Type: Rparen, Val: ")"},
+				},
+				0,
+			},
+			expectedErr:  nil,
+			expectedStmt: `function add( function foo(  ), 2 )`,
+			chkScopeFn:   defaultChkScopeFn,
+		},
+		{
+			setupScopeFn: func() *symbol.Scope {
+				scope := symbol.NewScope()
+				scope.Set("add", &symbol.Function{Name: "add"})
+				return scope
+			},
+			buf: &mockTokenBuffer{
+				[]Token{
+					{Type: Return, Val: "return"},
+					{Type: Lparen, Val: "("},
+					{Type: Ident, Val: "add"},
+					{Type: Lparen, Val: "("},
+					{Type: Int, Val: "1"},
+					{Type: Comma, Val: ","},
+					{Type: Int, Val: "2"},
+					{Type: Rparen, Val: ")"},
+					{Type: Plus, Val: "+"},
+					{Type: Int, Val: "2"},
+					{Type: Asterisk, Val: "*"},
+					{Type: Int, Val: "3"},
+					{Type: Rparen, Val: ")"},
 					{Type: Semicolon, Val: "\n"},
-					{Type: Eof},
+					{Type: Eof, Val: "eof"},
 				},
 				0,
 			},
-			"",
-			ExpectError{
-				Token{Type: Rbrace},
-				Rparen,
+			expectedErr:  nil,
+			expectedStmt: `return (function add( 1, 2 ) + (2 * 3))`,
+			chkScopeFn:   defaultChkScopeFn,
+		},
+
+		// tests for Default
+		{
+			setupScopeFn: defaultSetupScopeFn,
+			buf: &mockTokenBuffer{
+				[]Token{
+					{Type: Int, Val: "1"},
+					{Type: Eof, Val: "eof"},
+				},
+				0,
 			},
-			defaultChkScopeFn,
+			expectedErr: ExpectError{
+				Token{Type: Int},
+				Ident,
+			},
+			expectedStmt: ``,
+			chkScopeFn:   defaultChkScopeFn,
 		},
 	}
 
 	for i, test := range tests {
 		// setup
-		scope = test.setupScopeFn()
+		scope := test.setupScopeFn()
 
 		// exercise
-		stmt, err := parseIfStatement(test.buf)
+		stmt, err := newTestParser(test.buf, scope).parseStatement()
 
 		// verify
 		if err != nil && err.Error() != test.expectedErr.Error() {
-			t.Fatalf("test[%d] - TestParseIfStatement() wrong error. Expected=%s got=%s",
-				i, test.expectedErr.Error(), err.Error())
+			t.Errorf(`test[%d] - parseStatement wrong error. Expected="%v", got="%v"`,
+				i, test.expectedErr, err)
+			continue
 		}
 
-		if stmt != nil && stmt.String() != test.expected {
-			t.Fatalf("test[%d] - TestParseIfStatement() wrong result. Expected=%s, got=%s",
-				i, test.expected, stmt.String())
+		if err == nil && stmt.String() != test.expectedStmt {
+			t.Errorf(`test[%d] - parseStatement wrong result. Expected="%s", got="%s"`,
+				i, test.expectedStmt, stmt.String())
 		}
 
 		if !test.chkScopeFn(scope) {
-			t.Fatalf("test[%d] - updateScopeSymbol updates scope incorrectly", i)
+			t.Errorf("test[%d] - updateScopeSymbol updates scope incorrectly", i)
 		}
 	}
 }
 
-func TestParseBlockStatement(t *testing.T) {
-	initParseFnMap()
+func TestParseExpressionStatement(t *testing.T) {
 	tests := []struct {
-		setupScopeFn
-		buf         TokenBuffer
-		expected    string
-		expectedErr error
-		chkScopeFn
+		buf          TokenBuffer
+		setupScope   setupScopeFn
+		expectedStmt string
+		expectedErr  error
 	}{
 		{
-			defaultSetupScopeFn,
+			// add()
 			&mockTokenBuffer{
 				[]Token{
-					{Type: Lbrace, Val: "{"},
-					{Type: IntType, Val: "int"},
-					{Type: Ident, Val: "a"},
-					{Type: Assign, Val: "="},
-					{Type: Int, Val: "0"},
-					{Type: Semicolon, Val: "\n"},
-					{Type: Rbrace, Val: "}"},
+					{Type: Ident, Val: "add"},
+					{Type: Lparen, Val: "("},
+					{Type: Rparen, Val: ")"},
 				},
 				0,
 			},
-			"int a = 0",
+			func() *symbol.Scope {
+				scope := symbol.NewScope()
+				scope.Set("add", &symbol.Function{Name: "add"})
+				return scope
+			},
+			"function add(  )",
 			nil,
-			func(scope *symbol.Scope) bool {
-				sym := scope.GetInner()[0].Get("a")
-				if sym == nil {
-					return false
-				}
-
-				if sym.Type() != symbol.IntegerSymbol {
-					return false
-				}
-
-				if sym.String() != "a" {
-					return false
-				}
-
-				return true
+		},
+		{
+			// read(x int)
+			&mockTokenBuffer{
+				[]Token{
+					{Type: Ident, Val: "read"},
+					{Type: Lparen, Val: "("},
+					{Type: Ident, Val: "x"},
+					{Type: Rparen, Val: ")"},
+				},
+				0,
+			},
+			func() *symbol.Scope {
+				scope := symbol.NewScope()
+				scope.Set("read", &symbol.Function{Name: "read"})
+				scope.Set("x", &symbol.Integer{Name: &ast.Identifier{Name: "x"}})
+				return scope
 			},
+			"function read( x )",
+			nil,
 		},
 		{
-			defaultSetupScopeFn,
+			// testFunction(a int, b string)
 			&mockTokenBuffer{
 				[]Token{
-					{Type: Lbrace, Val: "{"},
-					{Type: IntType, Val: "int"},
+					{Type: Ident, Val: "testFunction"},
+					{Type: Lparen, Val: "("},
 					{Type: Ident, Val: "a"},
-					{Type: Assign, Val: "="},
-					{Type: Int, Val: "0"},
-					{Type: Semicolon, Val: "\n"},
-					{Type: StringType, Val: "string"},
+					{Type: Comma, Val: ","},
 					{Type: Ident, Val: "b"},
-					{Type: Assign, Val: "="},
-					{Type: String, Val: "abc"},
-					{Type: Semicolon, Val: "\n"},
-					{Type: Rbrace, Val: "}"},
+					{Type: Rparen, Val: ")"},
 				},
 				0,
 			},
-			`int a = 0
-string b = abc`,
-			nil,
-			func(scope *symbol.Scope) bool {
-				sym := scope.GetInner()[0].Get("a")
-				if sym == nil {
-					return false
-				}
-
-				if sym.Type() != symbol.IntegerSymbol {
-					return false
-				}
-
-				if sym.String() != "a" {
-					return false
-				}
-
-				sym = scope.GetInner()[0].Get("b")
-				if sym == nil {
-					return false
-				}
-
-				if sym.Type() != symbol.StringSymbol {
-					return false
-				}
-
-				if sym.String() != "b" {
-					return false
-				}
-
-				return true
+			func() *symbol.Scope {
+				scope := symbol.NewScope()
+				scope.Set("testFunction", &symbol.Function{Name: "testFunction"})
+				scope.Set("a", &symbol.Integer{Name: &ast.Identifier{Name: "a"}})
+				scope.Set("b", &symbol.Integer{Name: &ast.Identifier{Name: "b"}})
+				return scope
 			},
+			"function testFunction( a, b )",
+			nil,
 		},
 		{
-			defaultSetupScopeFn,
+			// testFunction(a int b string) <= error case
 			&mockTokenBuffer{
 				[]Token{
-					{Type: Lbrace, Val: "{"},
-					{Type: IntType, Val: "int"},
+					{Type: Ident, Val: "testFunction"},
+					{Type: Lparen, Val: "("},
 					{Type: Ident, Val: "a"},
-					{Type: Assign, Val: "="},
-					{Type: Int, Val: "0"},
-					{Type: Semicolon, Val: "\n"},
-					{Type: StringType, Val: "string"},
+					{Type: IntType, Val: "int"},
 					{Type: Ident, Val: "b"},
-					{Type: Assign, Val: "="},
-					{Type: String, Val: "abc"},
-					{Type: Semicolon, Val: "\n"},
-					{Type: BoolType, Val: "bool"},
-					{Type: Ident, Val: "c"},
-					{Type: Assign, Val: "="},
-					{Type: True, Val: "true"},
-					{Type: Semicolon, Val: "\n"},
-					{Type: Rbrace, Val: "}"},
+					{Type: IntType, Val: "string"},
+					{Type: Rparen, Val: ")"},
 				},
 				0,
 			},
-			`int a = 0
-string b = abc
-bool c = true`,
-			nil,
-			func(scope *symbol.Scope) bool {
-				sym := scope.GetInner()[0].Get("a")
-				if sym == nil {
-					return false
-				}
-
-				if sym.Type() != symbol.IntegerSymbol {
-					return false
-				}
-
-				if sym.String() != "a" {
-					return false
-				}
-
-				sym = scope.GetInner()[0].Get("b")
-				if sym == nil {
-					return false
-				}
-
-				if sym.Type() != symbol.StringSymbol {
-					return false
-				}
-
-				if sym.String() != "b" {
-					return false
-				}
-
-				sym = scope.GetInner()[0].Get("c")
-				if sym == nil {
-					return false
-				}
-
-				if sym.Type() != symbol.BooleanSymbol {
-					return false
-				}
-
-				if sym.String() != "c" {
-					return false
-				}
-
-				return true
+			func() *symbol.Scope {
+				scope := symbol.NewScope()
+				scope.Set("testFunction", &symbol.Function{Name: "testFunction"})
+				scope.Set("a", &symbol.Integer{Name: &ast.Identifier{Name: "a"}})
+				scope.Set("b", &symbol.Integer{Name: &ast.Identifier{Name: "b"}})
+				return scope
+			},
+			"",
+			ExpectError{
+				Token{IntType, "int", 0, 0, 0},
+				Rparen,
 			},
 		},
 		{
-			defaultSetupScopeFn,
+			// 1() <= error case
 			&mockTokenBuffer{
 				[]Token{
-					{Type: Lbrace, Val: "{"},
-					{Type: IntType, Val: "int"},
-					{Type: Ident, Val: "a"},
-					{Type: Assign, Val: "="},
-					{Type: Int, Val: "0"},
-					{Type: Semicolon, Val: "\n"},
-					{Type: Ident, Val: "a"},
-					{Type: Assign, Val: "="},
 					{Type: Int, Val: "1"},
-					{Type: Semicolon, Val: "\n"},
-					{Type: Rbrace, Val: "}"},
+					{Type: Lparen, Val: "("},
+					{Type: Rparen, Val: ")"},
 				},
 				0,
 			},
-			"int a = 0\na = 1",
-			nil,
-			defaultChkScopeFn,
+			defaultSetupScopeFn,
+			"",
+			ExpectError{
+				Token{Type: Int},
+				Ident,
+			},
+		},
+		{
+			// add) <= error case
+			&mockTokenBuffer{
+				[]Token{
+					{Type: Ident, Val: "add"},
+					{Type: Rparen, Val: ")"},
+				},
+				0,
+			},
+			func() *symbol.Scope {
+				scope := symbol.NewScope()
+				scope.Set("add", &symbol.Function{Name: "add"})
+				return scope
+			},
+			"",
+			ExpectError{
+				Token{Rparen, "}", 0, 0, 0},
+				Lparen,
+			},
 		},
 	}
 
 	for i, test := range tests {
-		// setup
-		scope = test.setupScopeFn()
-
-		// exercise
-		exp, err := parseBlockStatement(test.buf)
+		scope := test.setupScope()
+		stmt, err := newTestParser(test.buf, scope).parseExpressionStatement()
+		if stmt != nil && stmt.String() != test.expectedStmt {
+			t.Fatalf("test[%d] - TestParseFunctionStatement wrong answer.\n"+
+				"Expected= %s\n"+
+				"got= %s", i, test.expectedStmt, stmt.String())
+		}
 
-		// verify
 		if err != nil && err.Error() != test.expectedErr.Error() {
-			t.Fatalf("test[%d] - TestParseBlockStatement() wrong error. Expected=%s, got=%s",
-				i, test.expectedErr.Error(), err.Error())
+			t.Fatalf("test[%d] - TestParseFunctionStatement wrong error.\n"+
+				"Expected= %s\n"+
+				"got= %s", i, test.expectedErr.Error(), err.Error())
 		}
+	}
+}
+
+func TestEnterLeaveScope(t *testing.T) {
+	scope := symbol.NewScope()
+	scope.Set("foo", &symbol.String{Name: &ast.Identifier{Name: "foo"}})
+
+	p := newTestParser(nil, scope)
+	p.enterScope()
+
+	p.scope.Set("bar", &symbol.String{Name: &ast.Identifier{Name: "bar"}})
+
+	if p.scope.Get("foo") == nil {
+		t.Errorf("scope should have foo symbol, because we're in the inner scope")
+	}
+
+	p.leaveScope()
+
+	// test whether inner exist
+	inner := scope.GetInner()
+	if len(inner) != 1 {
+		t.Errorf("scope should have 1 inner scope, but have %d", len(inner))
+	}
 
-		if exp != nil && exp.String() != test.expected {
-			t.Fatalf("test[%d] - TestParseBlockStatement() wrong result. Expected=%s, got=%s",
-				i, test.expected, exp.String())
-		}
+	if inner[0].Get("bar") == nil {
+		t.Errorf("scope should have bar symbol, because we're in the inner scope")
+	}
 
-		if !test.chkScopeFn(scope) {
-			t.Fatalf("test[%d] - updateScopeSymbol updates scope incorrectly", i)
-		}
+	scope.Set("baz", &symbol.String{Name: &ast.Identifier{Name: "baz"}})
+
+	if scope.Get("bar") != nil {
+		t.Errorf("scope should NOT have \"bar\" symbol, because we're in the outer scope")
+	}
+	if scope.Get("foo") == nil {
+		t.Errorf("scope should have \"foo\" symbol, because we're in the outer scope")
+	}
+	if scope.Get("baz") == nil {
+		t.Errorf("scope should have \"baz\" symbol, because we're in the outer scope")
 	}
 }
 
-func TestParseStatement(t *testing.T) {
-	initParseFnMap()
+func TestUpdateScopeSymbol(t *testing.T) {
 	tests := []struct {
 		setupScopeFn
-		buf          TokenBuffer
-		expectedErr  error
-		expectedStmt string
-		chkScopeFn
+		ident       Token
+		keyword     Token
+		expectedErr error
+		chkScope    func(scope *symbol.Scope) bool
 	}{
-		// tests for IntType
-		{
-			setupScopeFn: defaultSetupScopeFn,
-			buf: &mockTokenBuffer{
-				[]Token{
-					{Type: IntType, Val: "int"},
-					{Type: Ident, Val: "a"},
-					{Type: Assign, Val: "="},
-					{Type: Int, Val: "1"},
-					{Type: Semicolon, Val: "\n"},
-					{Type: Eof},
-				},
-				0,
-			},
-			expectedErr:  nil,
-			expectedStmt: "int a = 1",
-			chkScopeFn: func(scope *symbol.Scope) bool {
-				sym := scope.Get("a")
-				if sym == nil {
-					return false
-				}
-
-				if sym.Type() != symbol.IntegerSymbol {
-					return false
-				}
-
-				if sym.String() != "a" {
-					return false
-				}
-
-				return true
-			},
-		},
+		// test when scope already have identifier symbol
 		{
-			setupScopeFn: defaultSetupScopeFn,
-			buf: &mockTokenBuffer{
-				[]Token{
-					{Type: IntType, Val: "int"},
-					{Type: Ident, Val: "a"},
-					{Type: Assign, Val: "="},
-					{Type: Int, Val: "1"},
-					{Type: Plus, Val: "+"},
-					{Type: Int, Val: "2"},
-					{Type: Semicolon, Val: "\n"},
-					{Type: Eof},
-				},
-				0,
+			setupScopeFn: func() *symbol.Scope {
+				scope := symbol.NewScope()
+				scope.Set("a", &symbol.Integer{Name: &ast.Identifier{Name: "a"}})
+				return scope
 			},
-			expectedErr:  nil,
-			expectedStmt: "int a = (1 + 2)",
-			chkScopeFn: func(scope *symbol.Scope) bool {
-				sym := scope.Get("a")
-				if sym == nil {
-					return false
-				}
-
-				if sym.Type() != symbol.IntegerSymbol {
-					return false
-				}
-
-				if sym.String() != "a" {
-					return false
-				}
-
+			ident:       Token{Type: Ident, Val: "a"},
+			keyword:     Token{Type: StringType, Val: "string"},
+			expectedErr: DupSymError{Token{Type: Ident, Val: "a"}},
+			chkScope: func(scope *symbol.Scope) bool {
 				return true
 			},
 		},
+		// test when keyword token is IntType, BoolType, StringType, Function, Or Not one of those
 		{
 			setupScopeFn: defaultSetupScopeFn,
-			buf: &mockTokenBuffer{
-				[]Token{
-					{Type: IntType, Val: "int"},
-					{Type: Ident, Val: "a"},
-					{Type: Assign, Val: "="},
-					{Type: Int, Val: "1"},
-					{Type: Plus, Val: "+"},
-					{Type: Int, Val: "2"},
-					{Type: Asterisk, Val: "*"},
-					{Type: Int, Val: "3"},
-					{Type: Semicolon, Val: "\n"},
-					{Type: Eof},
-				},
-				0,
-			},
+			ident:        Token{Type: Ident, Val: "a"},
+			keyword:      Token{Type: IntType, Val: "int"},
 			expectedErr:  nil,
-			expectedStmt: "int a = (1 + (2 * 3))",
-			chkScopeFn: func(scope *symbol.Scope) bool {
+			chkScope: func(scope *symbol.Scope) bool {
 				sym := scope.Get("a")
 				if sym == nil {
 					return false
@@ -3314,55 +5641,33 @@ func TestParseStatement(t *testing.T) {
 		},
 		{
 			setupScopeFn: defaultSetupScopeFn,
-			buf: &mockTokenBuffer{
-				[]Token{
-					{Type: IntType, Val: "int"},
-					{Type: Ident, Val: "a"},
-					{Type: Assign, Val: "="},
-					{Type: String, Val: "1"},
-					{Type: Semicolon, Val: "\n"},
-					{Type: Eof},
-				},
-				0,
-			},
+			ident:        Token{Type: Ident, Val: "b"},
+			keyword:      Token{Type: BoolType, Val: "bool"},
 			expectedErr:  nil,
-			expectedStmt: `int a = 1`,
-			chkScopeFn: func(scope *symbol.Scope) bool {
-				sym := scope.Get("a")
+			chkScope: func(scope *symbol.Scope) bool {
+				sym := scope.Get("b")
 				if sym == nil {
 					return false
 				}
 
-				if sym.Type() != symbol.IntegerSymbol {
+				if sym.Type() != symbol.BooleanSymbol {
 					return false
 				}
 
-				if sym.String() != "a" {
+				if sym.String() != "b" {
 					return false
 				}
 
 				return true
 			},
 		},
-
-		// tests for StringType
 		{
 			setupScopeFn: defaultSetupScopeFn,
-			buf: &mockTokenBuffer{
-				[]Token{
-					{Type: StringType, Val: "string"},
-					{Type: Ident, Val: "abb"},
-					{Type: Assign, Val: "="},
-					{Type: String, Val: "do not merge, rebase!"},
-					{Type: Semicolon, Val: "\n"},
-					{Type: Eof},
-				},
-				0,
-			},
+			ident:        Token{Type: Ident, Val: "c"},
+			keyword:      Token{Type: StringType, Val: "string"},
 			expectedErr:  nil,
-			expectedStmt: `string abb = do not merge, rebase!`,
-			chkScopeFn: func(scope *symbol.Scope) bool {
-				sym := scope.Get("abb")
+			chkScope: func(scope *symbol.Scope) bool {
+				sym := scope.Get("c")
 				if sym == nil {
 					return false
 				}
@@ -3371,7 +5676,7 @@ func TestParseStatement(t *testing.T) {
 					return false
 				}
 
-				if sym.String() != "abb" {
+				if sym.String() != "c" {
 					return false
 				}
 
@@ -3380,30 +5685,20 @@ func TestParseStatement(t *testing.T) {
 		},
 		{
 			setupScopeFn: defaultSetupScopeFn,
-			buf: &mockTokenBuffer{
-				[]Token{
-					{Type: StringType, Val: "string"},
-					{Type: Ident, Val: "abb"},
-					{Type: Assign, Val: "="},
-					{Type: String, Val: "hello,*+"},
-					{Type: Semicolon, Val: "\n"},
-					{Type: Eof},
-				},
-				0,
-			},
+			ident:        Token{Type: Ident, Val: "d"},
+			keyword:      Token{Type: Function, Val: "func"},
 			expectedErr:  nil,
-			expectedStmt: `string abb = hello,*+`,
-			chkScopeFn: func(scope *symbol.Scope) bool {
-				sym := scope.Get("abb")
+			chkScope: func(scope *symbol.Scope) bool {
+				sym := scope.Get("d")
 				if sym == nil {
 					return false
 				}
 
-				if sym.Type() != symbol.StringSymbol {
+				if sym.Type() != symbol.FunctionSymbol {
 					return false
 				}
 
-				if sym.String() != "abb" {
+				if sym.String() != "d" {
 					return false
 				}
 
@@ -3412,307 +5707,270 @@ func TestParseStatement(t *testing.T) {
 		},
 		{
 			setupScopeFn: defaultSetupScopeFn,
-			buf: &mockTokenBuffer{
-				[]Token{
-					{Type: StringType, Val: "string"},
-					{Type: Ident, Val: "abb"},
-					{Type: Assign, Val: "="},
-					{Type: Int, Val: "1"},
-					{Type: Semicolon, Val: "\n"},
-					{Type: Eof},
-				},
-				0,
+			ident:        Token{Type: Ident, Val: "e"},
+			keyword:      Token{Type: Illegal, Val: "illegal"},
+			expectedErr: Error{
+				Token{Type: Illegal, Val: "illegal"},
+				fmt.Sprintf("unexpected symbol type [%v]", TokenTypeMap[Illegal]),
 			},
-			expectedErr:  nil,
-			expectedStmt: `string abb = 1`,
-			chkScopeFn: func(scope *symbol.Scope) bool {
-				sym := scope.Get("abb")
-				if sym == nil {
-					return false
-				}
-
-				if sym.Type() != symbol.StringSymbol {
-					return false
-				}
-
-				if sym.String() != "abb" {
-					return false
-				}
-
+			chkScope: func(scope *symbol.Scope) bool {
 				return true
 			},
 		},
+	}
 
-		// tests for BoolType
-		{
-			setupScopeFn: defaultSetupScopeFn,
-			buf: &mockTokenBuffer{
-				[]Token{
-					{Type: BoolType, Val: "bool"},
-					{Type: Ident, Val: "asdf"},
-					{Type: Assign, Val: "="},
-					{Type: True, Val: "true"},
-					{Type: Semicolon, Val: "\n"},
-					{Type: Eof},
-				},
-				0,
-			},
-			expectedErr:  nil,
-			expectedStmt: `bool asdf = true`,
-			chkScopeFn: func(scope *symbol.Scope) bool {
-				sym := scope.Get("asdf")
-				if sym == nil {
-					return false
-				}
+	for i, tt := range tests {
+		// setup
+		scope := tt.setupScopeFn()
 
-				if sym.Type() != symbol.BooleanSymbol {
-					return false
-				}
+		// exercise
+		err := newTestParser(nil, scope).updateScopeSymbol(tt.ident, tt.keyword)
+
+		// verify
+		if err != nil && err.Error() != tt.expectedErr.Error() {
+			t.Errorf("test[%d] - updateScopeSymbol returns unexpected error, Expected=\"%s\", got=\"%s\"",
+				i, err.Error(), tt.expectedErr.Error())
+		}
+		// verify
+		if ok := tt.chkScope(scope); !ok {
+			t.Errorf("test[%d] - updateScopeSymbol updates scope incorrectly", i)
+		}
+	}
+}
 
-				if sym.String() != "asdf" {
-					return false
-				}
+func TestParseImportList(t *testing.T) {
+	strLibrary := &ast.Library{Name: "str"}
+	importer := mockImporter{
+		"lib/math.koa":   mathLibrary,
+		"lib/str.koa":    strLibrary,
+		"other/math.koa": &ast.Library{Name: "math"},
+	}
 
-				return true
-			},
-		},
+	tests := []struct {
+		buf         TokenBuffer
+		importer    Importer
+		expected    []*ast.Import
+		expectedErr error
+	}{
 		{
-			setupScopeFn: defaultSetupScopeFn,
-			buf: &mockTokenBuffer{
+			&mockTokenBuffer{
 				[]Token{
-					{Type: BoolType, Val: "bool"},
-					{Type: Ident, Val: "asdf"},
-					{Type: Assign, Val: "="},
-					{Type: False, Val: "false"},
+					{Type: Import, Val: "import"},
+					{Type: String, Val: `"lib/math.koa"`},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Import, Val: "import"},
+					{Type: String, Val: `"lib/str.koa"`},
 					{Type: Semicolon, Val: "\n"},
+					{Type: Contract, Val: "contract"},
 					{Type: Eof},
 				},
 				0,
 			},
-			expectedErr:  nil,
-			expectedStmt: `bool asdf = false`,
-			chkScopeFn: func(scope *symbol.Scope) bool {
-				sym := scope.Get("asdf")
-				if sym == nil {
-					return false
-				}
-
-				if sym.Type() != symbol.BooleanSymbol {
-					return false
-				}
-
-				if sym.String() != "asdf" {
-					return false
-				}
-
-				return true
+			importer,
+			[]*ast.Import{
+				{Path: "lib/math.koa", Library: mathLibrary},
+				{Path: "lib/str.koa", Library: strLibrary},
 			},
+			nil,
 		},
 		{
-			setupScopeFn: defaultSetupScopeFn,
-			buf: &mockTokenBuffer{
+			&mockTokenBuffer{
 				[]Token{
-					{Type: BoolType, Val: "bool"},
-					{Type: Ident, Val: "asdf"},
-					{Type: Assign, Val: "="},
-					{Type: Int, Val: "1"},
-					{Type: Semicolon, Val: "\n"},
+					{Type: Contract, Val: "contract"},
 					{Type: Eof},
 				},
 				0,
 			},
-			expectedErr:  nil,
-			expectedStmt: `bool asdf = 1`,
-			chkScopeFn: func(scope *symbol.Scope) bool {
-				sym := scope.Get("asdf")
-				if sym == nil {
-					return false
-				}
-
-				if sym.Type() != symbol.BooleanSymbol {
-					return false
-				}
-
-				if sym.String() != "asdf" {
-					return false
-				}
-
-				return true
-			},
+			nil,
+			[]*ast.Import{},
+			nil,
 		},
-
-		// tests for If statement
 		{
-			setupScopeFn: defaultSetupScopeFn,
-			buf: &mockTokenBuffer{
+			&mockTokenBuffer{
 				[]Token{
-					{Type: If, Val: "if"},
-					{Type: Lparen, Val: "("},
-					{Type: True, Val: "true"},
-					{Type: Rparen, Val: ")"},
-					{Type: Lbrace, Val: "{"},
-					{Type: Rbrace, Val: "}"},
+					{Type: Import, Val: "import"},
+					{Type: String, Val: `"lib/math.koa"`},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Import, Val: "import"},
+					{Type: String, Val: `"other/math.koa"`},
 					{Type: Semicolon, Val: "\n"},
 					{Type: Eof},
 				},
 				0,
 			},
-			expectedErr:  nil,
-			expectedStmt: `if ( true ) {  }`,
-			chkScopeFn:   defaultChkScopeFn,
+			importer,
+			nil,
+			Error{
+				Token{Type: String, Val: `"other/math.koa"`},
+				"library math is already imported",
+			},
 		},
 		{
-			setupScopeFn: defaultSetupScopeFn,
-			buf: &mockTokenBuffer{
+			&mockTokenBuffer{
 				[]Token{
-					{Type: If, Val: "if"},
-					{Type: Lparen, Val: "("},
-					{Type: Int, Val: "1"},
-					{Type: Plus, Val: "+"},
-					{Type: Int, Val: "2"},
-					{Type: EQ, Val: "=="},
-					{Type: Int, Val: "3"},
-					{Type: Rparen, Val: ")"},
-					{Type: Lbrace, Val: "{"},
-					{Type: Rbrace, Val: "}"},
+					{Type: Import, Val: "import"},
+					{Type: String, Val: `"lib/none.koa"`},
 					{Type: Semicolon, Val: "\n"},
 					{Type: Eof},
 				},
 				0,
 			},
-			expectedErr:  nil,
-			expectedStmt: `if ( ((1 + 2) == 3) ) {  }`,
-			chkScopeFn:   defaultChkScopeFn,
+			importer,
+			nil,
+			Error{
+				Token{Type: String, Val: `"lib/none.koa"`},
+				"lib/none.koa is not found",
+			},
 		},
 		{
-			setupScopeFn: defaultSetupScopeFn,
-			buf: &mockTokenBuffer{
+			&mockTokenBuffer{
 				[]Token{
-					{Type: If, Val: "if"},
-					{Type: Lparen, Val: "("},
-					{Type: True, Val: "true"},
-					{Type: Rparen, Val: ")"},
-					{Type: Lbrace, Val: "{"},
-					{Type: Int, Val: "1"},
-					{Type: Plus, Val: "+"},
-					{Type: Int, Val: "2"},
-					{Type: EQ, Val: "=="},
-					{Type: Int, Val: "3"},
-					{Type: Rbrace, Val: "}"},
+					{Type: Import, Val: "import"},
+					{Type: String, Val: `"lib/math.koa"`},
 					{Type: Semicolon, Val: "\n"},
 					{Type: Eof},
 				},
 				0,
 			},
-			expectedErr: ExpectError{
-				Token{Type: Int},
-				Ident,
+			nil,
+			nil,
+			Error{
+				Token{Type: String, Val: `"lib/math.koa"`},
+				"can't import lib/math.koa without importer",
 			},
-			expectedStmt: ``,
-			chkScopeFn:   defaultChkScopeFn,
 		},
 		{
-			setupScopeFn: defaultSetupScopeFn,
-			buf: &mockTokenBuffer{
+			&mockTokenBuffer{
 				[]Token{
-					{Type: If, Val: "if"},
-					{Type: Lparen, Val: "("},
-					{Type: True, Val: "true"},
-					{Type: Rparen, Val: ")"},
-					{Type: Lbrace, Val: "{"},
-					{Type: IntType, Val: "int"},
-					{Type: Ident, Val: "a"},
-					{Type: Assign, Val: "="},
-					{Type: Int, Val: "2"},
-					{Type: Semicolon, Val: "\n"},
-					{Type: Rbrace, Val: "}"},
+					{Type: Import, Val: "import"},
+					{Type: Ident, Val: "math"},
 					{Type: Semicolon, Val: "\n"},
 					{Type: Eof},
 				},
 				0,
 			},
-			expectedErr:  nil,
-			expectedStmt: `if ( true ) { int a = 2 }`,
-			chkScopeFn: func(scope *symbol.Scope) bool {
-				sym := scope.GetInner()[0].Get("a")
-				if sym == nil {
-					return false
-				}
+			importer,
+			nil,
+			ExpectError{
+				Token{Type: Ident, Val: "math"},
+				String,
+			},
+		},
+	}
 
-				if sym.Type() != symbol.IntegerSymbol {
-					return false
-				}
+	for i, test := range tests {
+		imports, err := NewParser(test.buf, test.importer).parseImportList()
 
-				if sym.String() != "a" {
-					return false
-				}
+		if err != nil && (test.expectedErr == nil || err.Error() != test.expectedErr.Error()) {
+			t.Fatalf("test[%d] - parseImportList() with wrong error. Expected=%v, got=%v",
+				i, test.expectedErr, err)
+		}
 
-				return true
-			},
-		},
+		if err == nil && test.expectedErr != nil {
+			t.Fatalf("test[%d] - parseImportList() should return error. Expected=%v",
+				i, test.expectedErr)
+		}
+
+		if len(imports) != len(test.expected) {
+			t.Fatalf("test[%d] - parseImportList() with wrong length. Expected=%d, got=%d",
+				i, len(test.expected), len(imports))
+		}
+
+		for j, imp := range imports {
+			if imp.Path != test.expected[j].Path || imp.Library != test.expected[j].Library {
+				t.Fatalf("test[%d] - parseImportList()[%d] with wrong import. Expected=%s, got=%s",
+					i, j, test.expected[j].String(), imp.String())
+			}
+		}
+	}
+}
+
+func TestParseLibrary(t *testing.T) {
+	tests := []struct {
+		buf         TokenBuffer
+		expected    string
+		expectedErr error
+	}{
 		{
-			setupScopeFn: defaultSetupScopeFn,
-			buf: &mockTokenBuffer{
+			&mockTokenBuffer{
 				[]Token{
-					{Type: If, Val: "if"},
+					{Type: Function, Val: "func"},
+					{Type: Ident, Val: "max"},
 					{Type: Lparen, Val: "("},
-					{Type: True, Val: "true"},
+					{Type: Ident, Val: "a"},
+					{Type: IntType, Val: "int"},
+					{Type: Comma, Val: ","},
+					{Type: Ident, Val: "b"},
+					{Type: IntType, Val: "int"},
 					{Type: Rparen, Val: ")"},
-					{Type: Lbrace, Val: "{"},
 					{Type: IntType, Val: "int"},
+					{Type: Lbrace, Val: "{"},
+					{Type: Return, Val: "return"},
 					{Type: Ident, Val: "a"},
-					{Type: Assign, Val: "="},
-					{Type: Int, Val: "2"},
 					{Type: Semicolon, Val: "\n"},
 					{Type: Rbrace, Val: "}"},
-					{Type: Else, Val: "else"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Function, Val: "func"},
+					{Type: Ident, Val: "twice"},
+					{Type: Lparen, Val: "("},
+					{Type: Ident, Val: "x"},
+					{Type: IntType, Val: "int"},
+					{Type: Rparen, Val: ")"},
+					{Type: IntType, Val: "int"},
 					{Type: Lbrace, Val: "{"},
+					{Type: Return, Val: "return"},
+					{Type: Ident, Val: "max"},
+					{Type: Lparen, Val: "("},
+					{Type: Ident, Val: "x"},
+					{Type: Comma, Val: ","},
+					{Type: Ident, Val: "x"},
+					{Type: Rparen, Val: ")"},
+					{Type: Semicolon, Val: "\n"},
 					{Type: Rbrace, Val: "}"},
 					{Type: Semicolon, Val: "\n"},
 					{Type: Eof},
 				},
 				0,
 			},
-			expectedErr:  nil,
-			expectedStmt: `if ( true ) { int a = 2 } else {  }`,
-			chkScopeFn: func(scope *symbol.Scope) bool {
-				sym := scope.GetInner()[0].Get("a")
-				if sym == nil {
-					return false
-				}
-
-				if sym.Type() != symbol.IntegerSymbol {
-					return false
-				}
-
-				if sym.String() != "a" {
-					return false
-				}
+			`
+func max(Parameter : (Identifier: a, Type: int), Parameter : (Identifier: b, Type: int)) int {
+return a
+}
 
-				return true
-			},
+func twice(Parameter : (Identifier: x, Type: int)) int {
+return function util.max( x, x )
+}
+`,
+			nil,
 		},
 		{
-			setupScopeFn: defaultSetupScopeFn,
-			buf: &mockTokenBuffer{
+			&mockTokenBuffer{
 				[]Token{
-					{Type: If, Val: "if"},
+					{Type: Function, Val: "func"},
+					{Type: Ident, Val: "one"},
 					{Type: Lparen, Val: "("},
-					{Type: True, Val: "true"},
 					{Type: Rparen, Val: ")"},
-					{Type: Lbrace, Val: "{"},
 					{Type: IntType, Val: "int"},
-					{Type: Ident, Val: "a"},
-					{Type: Assign, Val: "="},
-					{Type: Int, Val: "2"},
+					{Type: Lbrace, Val: "{"},
+					{Type: Return, Val: "return"},
+					{Type: Ident, Val: "one"},
+					{Type: Lparen, Val: "("},
+					{Type: Int, Val: "1"},
+					{Type: Rparen, Val: ")"},
 					{Type: Semicolon, Val: "\n"},
 					{Type: Rbrace, Val: "}"},
-					{Type: Else, Val: "else"},
-					{Type: Lbrace, Val: "{"},
-					{Type: StringType, Val: "string"},
-					{Type: Ident, Val: "b"},
-					{Type: Assign, Val: "="},
-					{Type: String, Val: "hello"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Function, Val: "func"},
+					{Type: Ident, Val: "two"},
+					{Type: Lparen, Val: "("},
+					{Type: Rparen, Val: ")"},
+					{Type: IntType, Val: "int"},
+					{Type: Lbrace, Val: "{"},
+					{Type: Return, Val: "return"},
+					{Type: Ident, Val: "one"},
+					{Type: Lparen, Val: "("},
+					{Type: Int, Val: "1"},
+					{Type: Rparen, Val: ")"},
 					{Type: Semicolon, Val: "\n"},
 					{Type: Rbrace, Val: "}"},
 					{Type: Semicolon, Val: "\n"},
@@ -3720,532 +5978,439 @@ func TestParseStatement(t *testing.T) {
 				},
 				0,
 			},
-			expectedErr:  nil,
-			expectedStmt: `if ( true ) { int a = 2 } else { string b = hello }`,
-			chkScopeFn: func(scope *symbol.Scope) bool {
-				sym := scope.GetInner()[0].Get("a")
-				if sym == nil {
-					return false
-				}
-
-				if sym.Type() != symbol.IntegerSymbol {
-					return false
-				}
-
-				if sym.String() != "a" {
-					return false
-				}
-
-				sym = scope.GetInner()[1].Get("b")
-				if sym == nil {
-					return false
-				}
-
-				if sym.Type() != symbol.StringSymbol {
-					return false
-				}
-
-				if sym.String() != "b" {
-					return false
-				}
-
-				return true
+			"",
+			Error{
+				Token{Type: Lparen, Val: "("},
+				"function util.one needs 0 arguments, but got 1",
 			},
 		},
-
-		// tests for Return statement
 		{
-			setupScopeFn: func() *symbol.Scope {
-				scope := symbol.NewScope()
-				scope.Set("asdf", &symbol.Integer{Name: &ast.Identifier{Name: "asdf"}})
-				return scope
-			},
-			buf: &mockTokenBuffer{
+			&mockTokenBuffer{
 				[]Token{
-					{Type: Return, Val: "return"},
-					{Type: Ident, Val: "asdf"},
-					{Type: Semicolon, Val: "\n"},
-					{Type: Eof, Val: "eof"},
+					{Type: Contract, Val: "contract"},
+					{Type: Lbrace, Val: "{"},
+					{Type: Rbrace, Val: "}"},
+					{Type: Eof},
 				},
 				0,
 			},
-			expectedErr:  nil,
-			expectedStmt: `return asdf`,
-			chkScopeFn:   defaultChkScopeFn,
+			"",
+			ExpectError{
+				Token{Type: Contract, Val: "contract"},
+				Function,
+			},
 		},
+	}
+
+	for i, test := range tests {
+		library, err := ParseLibrary(test.buf, "util", nil)
+
+		if err != nil && (test.expectedErr == nil || err.Error() != test.expectedErr.Error()) {
+			t.Fatalf("test[%d] - ParseLibrary() with wrong error. Expected=%v, got=%v",
+				i, test.expectedErr, err)
+		}
+
+		if err == nil && test.expectedErr != nil {
+			t.Fatalf("test[%d] - ParseLibrary() should return error. Expected=%v",
+				i, test.expectedErr)
+		}
+
+		if err != nil {
+			continue
+		}
+
+		if library.Name != "util" {
+			t.Fatalf("test[%d] - ParseLibrary() with wrong name. Expected=util, got=%s", i, library.Name)
+		}
+
+		if library.String() != test.expected {
+			t.Fatalf("test[%d] - ParseLibrary() with wrong result. Expected=%s, got=%s",
+				i, test.expected, library.String())
+		}
+	}
+}
+
+func TestParseQualifiedCallExpression(t *testing.T) {
+	tests := []struct {
+		buf          TokenBuffer
+		expected     string
+		expectedType ast.DataStructure
+		expectedErr  error
+	}{
 		{
-			setupScopeFn: defaultSetupScopeFn,
-			buf: &mockTokenBuffer{
+			&mockTokenBuffer{
 				[]Token{
-					{Type: Return, Val: "return"},
+					{Type: Ident, Val: "math"},
+					{Type: Dot, Val: "."},
+					{Type: Ident, Val: "max"},
 					{Type: Lparen, Val: "("},
 					{Type: Int, Val: "1"},
-					{Type: Plus, Val: "+"},
+					{Type: Comma, Val: ","},
 					{Type: Int, Val: "2"},
-					{Type: Asterisk, Val: "*"},
-					{Type: Int, Val: "3"},
 					{Type: Rparen, Val: ")"},
 					{Type: Semicolon, Val: "\n"},
-					{Type: Eof, Val: "eof"},
+					{Type: Eof},
 				},
 				0,
 			},
-			expectedErr:  nil,
-			expectedStmt: `return (1 + (2 * 3))`,
-			chkScopeFn:   defaultChkScopeFn,
+			"function math.max( 1, 2 )",
+			ast.IntType,
+			nil,
 		},
-		// test call expression
 		{
-			setupScopeFn: func() *symbol.Scope {
-				scope := symbol.NewScope()
-				scope.Set("add", &symbol.Function{Name: "add"})
-				return scope
-			},
-			buf: &mockTokenBuffer{
+			&mockTokenBuffer{
 				[]Token{
-					{Type: Ident, Val: "add"},
+					{Type: Ident, Val: "math"},
+					{Type: Dot, Val: "."},
+					{Type: Ident, Val: "min"},
 					{Type: Lparen, Val: "("},
-					{Type: Int, Val: "1"},
-					{Type: Comma, Val: ","},
-					{Type: Int, Val: "2"},
 					{Type: Rparen, Val: ")"},
+					{Type: Eof},
 				},
 				0,
 			},
-			expectedErr:  nil,
-			expectedStmt: `function add( 1, 2 )`,
-			chkScopeFn:   defaultChkScopeFn,
+			"",
+			0,
+			Error{
+				Token{Type: Ident, Val: "min"},
+				"library math has no function min",
+			},
 		},
 		{
-			setupScopeFn: func() *symbol.Scope {
-				scope := symbol.NewScope()
-				scope.Set("add", &symbol.Function{Name: "add"})
-				scope.Set("foo", &symbol.Function{Name: "foo"})
-				return scope
-			},
-			buf: &mockTokenBuffer{
+			&mockTokenBuffer{
 				[]Token{
-					{Type: Ident, Val: "add"},
-					{Type: Lparen, Val: "("},
-					{Type: Ident, Val: "foo"},
-					{Type: Lparen, Val: "("},
-					{Type: Rparen, Val: ")"},
-					{Type: Comma, Val: ","},
-					{Type: Int, Val: "2"},
-					{Type: Rparen, Val: ")"},
+					{Type: Ident, Val: "math"},
+					{Type: Dot, Val: "."},
+					{Type: Ident, Val: "max"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Eof},
 				},
 				0,
 			},
-			expectedErr:  nil,
-			expectedStmt: `function add( function foo(  ), 2 )`,
-			chkScopeFn:   defaultChkScopeFn,
+			"",
+			0,
+			ExpectError{
+				Token{Type: Semicolon, Val: "\n"},
+				Lparen,
+			},
 		},
 		{
-			setupScopeFn: func() *symbol.Scope {
-				scope := symbol.NewScope()
-				scope.Set("add", &symbol.Function{Name: "add"})
-				return scope
-			},
-			buf: &mockTokenBuffer{
+			&mockTokenBuffer{
 				[]Token{
-					{Type: Return, Val: "return"},
-					{Type: Lparen, Val: "("},
-					{Type: Ident, Val: "add"},
+					{Type: Ident, Val: "math"},
+					{Type: Dot, Val: "."},
+					{Type: Ident, Val: "max"},
 					{Type: Lparen, Val: "("},
 					{Type: Int, Val: "1"},
-					{Type: Comma, Val: ","},
-					{Type: Int, Val: "2"},
-					{Type: Rparen, Val: ")"},
-					{Type: Plus, Val: "+"},
-					{Type: Int, Val: "2"},
-					{Type: Asterisk, Val: "*"},
-					{Type: Int, Val: "3"},
 					{Type: Rparen, Val: ")"},
-					{Type: Semicolon, Val: "\n"},
-					{Type: Eof, Val: "eof"},
+					{Type: Eof},
 				},
 				0,
 			},
-			expectedErr:  nil,
-			expectedStmt: `return (function add( 1, 2 ) + (2 * 3))`,
-			chkScopeFn:   defaultChkScopeFn,
+			"",
+			0,
+			Error{
+				Token{Type: Lparen, Val: "("},
+				"function math.max needs 2 arguments, but got 1",
+			},
 		},
-
-		// tests for Default
 		{
-			setupScopeFn: defaultSetupScopeFn,
-			buf: &mockTokenBuffer{
+			&mockTokenBuffer{
 				[]Token{
+					{Type: Ident, Val: "math"},
+					{Type: Dot, Val: "."},
+					{Type: Ident, Val: "max"},
+					{Type: Lparen, Val: "("},
 					{Type: Int, Val: "1"},
-					{Type: Eof, Val: "eof"},
+					{Type: Comma, Val: ","},
+					{Type: True, Val: "true"},
+					{Type: Rparen, Val: ")"},
+					{Type: Eof},
 				},
 				0,
 			},
-			expectedErr: ExpectError{
-				Token{Type: Int},
-				Ident,
+			"",
+			0,
+			Error{
+				Token{Type: Lparen, Val: "("},
+				"argument b of function math.max must be [int], but got [bool]",
 			},
-			expectedStmt: ``,
-			chkScopeFn:   defaultChkScopeFn,
 		},
 	}
 
 	for i, test := range tests {
-		// setup
-		scope = test.setupScopeFn()
-
-		// exercise
-		stmt, err := parseStatement(test.buf)
+		p := newTestParser(test.buf, setupLibraryScopeFn())
+		exp, err := p.parseExpression(LOWEST)
 
-		// verify
-		if err != nil && err.Error() != test.expectedErr.Error() {
-			t.Errorf(`test[%d] - parseStatement wrong error. Expected="%v", got="%v"`,
+		if err != nil && (test.expectedErr == nil || err.Error() != test.expectedErr.Error()) {
+			t.Fatalf("test[%d] - parseExpression() with wrong error. Expected=%v, got=%v",
 				i, test.expectedErr, err)
+		}
+
+		if err == nil && test.expectedErr != nil {
+			t.Fatalf("test[%d] - parseExpression() should return error. Expected=%v",
+				i, test.expectedErr)
+		}
+
+		if err != nil {
 			continue
 		}
 
-		if err == nil && stmt.String() != test.expectedStmt {
-			t.Errorf(`test[%d] - parseStatement wrong result. Expected="%s", got="%s"`,
-				i, test.expectedStmt, stmt.String())
+		if exp.String() != test.expected {
+			t.Fatalf("test[%d] - parseExpression() with wrong result. Expected=%s, got=%s",
+				i, test.expected, exp.String())
 		}
 
-		if !test.chkScopeFn(scope) {
-			t.Errorf("test[%d] - updateScopeSymbol updates scope incorrectly", i)
+		if p.expressionType(exp) != test.expectedType {
+			t.Fatalf("test[%d] - p.expressionType() with wrong type. Expected=%s, got=%s",
+				i, test.expectedType.String(), p.expressionType(exp).String())
 		}
 	}
 }
 
-func TestParseExpressionStatement(t *testing.T) {
-	initParseFnMap()
-
+func TestParseContractCallExpression(t *testing.T) {
 	tests := []struct {
 		buf          TokenBuffer
-		setupScope   setupScopeFn
-		expectedStmt string
+		expected     string
+		expectedType ast.DataStructure
 		expectedErr  error
 	}{
 		{
-			// add()
 			&mockTokenBuffer{
 				[]Token{
-					{Type: Ident, Val: "add"},
+					{Type: Call, Val: "call"},
 					{Type: Lparen, Val: "("},
+					{Type: Int, Val: "1"},
+					{Type: Comma, Val: ","},
+					{Type: String, Val: "\"release(int, int)\""},
+					{Type: Comma, Val: ","},
+					{Type: Int, Val: "2"},
+					{Type: Comma, Val: ","},
+					{Type: Ident, Val: "s"},
 					{Type: Rparen, Val: ")"},
+					{Type: Semicolon, Val: "\n"},
+					{Type: Eof},
 				},
 				0,
 			},
-			func() *symbol.Scope {
-				scope := symbol.NewScope()
-				scope.Set("add", &symbol.Function{Name: "add"})
-				return scope
+			"call(1, \"release(int,int)\", 2, s)",
+			ast.IntType,
+			nil,
+		},
+		{
+			&mockTokenBuffer{
+				[]Token{
+					{Type: Call, Val: "call"},
+					{Type: Lparen, Val: "("},
+					{Type: Int, Val: "1"},
+					{Type: Comma, Val: ","},
+					{Type: String, Val: "\"opened() bool\""},
+					{Type: Rparen, Val: ")"},
+					{Type: Eof},
+				},
+				0,
 			},
-			"function add(  )",
+			"call(1, \"opened() bool\")",
+			ast.BoolType,
 			nil,
 		},
 		{
-			// read(x int)
 			&mockTokenBuffer{
 				[]Token{
-					{Type: Ident, Val: "read"},
+					{Type: Call, Val: "call"},
 					{Type: Lparen, Val: "("},
-					{Type: Ident, Val: "x"},
+					{Type: Int, Val: "1"},
 					{Type: Rparen, Val: ")"},
+					{Type: Eof},
 				},
 				0,
 			},
-			func() *symbol.Scope {
-				scope := symbol.NewScope()
-				scope.Set("read", &symbol.Function{Name: "read"})
-				scope.Set("x", &symbol.Integer{Name: &ast.Identifier{Name: "x"}})
-				return scope
+			"",
+			0,
+			Error{
+				Token{Type: Call, Val: "call"},
+				"call needs address and method",
+			},
+		},
+		{
+			&mockTokenBuffer{
+				[]Token{
+					{Type: Call, Val: "call"},
+					{Type: Lparen, Val: "("},
+					{Type: True, Val: "true"},
+					{Type: Comma, Val: ","},
+					{Type: String, Val: "\"opened()\""},
+					{Type: Rparen, Val: ")"},
+					{Type: Eof},
+				},
+				0,
+			},
+			"",
+			0,
+			Error{
+				Token{Type: Call, Val: "call"},
+				"address of call must be [int], but got [bool]",
 			},
-			"function read( x )",
-			nil,
 		},
 		{
-			// testFunction(a int, b string)
 			&mockTokenBuffer{
 				[]Token{
-					{Type: Ident, Val: "testFunction"},
+					{Type: Call, Val: "call"},
 					{Type: Lparen, Val: "("},
-					{Type: Ident, Val: "a"},
+					{Type: Int, Val: "1"},
 					{Type: Comma, Val: ","},
-					{Type: Ident, Val: "b"},
+					{Type: Int, Val: "2"},
 					{Type: Rparen, Val: ")"},
+					{Type: Eof},
 				},
 				0,
 			},
-			func() *symbol.Scope {
-				scope := symbol.NewScope()
-				scope.Set("testFunction", &symbol.Function{Name: "testFunction"})
-				scope.Set("a", &symbol.Integer{Name: &ast.Identifier{Name: "a"}})
-				scope.Set("b", &symbol.Integer{Name: &ast.Identifier{Name: "b"}})
-				return scope
+			"",
+			0,
+			Error{
+				Token{Type: Call, Val: "call"},
+				"method of call must be a string literal",
 			},
-			"function testFunction( a, b )",
-			nil,
 		},
 		{
-			// testFunction(a int b string) <= error case
 			&mockTokenBuffer{
 				[]Token{
-					{Type: Ident, Val: "testFunction"},
+					{Type: Call, Val: "call"},
 					{Type: Lparen, Val: "("},
-					{Type: Ident, Val: "a"},
-					{Type: IntType, Val: "int"},
-					{Type: Ident, Val: "b"},
-					{Type: IntType, Val: "string"},
+					{Type: Int, Val: "1"},
+					{Type: Comma, Val: ","},
+					{Type: String, Val: "\"release(int)\""},
 					{Type: Rparen, Val: ")"},
+					{Type: Eof},
 				},
 				0,
 			},
-			func() *symbol.Scope {
-				scope := symbol.NewScope()
-				scope.Set("testFunction", &symbol.Function{Name: "testFunction"})
-				scope.Set("a", &symbol.Integer{Name: &ast.Identifier{Name: "a"}})
-				scope.Set("b", &symbol.Integer{Name: &ast.Identifier{Name: "b"}})
-				return scope
-			},
 			"",
-			ExpectError{
-				Token{IntType, "int", 0, 0},
-				Rparen,
+			0,
+			Error{
+				Token{Type: Call, Val: "call"},
+				"method release(int) needs 1 arguments, but got 0",
 			},
 		},
 		{
-			// 1() <= error case
 			&mockTokenBuffer{
 				[]Token{
-					{Type: Int, Val: "1"},
+					{Type: Call, Val: "call"},
 					{Type: Lparen, Val: "("},
+					{Type: Int, Val: "1"},
+					{Type: Comma, Val: ","},
+					{Type: String, Val: "\"release(int,string)\""},
+					{Type: Comma, Val: ","},
+					{Type: Int, Val: "2"},
+					{Type: Comma, Val: ","},
+					{Type: Int, Val: "3"},
 					{Type: Rparen, Val: ")"},
+					{Type: Eof},
 				},
 				0,
 			},
-			defaultSetupScopeFn,
 			"",
-			ExpectError{
-				Token{Type: Int},
-				Ident,
+			0,
+			Error{
+				Token{Type: Call, Val: "call"},
+				"argument 2 of method release(int,string) must be [string], but got [int]",
 			},
 		},
 		{
-			// add) <= error case
 			&mockTokenBuffer{
 				[]Token{
-					{Type: Ident, Val: "add"},
+					{Type: Call, Val: "call"},
+					{Type: Lparen, Val: "("},
+					{Type: Int, Val: "1"},
+					{Type: Comma, Val: ","},
+					{Type: String, Val: "\"release\""},
 					{Type: Rparen, Val: ")"},
+					{Type: Eof},
 				},
 				0,
 			},
-			func() *symbol.Scope {
-				scope := symbol.NewScope()
-				scope.Set("add", &symbol.Function{Name: "add"})
-				return scope
-			},
 			"",
-			ExpectError{
-				Token{Rparen, "}", 0, 0},
-				Lparen,
+			0,
+			Error{
+				Token{Type: Call, Val: "call"},
+				"invalid method \"release\", it should be like \"name(int,string) bool\"",
 			},
 		},
 	}
 
 	for i, test := range tests {
-		scope = test.setupScope()
-		stmt, err := parseExpressionStatement(test.buf)
-		if stmt != nil && stmt.String() != test.expectedStmt {
-			t.Fatalf("test[%d] - TestParseFunctionStatement wrong answer.\n"+
-				"Expected= %s\n"+
-				"got= %s", i, test.expectedStmt, stmt.String())
-		}
+		p := newTestParser(test.buf, setupEnumScopeFn())
+		exp, err := p.parseExpression(LOWEST)
 
-		if err != nil && err.Error() != test.expectedErr.Error() {
-			t.Fatalf("test[%d] - TestParseFunctionStatement wrong error.\n"+
-				"Expected= %s\n"+
-				"got= %s", i, test.expectedErr.Error(), err.Error())
+		if err != nil && (test.expectedErr == nil || err.Error() != test.expectedErr.Error()) {
+			t.Fatalf("test[%d] - parseExpression() with wrong error. Expected=%v, got=%v",
+				i, test.expectedErr, err)
 		}
-	}
-}
-
-func TestEnterLeaveScope(t *testing.T) {
-	// scope is global variable which defined in parser.go
-	scope = symbol.NewScope()
-	scope.Set("foo", &symbol.String{Name: &ast.Identifier{Name: "foo"}})
-
-	enterScope()
-
-	scope.Set("bar", &symbol.String{Name: &ast.Identifier{Name: "bar"}})
-
-	if scope.Get("foo") == nil {
-		t.Errorf("scope should have foo symbol, because we're in the inner scope")
-	}
-
-	leaveScope()
 
-	// test whether inner exist
-	inner := scope.GetInner()
-	if len(inner) != 1 {
-		t.Errorf("scope should have 1 inner scope, but have %d", len(inner))
-	}
+		if err == nil && test.expectedErr != nil {
+			t.Fatalf("test[%d] - parseExpression() should return error. Expected=%v",
+				i, test.expectedErr)
+		}
 
-	if inner[0].Get("bar") == nil {
-		t.Errorf("scope should have bar symbol, because we're in the inner scope")
-	}
+		if err != nil {
+			continue
+		}
 
-	scope.Set("baz", &symbol.String{Name: &ast.Identifier{Name: "baz"}})
+		if exp.String() != test.expected {
+			t.Fatalf("test[%d] - parseExpression() with wrong result. Expected=%s, got=%s",
+				i, test.expected, exp.String())
+		}
 
-	if scope.Get("bar") != nil {
-		t.Errorf("scope should NOT have \"bar\" symbol, because we're in the outer scope")
-	}
-	if scope.Get("foo") == nil {
-		t.Errorf("scope should have \"foo\" symbol, because we're in the outer scope")
-	}
-	if scope.Get("baz") == nil {
-		t.Errorf("scope should have \"baz\" symbol, because we're in the outer scope")
+		if p.expressionType(exp) != test.expectedType {
+			t.Fatalf("test[%d] - p.expressionType() with wrong type. Expected=%s, got=%s",
+				i, test.expectedType.String(), p.expressionType(exp).String())
+		}
 	}
 }
 
-func TestUpdateScopeSymbol(t *testing.T) {
+func TestParseMethodSignature(t *testing.T) {
 	tests := []struct {
-		setupScopeFn
-		ident       Token
-		keyword     Token
-		expectedErr error
-		chkScope    func(scope *symbol.Scope) bool
+		method             string
+		expectedSignature  string
+		expectedParams     []ast.DataStructure
+		expectedReturnType ast.DataStructure
+		expectErr          bool
 	}{
-		// test when scope already have identifier symbol
-		{
-			setupScopeFn: func() *symbol.Scope {
-				scope := symbol.NewScope()
-				scope.Set("a", &symbol.Integer{Name: &ast.Identifier{Name: "a"}})
-				return scope
-			},
-			ident:       Token{Type: Ident, Val: "a"},
-			keyword:     Token{Type: StringType, Val: "string"},
-			expectedErr: DupSymError{Token{Type: Ident, Val: "a"}},
-			chkScope: func(scope *symbol.Scope) bool {
-				return true
-			},
-		},
-		// test when keyword token is IntType, BoolType, StringType, Function, Or Not one of those
-		{
-			setupScopeFn: defaultSetupScopeFn,
-			ident:        Token{Type: Ident, Val: "a"},
-			keyword:      Token{Type: IntType, Val: "int"},
-			expectedErr:  nil,
-			chkScope: func(scope *symbol.Scope) bool {
-				sym := scope.Get("a")
-				if sym == nil {
-					return false
-				}
-
-				if sym.Type() != symbol.IntegerSymbol {
-					return false
-				}
-
-				if sym.String() != "a" {
-					return false
-				}
-
-				return true
-			},
-		},
-		{
-			setupScopeFn: defaultSetupScopeFn,
-			ident:        Token{Type: Ident, Val: "b"},
-			keyword:      Token{Type: BoolType, Val: "bool"},
-			expectedErr:  nil,
-			chkScope: func(scope *symbol.Scope) bool {
-				sym := scope.Get("b")
-				if sym == nil {
-					return false
-				}
-
-				if sym.Type() != symbol.BooleanSymbol {
-					return false
-				}
-
-				if sym.String() != "b" {
-					return false
-				}
-
-				return true
-			},
-		},
-		{
-			setupScopeFn: defaultSetupScopeFn,
-			ident:        Token{Type: Ident, Val: "c"},
-			keyword:      Token{Type: StringType, Val: "string"},
-			expectedErr:  nil,
-			chkScope: func(scope *symbol.Scope) bool {
-				sym := scope.Get("c")
-				if sym == nil {
-					return false
-				}
-
-				if sym.Type() != symbol.StringSymbol {
-					return false
-				}
-
-				if sym.String() != "c" {
-					return false
-				}
-
-				return true
-			},
-		},
-		{
-			setupScopeFn: defaultSetupScopeFn,
-			ident:        Token{Type: Ident, Val: "d"},
-			keyword:      Token{Type: Function, Val: "func"},
-			expectedErr:  nil,
-			chkScope: func(scope *symbol.Scope) bool {
-				sym := scope.Get("d")
-				if sym == nil {
-					return false
-				}
-
-				if sym.Type() != symbol.FunctionSymbol {
-					return false
-				}
-
-				if sym.String() != "d" {
-					return false
-				}
-
-				return true
-			},
-		},
-		{
-			setupScopeFn: defaultSetupScopeFn,
-			ident:        Token{Type: Ident, Val: "e"},
-			keyword:      Token{Type: Illegal, Val: "illegal"},
-			expectedErr: Error{
-				Token{Type: Illegal, Val: "illegal"},
-				fmt.Sprintf("unexpected symbol type [%v]", TokenTypeMap[Illegal]),
-			},
-			chkScope: func(scope *symbol.Scope) bool {
-				return true
-			},
-		},
+		{"release()", "release()", []ast.DataStructure{}, ast.IntType, false},
+		{" transfer( int , string ) bool ", "transfer(int,string)", []ast.DataStructure{ast.IntType, ast.StringType}, ast.BoolType, false},
+		{"name(bool) string", "name(bool)", []ast.DataStructure{ast.BoolType}, ast.StringType, false},
+		{"release", "", nil, 0, true},
+		{"(int)", "", nil, 0, true},
+		{"if(int)", "", nil, 0, true},
+		{"release)int(", "", nil, 0, true},
+		{"release(int,)", "", nil, 0, true},
+		{"release(uint)", "", nil, 0, true},
+		{"release(int) void", "", nil, 0, true},
 	}
 
-	for i, tt := range tests {
-		// setup
-		scope = tt.setupScopeFn()
+	for i, test := range tests {
+		signature, params, returnType, err := parseMethodSignature(test.method)
+		if test.expectErr {
+			if err == nil {
+				t.Fatalf("test[%d] - parseMethodSignature() should return error", i)
+			}
+			continue
+		}
 
-		// exercise
-		err := updateScopeSymbol(tt.ident, tt.keyword)
+		if err != nil {
+			t.Fatalf("test[%d] - parseMethodSignature() returns unexpected error %v", i, err)
+		}
 
-		// verify
-		if err != nil && err.Error() != tt.expectedErr.Error() {
-			t.Errorf("test[%d] - updateScopeSymbol returns unexpected error, Expected=\"%s\", got=\"%s\"",
-				i, err.Error(), tt.expectedErr.Error())
+		if signature != test.expectedSignature {
+			t.Errorf("test[%d] - wrong signature. Expected=%s, got=%s", i, test.expectedSignature, signature)
 		}
-		// verify
-		if ok := tt.chkScope(scope); !ok {
-			t.Errorf("test[%d] - updateScopeSymbol updates scope incorrectly", i)
+
+		if !reflect.DeepEqual(params, test.expectedParams) {
+			t.Errorf("test[%d] - wrong parameters. Expected=%v, got=%v", i, test.expectedParams, params)
+		}
+
+		if returnType != test.expectedReturnType {
+			t.Errorf("test[%d] - wrong return type. Expected=%s, got=%s", i, test.expectedReturnType, returnType)
 		}
 	}
 }
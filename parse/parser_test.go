@@ -18,11 +18,14 @@ package parse_test
 
 import (
 	"bytes"
+	"strings"
+	"sync"
 	"testing"
 	"text/template"
 
 	"github.com/DE-labtory/koa/ast"
 	"github.com/DE-labtory/koa/parse"
+	"github.com/DE-labtory/koa/symbol"
 )
 
 // expectedFnArg is used to verifing parsed function args data
@@ -82,6 +85,12 @@ func parseTestContract(input string) (*ast.Contract, error) {
 	return parse.Parse(buf)
 }
 
+// isOnlyError reports whether err has only the expected error.
+func isOnlyError(err error, expected error) bool {
+	list, ok := err.(parse.ErrorList)
+	return ok && len(list) == 1 && list[0] == expected
+}
+
 // chkFnHeader verify smart contract's function header
 func chkFnHeader(t *testing.T, fn *ast.FunctionLiteral, efh expectedFnHeader) {
 	if fn.ReturnType != efh.retType {
@@ -400,7 +409,7 @@ func TestReturnStatement(t *testing.T) {
 		input := createTestContractCode(tt.contractTmpl)
 		contract, err := parseTestContract(input)
 
-		if err != nil && err == tt.expectedErr {
+		if err != nil && isOnlyError(err, tt.expectedErr) {
 			continue
 		}
 
@@ -854,7 +863,7 @@ func TestAssignStatement(t *testing.T) {
 				{
 					Type:     ast.StringType,
 					Variable: ast.Identifier{Name: "a"},
-					Value:    &ast.StringLiteral{Value: "\"hello, world\""},
+					Value:    &ast.StringLiteral{Value: "hello, world"},
 				},
 			},
 		},
@@ -888,7 +897,7 @@ func TestAssignStatement(t *testing.T) {
 				{
 					Type:     ast.StringType,
 					Variable: ast.Identifier{Name: "a"},
-					Value:    &ast.StringLiteral{Value: "\"hello, world\""},
+					Value:    &ast.StringLiteral{Value: "hello, world"},
 				},
 			},
 		},
@@ -931,7 +940,7 @@ func TestAssignStatement(t *testing.T) {
 
 		contract, err := parseTestContract(input)
 
-		if err != nil && err == tt.expectedErr {
+		if err != nil && isOnlyError(err, tt.expectedErr) {
 			continue
 		}
 
@@ -1312,7 +1321,7 @@ func TestIfElseStatement(t *testing.T) {
 				},
 			},
 			expectedErr: parse.DupSymError{
-				Source: parse.Token{Type: parse.Ident, Val: "a", Line: 6, Column: 15}},
+				Source: parse.Token{Type: parse.Ident, Val: "a", Line: 6, Column: 15, Offset: 118}},
 		},
 	}
 
@@ -1320,7 +1329,7 @@ func TestIfElseStatement(t *testing.T) {
 		input := createTestContractCode(tt.contractTmpl)
 		contract, err := parseTestContract(input)
 
-		if err != nil && err == tt.expectedErr {
+		if err != nil && isOnlyError(err, tt.expectedErr) {
 			continue
 		}
 
@@ -1578,7 +1587,7 @@ func TestReassignStatement(t *testing.T) {
 			expected: []ast.ReassignStatement{
 				{
 					Variable: &ast.Identifier{Name: "a"},
-					Value:    &ast.StringLiteral{Value: "\"hello, world\""},
+					Value:    &ast.StringLiteral{Value: "hello, world"},
 				},
 			},
 		},
@@ -1609,7 +1618,7 @@ func TestReassignStatement(t *testing.T) {
 			expected: []ast.ReassignStatement{
 				{
 					Variable: &ast.Identifier{Name: "a"},
-					Value:    &ast.StringLiteral{Value: "\"hello, world\""},
+					Value:    &ast.StringLiteral{Value: "hello, world"},
 				},
 			},
 			expectedErr: parse.NotExistSymError{
@@ -1730,3 +1739,547 @@ func testReassignStatement(t *testing.T, stmt *ast.ReassignStatement, ident ast.
 			value.String(), stmt.Value.String())
 	}
 }
+
+func TestParseSource(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedNames []string
+		expectedErr   string
+	}{
+		{
+			input: `
+contract Escrow {
+	func deposit() int {
+		return 1
+	}
+}
+
+contract Token {
+	func deposit() int {
+		return 2
+	}
+}
+`,
+			expectedNames: []string{"Escrow", "Token"},
+		},
+		{
+			input: `
+contract {
+	func foo() int {
+		return 1
+	}
+}
+`,
+			expectedNames: []string{""},
+		},
+		{
+			input: `
+contract Escrow {
+}
+
+contract Escrow {
+}
+`,
+			expectedErr: "[line 4, column 15] symbol [Escrow] already exist",
+		},
+		{
+			input: `
+contract Escrow {
+}
+
+contract {
+}
+`,
+			expectedErr: "[line 4, column 8] [CONTRACT] contract without name should be the only contract in source",
+		},
+		{
+			input: `
+contract Escrow {
+}
+
+func foo() {
+}
+`,
+			expectedErr: "[line 4, column 4] Expected [CONTRACT], but got [FUNCTION]",
+		},
+	}
+
+	for i, test := range tests {
+		file, err := parse.ParseSource(parse.NewTokenBuffer(parse.NewLexer(test.input)), nil)
+
+		if test.expectedErr != "" {
+			if err == nil || err.Error() != test.expectedErr {
+				t.Fatalf("test[%d] - ParseSource() with wrong error. expected=%s, got=%v", i, test.expectedErr, err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("test[%d] - ParseSource() returns error: %v", i, err)
+		}
+
+		if len(file.Contracts) != len(test.expectedNames) {
+			t.Fatalf("test[%d] - ParseSource() with wrong number of contracts. expected=%d, got=%d",
+				i, len(test.expectedNames), len(file.Contracts))
+		}
+
+		for j, name := range test.expectedNames {
+			if file.Contract(name) != file.Contracts[j] {
+				t.Fatalf("test[%d] - Contract(%s) should return contracts[%d]", i, name, j)
+			}
+		}
+	}
+}
+
+func TestParse_severalContracts(t *testing.T) {
+	_, err := parseTestContract(`
+contract Escrow {
+}
+
+contract Token {
+}
+`)
+
+	expected := "[line 4, column 8] [CONTRACT] expected one contract, but got 2"
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Parse() with wrong error. expected=%s, got=%v", expected, err)
+	}
+}
+
+func TestParse_overload(t *testing.T) {
+	tests := []struct {
+		input              string
+		expectedSignatures []string
+		expectedErr        string
+	}{
+		{
+			input: `
+contract {
+	func transfer(to int) {
+	}
+
+	func transfer(to int, amount int) {
+	}
+
+	func transfer(to string) {
+	}
+}
+`,
+			expectedSignatures: []string{"transfer(int)", "transfer(int,int)", "transfer(string)"},
+		},
+		{
+			input: `
+contract {
+	func transfer(to int) {
+	}
+
+	func transfer(amount int) bool {
+		return true
+	}
+}
+`,
+			expectedErr: "[line 5, column 5] [FUNCTION] function transfer(int) is already declared",
+		},
+		{
+			input: `
+contract {
+	enum State { Open, Closed }
+
+	func set(s State) {
+	}
+
+	func set(a int) {
+	}
+}
+`,
+			expectedErr: "[line 7, column 5] [FUNCTION] function set(int) is already declared",
+		},
+	}
+
+	for i, test := range tests {
+		contract, err := parseTestContract(test.input)
+		if test.expectedErr != "" {
+			if err == nil || err.Error() != test.expectedErr {
+				t.Fatalf("test[%d] - Parse() with wrong error. expected=%s, got=%v", i, test.expectedErr, err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("test[%d] - Parse() returns error: %v", i, err)
+		}
+
+		if len(contract.Functions) != len(test.expectedSignatures) {
+			t.Fatalf("test[%d] - wrong number of functions. expected=%d, got=%d",
+				i, len(test.expectedSignatures), len(contract.Functions))
+		}
+
+		for j, sig := range test.expectedSignatures {
+			if contract.Functions[j].Signature() != sig {
+				t.Errorf("test[%d] - functions[%d] has wrong signature. expected=%s, got=%s",
+					i, j, sig, contract.Functions[j].Signature())
+			}
+		}
+	}
+}
+
+func TestParse_position(t *testing.T) {
+	input := `contract {
+	enum State { Open, Closed }
+
+	func foo(a int) int {
+		int b = a + 1
+		if (b > 2) {
+			return -b
+		}
+		b = math(b, State.Open)
+		return (b + 1) * 2
+	}
+}`
+
+	contract, err := parseTestContract(input)
+	if err != nil {
+		t.Fatalf("Parse() returns error: %v", err)
+	}
+
+	enum := contract.Enums[0]
+	fn := contract.Functions[0]
+	stmts := fn.Body.Statements
+	assign := stmts[0].(*ast.AssignStatement)
+	ifStmt := stmts[1].(*ast.IfStatement)
+	ret := ifStmt.Consequence.Statements[0].(*ast.ReturnStatement)
+	reassign := stmts[2].(*ast.ReassignStatement)
+	call := reassign.Value.(*ast.CallExpression)
+	last := stmts[3].(*ast.ReturnStatement)
+	mul := last.ReturnValue.(*ast.InfixExpression)
+
+	tests := []struct {
+		node          ast.Node
+		expectedStart string
+		expectedText  string
+	}{
+		{contract, "1:1", input},
+		{enum, "2:2", "enum State { Open, Closed }"},
+		{enum.Name, "2:7", "State"},
+		{enum.Members[1], "2:21", "Closed"},
+		{fn, "4:2", input[strings.Index(input, "func") : strings.LastIndex(input, "}")-1]},
+		{fn.Name, "4:7", "foo"},
+		{fn.Parameters[0], "4:11", "a int"},
+		{assign, "5:3", "int b = a + 1"},
+		{&assign.Variable, "5:7", "b"},
+		{assign.Value, "5:11", "a + 1"},
+		{assign.Value.(*ast.InfixExpression).Right, "5:15", "1"},
+		{ifStmt, "6:3", "if (b > 2) {\n\t\t\treturn -b\n\t\t}"},
+		{ifStmt.Condition, "6:7", "b > 2"},
+		{ifStmt.Consequence, "6:14", "{\n\t\t\treturn -b\n\t\t}"},
+		{ret, "7:4", "return -b"},
+		{ret.ReturnValue, "7:11", "-b"},
+		{reassign, "9:3", "b = math(b, State.Open)"},
+		{reassign.Variable, "9:3", "b"},
+		{call, "9:7", "math(b, State.Open)"},
+		{call.Function, "9:7", "math"},
+		{call.Arguments[1], "9:15", "State.Open"},
+		{call.Arguments[1].(*ast.EnumMemberLiteral).Member, "9:21", "Open"},
+		{last, "10:3", "return (b + 1) * 2"},
+		{mul, "10:10", "(b + 1) * 2"},
+		{mul.Left, "10:10", "(b + 1)"},
+	}
+
+	for i, test := range tests {
+		span := test.node.Position()
+		if span.Start.String() != test.expectedStart {
+			t.Errorf("test[%d] - %s starts at wrong position. expected=%s, got=%s",
+				i, test.node, test.expectedStart, span.Start)
+		}
+
+		if span.Start.Offset > span.End.Offset || span.End.Offset > len(input) {
+			t.Errorf("test[%d] - %s has invalid offsets. got=%d-%d",
+				i, test.node, span.Start.Offset, span.End.Offset)
+			continue
+		}
+
+		if text := input[span.Start.Offset:span.End.Offset]; text != test.expectedText {
+			t.Errorf("test[%d] - %s has wrong range. expected=%q, got=%q",
+				i, test.node, test.expectedText, text)
+		}
+
+		lines := strings.Split(input, "\n")
+		endLine := lines[span.End.Line-1]
+		if span.End.Column < 1 || span.End.Column > len(endLine)+1 {
+			t.Errorf("test[%d] - %s ends at invalid column. got=%s", i, test.node, span.End)
+		}
+	}
+}
+
+func TestParse_recovery(t *testing.T) {
+	tests := []struct {
+		input             string
+		expectedErrs      []string
+		expectedFunctions []string
+	}{
+		{
+			input: `
+contract {
+	func foo(a int) int {
+		int b = a
+		if (b > ) {
+			return 1
+		}
+		x = 3
+		return b
+	}
+
+	func bar(a int, ) int {
+		return a
+	}
+
+	func baz() int {
+		return 1 +* 2
+	}
+
+	func ok() bool {
+		return true
+	}
+}`,
+			expectedErrs: []string{
+				"[line 4, column 11] [RPAREN] prefix parse function not defined",
+				"[line 7, column 3] symbol [x] is not exist",
+				"[line 11, column 18] Expected [IDENT], but got [RPAREN]",
+				"[line 16, column 13] [ASTERISK] prefix parse function not defined",
+			},
+			expectedFunctions: []string{
+				"func foo(Parameter : (Identifier: a, Type: int)) int {\nint b = a\nreturn b\n}",
+				"func baz() int {\n\n}",
+				"func ok() bool {\nreturn true\n}",
+			},
+		},
+		{
+			input: `
+contract {
+	enum State { Open }
+	int a = 1
+	func foo() {
+	}
+	func foo() {
+	}
+}`,
+			expectedErrs: []string{
+				"[line 3, column 4] Expected [RBRACE], but got [INT_TYPE]",
+				"[line 6, column 5] [FUNCTION] function foo() is already declared",
+			},
+			expectedFunctions: []string{
+				"func foo() void {\n\n}",
+			},
+		},
+		{
+			input: `
+contract {
+	func foo() int {
+		int a = 1
+		return a
+`,
+			expectedErrs: []string{
+				"[line 5, column 0] Expected [RBRACE], but got [EOF]",
+			},
+			expectedFunctions: []string{
+				"func foo() int {\nint a = 1\nreturn a\n}",
+			},
+		},
+	}
+
+	for i, test := range tests {
+		contract, err := parseTestContract(test.input)
+
+		list, ok := err.(parse.ErrorList)
+		if !ok {
+			t.Fatalf("test[%d] - Parse() should return ErrorList. got=%v", i, err)
+		}
+
+		if len(list) != len(test.expectedErrs) {
+			t.Fatalf("test[%d] - Parse() with wrong number of errors. expected=%d, got=%d\n%v",
+				i, len(test.expectedErrs), len(list), list)
+		}
+
+		for j, e := range list {
+			if e.Error() != test.expectedErrs[j] {
+				t.Errorf("test[%d] - errors[%d] is wrong. expected=%s, got=%s", i, j, test.expectedErrs[j], e)
+			}
+		}
+
+		if contract == nil {
+			t.Fatalf("test[%d] - Parse() should return the partial contract", i)
+		}
+
+		if len(contract.Functions) != len(test.expectedFunctions) {
+			t.Fatalf("test[%d] - contract has wrong number of functions. expected=%d, got=%d",
+				i, len(test.expectedFunctions), len(contract.Functions))
+		}
+
+		for j, f := range contract.Functions {
+			if f.String() != test.expectedFunctions[j] {
+				t.Errorf("test[%d] - functions[%d] is wrong. expected=%q, got=%q",
+					i, j, test.expectedFunctions[j], f.String())
+			}
+		}
+	}
+}
+
+func TestParse_concurrent(t *testing.T) {
+	inputs := []string{
+		`
+contract {
+	enum State { Open, Closed }
+
+	func isOpen(s State) bool {
+		return s == State.Open
+	}
+}
+`,
+		`
+contract {
+	func add(a int, b int) int {
+		return a + b
+	}
+
+	func bad() {
+		int a = 
+	}
+}
+`,
+		`
+contract {
+	modifier onlyOwner(owner int) {
+		require(owner == 1)
+		_
+	}
+
+	func foo() onlyOwner(1) int {
+		int a = 1 << 2
+		return a ? 1 : 2
+	}
+}
+`,
+	}
+
+	expected := make([]string, len(inputs))
+	expectedErrs := make([]string, len(inputs))
+	for i, input := range inputs {
+		contract, err := parseTestContract(input)
+		if contract != nil {
+			expected[i] = contract.String()
+		}
+		if err != nil {
+			expectedErrs[i] = err.Error()
+		}
+	}
+
+	var wg sync.WaitGroup
+	for n := 0; n < 8; n++ {
+		for i, input := range inputs {
+			wg.Add(1)
+			go func(i int, input string) {
+				defer wg.Done()
+
+				contract, err := parseTestContract(input)
+				if contract != nil && contract.String() != expected[i] {
+					t.Errorf("input[%d] - Parse() with wrong result. expected=%s, got=%s",
+						i, expected[i], contract.String())
+				}
+				if err != nil && err.Error() != expectedErrs[i] {
+					t.Errorf("input[%d] - Parse() with wrong error. expected=%s, got=%s",
+						i, expectedErrs[i], err)
+				}
+			}(i, input)
+		}
+	}
+	wg.Wait()
+}
+
+func TestParser_Scopes(t *testing.T) {
+	input := `
+contract {
+	enum State { Open, Closed }
+
+	func f(a int) int {
+		int b = 1
+		if (b > a) {
+			int c = 2
+		}
+		return b
+	}
+}
+`
+	p := parse.NewParser(parse.NewTokenBuffer(parse.NewLexer(input)), nil)
+	file, err := p.ParseSource()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	contract := file.Contracts[0]
+	fn := contract.Functions[0]
+	ifStmt := fn.Body.Statements[1].(*ast.IfStatement)
+
+	tests := []struct {
+		span     ast.Span
+		declared []string
+		hidden   []string
+	}{
+		{ifStmt.Consequence.Span, []string{"a", "b", "c", "State"}, nil},
+		{fn.Body.Span, []string{"a", "b"}, []string{"c"}},
+		{fn.Span, []string{"a", "f"}, []string{"b", "c"}},
+		{contract.Span, []string{"State"}, []string{"a", "f"}},
+	}
+
+	scopes := p.Scopes()
+	if len(scopes) != len(tests) {
+		t.Fatalf("wrong number of scopes. expected=%d, got=%d", len(tests), len(scopes))
+	}
+
+	for i, test := range tests {
+		scope := scopes[i]
+		if scope.Span != test.span {
+			t.Errorf("scopes[%d] has wrong span. expected=%v, got=%v", i, test.span, scope.Span)
+		}
+
+		for _, name := range test.declared {
+			if scope.Symbols.Get(name) == nil {
+				t.Errorf("scopes[%d] should have symbol %s", i, name)
+			}
+		}
+
+		for _, name := range test.hidden {
+			if scope.Symbols.Get(name) != nil {
+				t.Errorf("scopes[%d] should not have symbol %s", i, name)
+			}
+		}
+	}
+
+	// symbol of variable knows where it is declared
+	sym, ok := scopes[0].Symbols.Get("c").(*symbol.Integer)
+	if !ok {
+		t.Fatalf("symbol c should be integer. got=%v", scopes[0].Symbols.Get("c"))
+	}
+
+	if offset := strings.Index(input, "c = 2"); sym.Name.Start.Offset != offset {
+		t.Errorf("symbol c is declared at wrong offset. expected=%d, got=%d", offset, sym.Name.Start.Offset)
+	}
+}
+
+func TestIsLibrary(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"func max(a int, b int) int { return a }", true},
+		{"// math\nimport \"util.koa\"\n\nfunc max(a int, b int) int { return a }", true},
+		{"import \"util.koa\"\ncontract { }", false},
+		{"contract { func f() { } }", false},
+		{"", false},
+	}
+
+	for i, test := range tests {
+		if got := parse.IsLibrary(test.input); got != test.expected {
+			t.Errorf("tests[%d] - wrong result. expected=%t, got=%t", i, test.expected, got)
+		}
+	}
+}
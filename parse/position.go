@@ -0,0 +1,86 @@
+/*
+ * Copyright 2018-2019 De-labtory
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package parse
+
+import "github.com/DE-labtory/koa/ast"
+
+// Read reads token from buffer of parser, and remembers the last token
+// read except semicolon and eof.
+func (p *Parser) Read() Token {
+	t := p.buf.Read()
+	if t.Type != Semicolon && t.Type != Eof {
+		p.last = t
+		p.read = true
+	}
+
+	return t
+}
+
+// Peek peeks token from buffer of parser.
+func (p *Parser) Peek(n peekNumber) Token {
+	return p.buf.Peek(n)
+}
+
+// startPos returns the position of the first byte of token.
+// Line of token starts from 0 and its column is at its end.
+func startPos(t Token) ast.Pos {
+	return ast.Pos{
+		Line:   t.Line + 1,
+		Column: int(t.Column) - len(t.Val) + 1,
+		Offset: int(t.Offset),
+	}
+}
+
+// endPos returns the position right after the last byte of token.
+func endPos(t Token) ast.Pos {
+	return ast.Pos{
+		Line:   t.Line + 1,
+		Column: int(t.Column) + 1,
+		Offset: int(t.Offset) + len(t.Val),
+	}
+}
+
+// lastPos returns the position right after the last token read. It is
+// unknown if no token is read.
+func (p *Parser) lastPos() ast.Pos {
+	if !p.read {
+		return ast.Pos{}
+	}
+
+	return endPos(p.last)
+}
+
+// positioner is the node whose position can be set.
+type positioner interface {
+	SetPosition(start ast.Pos, end ast.Pos)
+}
+
+// setPosition sets the position of node from start to the last token
+// read.
+func (p *Parser) setPosition(node interface{}, start ast.Pos) {
+	if n, ok := node.(positioner); ok {
+		n.SetPosition(start, p.lastPos())
+	}
+}
+
+// newIdentifier returns the identifier of token with its position.
+func newIdentifier(t Token) *ast.Identifier {
+	ident := &ast.Identifier{Name: t.Val}
+	ident.SetPosition(startPos(t), endPos(t))
+
+	return ident
+}
@@ -16,15 +16,23 @@
 
 package parse
 
-import "fmt"
+import (
+	"fmt"
+	"sort"
+	"unicode"
+)
 
 type TokenType int
 
+// Token is the unit of source. Column is the column of the last byte of
+// token and Line starts from 0. Offset is the byte offset of the first
+// byte of token in source.
 type Token struct {
 	Type   TokenType
 	Val    string
 	Column Pos
 	Line   int
+	Offset Pos
 }
 
 func (t Token) String() string {
@@ -51,6 +59,9 @@ const (
 	String   // "hello world"
 	Function // func
 	Contract // contract
+	Enum     // enum
+	Modifier // modifier
+	Import   // import
 
 	IntType
 	StringType
@@ -76,6 +87,13 @@ const (
 	Inc  // ++
 	Dec  //--
 
+	And   // &
+	Or    // |
+	Xor   // ^
+	Tilde // ~
+	Shl   // <<
+	Shr   // >>
+
 	LT     // <
 	GT     // >
 	LTE    // <=
@@ -83,21 +101,30 @@ const (
 	EQ     // ==
 	NOT_EQ // !=
 
-	Comma // ,
+	Comma    // ,
+	Question // ?
+	Colon    // :
+	Dot      // .
 
 	Lparen // (
 	Rparen // )
 	Lbrace // {
 	Rbrace // }
 
-	True   // true
-	False  // false
-	If     // if
-	Else   // else
-	Return // return
-	Eof    // end of file
-	Eol    // end of line
+	True    // true
+	False   // false
+	If      // if
+	Else    // else
+	Return  // return
+	Require // require
+	Call    // call
+	Store   // store
+	Load    // load
+	Emit    // emit
+	Eof     // end of file
+	Eol     // end of line
 	Semicolon
+	Comment // comment, which parser doesn't read
 )
 
 // TokenTypeMap mapping TokenType with its
@@ -110,6 +137,9 @@ var TokenTypeMap = map[TokenType]string{
 	String:   "STRING",
 	Function: "FUNCTION",
 	Contract: "CONTRACT",
+	Enum:     "ENUM",
+	Modifier: "MODIFIER",
+	Import:   "IMPORT",
 
 	IntType:    "INT_TYPE",
 	StringType: "STRING_TYPE",
@@ -134,6 +164,13 @@ var TokenTypeMap = map[TokenType]string{
 	Inc:  "INC",
 	Dec:  "DEC",
 
+	And:   "AND",
+	Or:    "OR",
+	Xor:   "XOR",
+	Tilde: "TILDE",
+	Shl:   "SHL",
+	Shr:   "SHR",
+
 	LT:     "LT",
 	GT:     "GT",
 	LTE:    "LTE",
@@ -141,26 +178,38 @@ var TokenTypeMap = map[TokenType]string{
 	EQ:     "EQ",
 	NOT_EQ: "NOT_EQ",
 
-	Comma: "COMMA",
+	Comma:    "COMMA",
+	Question: "QUESTION",
+	Colon:    "COLON",
+	Dot:      "DOT",
 
 	Lparen: "LPAREN",
 	Rparen: "RPAREN",
 	Lbrace: "LBRACE",
 	Rbrace: "RBRACE",
 
-	True:   "TRUE",
-	False:  "FALSE",
-	If:     "IF",
-	Else:   "ELSE",
-	Return: "RETURN",
+	True:    "TRUE",
+	False:   "FALSE",
+	If:      "IF",
+	Else:    "ELSE",
+	Return:  "RETURN",
+	Require: "REQUIRE",
+	Call:    "CALL",
+	Store:   "STORE",
+	Load:    "LOAD",
+	Emit:    "EMIT",
 
 	Eof:       "EOF",
 	Eol:       "EOL",
 	Semicolon: "SEMICOLON",
+	Comment:   "COMMENT",
 }
 
 var keywords = map[string]TokenType{
 	"contract": Contract,
+	"enum":     Enum,
+	"modifier": Modifier,
+	"import":   Import,
 	"func":     Function,
 	"if":       If,
 	"else":     Else,
@@ -168,6 +217,11 @@ var keywords = map[string]TokenType{
 	"string":   StringType,
 	"bool":     BoolType,
 	"return":   Return,
+	"require":  Require,
+	"call":     Call,
+	"store":    Store,
+	"load":     Load,
+	"emit":     Emit,
 	"true":     True,
 	"false":    False,
 }
@@ -178,3 +232,30 @@ func LookupIdent(ident string) TokenType {
 	}
 	return Ident
 }
+
+// Keywords returns the keywords of koa in alphabetical order.
+func Keywords() []string {
+	words := make([]string, 0, len(keywords))
+	for k := range keywords {
+		words = append(words, k)
+	}
+	sort.Strings(words)
+
+	return words
+}
+
+// isIdentifier reports whether name can be an identifier, which is
+// made of letters, digits and '_', starts with non-digit and isn't a keyword.
+func isIdentifier(name string) bool {
+	if name == "" || LookupIdent(name) != Ident {
+		return false
+	}
+
+	for n, r := range name {
+		if r != '_' && !unicode.IsLetter(r) && (n == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+
+	return true
+}
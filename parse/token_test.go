@@ -33,6 +33,14 @@ func TestLookupIdent(t *testing.T) {
 		{"return", Return},
 		{"true", True},
 		{"false", False},
+		{"enum", Enum},
+		{"modifier", Modifier},
+		{"import", Import},
+		{"require", Require},
+		{"call", Call},
+		{"store", Store},
+		{"load", Load},
+		{"emit", Emit},
 	}
 
 	for i, test := range tests {
@@ -40,9 +48,46 @@ func TestLookupIdent(t *testing.T) {
 		tokType := LookupIdent(input)
 
 		if tokType != test.expectedTokenType {
-			t.Fatalf("tests[%d] - wrong token Type. Expected=%q, got=%q",
-				i, test.expectedTokenType, input)
+			t.Fatalf("tests[%d] - wrong token Type. Expected=%s, got=%s",
+				i, TokenTypeMap[test.expectedTokenType], TokenTypeMap[tokType])
 		}
 	}
 
 }
+
+func TestKeywords(t *testing.T) {
+	words := Keywords()
+	if len(words) != len(keywords) {
+		t.Fatalf("wrong number of keywords. expected=%d, got=%d", len(keywords), len(words))
+	}
+
+	for i, w := range words {
+		if LookupIdent(w) == Ident {
+			t.Errorf("words[%d] - %s is not a keyword", i, w)
+		}
+		if i > 0 && words[i-1] >= w {
+			t.Errorf("words[%d] - %s is not sorted after %s", i, w, words[i-1])
+		}
+	}
+}
+
+func TestIsIdentifier(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected bool
+	}{
+		{"balance", true},
+		{"_total2", true},
+		{"", false},
+		{"2total", false},
+		{"total-2", false},
+		{"contract", false},
+	}
+
+	for i, test := range tests {
+		if got := isIdentifier(test.name); got != test.expected {
+			t.Errorf("tests[%d] - isIdentifier(%q) is wrong. Expected=%v, got=%v",
+				i, test.name, test.expected, got)
+		}
+	}
+}
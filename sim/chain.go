@@ -0,0 +1,355 @@
+/*
+ * Copyright 2018-2019 De-labtory
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sim
+
+import (
+	"errors"
+	"time"
+
+	"github.com/DE-labtory/koa"
+	"github.com/DE-labtory/koa/abi"
+	"github.com/DE-labtory/koa/vm"
+)
+
+var ErrUnknownAccount = errors.New("unknown account")
+var ErrNoContract = errors.New("no contract at the address")
+var ErrUnknownBlock = errors.New("unknown block")
+var ErrUnknownSnapshot = errors.New("unknown snapshot")
+
+// GenesisTime is the time of genesis block, 2019-01-01T00:00:00Z.
+const GenesisTime int64 = 1546300800
+
+// BlockTime is the time between two blocks.
+const BlockTime = 10 * time.Second
+
+// Address identifies an account or a contract in the chain.
+type Address int64
+
+// Transaction calls the function of contract at To, Func is the
+// function selector and Args is the arguments encoded with abi.
+// GasLimit is the maximum gas to use, zero means no limit.
+// Tracer receives the steps of execution, it can be nil.
+type Transaction struct {
+	From     Address
+	To       Address
+	Func     []byte
+	Args     []byte
+	GasLimit uint64
+	Tracer   vm.Tracer
+}
+
+// Log is the data recorded by the contract at Address with emit.
+type Log struct {
+	Address Address
+	Data    []int64
+}
+
+// Receipt is the result of transaction. If the execution fails, Err has
+// the reason, and the changes of storage and logs are discarded.
+type Receipt struct {
+	BlockHeight uint64
+	Index       int
+	From        Address
+	To          Address
+
+	// ContractAddress is the address of deployed contract,
+	// it is set only for the deployment.
+	ContractAddress Address
+
+	Output  []byte
+	GasUsed uint64
+	Logs    []Log
+	Err     error
+}
+
+// Failed reports whether the execution of transaction failed.
+func (r *Receipt) Failed() bool {
+	return r.Err != nil
+}
+
+// Block has the receipts of transactions in the order they are sent.
+type Block struct {
+	Height   uint64
+	Time     int64
+	Receipts []*Receipt
+}
+
+func (b *Block) copy() *Block {
+	return &Block{
+		Height:   b.Height,
+		Time:     b.Time,
+		Receipts: append([]*Receipt{}, b.Receipts...),
+	}
+}
+
+// snapshot is the chain at the moment Snapshot is called.
+type snapshot struct {
+	state   *state
+	blocks  []*Block
+	pending *Block
+}
+
+// Chain is an in-memory blockchain to test contracts. It runs without
+// network and its result is deterministic: addresses are given in order,
+// and the time of block advances by BlockTime from GenesisTime.
+//
+// Transactions are executed immediately and included in the pending block,
+// Commit seals the pending block and starts the next one.
+type Chain struct {
+	state     *state
+	blocks    []*Block
+	pending   *Block
+	snapshots []snapshot
+}
+
+// New creates a chain which has only genesis block.
+func New() *Chain {
+	genesis := &Block{Height: 0, Time: GenesisTime, Receipts: []*Receipt{}}
+
+	return &Chain{
+		state:   newState(),
+		blocks:  []*Block{genesis},
+		pending: nextBlock(genesis),
+	}
+}
+
+func nextBlock(b *Block) *Block {
+	return &Block{
+		Height:   b.Height + 1,
+		Time:     b.Time + int64(BlockTime/time.Second),
+		Receipts: []*Receipt{},
+	}
+}
+
+// NewAccount creates an account which can send transactions.
+func (c *Chain) NewAccount() Address {
+	address := c.state.newAddress()
+	c.state.nonces[address] = 0
+	return address
+}
+
+// Nonce returns the number of transactions sent by the account.
+func (c *Chain) Nonce(account Address) (uint64, error) {
+	nonce, ok := c.state.nonces[account]
+	if !ok {
+		return 0, ErrUnknownAccount
+	}
+
+	return nonce, nil
+}
+
+// Code returns the bytecode of contract.
+func (c *Chain) Code(contract Address) ([]byte, error) {
+	code, ok := c.state.codes[contract]
+	if !ok {
+		return nil, ErrNoContract
+	}
+
+	return code, nil
+}
+
+// Storage returns the value of key in the storage of contract.
+func (c *Chain) Storage(contract Address, key int64) (int64, error) {
+	if _, ok := c.state.codes[contract]; !ok {
+		return 0, ErrNoContract
+	}
+
+	return c.state.storages[contract][key], nil
+}
+
+// Deploy deploys the bytecode of contract, the address of contract is in
+// the receipt. Deploying uses a gas per byte of bytecode.
+func (c *Chain) Deploy(from Address, code []byte) (*Receipt, error) {
+	if err := c.useNonce(from); err != nil {
+		return nil, err
+	}
+
+	address := c.state.newAddress()
+	c.state.codes[address] = append([]byte{}, code...)
+
+	return c.include(&Receipt{
+		From:            from,
+		ContractAddress: address,
+		GasUsed:         uint64(len(code)),
+		Logs:            []Log{},
+	}), nil
+}
+
+// DeploySource compiles the source of contract and deploys it.
+// It returns the ABI of contract with the receipt.
+func (c *Chain) DeploySource(from Address, src string) (*Receipt, abi.ABI, error) {
+	asm, a, err := koa.Compile(src)
+	if err != nil {
+		return nil, abi.ABI{}, err
+	}
+
+	r, err := c.Deploy(from, asm.ToRawByteCode())
+	if err != nil {
+		return nil, abi.ABI{}, err
+	}
+
+	return r, a, nil
+}
+
+// Send executes the transaction and includes it in the pending block.
+// The error is returned only when the transaction is invalid, and the
+// failure of execution is in the receipt.
+func (c *Chain) Send(tx Transaction) (*Receipt, error) {
+	if _, ok := c.state.codes[tx.To]; !ok {
+		return nil, ErrNoContract
+	}
+
+	if err := c.useNonce(tx.From); err != nil {
+		return nil, err
+	}
+
+	s := c.state.copy()
+	r := execute(s, tx)
+	if !r.Failed() {
+		c.state = s
+	}
+
+	return c.include(r), nil
+}
+
+// Call executes the transaction without changing the chain, and
+// returns the output. It is used to read the state of contract.
+func (c *Chain) Call(tx Transaction) ([]byte, error) {
+	if _, ok := c.state.codes[tx.To]; !ok {
+		return nil, ErrNoContract
+	}
+
+	if _, ok := c.state.nonces[tx.From]; !ok {
+		return nil, ErrUnknownAccount
+	}
+
+	r := execute(c.state.copy(), tx)
+	return r.Output, r.Err
+}
+
+// execute runs the transaction on the state.
+func execute(s *state, tx Transaction) *Receipt {
+	h := &host{state: s, logs: []Log{}}
+	callFunc := &vm.CallFunc{
+		Func:     tx.Func,
+		Args:     tx.Args,
+		Host:     h,
+		State:    h,
+		Address:  int64(tx.To),
+		GasLimit: tx.GasLimit,
+		Tracer:   tx.Tracer,
+	}
+
+	r := &Receipt{
+		From: tx.From,
+		To:   tx.To,
+		Logs: []Log{},
+	}
+
+	stack, err := vm.Execute(s.codes[tx.To], vm.NewMemory(), callFunc)
+	r.GasUsed = callFunc.GasUsed
+	if err != nil {
+		r.Err = err
+		return r
+	}
+
+	if stack.Len() > 0 {
+		r.Output = koa.Bytes(int64(stack.Pop()))
+	}
+	r.Logs = h.logs
+
+	return r
+}
+
+// useNonce increases the nonce of account which sends transaction.
+func (c *Chain) useNonce(account Address) error {
+	nonce, ok := c.state.nonces[account]
+	if !ok {
+		return ErrUnknownAccount
+	}
+
+	c.state.nonces[account] = nonce + 1
+	return nil
+}
+
+// include adds the receipt to the pending block.
+func (c *Chain) include(r *Receipt) *Receipt {
+	r.BlockHeight = c.pending.Height
+	r.Index = len(c.pending.Receipts)
+	c.pending.Receipts = append(c.pending.Receipts, r)
+	return r
+}
+
+// Commit seals the pending block and returns it.
+func (c *Chain) Commit() *Block {
+	sealed := c.pending
+	c.blocks = append(c.blocks, sealed)
+	c.pending = nextBlock(sealed)
+	return sealed
+}
+
+// AdjustTime advances the time of pending block.
+func (c *Chain) AdjustTime(d time.Duration) {
+	c.pending.Time += int64(d / time.Second)
+}
+
+// Height returns the height of the last sealed block.
+func (c *Chain) Height() uint64 {
+	return c.blocks[len(c.blocks)-1].Height
+}
+
+// Block returns the sealed block at height.
+func (c *Chain) Block(height uint64) (*Block, error) {
+	if height >= uint64(len(c.blocks)) {
+		return nil, ErrUnknownBlock
+	}
+
+	return c.blocks[height], nil
+}
+
+// Pending returns the block which includes transactions now.
+func (c *Chain) Pending() *Block {
+	return c.pending
+}
+
+// Snapshot saves the chain and returns its id, Rollback
+// restores the chain to the snapshot.
+func (c *Chain) Snapshot() int {
+	c.snapshots = append(c.snapshots, snapshot{
+		state:   c.state.copy(),
+		blocks:  append([]*Block{}, c.blocks...),
+		pending: c.pending.copy(),
+	})
+
+	return len(c.snapshots) - 1
+}
+
+// Rollback restores the chain to the snapshot. The snapshots taken
+// after it are removed, but the snapshot itself can be used again.
+func (c *Chain) Rollback(id int) error {
+	if id < 0 || id >= len(c.snapshots) {
+		return ErrUnknownSnapshot
+	}
+
+	s := c.snapshots[id]
+	c.state = s.state.copy()
+	c.blocks = append([]*Block{}, s.blocks...)
+	c.pending = s.pending.copy()
+	c.snapshots = c.snapshots[:id+1]
+
+	return nil
+}
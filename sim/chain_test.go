@@ -0,0 +1,378 @@
+/*
+ * Copyright 2018-2019 De-labtory
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sim_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DE-labtory/koa"
+	"github.com/DE-labtory/koa/abi"
+	"github.com/DE-labtory/koa/sim"
+	"github.com/DE-labtory/koa/vm"
+)
+
+const bank = `
+contract {
+	func deposit(amount int) int {
+		require(amount > 0)
+		store(1, load(1) + amount)
+		emit(amount, load(1))
+		return load(1)
+	}
+
+	func balance() int {
+		return load(1)
+	}
+}
+`
+
+const depositor = `
+contract {
+	func deposit(bank int, amount int) int {
+		store(1, load(1) + 1)
+		return call(bank, "deposit(int)", amount)
+	}
+}
+`
+
+func deploy(t *testing.T, c *sim.Chain, from sim.Address, src string) sim.Address {
+	t.Helper()
+
+	r, _, err := c.DeploySource(from, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return r.ContractAddress
+}
+
+func tx(from sim.Address, to sim.Address, signature string, args ...interface{}) sim.Transaction {
+	encoded, err := abi.Encode(args...)
+	if err != nil {
+		panic(err)
+	}
+
+	return sim.Transaction{
+		From: from,
+		To:   to,
+		Func: abi.Selector(signature),
+		Args: encoded,
+	}
+}
+
+func TestChain_Send(t *testing.T) {
+	c := sim.New()
+	alice := c.NewAccount()
+	contract := deploy(t, c, alice, bank)
+
+	tests := []struct {
+		tx      sim.Transaction
+		output  []byte
+		logs    []sim.Log
+		err     error
+		balance int64
+	}{
+		{
+			tx:      tx(alice, contract, "deposit(int)", 10),
+			output:  koa.Bytes(10),
+			logs:    []sim.Log{{Address: contract, Data: []int64{10, 10}}},
+			balance: 10,
+		},
+		{
+			tx:      tx(alice, contract, "deposit(int)", 5),
+			output:  koa.Bytes(15),
+			logs:    []sim.Log{{Address: contract, Data: []int64{5, 15}}},
+			balance: 15,
+		},
+		{
+			tx:      tx(alice, contract, "deposit(int)", 0),
+			logs:    []sim.Log{},
+			err:     vm.ErrRevert,
+			balance: 15,
+		},
+	}
+
+	for i, test := range tests {
+		r, err := c.Send(test.tx)
+		if err != nil {
+			t.Fatalf("test[%d] - Send() returns error: %v", i, err)
+		}
+
+		if r.Err != test.err {
+			t.Errorf("test[%d] - receipt has wrong error. expected=%v, got=%v", i, test.err, r.Err)
+		}
+
+		if !bytes.Equal(r.Output, test.output) {
+			t.Errorf("test[%d] - receipt has wrong output. expected=%x, got=%x", i, test.output, r.Output)
+		}
+
+		if !reflect.DeepEqual(r.Logs, test.logs) {
+			t.Errorf("test[%d] - receipt has wrong logs. expected=%v, got=%v", i, test.logs, r.Logs)
+		}
+
+		if r.GasUsed == 0 {
+			t.Errorf("test[%d] - receipt has no gas used", i)
+		}
+
+		balance, err := c.Storage(contract, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if balance != test.balance {
+			t.Errorf("test[%d] - wrong storage. expected=%d, got=%d", i, test.balance, balance)
+		}
+	}
+
+	if nonce, _ := c.Nonce(alice); nonce != 4 {
+		t.Errorf("wrong nonce. expected=4, got=%d", nonce)
+	}
+
+	if _, err := c.Send(tx(sim.Address(100), contract, "deposit(int)", 1)); err != sim.ErrUnknownAccount {
+		t.Errorf("Send() from unknown account should return %v, got=%v", sim.ErrUnknownAccount, err)
+	}
+
+	if _, err := c.Send(tx(alice, alice, "deposit(int)", 1)); err != sim.ErrNoContract {
+		t.Errorf("Send() to account should return %v, got=%v", sim.ErrNoContract, err)
+	}
+}
+
+func TestChain_Call(t *testing.T) {
+	c := sim.New()
+	alice := c.NewAccount()
+	contract := deploy(t, c, alice, bank)
+
+	if _, err := c.Send(tx(alice, contract, "deposit(int)", 7)); err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := c.Call(tx(alice, contract, "balance()"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(output, koa.Bytes(7)) {
+		t.Errorf("Call() returns wrong output. expected=%x, got=%x", koa.Bytes(7), output)
+	}
+
+	// Call doesn't change storage and nonce
+	output, err = c.Call(tx(alice, contract, "deposit(int)", 3))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(output, koa.Bytes(10)) {
+		t.Errorf("Call() returns wrong output. expected=%x, got=%x", koa.Bytes(10), output)
+	}
+
+	if balance, _ := c.Storage(contract, 1); balance != 7 {
+		t.Errorf("Call() changes storage. expected=7, got=%d", balance)
+	}
+
+	if nonce, _ := c.Nonce(alice); nonce != 2 {
+		t.Errorf("Call() changes nonce. expected=2, got=%d", nonce)
+	}
+
+	if len(c.Pending().Receipts) != 2 {
+		t.Errorf("Call() is included in block. expected=2 receipts, got=%d", len(c.Pending().Receipts))
+	}
+
+	if _, err := c.Call(tx(alice, contract, "deposit(int)", 0)); err != vm.ErrRevert {
+		t.Errorf("Call() should return %v, got=%v", vm.ErrRevert, err)
+	}
+}
+
+func TestChain_contractCall(t *testing.T) {
+	c := sim.New()
+	alice := c.NewAccount()
+	b := deploy(t, c, alice, bank)
+	d := deploy(t, c, alice, depositor)
+
+	r, err := c.Send(tx(alice, d, "deposit(int,int)", int(b), 4))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r.Failed() {
+		t.Fatalf("transaction failed: %v", r.Err)
+	}
+
+	expected := []sim.Log{{Address: b, Data: []int64{4, 4}}}
+	if !reflect.DeepEqual(r.Logs, expected) {
+		t.Errorf("wrong logs. expected=%v, got=%v", expected, r.Logs)
+	}
+
+	// each contract has its own storage
+	if balance, _ := c.Storage(b, 1); balance != 4 {
+		t.Errorf("wrong storage of bank. expected=4, got=%d", balance)
+	}
+
+	if count, _ := c.Storage(d, 1); count != 1 {
+		t.Errorf("wrong storage of depositor. expected=1, got=%d", count)
+	}
+
+	// the failure of called contract discards the changes of caller
+	r, err = c.Send(tx(alice, d, "deposit(int,int)", int(b), 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r.Err != vm.ErrRevert {
+		t.Errorf("receipt has wrong error. expected=%v, got=%v", vm.ErrRevert, r.Err)
+	}
+
+	if count, _ := c.Storage(d, 1); count != 1 {
+		t.Errorf("failed transaction changes storage. expected=1, got=%d", count)
+	}
+}
+
+func TestChain_blocks(t *testing.T) {
+	c := sim.New()
+	alice := c.NewAccount()
+
+	if c.Height() != 0 {
+		t.Fatalf("wrong height of genesis. expected=0, got=%d", c.Height())
+	}
+
+	r, _, err := c.DeploySource(alice, bank)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r.BlockHeight != 1 || r.Index != 0 {
+		t.Errorf("receipt is in wrong place. expected=1:0, got=%d:%d", r.BlockHeight, r.Index)
+	}
+
+	b := c.Commit()
+	if b.Height != 1 || c.Height() != 1 {
+		t.Errorf("wrong height. expected=1, got=%d, %d", b.Height, c.Height())
+	}
+
+	if b.Time != sim.GenesisTime+10 {
+		t.Errorf("wrong time. expected=%d, got=%d", sim.GenesisTime+10, b.Time)
+	}
+
+	if len(b.Receipts) != 1 || b.Receipts[0] != r {
+		t.Errorf("block has wrong receipts. expected=%v, got=%v", []*sim.Receipt{r}, b.Receipts)
+	}
+
+	c.AdjustTime(time.Hour)
+	b = c.Commit()
+	if b.Time != sim.GenesisTime+20+3600 {
+		t.Errorf("wrong time. expected=%d, got=%d", sim.GenesisTime+20+3600, b.Time)
+	}
+
+	if len(b.Receipts) != 0 {
+		t.Errorf("block should have no receipts, got=%d", len(b.Receipts))
+	}
+
+	genesis, err := c.Block(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if genesis.Time != sim.GenesisTime {
+		t.Errorf("wrong time of genesis. expected=%d, got=%d", sim.GenesisTime, genesis.Time)
+	}
+
+	if _, err := c.Block(3); err != sim.ErrUnknownBlock {
+		t.Errorf("Block() should return %v, got=%v", sim.ErrUnknownBlock, err)
+	}
+}
+
+func TestChain_Rollback(t *testing.T) {
+	c := sim.New()
+	alice := c.NewAccount()
+	contract := deploy(t, c, alice, bank)
+	c.Commit()
+
+	id := c.Snapshot()
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.Send(tx(alice, contract, "deposit(int)", 10)); err != nil {
+			t.Fatal(err)
+		}
+		c.Commit()
+		bob := c.NewAccount()
+
+		if err := c.Rollback(id); err != nil {
+			t.Fatal(err)
+		}
+
+		if balance, _ := c.Storage(contract, 1); balance != 0 {
+			t.Errorf("storage isn't restored. expected=0, got=%d", balance)
+		}
+
+		if nonce, _ := c.Nonce(alice); nonce != 1 {
+			t.Errorf("nonce isn't restored. expected=1, got=%d", nonce)
+		}
+
+		if _, err := c.Nonce(bob); err != sim.ErrUnknownAccount {
+			t.Errorf("account isn't removed. expected=%v, got=%v", sim.ErrUnknownAccount, err)
+		}
+
+		if c.Height() != 1 || len(c.Pending().Receipts) != 0 {
+			t.Errorf("blocks aren't restored. height=%d, pending=%d", c.Height(), len(c.Pending().Receipts))
+		}
+	}
+
+	if err := c.Rollback(id + 1); err != sim.ErrUnknownSnapshot {
+		t.Errorf("Rollback() should return %v, got=%v", sim.ErrUnknownSnapshot, err)
+	}
+}
+
+func TestChain_gas(t *testing.T) {
+	c := sim.New()
+	alice := c.NewAccount()
+	contract := deploy(t, c, alice, bank)
+
+	r, err := c.Send(tx(alice, contract, "deposit(int)", 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the same transaction uses the same gas in the other chain
+	other := sim.New()
+	account := other.NewAccount()
+	r2, err := other.Send(tx(account, deploy(t, other, account, bank), "deposit(int)", 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r.GasUsed != r2.GasUsed {
+		t.Errorf("gas used isn't deterministic. %d != %d", r.GasUsed, r2.GasUsed)
+	}
+
+	limited := tx(alice, contract, "deposit(int)", 10)
+	limited.GasLimit = r.GasUsed - 1
+
+	r, err = c.Send(limited)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r.Err != vm.ErrOutOfGas || r.GasUsed != limited.GasLimit {
+		t.Errorf("transaction should be out of gas. err=%v, gas used=%d", r.Err, r.GasUsed)
+	}
+
+	if balance, _ := c.Storage(contract, 1); balance != 10 {
+		t.Errorf("failed transaction changes storage. expected=10, got=%d", balance)
+	}
+}
@@ -0,0 +1,108 @@
+/*
+ * Copyright 2018-2019 De-labtory
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sim
+
+// state is the world state of chain, which has accounts, the bytecode
+// of contracts and their storage. Accounts and contracts share the
+// address space, addresses are given in the order of creation.
+type state struct {
+	next     Address
+	nonces   map[Address]uint64
+	codes    map[Address][]byte
+	storages map[Address]map[int64]int64
+}
+
+func newState() *state {
+	return &state{
+		next:     1,
+		nonces:   make(map[Address]uint64),
+		codes:    make(map[Address][]byte),
+		storages: make(map[Address]map[int64]int64),
+	}
+}
+
+// newAddress returns the address which is not used yet.
+func (s *state) newAddress() Address {
+	address := s.next
+	s.next++
+	return address
+}
+
+// copy returns the deep copy of state. Bytecode is never modified,
+// so it is shared between the copies.
+func (s *state) copy() *state {
+	cpy := &state{
+		next:     s.next,
+		nonces:   make(map[Address]uint64, len(s.nonces)),
+		codes:    make(map[Address][]byte, len(s.codes)),
+		storages: make(map[Address]map[int64]int64, len(s.storages)),
+	}
+
+	for address, nonce := range s.nonces {
+		cpy.nonces[address] = nonce
+	}
+
+	for address, code := range s.codes {
+		cpy.codes[address] = code
+	}
+
+	for address, storage := range s.storages {
+		cpy.storages[address] = make(map[int64]int64, len(storage))
+		for key, value := range storage {
+			cpy.storages[address][key] = value
+		}
+	}
+
+	return cpy
+}
+
+// host runs contracts on the state, and keeps the logs of contracts
+// in the order they are recorded.
+type host struct {
+	state *state
+	logs  []Log
+}
+
+func (h *host) Code(address int64) ([]byte, error) {
+	code, ok := h.state.codes[Address(address)]
+	if !ok {
+		return nil, ErrNoContract
+	}
+
+	return code, nil
+}
+
+func (h *host) Load(address int64, key int64) int64 {
+	return h.state.storages[Address(address)][key]
+}
+
+func (h *host) Store(address int64, key int64, value int64) {
+	storage, ok := h.state.storages[Address(address)]
+	if !ok {
+		storage = make(map[int64]int64)
+		h.state.storages[Address(address)] = storage
+	}
+
+	storage[key] = value
+}
+
+func (h *host) Log(address int64, data []int64) {
+	h.logs = append(h.logs, Log{
+		Address: Address(address),
+		Data:    append([]int64{}, data...),
+	})
+}
@@ -84,6 +84,17 @@ func (s *Scope) GetInner() []*Scope {
 	return s.inner
 }
 
+// GetSymbols returns the symbols declared in the scope by their names,
+// without the symbols of outer scope.
+func (s *Scope) GetSymbols() map[string]Symbol {
+	symbols := make(map[string]Symbol, len(s.store))
+	for k, v := range s.store {
+		symbols[k] = v
+	}
+
+	return symbols
+}
+
 func (s *Scope) String() string {
 	var out bytes.Buffer
 	scope := s
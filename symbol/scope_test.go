@@ -207,3 +207,21 @@ func TestScopeString(t *testing.T) {
 		}
 	}
 }
+
+func TestScope_GetSymbols(t *testing.T) {
+	outer := NewScope()
+	outer.Set("a", &Integer{&ast.Identifier{Name: "a"}})
+
+	s := NewEnclosedScope(outer)
+	s.Set("b", &Boolean{&ast.Identifier{Name: "b"}})
+
+	symbols := s.GetSymbols()
+	if len(symbols) != 1 || symbols["b"] != s.Get("b") {
+		t.Fatalf("GetSymbols() should return only the symbols of scope. got=%v", symbols)
+	}
+
+	delete(symbols, "b")
+	if s.Get("b") == nil {
+		t.Fatalf("GetSymbols() should return a copy of symbols")
+	}
+}
@@ -25,10 +25,14 @@ import (
 type SymbolType string
 
 const (
-	IntegerSymbol  = "INTEGER"
-	BooleanSymbol  = "BOOLEAN"
-	StringSymbol   = "STRING"
-	FunctionSymbol = "FUNCTION"
+	IntegerSymbol   = "INTEGER"
+	BooleanSymbol   = "BOOLEAN"
+	StringSymbol    = "STRING"
+	FunctionSymbol  = "FUNCTION"
+	EnumSymbol      = "ENUM"
+	EnumValueSymbol = "ENUM_VALUE"
+	ModifierSymbol  = "MODIFIER"
+	LibrarySymbol   = "LIBRARY"
 )
 
 type Symbol interface {
@@ -79,9 +83,13 @@ func (s *String) String() string {
 // Represent Function symbol
 // Name represents function's name.
 // Scope represents function value's scope.
+// Literal and Library represent function's definition and the library
+// which has it, when the function can be called in the library.
 type Function struct {
-	Name  string
-	Scope *Scope
+	Name    string
+	Scope   *Scope
+	Literal *ast.FunctionLiteral
+	Library *ast.Library
 }
 
 func (f *Function) Type() SymbolType {
@@ -91,3 +99,61 @@ func (f *Function) Type() SymbolType {
 func (f *Function) String() string {
 	return fmt.Sprintf("%s", f.Name)
 }
+
+// Represent Enum symbol
+// Literal represents enum's declaration which has its members.
+type Enum struct {
+	Literal *ast.EnumLiteral
+}
+
+func (e *Enum) Type() SymbolType {
+	return EnumSymbol
+}
+
+func (e *Enum) String() string {
+	return fmt.Sprintf("%s", e.Literal.Name.String())
+}
+
+// Represent EnumValue symbol, variable whose type is enum
+// Name represents variable's name.
+// Enum represents enum's declaration of variable.
+type EnumValue struct {
+	Name *ast.Identifier
+	Enum *ast.EnumLiteral
+}
+
+func (e *EnumValue) Type() SymbolType {
+	return EnumValueSymbol
+}
+
+func (e *EnumValue) String() string {
+	return fmt.Sprintf("%s", e.Name.String())
+}
+
+// Represent Modifier symbol
+// Literal represents modifier's definition which is inlined to functions.
+type Modifier struct {
+	Literal *ast.ModifierLiteral
+}
+
+func (m *Modifier) Type() SymbolType {
+	return ModifierSymbol
+}
+
+func (m *Modifier) String() string {
+	return fmt.Sprintf("%s", m.Literal.Name.String())
+}
+
+// Represent Library symbol
+// Literal represents imported library whose functions are called with its name.
+type Library struct {
+	Literal *ast.Library
+}
+
+func (l *Library) Type() SymbolType {
+	return LibrarySymbol
+}
+
+func (l *Library) String() string {
+	return fmt.Sprintf("%s", l.Literal.Name)
+}
@@ -116,8 +116,8 @@ func TestFunction(t *testing.T) {
 	}{
 		{
 			&Function{
-				"add",
-				&Scope{},
+				Name:  "add",
+				Scope: &Scope{},
 			},
 			"add",
 			FunctionSymbol,
@@ -141,3 +141,41 @@ func TestFunction(t *testing.T) {
 		}
 	}
 }
+
+func TestEnum(t *testing.T) {
+	state := &ast.EnumLiteral{
+		Name: &ast.Identifier{Name: "State"},
+		Members: []*ast.Identifier{
+			{Name: "Open"},
+			{Name: "Closed"},
+		},
+	}
+
+	tests := []struct {
+		input          Symbol
+		expectedStr    string
+		expectedSymbol SymbolType
+	}{
+		{&Enum{state}, "State", EnumSymbol},
+		{&EnumValue{&ast.Identifier{Name: "s"}, state}, "s", EnumValueSymbol},
+		{&Modifier{&ast.ModifierLiteral{Name: &ast.Identifier{Name: "positive"}}}, "positive", ModifierSymbol},
+		{&Library{&ast.Library{Name: "math"}}, "math", LibrarySymbol},
+	}
+
+	for i, test := range tests {
+		str := test.input.String()
+		obj := test.input.Type()
+
+		if str != test.expectedStr {
+			t.Fatalf("test[%d] String() in Enum wrong result.\n"+
+				"expected: %s\n"+
+				"got: %s", i, test.expectedStr, str)
+		}
+
+		if obj != test.expectedSymbol {
+			t.Fatalf("test[%d] Type() in Enum wrong result.\n"+
+				"expected: %s\n"+
+				"got: %s", i, test.expectedSymbol, obj)
+		}
+	}
+}
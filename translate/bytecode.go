@@ -23,17 +23,33 @@ import (
 
 	"strings"
 
+	"github.com/DE-labtory/koa/ast"
 	"github.com/DE-labtory/koa/opcode"
 )
 
 // Asm is generated by compiling.
+// Labels manage the start point of library functions by their
+// qualified name, so that the functions can be called. The function
+// jumper is labeled too.
+//
+// file, line and column are the origin of the codes being emerged,
+// see at().
 type Asm struct {
 	AsmCodes []AsmCode
+	Labels   map[string]int
+
+	file   string
+	line   int
+	column int
 }
 
+// AsmCode is an operator or an operand. File and Pos are the position
+// of the statement in source which the code is compiled from.
 type AsmCode struct {
 	RawByte []byte
 	Value   string
+	File    string
+	Pos     ast.Pos
 }
 
 // Emerge() translates instruction to bytecode
@@ -44,10 +60,20 @@ func (a *Asm) Emerge(operator opcode.Type, operands ...[]byte) int {
 		return 0
 	}
 
+	a.locate(asmCode)
 	a.AsmCodes = append(a.AsmCodes, asmCode...)
 	return len(a.AsmCodes)
 }
 
+// Label() saves the current position of bytecode with name.
+func (a *Asm) Label(name string) {
+	if a.Labels == nil {
+		a.Labels = make(map[string]int)
+	}
+
+	a.Labels[name] = len(a.AsmCodes)
+}
+
 // EmergeAt() translates instruction to bytecode and append at index
 // An operand of operands should be 4 bytes.
 func (a *Asm) EmergeAt(index int, operator opcode.Type, operands ...[]byte) int {
@@ -56,15 +82,14 @@ func (a *Asm) EmergeAt(index int, operator opcode.Type, operands ...[]byte) int
 		return 0
 	}
 
+	a.locate(asmCode)
 	a.AsmCodes = append(a.AsmCodes[:index], append(asmCode, a.AsmCodes[index:]...)...)
 	return len(a.AsmCodes)
 }
 
 func (a *Asm) ReplaceOperandAt(index int, operands []byte) error {
-	a.AsmCodes[index] = AsmCode{
-		Value:   fmt.Sprintf("%x", operands),
-		RawByte: operands,
-	}
+	a.AsmCodes[index].Value = fmt.Sprintf("%x", operands)
+	a.AsmCodes[index].RawByte = operands
 	return nil
 }
 
@@ -74,13 +99,30 @@ func (a *Asm) ReplaceOperatorAt(index int, operator opcode.Type) error {
 		return err
 	}
 
-	a.AsmCodes[index] = AsmCode{
-		Value:   opStr,
-		RawByte: []byte{byte(operator)},
-	}
+	a.AsmCodes[index].Value = opStr
+	a.AsmCodes[index].RawByte = []byte{byte(operator)}
 	return nil
 }
 
+// at() sets the origin of the codes emerged after, and returns the
+// function which restores the previous origin.
+func (a *Asm) at(file string, pos ast.Pos) func() {
+	prevFile, prevLine, prevColumn := a.file, a.line, a.column
+	a.file, a.line, a.column = file, pos.Line, pos.Column
+
+	return func() {
+		a.file, a.line, a.column = prevFile, prevLine, prevColumn
+	}
+}
+
+// locate() sets the current origin to the codes.
+func (a *Asm) locate(codes []AsmCode) {
+	for i := range codes {
+		codes[i].File = a.file
+		codes[i].Pos = ast.Pos{Line: a.line, Column: a.column}
+	}
+}
+
 func (a *Asm) Equal(a1 Asm) bool {
 	if len(a.AsmCodes) != len(a1.AsmCodes) {
 		return false
@@ -352,3 +352,27 @@ func TestAsm_ToRawByteCode(t *testing.T) {
 		}
 	}
 }
+
+func TestAsm_Label(t *testing.T) {
+	asm := &translate.Asm{}
+
+	asm.Label("math.max")
+	asm.Emerge(opcode.Add)
+	asm.Emerge(opcode.Sub)
+	asm.Label("math.min")
+
+	tests := []struct {
+		name   string
+		expect int
+	}{
+		{"math.max", 0},
+		{"math.min", 2},
+	}
+
+	for i, tt := range tests {
+		if result := asm.Labels[tt.name]; result != tt.expect {
+			t.Errorf("test[%d] - got unexpected label of %s, expected=%d, got=%d",
+				i, tt.name, tt.expect, result)
+		}
+	}
+}
@@ -26,8 +26,18 @@ import (
 	"github.com/DE-labtory/koa/opcode"
 )
 
+// FuncMap manages the start point of functions in contract by their
+// selectors. The labels of function jumper are in Asm.Labels, so that
+// they can't collide with the selectors.
 type FuncMap map[string]int
 
+// Labels of the function jumper. They aren't identifiers, so they
+// can't be the qualified names of library functions.
+const (
+	funcJmprLabel = "$funcJmpr"
+	revertLabel   = "$revert"
+)
+
 // Declare() saves the start point of function.
 func (m FuncMap) Declare(signature string, asm Asm) {
 	funcSig := abi.Selector(signature)
@@ -42,6 +52,10 @@ func CompileContract(c ast.Contract) (Asm, error) {
 		AsmCodes: make([]AsmCode, 0),
 	}
 
+	if err := checkSelectors(c); err != nil {
+		return *asm, err
+	}
+
 	// Keep the size of the memory with createMemSizePlaceholder.
 	if err := createMemSizePlaceholder(asm); err != nil {
 		return *asm, err
@@ -49,17 +63,38 @@ func CompileContract(c ast.Contract) (Asm, error) {
 
 	// Keep the size of the jumper with createFuncJmprPlaceholder.
 	funcMap := FuncMap{}
-	if err := createFuncJmprPlaceholder(c, asm, funcMap); err != nil {
+	if err := createFuncJmprPlaceholder(c, asm); err != nil {
 		return *asm, err
 	}
 
-	// Compile the functions in contract.
 	memTracer := NewMemEntryTable()
 
+	// Compile the functions of libraries before the functions in contract.
+	// A library comes after the libraries it imports, so every function
+	// is labeled before it is called.
+	for _, l := range c.Libraries() {
+		for _, f := range l.Functions {
+			asm.Label(l.Qualify(f))
+			restore := asm.at(l.File, f.Start)
+
+			err := compileLibraryFunction(*f, asm, memTracer)
+			restore()
+
+			if err != nil {
+				return *asm, err
+			}
+		}
+	}
+
+	// Compile the functions in contract.
 	for _, f := range c.Functions {
 		funcMap.Declare(f.Signature(), *asm)
+		restore := asm.at(c.File, f.Start)
+
+		err := compileFunction(*f, asm, memTracer)
+		restore()
 
-		if err := compileFunction(*f, asm, memTracer); err != nil {
+		if err != nil {
 			return *asm, err
 		}
 	}
@@ -79,6 +114,31 @@ func CompileContract(c ast.Contract) (Asm, error) {
 	return *asm, nil
 }
 
+// checkSelectors checks that the functions of contract have different
+// selectors, otherwise the function jumper can't find the function.
+func checkSelectors(c ast.Contract) error {
+	signatures := make(map[string]string)
+
+	for _, f := range c.Functions {
+		signature := f.Signature()
+		selector := string(abi.Selector(signature))
+
+		other, ok := signatures[selector]
+		if ok && other == signature {
+			return fmt.Errorf("function %s is declared more than once", signature)
+		}
+
+		if ok {
+			return fmt.Errorf("selector %x of function %s collides with function %s",
+				selector, signature, other)
+		}
+
+		signatures[selector] = signature
+	}
+
+	return nil
+}
+
 // TODO: implement test cases :-)
 // Create a placeholder to calculate a size of the memory.
 // It emerges with the unmeaningful value.
@@ -95,7 +155,7 @@ func createMemSizePlaceholder(asm *Asm) error {
 
 // Create a placeholder to calculate a size of the function jumper.
 // It emerges with the unmeaningful value.
-func createFuncJmprPlaceholder(c ast.Contract, asm *Asm, funcMap FuncMap) error {
+func createFuncJmprPlaceholder(c ast.Contract, asm *Asm) error {
 	// Pushes the location of revert with the unmeaningful value.
 	if err := compileProgramEndPoint(asm, 0); err != nil {
 		return err
@@ -105,7 +165,7 @@ func createFuncJmprPlaceholder(c ast.Contract, asm *Asm, funcMap FuncMap) error
 	asm.Emerge(opcode.LoadFunc)
 
 	// Adds the logic to compare and find the corresponding function selector with the unmeaningful value.
-	funcMap.Declare("FuncJmpr", *asm)
+	asm.Label(funcJmprLabel)
 	for range c.Functions {
 		if err := compileFuncSel(asm, abi.Selector(""), 0); err != nil {
 			return err
@@ -113,7 +173,7 @@ func createFuncJmprPlaceholder(c ast.Contract, asm *Asm, funcMap FuncMap) error
 	}
 
 	// No match to any function selector, Revert!
-	funcMap.Declare("Revert", *asm)
+	asm.Label(revertLabel)
 	compileExit(asm)
 
 	return nil
@@ -158,7 +218,7 @@ func compileFuncJmpr(c ast.Contract, asm *Asm, funcMap FuncMap) error {
 	}
 
 	// Pushes the location of revert.
-	revertDst := funcMap[string(abi.Selector("Revert"))]
+	revertDst := asm.Labels[revertLabel]
 	if err := compileProgramEndPoint(funcJmpr, revertDst); err != nil {
 		return err
 	}
@@ -229,7 +289,13 @@ func ExtractAbi(c ast.Contract) (*abi.ABI, error) {
 		return nil, err
 	}
 
+	name := ""
+	if c.Name != nil {
+		name = c.Name.String()
+	}
+
 	return &abi.ABI{
+		Name:    name,
 		Methods: abiMethods,
 	}, nil
 }
@@ -260,14 +326,119 @@ func compileFunction(f ast.FunctionLiteral, bytecode *Asm, tracer *MemEntryTable
 		}
 	}
 
-	statements := f.Body.Statements
-	for _, s := range statements {
-		if err := compileStatement(s, bytecode, closedTracer); err != nil {
+	if err := compileFunctionBody(f.Modifiers, f.Body, bytecode, closedTracer); err != nil {
+		return err
+	}
+
+	tracer = closedTracer.Out()
+	return nil
+}
+
+// compileLibraryFunction() compiles a function of library.
+//
+// Caller pushes the return point, zero in place of function selector and
+// the arguments, so the arguments are saved in the memory in reverse order.
+// Then, Returning jumps back to the return point with the return value.
+//
+// If the function ends without return statement, it returns zero.
+func compileLibraryFunction(f ast.FunctionLiteral, bytecode *Asm, tracer *MemEntryTable) error {
+	closedTracer := NewEnclosedMemEntryTable(tracer)
+
+	entries := make([]MemEntry, 0)
+	for _, param := range f.Parameters {
+		entries = append(entries, closedTracer.Define(param.Identifier.String()))
+	}
+
+	for i := len(entries) - 1; i >= 0; i-- {
+		size, err := encoding.EncodeOperand(entries[i].Size)
+		if err != nil {
+			return err
+		}
+
+		offset, err := encoding.EncodeOperand(entries[i].Offset)
+		if err != nil {
 			return err
 		}
+
+		bytecode.Emerge(opcode.Push, size)
+		bytecode.Emerge(opcode.Push, offset)
+		bytecode.Emerge(opcode.Mstore)
 	}
 
-	tracer = closedTracer.Out()
+	if err := compileBlockStatement(f.Body, bytecode, closedTracer); err != nil {
+		return err
+	}
+
+	if err := compileReturnStatement(&ast.ReturnStatement{}, bytecode, closedTracer); err != nil {
+		return err
+	}
+
+	closedTracer.Out()
+	return nil
+}
+
+// compileFunctionBody() compiles the body of function with its modifiers.
+// Modifiers are inlined in the applied order, and the body of function
+// is compiled at the placeholder of the last modifier.
+func compileFunctionBody(modifiers []*ast.ModifierInvocation, body *ast.BlockStatement, bytecode *Asm, tracer *MemEntryTable) error {
+	if len(modifiers) == 0 {
+		return compileBlockStatement(body, bytecode, tracer)
+	}
+
+	return compileModifier(modifiers[0], bytecode, tracer, func(t *MemEntryTable) error {
+		return compileFunctionBody(modifiers[1:], body, bytecode, t)
+	})
+}
+
+// compileModifier() inlines a modifier applied to function.
+//
+// The arguments are saved in the memory as the parameters of modifier,
+// then the statements of modifier are compiled. At the placeholder,
+// compilePlaceholder compiles the wrapped code with the tracer of function.
+//
+// Modifier has its own memory entry table, so the names in modifier
+// don't conflict with the names in function.
+func compileModifier(m *ast.ModifierInvocation, bytecode *Asm, tracer *MemEntryTable, compilePlaceholder func(*MemEntryTable) error) error {
+	modTracer := NewEnclosedMemEntryTable(tracer)
+
+	for i, param := range m.Modifier.Parameters {
+		if err := compileExpression(m.Arguments[i], bytecode, tracer); err != nil {
+			return err
+		}
+
+		entry := modTracer.Define(param.Identifier.String())
+		size, err := encoding.EncodeOperand(entry.Size)
+		if err != nil {
+			return err
+		}
+
+		offset, err := encoding.EncodeOperand(entry.Offset)
+		if err != nil {
+			return err
+		}
+
+		bytecode.Emerge(opcode.Push, size)
+		bytecode.Emerge(opcode.Push, offset)
+		bytecode.Emerge(opcode.Mstore)
+	}
+
+	for _, s := range m.Modifier.Body.Statements {
+		if _, ok := s.(*ast.PlaceholderStatement); !ok {
+			if err := compileStatement(s, bytecode, modTracer); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// The wrapped code uses the memory after the modifier's.
+		modTracer.Out()
+		if err := compilePlaceholder(tracer); err != nil {
+			return err
+		}
+		modTracer.MemoryCounter = tracer.MemoryCounter
+	}
+
+	modTracer.Out()
 	return nil
 }
 
@@ -301,7 +472,13 @@ func compileParameter(p ast.ParameterLiteral, argNum int, bytecode *Asm, tracer
 
 // compileStatement() compiles a statement in function.
 // Generates and adds output to bytecode.
+//
+// The codes of statement are located at the start of statement.
 func compileStatement(s ast.Statement, bytecode *Asm, tracer MemTracer) error {
+	if p, ok := s.(interface{ Position() ast.Span }); ok && p.Position().Start.IsValid() {
+		defer bytecode.at(bytecode.file, p.Position().Start)()
+	}
+
 	switch statement := s.(type) {
 	case *ast.AssignStatement:
 		return compileAssignStatement(statement, bytecode, tracer)
@@ -318,6 +495,18 @@ func compileStatement(s ast.Statement, bytecode *Asm, tracer MemTracer) error {
 	case *ast.ExpressionStatement:
 		return compileExpressionStatement(statement, bytecode, tracer)
 
+	case *ast.RequireStatement:
+		return compileRequireStatement(statement, bytecode, tracer)
+
+	case *ast.StoreStatement:
+		return compileStoreStatement(statement, bytecode, tracer)
+
+	case *ast.EmitStatement:
+		return compileEmitStatement(statement, bytecode, tracer)
+
+	case *ast.PlaceholderStatement:
+		return errors.New("placeholder \"_\" is only allowed in modifier")
+
 	default:
 		return nil
 	}
@@ -451,6 +640,90 @@ func compileIfElse(s *ast.IfStatement, asm *Asm, tracer MemTracer) error {
 	return nil
 }
 
+// compileRequireStatement() compiles a 'require statement'.
+//
+// Ex)
+//
+// translate
+// 	'require(expression)'
+// to
+// 	'push <expression> NOT push <pc-to-end-of-revert> jumpi Revert'
+//
+func compileRequireStatement(s *ast.RequireStatement, asm *Asm, tracer MemTracer) error {
+	if err := compileExpression(s.Condition, asm, tracer); err != nil {
+		return err
+	}
+
+	asm.Emerge(opcode.NOT)
+	asm.Emerge(opcode.Push, []byte(fmt.Sprintf("%d", -1)))
+	// 'push <expression> NOT push <-1(will be replaced)>'
+
+	l1 := len(asm.AsmCodes)
+	asm.Emerge(opcode.Jumpi)
+	asm.Emerge(opcode.Revert)
+	// 'push <expression> NOT push <-1(will be replaced)> jumpi Revert'
+
+	l2 := len(asm.AsmCodes)
+	pc2end, err := encoding.EncodeOperand(l2)
+	if err != nil {
+		return err
+	}
+
+	if err := asm.ReplaceOperandAt(l1-1, pc2end); err != nil {
+		return err
+	}
+	// 'push <expression> NOT push <pc-to-end-of-revert> jumpi Revert'
+
+	return nil
+}
+
+// compileStoreStatement() compiles a store statement.
+//
+// Ex)
+//
+// translate
+// 	'store(key, value)'
+// to
+// 	'<key> <value> SStore'
+//
+func compileStoreStatement(s *ast.StoreStatement, asm *Asm, tracer MemTracer) error {
+	if err := compileExpression(s.Key, asm, tracer); err != nil {
+		return err
+	}
+
+	if err := compileExpression(s.Value, asm, tracer); err != nil {
+		return err
+	}
+
+	asm.Emerge(opcode.SStore)
+
+	return nil
+}
+
+// compileEmitStatement() compiles a emit statement.
+//
+// Ex)
+//
+// translate
+// 	'emit(a, b)'
+// to
+// 	'<a> <b> push 2 Log'
+//
+func compileEmitStatement(s *ast.EmitStatement, asm *Asm, tracer MemTracer) error {
+	for _, v := range s.Values {
+		if err := compileExpression(v, asm, tracer); err != nil {
+			return err
+		}
+	}
+
+	if err := compilePrimitive(len(s.Values), asm); err != nil {
+		return err
+	}
+	asm.Emerge(opcode.Log)
+
+	return nil
+}
+
 func compileIf(s *ast.IfStatement, asm *Asm, tracer MemTracer) error {
 	// 'push <expression>
 
@@ -504,7 +777,13 @@ func compileExpressionStatement(s *ast.ExpressionStatement, bytecode *Asm, trace
 func compileExpression(e ast.Expression, asm *Asm, tracer MemTracer) error {
 	switch expr := e.(type) {
 	case *ast.CallExpression:
-		return compileCallExpression(expr, asm)
+		return compileCallExpression(expr, asm, tracer)
+
+	case *ast.ContractCallExpression:
+		return compileContractCallExpression(expr, asm, tracer)
+
+	case *ast.LoadExpression:
+		return compileLoadExpression(expr, asm, tracer)
 
 	case *ast.InfixExpression:
 		return compileInfixExpression(expr, asm, tracer)
@@ -512,6 +791,12 @@ func compileExpression(e ast.Expression, asm *Asm, tracer MemTracer) error {
 	case *ast.PrefixExpression:
 		return compilePrefixExpression(expr, asm, tracer)
 
+	case *ast.ConditionalExpression:
+		return compileConditionalExpression(expr, asm, tracer)
+
+	case *ast.ConversionExpression:
+		return compileConversionExpression(expr, asm, tracer)
+
 	case *ast.IntegerLiteral:
 		return compilePrimitive(expr.Value, asm)
 
@@ -521,6 +806,9 @@ func compileExpression(e ast.Expression, asm *Asm, tracer MemTracer) error {
 	case *ast.BooleanLiteral:
 		return compilePrimitive(expr.Value, asm)
 
+	case *ast.EnumMemberLiteral:
+		return compilePrimitive(expr.Value, asm)
+
 	case *ast.Identifier:
 		return compileIdentifier(expr, asm, tracer)
 
@@ -529,12 +817,121 @@ func compileExpression(e ast.Expression, asm *Asm, tracer MemTracer) error {
 	}
 }
 
-// TODO: implement me w/ test cases :-)
-func compileCallExpression(e *ast.CallExpression, asm *Asm) error {
+// compileCallExpression() compiles a call of library function.
+//
+// Ex)
+//
+// translate
+// 	'math.max(a, b)'
+// to
+// 	'push <pc-to-jumpdst> push 0 <a> <b> push <start-of-math.max> jump jumpdst'
+//
+// Zero is in place of function selector, so that Returning of the
+// function jumps back to jumpdst with its return value.
+func compileCallExpression(e *ast.CallExpression, asm *Asm, tracer MemTracer) error {
+	fn, ok := e.Function.(*ast.QualifiedIdentifier)
+	if !ok {
+		return fmt.Errorf("can't call %s, only the functions of library can be called", e.Function.String())
+	}
+
+	start, ok := asm.Labels[fn.String()]
+	if !ok {
+		return fmt.Errorf("function %s is not compiled", fn.String())
+	}
+
+	asm.Emerge(opcode.Push, []byte(fmt.Sprintf("%d", -1)))
+	// 'push <-1(will be replaced)>'
+
+	l1 := len(asm.AsmCodes)
+	if err := compilePrimitive(0, asm); err != nil {
+		return err
+	}
+
+	for _, arg := range e.Arguments {
+		if err := compileExpression(arg, asm, tracer); err != nil {
+			return err
+		}
+	}
+
+	if err := compilePrimitive(start, asm); err != nil {
+		return err
+	}
+	asm.Emerge(opcode.Jump)
+	// 'push <-1(will be replaced)> push 0 <a> <b> push <start-of-math.max> jump'
+
+	l2 := len(asm.AsmCodes)
+	asm.Emerge(opcode.JumpDst)
+
+	pc2jumpdst, err := encoding.EncodeOperand(l2)
+	if err != nil {
+		return err
+	}
+
+	asm.ReplaceOperandAt(l1-1, pc2jumpdst)
+	// 'push <pc-to-jumpdst> push 0 <a> <b> push <start-of-math.max> jump jumpdst'
+
+	return nil
+}
+
+// compileContractCallExpression() compiles a call of function in other contract.
+//
+// Ex)
+//
+// translate
+// 	'call(addr, "transfer(int,int) bool", to, amount)'
+// to
+// 	'<addr> push <selector-of-transfer(int,int)> <to> <amount> push 2 call'
+//
+func compileContractCallExpression(e *ast.ContractCallExpression, asm *Asm, tracer MemTracer) error {
+	if err := compileExpression(e.Address, asm, tracer); err != nil {
+		return err
+	}
+
+	selector, err := encoding.EncodeOperand(abi.Selector(e.Signature))
+	if err != nil {
+		return err
+	}
+	asm.Emerge(opcode.Push, selector)
+
+	for _, arg := range e.Arguments {
+		if err := compileExpression(arg, asm, tracer); err != nil {
+			return err
+		}
+	}
+
+	if err := compilePrimitive(len(e.Arguments), asm); err != nil {
+		return err
+	}
+	asm.Emerge(opcode.Call)
+
+	return nil
+}
+
+// compileLoadExpression() compiles a load expression.
+//
+// Ex)
+//
+// translate
+// 	'load(key)'
+// to
+// 	'<key> SLoad'
+//
+func compileLoadExpression(e *ast.LoadExpression, asm *Asm, tracer MemTracer) error {
+	if err := compileExpression(e.Key, asm, tracer); err != nil {
+		return err
+	}
+
+	asm.Emerge(opcode.SLoad)
+
 	return nil
 }
 
 func compileInfixExpression(e *ast.InfixExpression, asm *Asm, tracer MemTracer) error {
+	switch e.Operator {
+	case ast.LAND, ast.LOR:
+		return compileLogicalExpression(e, asm, tracer)
+	}
+
 	if err := compileExpression(e.Left, asm, tracer); err != nil {
 		return err
 	}
@@ -569,10 +966,18 @@ func compileInfixExpression(e *ast.InfixExpression, asm *Asm, tracer MemTracer)
 	case ast.NOT_EQ:
 		asm.Emerge(opcode.EQ)
 		asm.Emerge(opcode.NOT)
-	case ast.LAND:
-		asm.Emerge(opcode.And)
-	case ast.LOR:
-		asm.Emerge(opcode.Or)
+
+		//bitwise
+	case ast.BitAnd:
+		asm.Emerge(opcode.BitAnd)
+	case ast.BitOr:
+		asm.Emerge(opcode.BitOr)
+	case ast.BitXor:
+		asm.Emerge(opcode.BitXor)
+	case ast.Shl:
+		asm.Emerge(opcode.Shl)
+	case ast.Shr:
+		asm.Emerge(opcode.Shr)
 
 	default:
 		return fmt.Errorf("Undefined operator %s", e.Operator.String())
@@ -581,6 +986,149 @@ func compileInfixExpression(e *ast.InfixExpression, asm *Asm, tracer MemTracer)
 	return nil
 }
 
+// compileLogicalExpression() compiles '&&' and '||' with short-circuit evaluation.
+// The right operand is evaluated only when the left operand can't decide the result.
+//
+// Ex)
+//
+// translate
+// 	'a && b'
+// to
+// 	'<a> DUP push <pc-to-end-of-b> jumpi pop <b>'
+//
+// translate
+// 	'a || b'
+// to
+// 	'<a> DUP NOT push <pc-to-end-of-b> jumpi pop <b>'
+//
+// If the jump is taken, the duplicated left operand remains on the stack as the result.
+func compileLogicalExpression(e *ast.InfixExpression, asm *Asm, tracer MemTracer) error {
+	if err := compileExpression(e.Left, asm, tracer); err != nil {
+		return err
+	}
+
+	asm.Emerge(opcode.DUP)
+	if e.Operator == ast.LOR {
+		asm.Emerge(opcode.NOT)
+	}
+
+	asm.Emerge(opcode.Push, []byte(fmt.Sprintf("%d", -1)))
+	// '<a> DUP push <-1(will be replaced)>'
+
+	l1 := len(asm.AsmCodes)
+	asm.Emerge(opcode.Jumpi)
+	asm.Emerge(opcode.Pop)
+	// '<a> DUP push <-1(will be replaced)> jumpi pop'
+
+	if err := compileExpression(e.Right, asm, tracer); err != nil {
+		return err
+	}
+	// '<a> DUP push <-1(will be replaced)> jumpi pop <b>'
+
+	l2 := len(asm.AsmCodes)
+	pc2end, err := encoding.EncodeOperand(l2)
+	if err != nil {
+		return err
+	}
+
+	if err := asm.ReplaceOperandAt(l1-1, pc2end); err != nil {
+		return err
+	}
+	// '<a> DUP push <pc-to-end-of-b> jumpi pop <b>'
+
+	return nil
+}
+
+// compileConditionalExpression() compiles a conditional expression.
+//
+// Ex)
+//
+// translate
+// 	'condition ? consequence : alternative'
+// to
+// 	'<condition> push <pc-to-alternative> jumpi <consequence> push <pc-to-end-of-alternative> jump <alternative>'
+//
+func compileConditionalExpression(e *ast.ConditionalExpression, asm *Asm, tracer MemTracer) error {
+	if err := compileExpression(e.Condition, asm, tracer); err != nil {
+		return err
+	}
+
+	asm.Emerge(opcode.Push, []byte(fmt.Sprintf("%d", -1)))
+	// '<condition> push <-1(will be replaced)>'
+
+	l1 := len(asm.AsmCodes)
+	asm.Emerge(opcode.Jumpi)
+	if err := compileExpression(e.Consequence, asm, tracer); err != nil {
+		return err
+	}
+	// '<condition> push <-1(will be replaced)> jumpi <consequence>'
+
+	asm.Emerge(opcode.Push, []byte(fmt.Sprintf("%d", -1)))
+	l2 := len(asm.AsmCodes)
+	asm.Emerge(opcode.Jump)
+	// '<condition> push <-1(will be replaced)> jumpi <consequence> push <-1(will be replaced)> jump'
+
+	if err := compileExpression(e.Alternative, asm, tracer); err != nil {
+		return err
+	}
+	// '<condition> push <-1(will be replaced)> jumpi <consequence> push <-1(will be replaced)> jump <alternative>'
+
+	l3 := len(asm.AsmCodes)
+	pc2al, err := encoding.EncodeOperand(l2 + 1)
+	if err != nil {
+		return err
+	}
+	if err := asm.ReplaceOperandAt(l1-1, pc2al); err != nil {
+		return err
+	}
+
+	pc2EndOfAlter, err := encoding.EncodeOperand(l3)
+	if err != nil {
+		return err
+	}
+	if err := asm.ReplaceOperandAt(l2-1, pc2EndOfAlter); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// compileConversionExpression() compiles a type conversion.
+// Conversion to the same type and bool to int generate nothing,
+// because booleans are already 0 or 1. Enums are converted as int.
+func compileConversionExpression(e *ast.ConversionExpression, asm *Asm, tracer MemTracer) error {
+	if err := compileExpression(e.Value, asm, tracer); err != nil {
+		return err
+	}
+
+	valueType := e.ValueType
+	if valueType == ast.EnumType {
+		valueType = ast.IntType
+	}
+
+	if valueType == e.Type {
+		return nil
+	}
+
+	switch {
+	case valueType == ast.IntType && e.Type == ast.StringType:
+		asm.Emerge(opcode.IntToString)
+	case valueType == ast.StringType && e.Type == ast.IntType:
+		asm.Emerge(opcode.StringToInt)
+	case valueType == ast.IntType && e.Type == ast.BoolType:
+		asm.Emerge(opcode.IntToBool)
+	case valueType == ast.BoolType && e.Type == ast.IntType:
+	case valueType == ast.BoolType && e.Type == ast.StringType:
+		asm.Emerge(opcode.BoolToString)
+	case valueType == ast.StringType && e.Type == ast.BoolType:
+		asm.Emerge(opcode.StringToBool)
+	default:
+		return fmt.Errorf("can't convert [%s] to [%s]", e.ValueType.String(), e.Type.String())
+	}
+
+	return nil
+}
+
 func compilePrefixExpression(e *ast.PrefixExpression, asm *Asm, tracer MemTracer) error {
 	if err := compileExpression(e.Right, asm, tracer); err != nil {
 		return err
@@ -591,6 +1139,8 @@ func compilePrefixExpression(e *ast.PrefixExpression, asm *Asm, tracer MemTracer
 		asm.Emerge(opcode.NOT)
 	case ast.Minus:
 		asm.Emerge(opcode.Minus)
+	case ast.BitNot:
+		asm.Emerge(opcode.BitNot)
 	default:
 		return fmt.Errorf("unknown operator %s", e.Operator.String())
 	}
@@ -19,6 +19,7 @@ package translate
 import (
 	"errors"
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/DE-labtory/koa/abi"
@@ -48,10 +49,10 @@ type statementCompileTestCase struct {
 
 func TestCreateFuncJmprPlaceholder(t *testing.T) {
 	tests := []struct {
-		contract      ast.Contract
-		expectAsm     *Asm
-		expectFuncMap FuncMap
-		err           error
+		contract     ast.Contract
+		expectAsm    *Asm
+		expectLabels map[string]int
+		err          error
 	}{
 		{
 			contract: ast.Contract{
@@ -124,9 +125,9 @@ func TestCreateFuncJmprPlaceholder(t *testing.T) {
 					},
 				},
 			},
-			expectFuncMap: FuncMap{
-				string(abi.Selector("FuncJmpr")): 3,
-				string(abi.Selector("Revert")):   11,
+			expectLabels: map[string]int{
+				funcJmprLabel: 3,
+				revertLabel:   11,
 			},
 			err: nil,
 		},
@@ -136,16 +137,14 @@ func TestCreateFuncJmprPlaceholder(t *testing.T) {
 		asm := &Asm{
 			AsmCodes: []AsmCode{},
 		}
-		funcMap := FuncMap{}
-
-		err := createFuncJmprPlaceholder(test.contract, asm, funcMap)
+		err := createFuncJmprPlaceholder(test.contract, asm)
 
 		if !asm.Equal(*test.expectAsm) {
 			t.Fatalf("test[%d] - createFuncJmprPlaceholder() bytecode result wrong.\nexpected=%v,\ngot=%v", i, test.expectAsm, asm)
 		}
 
-		if !compareFuncMap(funcMap, test.expectFuncMap) {
-			t.Fatalf("test[%d] - createFuncJmprPlaceholder() FuncMap result wrong.\nexpected=%v,\ngot=%v", i, test.expectFuncMap, funcMap)
+		if !reflect.DeepEqual(asm.Labels, test.expectLabels) {
+			t.Fatalf("test[%d] - createFuncJmprPlaceholder() labels result wrong.\nexpected=%v,\ngot=%v", i, test.expectLabels, asm.Labels)
 		}
 
 		if err != nil && err != test.err {
@@ -374,6 +373,7 @@ func TestCompileFuncJmpr(t *testing.T) {
 						Value:   "Returning",
 					},
 				},
+				Labels: map[string]int{revertLabel: 10},
 			},
 			expectAsm: &Asm{
 				AsmCodes: []AsmCode{
@@ -487,10 +487,8 @@ func TestCompileFuncJmpr(t *testing.T) {
 				},
 			},
 			funcMap: FuncMap{
-				string(abi.Selector("FuncJmpr")): 3,
-				string(abi.Selector("Revert")):   10,
-				string(abi.Selector("foo()")):    19,
-				string(abi.Selector("sam()")):    24,
+				string(abi.Selector("foo()")): 19,
+				string(abi.Selector("sam()")): 24,
 			},
 			err: nil,
 		},
@@ -1087,6 +1085,274 @@ func TestCompileFunction(t *testing.T) {
 	}
 }
 
+func TestCompileFunction_modifier(t *testing.T) {
+	//
+	// contract{
+	//    modifier positive(n int) {
+	//       require(n > 0)
+	//       _
+	//       int c = 1
+	//    }
+	//    func foo(a int) positive(a) {
+	//       int b = 5
+	//    }
+	// }
+	//
+	positive := &ast.ModifierLiteral{
+		Name: &ast.Identifier{Name: "positive"},
+		Parameters: []*ast.ParameterLiteral{
+			{
+				Type:       ast.IntType,
+				Identifier: &ast.Identifier{Name: "n"},
+			},
+		},
+		Body: &ast.BlockStatement{
+			Statements: []ast.Statement{
+				&ast.RequireStatement{
+					Condition: &ast.InfixExpression{
+						Left:     &ast.Identifier{Name: "n"},
+						Operator: ast.GT,
+						Right:    &ast.IntegerLiteral{Value: 0},
+					},
+				},
+				&ast.PlaceholderStatement{},
+				&ast.AssignStatement{
+					Type:     ast.IntType,
+					Variable: ast.Identifier{Name: "c"},
+					Value:    &ast.IntegerLiteral{Value: 1},
+				},
+			},
+		},
+	}
+
+	f := ast.FunctionLiteral{
+		Name: &ast.Identifier{Name: "foo"},
+		Parameters: []*ast.ParameterLiteral{
+			{
+				Type:       ast.IntType,
+				Identifier: &ast.Identifier{Name: "a"},
+			},
+		},
+		Modifiers: []*ast.ModifierInvocation{
+			{
+				Modifier:  positive,
+				Arguments: []ast.Expression{&ast.Identifier{Name: "a"}},
+			},
+		},
+		Body: &ast.BlockStatement{
+			Statements: []ast.Statement{
+				&ast.AssignStatement{
+					Type:     ast.IntType,
+					Variable: ast.Identifier{Name: "b"},
+					Value:    &ast.IntegerLiteral{Value: 5},
+				},
+			},
+		},
+	}
+
+	expected := Asm{
+		AsmCodes: []AsmCode{
+			{
+				RawByte: []byte{byte(opcode.Push)},
+				Value:   "Push",
+			},
+			{
+				RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+				Value:   "0000000000000000",
+			},
+			{
+				RawByte: []byte{byte(opcode.LoadArgs)},
+				Value:   "LoadArgs",
+			},
+			{
+				RawByte: []byte{byte(opcode.Push)},
+				Value:   "Push",
+			},
+			{
+				RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x08},
+				Value:   "0000000000000008",
+			},
+			{
+				RawByte: []byte{byte(opcode.Push)},
+				Value:   "Push",
+			},
+			{
+				RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+				Value:   "0000000000000000",
+			},
+			{
+				RawByte: []byte{byte(opcode.Mstore)},
+				Value:   "Mstore",
+			},
+			{
+				RawByte: []byte{byte(opcode.Push)},
+				Value:   "Push",
+			},
+			{
+				RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x08},
+				Value:   "0000000000000008",
+			},
+			{
+				RawByte: []byte{byte(opcode.Push)},
+				Value:   "Push",
+			},
+			{
+				RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+				Value:   "0000000000000000",
+			},
+			{
+				RawByte: []byte{byte(opcode.Mload)},
+				Value:   "Mload",
+			},
+			{
+				RawByte: []byte{byte(opcode.Push)},
+				Value:   "Push",
+			},
+			{
+				RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x08},
+				Value:   "0000000000000008",
+			},
+			{
+				RawByte: []byte{byte(opcode.Push)},
+				Value:   "Push",
+			},
+			{
+				RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x08},
+				Value:   "0000000000000008",
+			},
+			{
+				RawByte: []byte{byte(opcode.Mstore)},
+				Value:   "Mstore",
+			},
+			{
+				RawByte: []byte{byte(opcode.Push)},
+				Value:   "Push",
+			},
+			{
+				RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x08},
+				Value:   "0000000000000008",
+			},
+			{
+				RawByte: []byte{byte(opcode.Push)},
+				Value:   "Push",
+			},
+			{
+				RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x08},
+				Value:   "0000000000000008",
+			},
+			{
+				RawByte: []byte{byte(opcode.Mload)},
+				Value:   "Mload",
+			},
+			{
+				RawByte: []byte{byte(opcode.Push)},
+				Value:   "Push",
+			},
+			{
+				RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+				Value:   "0000000000000000",
+			},
+			{
+				RawByte: []byte{byte(opcode.GT)},
+				Value:   "GT",
+			},
+			{
+				RawByte: []byte{byte(opcode.NOT)},
+				Value:   "NOT",
+			},
+			{
+				RawByte: []byte{byte(opcode.Push)},
+				Value:   "Push",
+			},
+			{
+				RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x1f},
+				Value:   "000000000000001f",
+			},
+			{
+				RawByte: []byte{byte(opcode.Jumpi)},
+				Value:   "Jumpi",
+			},
+			{
+				RawByte: []byte{byte(opcode.Revert)},
+				Value:   "Revert",
+			},
+			{
+				RawByte: []byte{byte(opcode.Push)},
+				Value:   "Push",
+			},
+			{
+				RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x05},
+				Value:   "0000000000000005",
+			},
+			{
+				RawByte: []byte{byte(opcode.Push)},
+				Value:   "Push",
+			},
+			{
+				RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x08},
+				Value:   "0000000000000008",
+			},
+			{
+				RawByte: []byte{byte(opcode.Push)},
+				Value:   "Push",
+			},
+			{
+				RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x10},
+				Value:   "0000000000000010",
+			},
+			{
+				RawByte: []byte{byte(opcode.Mstore)},
+				Value:   "Mstore",
+			},
+			{
+				RawByte: []byte{byte(opcode.Push)},
+				Value:   "Push",
+			},
+			{
+				RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+				Value:   "0000000000000001",
+			},
+			{
+				RawByte: []byte{byte(opcode.Push)},
+				Value:   "Push",
+			},
+			{
+				RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x08},
+				Value:   "0000000000000008",
+			},
+			{
+				RawByte: []byte{byte(opcode.Push)},
+				Value:   "Push",
+			},
+			{
+				RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x18},
+				Value:   "0000000000000018",
+			},
+			{
+				RawByte: []byte{byte(opcode.Mstore)},
+				Value:   "Mstore",
+			},
+		},
+	}
+
+	a := &Asm{
+		AsmCodes: make([]AsmCode, 0),
+	}
+
+	memTracer := NewMemEntryTable()
+	if err := compileFunction(f, a, memTracer); err != nil {
+		t.Fatalf("compileFunction() returns error. %v", err)
+	}
+
+	if memTracer.MemoryCounter != 32 {
+		t.Fatalf("MemoryCounter is wrong. expected=%d, got=%d", 32, memTracer.MemoryCounter)
+	}
+
+	if !a.Equal(expected) {
+		t.Fatalf("result wrong. \nexpected %x, \ngot=%x", expected, a)
+	}
+}
+
 // TODO: implement test cases :-)
 func TestCompileParameter(t *testing.T) {
 
@@ -1618,6 +1884,218 @@ func TestCompileIfStatement(t *testing.T) {
 	}
 }
 
+func TestCompileRequireStatement(t *testing.T) {
+	tests := []statementCompileTestCase{
+		// require(true)
+		{
+			setupTracer: defaultSetupTracer,
+			statement: &ast.RequireStatement{
+				Condition: &ast.BooleanLiteral{Value: true},
+			},
+			expected: Asm{
+				AsmCodes: []AsmCode{
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+						Value:   "0000000000000001",
+					},
+					{
+						RawByte: []byte{byte(opcode.NOT)},
+						Value:   "NOT",
+					},
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x07},
+						Value:   "0000000000000007",
+					},
+					{
+						RawByte: []byte{byte(opcode.Jumpi)},
+						Value:   "Jumpi",
+					},
+					{
+						RawByte: []byte{byte(opcode.Revert)},
+						Value:   "Revert",
+					},
+				},
+			},
+		},
+		// require(1 < 2)
+		{
+			setupTracer: defaultSetupTracer,
+			statement: &ast.RequireStatement{
+				Condition: &ast.InfixExpression{
+					Left:     &ast.IntegerLiteral{Value: 1},
+					Operator: ast.LT,
+					Right:    &ast.IntegerLiteral{Value: 2},
+				},
+			},
+			expected: Asm{
+				AsmCodes: []AsmCode{
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+						Value:   "0000000000000001",
+					},
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02},
+						Value:   "0000000000000002",
+					},
+					{
+						RawByte: []byte{byte(opcode.LT)},
+						Value:   "LT",
+					},
+					{
+						RawByte: []byte{byte(opcode.NOT)},
+						Value:   "NOT",
+					},
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0a},
+						Value:   "000000000000000a",
+					},
+					{
+						RawByte: []byte{byte(opcode.Jumpi)},
+						Value:   "Jumpi",
+					},
+					{
+						RawByte: []byte{byte(opcode.Revert)},
+						Value:   "Revert",
+					},
+				},
+			},
+		},
+	}
+
+	runStatementCompileTests(t, tests)
+}
+
+func TestCompileStoreStatement(t *testing.T) {
+	tests := []statementCompileTestCase{
+		// store(1, 5)
+		{
+			setupTracer: defaultSetupTracer,
+			statement: &ast.StoreStatement{
+				Key:   &ast.IntegerLiteral{Value: 1},
+				Value: &ast.IntegerLiteral{Value: 5},
+			},
+			expected: Asm{
+				AsmCodes: []AsmCode{
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+						Value:   "0000000000000001",
+					},
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x05},
+						Value:   "0000000000000005",
+					},
+					{
+						RawByte: []byte{byte(opcode.SStore)},
+						Value:   "SStore",
+					},
+				},
+			},
+		},
+	}
+
+	runStatementCompileTests(t, tests)
+}
+
+func TestCompileEmitStatement(t *testing.T) {
+	tests := []statementCompileTestCase{
+		// emit(3, 4)
+		{
+			setupTracer: defaultSetupTracer,
+			statement: &ast.EmitStatement{
+				Values: []ast.Expression{
+					&ast.IntegerLiteral{Value: 3},
+					&ast.IntegerLiteral{Value: 4},
+				},
+			},
+			expected: Asm{
+				AsmCodes: []AsmCode{
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03},
+						Value:   "0000000000000003",
+					},
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x04},
+						Value:   "0000000000000004",
+					},
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02},
+						Value:   "0000000000000002",
+					},
+					{
+						RawByte: []byte{byte(opcode.Log)},
+						Value:   "Log",
+					},
+				},
+			},
+		},
+		// emit()
+		{
+			setupTracer: defaultSetupTracer,
+			statement: &ast.EmitStatement{
+				Values: []ast.Expression{},
+			},
+			expected: Asm{
+				AsmCodes: []AsmCode{
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+						Value:   "0000000000000000",
+					},
+					{
+						RawByte: []byte{byte(opcode.Log)},
+						Value:   "Log",
+					},
+				},
+			},
+		},
+	}
+
+	runStatementCompileTests(t, tests)
+}
+
 func TestCompileBlockStatement(t *testing.T) {
 	statements := makeTempStatements()
 
@@ -1763,9 +2241,247 @@ func TestCompileExpression(t *testing.T) {
 
 }
 
-// TODO: implement test cases :-)
 func TestCompileCallExpression(t *testing.T) {
+	max := &ast.FunctionLiteral{
+		Name: &ast.Identifier{Name: "max"},
+		Parameters: []*ast.ParameterLiteral{
+			{Identifier: &ast.Identifier{Name: "a"}, Type: ast.IntType},
+			{Identifier: &ast.Identifier{Name: "b"}, Type: ast.IntType},
+		},
+		Body:       &ast.BlockStatement{},
+		ReturnType: ast.IntType,
+	}
+	min := &ast.FunctionLiteral{
+		Name:       &ast.Identifier{Name: "min"},
+		Parameters: []*ast.ParameterLiteral{},
+		Body:       &ast.BlockStatement{},
+		ReturnType: ast.IntType,
+	}
+	math := &ast.Library{
+		Name:      "math",
+		Functions: []*ast.FunctionLiteral{max, min},
+	}
 
+	tests := []struct {
+		expression  *ast.CallExpression
+		expected    Asm
+		expectedErr error
+	}{
+		{
+			expression: &ast.CallExpression{
+				Function: &ast.QualifiedIdentifier{Library: math, Function: max},
+				Arguments: []ast.Expression{
+					&ast.IntegerLiteral{Value: 1},
+					&ast.IntegerLiteral{Value: 2},
+				},
+			},
+			expected: Asm{
+				AsmCodes: []AsmCode{
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0b},
+						Value:   "000000000000000b",
+					},
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+						Value:   "0000000000000000",
+					},
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+						Value:   "0000000000000001",
+					},
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02},
+						Value:   "0000000000000002",
+					},
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03},
+						Value:   "0000000000000003",
+					},
+					{
+						RawByte: []byte{byte(opcode.Jump)},
+						Value:   "Jump",
+					},
+					{
+						RawByte: []byte{byte(opcode.JumpDst)},
+						Value:   "JumpDst",
+					},
+				},
+			},
+			expectedErr: nil,
+		},
+		{
+			expression: &ast.CallExpression{
+				Function:  &ast.QualifiedIdentifier{Library: math, Function: min},
+				Arguments: []ast.Expression{},
+			},
+			expected:    Asm{},
+			expectedErr: errors.New("function math.min is not compiled"),
+		},
+		{
+			expression: &ast.CallExpression{
+				Function:  &ast.Identifier{Name: "foo"},
+				Arguments: []ast.Expression{},
+			},
+			expected:    Asm{},
+			expectedErr: errors.New("can't call foo, only the functions of library can be called"),
+		},
+	}
+
+	for i, test := range tests {
+		asm := &Asm{
+			AsmCodes: make([]AsmCode, 0),
+			Labels:   map[string]int{"math.max": 3},
+		}
+
+		err := compileCallExpression(test.expression, asm, NewMemEntryTable())
+		if err != nil && (test.expectedErr == nil || err.Error() != test.expectedErr.Error()) {
+			t.Fatalf("test[%d] - compileCallExpression() had wrong error. expected=%v, got=%v",
+				i, test.expectedErr, err)
+		}
+
+		if err == nil && test.expectedErr != nil {
+			t.Fatalf("test[%d] - compileCallExpression() should return error. expected=%v", i, test.expectedErr)
+		}
+
+		if err == nil && !asm.Equal(test.expected) {
+			t.Fatalf("test[%d] - compileCallExpression() result wrong.\nexpected=%v,\ngot=%v",
+				i, test.expected.String(), asm.String())
+		}
+	}
+}
+
+func TestCompileLibraryFunction(t *testing.T) {
+	fn := ast.FunctionLiteral{
+		Name: &ast.Identifier{Name: "first"},
+		Parameters: []*ast.ParameterLiteral{
+			{Identifier: &ast.Identifier{Name: "a"}, Type: ast.IntType},
+			{Identifier: &ast.Identifier{Name: "b"}, Type: ast.IntType},
+		},
+		Body: &ast.BlockStatement{
+			Statements: []ast.Statement{
+				&ast.ReturnStatement{ReturnValue: &ast.Identifier{Name: "a"}},
+			},
+		},
+		ReturnType: ast.IntType,
+	}
+
+	expected := Asm{
+		AsmCodes: []AsmCode{
+			{
+				RawByte: []byte{byte(opcode.Push)},
+				Value:   "Push",
+			},
+			{
+				RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x08},
+				Value:   "0000000000000008",
+			},
+			{
+				RawByte: []byte{byte(opcode.Push)},
+				Value:   "Push",
+			},
+			{
+				RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x08},
+				Value:   "0000000000000008",
+			},
+			{
+				RawByte: []byte{byte(opcode.Mstore)},
+				Value:   "Mstore",
+			},
+			{
+				RawByte: []byte{byte(opcode.Push)},
+				Value:   "Push",
+			},
+			{
+				RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x08},
+				Value:   "0000000000000008",
+			},
+			{
+				RawByte: []byte{byte(opcode.Push)},
+				Value:   "Push",
+			},
+			{
+				RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+				Value:   "0000000000000000",
+			},
+			{
+				RawByte: []byte{byte(opcode.Mstore)},
+				Value:   "Mstore",
+			},
+			{
+				RawByte: []byte{byte(opcode.Push)},
+				Value:   "Push",
+			},
+			{
+				RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x08},
+				Value:   "0000000000000008",
+			},
+			{
+				RawByte: []byte{byte(opcode.Push)},
+				Value:   "Push",
+			},
+			{
+				RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+				Value:   "0000000000000000",
+			},
+			{
+				RawByte: []byte{byte(opcode.Mload)},
+				Value:   "Mload",
+			},
+			{
+				RawByte: []byte{byte(opcode.Returning)},
+				Value:   "Returning",
+			},
+			{
+				RawByte: []byte{byte(opcode.Push)},
+				Value:   "Push",
+			},
+			{
+				RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+				Value:   "0000000000000000",
+			},
+			{
+				RawByte: []byte{byte(opcode.Returning)},
+				Value:   "Returning",
+			},
+		},
+	}
+
+	asm := &Asm{
+		AsmCodes: make([]AsmCode, 0),
+	}
+	tracer := NewMemEntryTable()
+
+	if err := compileLibraryFunction(fn, asm, tracer); err != nil {
+		t.Fatal(err)
+	}
+
+	if !asm.Equal(expected) {
+		t.Fatalf("compileLibraryFunction() result wrong.\nexpected=%v,\ngot=%v", expected.String(), asm.String())
+	}
+
+	if tracer.MemoryCounter != 16 {
+		t.Fatalf("compileLibraryFunction() should update memory counter. expected=16, got=%d", tracer.MemoryCounter)
+	}
 }
 
 // TODO: after implement compileIdentifier, add test cases for compiling
@@ -2142,9 +2858,9 @@ func TestCompileInfixExpression(t *testing.T) {
 		// LAND
 		{
 			expression: &ast.InfixExpression{
-				Left:     &ast.IntegerLiteral{Value: 1},
+				Left:     &ast.BooleanLiteral{Value: true},
 				Operator: ast.LAND,
-				Right:    &ast.IntegerLiteral{Value: 2},
+				Right:    &ast.BooleanLiteral{Value: false},
 			},
 			expected: Asm{
 				AsmCodes: []AsmCode{
@@ -2156,17 +2872,33 @@ func TestCompileInfixExpression(t *testing.T) {
 						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
 						Value:   "0000000000000001",
 					},
+					{
+						RawByte: []byte{byte(opcode.DUP)},
+						Value:   "DUP",
+					},
 					{
 						RawByte: []byte{byte(opcode.Push)},
 						Value:   "Push",
 					},
 					{
-						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02},
-						Value:   "0000000000000002",
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x09},
+						Value:   "0000000000000009",
+					},
+					{
+						RawByte: []byte{byte(opcode.Jumpi)},
+						Value:   "Jumpi",
+					},
+					{
+						RawByte: []byte{byte(opcode.Pop)},
+						Value:   "Pop",
+					},
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
 					},
 					{
-						RawByte: []byte{byte(opcode.And)},
-						Value:   "And",
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+						Value:   "0000000000000000",
 					},
 				},
 			},
@@ -2174,9 +2906,9 @@ func TestCompileInfixExpression(t *testing.T) {
 		// LOR
 		{
 			expression: &ast.InfixExpression{
-				Left:     &ast.IntegerLiteral{Value: 1},
+				Left:     &ast.BooleanLiteral{Value: true},
 				Operator: ast.LOR,
-				Right:    &ast.IntegerLiteral{Value: 2},
+				Right:    &ast.BooleanLiteral{Value: false},
 			},
 			expected: Asm{
 				AsmCodes: []AsmCode{
@@ -2188,17 +2920,122 @@ func TestCompileInfixExpression(t *testing.T) {
 						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
 						Value:   "0000000000000001",
 					},
+					{
+						RawByte: []byte{byte(opcode.DUP)},
+						Value:   "DUP",
+					},
+					{
+						RawByte: []byte{byte(opcode.NOT)},
+						Value:   "NOT",
+					},
 					{
 						RawByte: []byte{byte(opcode.Push)},
 						Value:   "Push",
 					},
 					{
-						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02},
-						Value:   "0000000000000002",
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0a},
+						Value:   "000000000000000a",
+					},
+					{
+						RawByte: []byte{byte(opcode.Jumpi)},
+						Value:   "Jumpi",
+					},
+					{
+						RawByte: []byte{byte(opcode.Pop)},
+						Value:   "Pop",
+					},
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+						Value:   "0000000000000000",
+					},
+				},
+			},
+		},
+		// LAND with nested LOR, jump destinations are
+		// relative to the beginning of the whole asm
+		{
+			expression: &ast.InfixExpression{
+				Left:     &ast.BooleanLiteral{Value: true},
+				Operator: ast.LAND,
+				Right: &ast.InfixExpression{
+					Left:     &ast.BooleanLiteral{Value: false},
+					Operator: ast.LOR,
+					Right:    &ast.BooleanLiteral{Value: true},
+				},
+			},
+			expected: Asm{
+				AsmCodes: []AsmCode{
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+						Value:   "0000000000000001",
+					},
+					{
+						RawByte: []byte{byte(opcode.DUP)},
+						Value:   "DUP",
+					},
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x11},
+						Value:   "0000000000000011",
+					},
+					{
+						RawByte: []byte{byte(opcode.Jumpi)},
+						Value:   "Jumpi",
+					},
+					{
+						RawByte: []byte{byte(opcode.Pop)},
+						Value:   "Pop",
+					},
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+						Value:   "0000000000000000",
+					},
+					{
+						RawByte: []byte{byte(opcode.DUP)},
+						Value:   "DUP",
+					},
+					{
+						RawByte: []byte{byte(opcode.NOT)},
+						Value:   "NOT",
+					},
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x11},
+						Value:   "0000000000000011",
+					},
+					{
+						RawByte: []byte{byte(opcode.Jumpi)},
+						Value:   "Jumpi",
+					},
+					{
+						RawByte: []byte{byte(opcode.Pop)},
+						Value:   "Pop",
 					},
 					{
-						RawByte: []byte{byte(opcode.Or)},
-						Value:   "Or",
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+						Value:   "0000000000000001",
 					},
 				},
 			},
@@ -2414,12 +3251,12 @@ func TestCompileInfixExpression(t *testing.T) {
 				},
 			},
 		},
-		// Mul negative integer
+		// BitAnd
 		{
 			expression: &ast.InfixExpression{
-				Left:     &ast.IntegerLiteral{Value: -1},
-				Operator: ast.Asterisk,
-				Right:    &ast.IntegerLiteral{Value: 1},
+				Left:     &ast.IntegerLiteral{Value: 12},
+				Operator: ast.BitAnd,
+				Right:    &ast.IntegerLiteral{Value: 10},
 			},
 			expected: Asm{
 				AsmCodes: []AsmCode{
@@ -2428,36 +3265,30 @@ func TestCompileInfixExpression(t *testing.T) {
 						Value:   "Push",
 					},
 					{
-						RawByte: []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
-						Value:   "ffffffffffffffff",
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0c},
+						Value:   "000000000000000c",
 					},
 					{
 						RawByte: []byte{byte(opcode.Push)},
 						Value:   "Push",
 					},
 					{
-						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
-						Value:   "0000000000000001",
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0a},
+						Value:   "000000000000000a",
 					},
 					{
-						RawByte: []byte{byte(opcode.Mul)},
-						Value:   "Mul",
+						RawByte: []byte{byte(opcode.BitAnd)},
+						Value:   "BitAnd",
 					},
 				},
 			},
 		},
-	}
-
-	runExpressionCompileTests(t, tests)
-}
-
-func TestCompilePrefixExpression(t *testing.T) {
-	tests := []expressionCompileTestCase{
-		// simple prefix expression case
+		// BitOr
 		{
-			expression: &ast.PrefixExpression{
-				Operator: ast.Bang,
-				Right:    &ast.BooleanLiteral{Value: true},
+			expression: &ast.InfixExpression{
+				Left:     &ast.IntegerLiteral{Value: 12},
+				Operator: ast.BitOr,
+				Right:    &ast.IntegerLiteral{Value: 10},
 			},
 			expected: Asm{
 				AsmCodes: []AsmCode{
@@ -2466,20 +3297,587 @@ func TestCompilePrefixExpression(t *testing.T) {
 						Value:   "Push",
 					},
 					{
-						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
-						Value:   "0000000000000001",
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0c},
+						Value:   "000000000000000c",
+					},
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0a},
+						Value:   "000000000000000a",
+					},
+					{
+						RawByte: []byte{byte(opcode.BitOr)},
+						Value:   "BitOr",
+					},
+				},
+			},
+		},
+		// BitXor
+		{
+			expression: &ast.InfixExpression{
+				Left:     &ast.IntegerLiteral{Value: 12},
+				Operator: ast.BitXor,
+				Right:    &ast.IntegerLiteral{Value: 10},
+			},
+			expected: Asm{
+				AsmCodes: []AsmCode{
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0c},
+						Value:   "000000000000000c",
+					},
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0a},
+						Value:   "000000000000000a",
+					},
+					{
+						RawByte: []byte{byte(opcode.BitXor)},
+						Value:   "BitXor",
+					},
+				},
+			},
+		},
+		// Shl
+		{
+			expression: &ast.InfixExpression{
+				Left:     &ast.IntegerLiteral{Value: 1},
+				Operator: ast.Shl,
+				Right:    &ast.IntegerLiteral{Value: 4},
+			},
+			expected: Asm{
+				AsmCodes: []AsmCode{
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+						Value:   "0000000000000001",
+					},
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x04},
+						Value:   "0000000000000004",
+					},
+					{
+						RawByte: []byte{byte(opcode.Shl)},
+						Value:   "Shl",
+					},
+				},
+			},
+		},
+		// Shr
+		{
+			expression: &ast.InfixExpression{
+				Left:     &ast.IntegerLiteral{Value: 16},
+				Operator: ast.Shr,
+				Right:    &ast.IntegerLiteral{Value: 2},
+			},
+			expected: Asm{
+				AsmCodes: []AsmCode{
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x10},
+						Value:   "0000000000000010",
+					},
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02},
+						Value:   "0000000000000002",
+					},
+					{
+						RawByte: []byte{byte(opcode.Shr)},
+						Value:   "Shr",
+					},
+				},
+			},
+		},
+		// Mul negative integer
+		{
+			expression: &ast.InfixExpression{
+				Left:     &ast.IntegerLiteral{Value: -1},
+				Operator: ast.Asterisk,
+				Right:    &ast.IntegerLiteral{Value: 1},
+			},
+			expected: Asm{
+				AsmCodes: []AsmCode{
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+						Value:   "ffffffffffffffff",
+					},
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+						Value:   "0000000000000001",
+					},
+					{
+						RawByte: []byte{byte(opcode.Mul)},
+						Value:   "Mul",
+					},
+				},
+			},
+		},
+	}
+
+	runExpressionCompileTests(t, tests)
+}
+
+func TestCompilePrefixExpression(t *testing.T) {
+	tests := []expressionCompileTestCase{
+		// simple prefix expression case
+		{
+			expression: &ast.PrefixExpression{
+				Operator: ast.Bang,
+				Right:    &ast.BooleanLiteral{Value: true},
+			},
+			expected: Asm{
+				AsmCodes: []AsmCode{
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+						Value:   "0000000000000001",
+					},
+					{
+						RawByte: []byte{byte(opcode.NOT)},
+						Value:   "NOT",
+					},
+				},
+			},
+		},
+		{
+			expression: &ast.PrefixExpression{
+				Operator: ast.Minus,
+				Right:    &ast.IntegerLiteral{Value: 2},
+			},
+			expected: Asm{
+				AsmCodes: []AsmCode{
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02},
+						Value:   "0000000000000002",
+					},
+					{
+						RawByte: []byte{byte(opcode.Minus)},
+						Value:   "Minus",
+					},
+				},
+			},
+		},
+		{
+			expression: &ast.PrefixExpression{
+				Operator: ast.Minus,
+				Right:    &ast.BooleanLiteral{Value: true},
+			},
+			expected: Asm{
+				AsmCodes: []AsmCode{
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+						Value:   "0000000000000001",
+					},
+					{
+						RawByte: []byte{byte(opcode.Minus)},
+						Value:   "Minus",
+					},
+				},
+			},
+		},
+		{
+			expression: &ast.PrefixExpression{
+				Operator: ast.BitNot,
+				Right:    &ast.IntegerLiteral{Value: 2},
+			},
+			expected: Asm{
+				AsmCodes: []AsmCode{
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02},
+						Value:   "0000000000000002",
+					},
+					{
+						RawByte: []byte{byte(opcode.BitNot)},
+						Value:   "BitNot",
+					},
+				},
+			},
+		},
+		// rather complex cases
+		{
+			expression: &ast.PrefixExpression{
+				Operator: ast.Minus,
+				Right: &ast.PrefixExpression{
+					Operator: ast.Minus,
+					Right: &ast.IntegerLiteral{
+						Value: 1,
+					},
+				},
+			},
+			expected: Asm{
+				AsmCodes: []AsmCode{
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+						Value:   "0000000000000001",
+					},
+					{
+						RawByte: []byte{byte(opcode.Minus)},
+						Value:   "Minus",
+					},
+					{
+						RawByte: []byte{byte(opcode.Minus)},
+						Value:   "Minus",
+					},
+				},
+			},
+		},
+		{
+			expression: &ast.PrefixExpression{
+				Operator: ast.Bang,
+				Right: &ast.PrefixExpression{
+					Operator: ast.Bang,
+					Right: &ast.BooleanLiteral{
+						Value: false,
+					},
+				},
+			},
+			expected: Asm{
+				AsmCodes: []AsmCode{
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+						Value:   "0000000000000000",
 					},
 					{
 						RawByte: []byte{byte(opcode.NOT)},
 						Value:   "NOT",
 					},
+					{
+						RawByte: []byte{byte(opcode.NOT)},
+						Value:   "NOT",
+					},
+				},
+			},
+		},
+	}
+
+	runExpressionCompileTests(t, tests)
+}
+
+func TestCompileConditionalExpression(t *testing.T) {
+	tests := []expressionCompileTestCase{
+		// true ? 1 : 2
+		{
+			expression: &ast.ConditionalExpression{
+				Condition:   &ast.BooleanLiteral{Value: true},
+				Consequence: &ast.IntegerLiteral{Value: 1},
+				Alternative: &ast.IntegerLiteral{Value: 2},
+			},
+			expected: Asm{
+				AsmCodes: []AsmCode{
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+						Value:   "0000000000000001",
+					},
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0a},
+						Value:   "000000000000000a",
+					},
+					{
+						RawByte: []byte{byte(opcode.Jumpi)},
+						Value:   "Jumpi",
+					},
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+						Value:   "0000000000000001",
+					},
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0c},
+						Value:   "000000000000000c",
+					},
+					{
+						RawByte: []byte{byte(opcode.Jump)},
+						Value:   "Jump",
+					},
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02},
+						Value:   "0000000000000002",
+					},
+				},
+			},
+		},
+		// true ? 1 : false ? 2 : 3
+		{
+			expression: &ast.ConditionalExpression{
+				Condition:   &ast.BooleanLiteral{Value: true},
+				Consequence: &ast.IntegerLiteral{Value: 1},
+				Alternative: &ast.ConditionalExpression{
+					Condition:   &ast.BooleanLiteral{Value: false},
+					Consequence: &ast.IntegerLiteral{Value: 2},
+					Alternative: &ast.IntegerLiteral{Value: 3},
+				},
+			},
+			expected: Asm{
+				AsmCodes: []AsmCode{
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+						Value:   "0000000000000001",
+					},
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0a},
+						Value:   "000000000000000a",
+					},
+					{
+						RawByte: []byte{byte(opcode.Jumpi)},
+						Value:   "Jumpi",
+					},
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+						Value:   "0000000000000001",
+					},
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x16},
+						Value:   "0000000000000016",
+					},
+					{
+						RawByte: []byte{byte(opcode.Jump)},
+						Value:   "Jump",
+					},
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+						Value:   "0000000000000000",
+					},
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x14},
+						Value:   "0000000000000014",
+					},
+					{
+						RawByte: []byte{byte(opcode.Jumpi)},
+						Value:   "Jumpi",
+					},
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02},
+						Value:   "0000000000000002",
+					},
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x16},
+						Value:   "0000000000000016",
+					},
+					{
+						RawByte: []byte{byte(opcode.Jump)},
+						Value:   "Jump",
+					},
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03},
+						Value:   "0000000000000003",
+					},
+				},
+			},
+		},
+	}
+
+	runExpressionCompileTests(t, tests)
+}
+
+func TestCompileConversionExpression(t *testing.T) {
+	tests := []expressionCompileTestCase{
+		// int(5)
+		{
+			expression: &ast.ConversionExpression{
+				Type:      ast.IntType,
+				Value:     &ast.IntegerLiteral{Value: 5},
+				ValueType: ast.IntType,
+			},
+			expected: Asm{
+				AsmCodes: []AsmCode{
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x05},
+						Value:   "0000000000000005",
+					},
+				},
+			},
+		},
+		// string(5)
+		{
+			expression: &ast.ConversionExpression{
+				Type:      ast.StringType,
+				Value:     &ast.IntegerLiteral{Value: 5},
+				ValueType: ast.IntType,
+			},
+			expected: Asm{
+				AsmCodes: []AsmCode{
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x05},
+						Value:   "0000000000000005",
+					},
+					{
+						RawByte: []byte{byte(opcode.IntToString)},
+						Value:   "IntToString",
+					},
+				},
+			},
+		},
+		// int("5")
+		{
+			expression: &ast.ConversionExpression{
+				Type:      ast.IntType,
+				Value:     &ast.StringLiteral{Value: "5"},
+				ValueType: ast.StringType,
+			},
+			expected: Asm{
+				AsmCodes: []AsmCode{
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x35, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+						Value:   "3500000000000000",
+					},
+					{
+						RawByte: []byte{byte(opcode.StringToInt)},
+						Value:   "StringToInt",
+					},
+				},
+			},
+		},
+		// bool(5)
+		{
+			expression: &ast.ConversionExpression{
+				Type:      ast.BoolType,
+				Value:     &ast.IntegerLiteral{Value: 5},
+				ValueType: ast.IntType,
+			},
+			expected: Asm{
+				AsmCodes: []AsmCode{
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x05},
+						Value:   "0000000000000005",
+					},
+					{
+						RawByte: []byte{byte(opcode.IntToBool)},
+						Value:   "IntToBool",
+					},
 				},
 			},
 		},
+		// int(true)
 		{
-			expression: &ast.PrefixExpression{
-				Operator: ast.Minus,
-				Right:    &ast.IntegerLiteral{Value: 2},
+			expression: &ast.ConversionExpression{
+				Type:      ast.IntType,
+				Value:     &ast.BooleanLiteral{Value: true},
+				ValueType: ast.BoolType,
 			},
 			expected: Asm{
 				AsmCodes: []AsmCode{
@@ -2488,20 +3886,18 @@ func TestCompilePrefixExpression(t *testing.T) {
 						Value:   "Push",
 					},
 					{
-						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02},
-						Value:   "0000000000000002",
-					},
-					{
-						RawByte: []byte{byte(opcode.Minus)},
-						Value:   "Minus",
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+						Value:   "0000000000000001",
 					},
 				},
 			},
 		},
+		// string(true)
 		{
-			expression: &ast.PrefixExpression{
-				Operator: ast.Minus,
-				Right:    &ast.BooleanLiteral{Value: true},
+			expression: &ast.ConversionExpression{
+				Type:      ast.StringType,
+				Value:     &ast.BooleanLiteral{Value: true},
+				ValueType: ast.BoolType,
 			},
 			expected: Asm{
 				AsmCodes: []AsmCode{
@@ -2514,23 +3910,43 @@ func TestCompilePrefixExpression(t *testing.T) {
 						Value:   "0000000000000001",
 					},
 					{
-						RawByte: []byte{byte(opcode.Minus)},
-						Value:   "Minus",
+						RawByte: []byte{byte(opcode.BoolToString)},
+						Value:   "BoolToString",
 					},
 				},
 			},
 		},
-		// rather complex cases
+		// bool("true")
 		{
-			expression: &ast.PrefixExpression{
-				Operator: ast.Minus,
-				Right: &ast.PrefixExpression{
-					Operator: ast.Minus,
-					Right: &ast.IntegerLiteral{
-						Value: 1,
+			expression: &ast.ConversionExpression{
+				Type:      ast.BoolType,
+				Value:     &ast.StringLiteral{Value: "true"},
+				ValueType: ast.StringType,
+			},
+			expected: Asm{
+				AsmCodes: []AsmCode{
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x74, 0x72, 0x75, 0x65, 0x00, 0x00, 0x00, 0x00},
+						Value:   "7472756500000000",
+					},
+					{
+						RawByte: []byte{byte(opcode.StringToBool)},
+						Value:   "StringToBool",
 					},
 				},
 			},
+		},
+		// void(5)
+		{
+			expression: &ast.ConversionExpression{
+				Type:      ast.VoidType,
+				Value:     &ast.IntegerLiteral{Value: 5},
+				ValueType: ast.IntType,
+			},
 			expected: Asm{
 				AsmCodes: []AsmCode{
 					{
@@ -2538,30 +3954,77 @@ func TestCompilePrefixExpression(t *testing.T) {
 						Value:   "Push",
 					},
 					{
-						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
-						Value:   "0000000000000001",
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x05},
+						Value:   "0000000000000005",
 					},
+				},
+			},
+			expectedErr: errors.New("can't convert [int] to [void]"),
+		},
+	}
+
+	runExpressionCompileTests(t, tests)
+}
+
+func TestCompileEnumMemberLiteral(t *testing.T) {
+	state := &ast.EnumLiteral{
+		Name: &ast.Identifier{Name: "State"},
+		Members: []*ast.Identifier{
+			{Name: "Open"},
+			{Name: "Closed"},
+		},
+	}
+
+	tests := []expressionCompileTestCase{
+		{
+			expression: &ast.EnumMemberLiteral{
+				Enum:   state,
+				Member: &ast.Identifier{Name: "Open"},
+				Value:  0,
+			},
+			expected: Asm{
+				AsmCodes: []AsmCode{
 					{
-						RawByte: []byte{byte(opcode.Minus)},
-						Value:   "Minus",
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
 					},
 					{
-						RawByte: []byte{byte(opcode.Minus)},
-						Value:   "Minus",
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+						Value:   "0000000000000000",
 					},
 				},
 			},
 		},
 		{
-			expression: &ast.PrefixExpression{
-				Operator: ast.Bang,
-				Right: &ast.PrefixExpression{
-					Operator: ast.Bang,
-					Right: &ast.BooleanLiteral{
-						Value: false,
+			expression: &ast.EnumMemberLiteral{
+				Enum:   state,
+				Member: &ast.Identifier{Name: "Closed"},
+				Value:  1,
+			},
+			expected: Asm{
+				AsmCodes: []AsmCode{
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+						Value:   "0000000000000001",
 					},
 				},
 			},
+		},
+		// int(State.Closed)
+		{
+			expression: &ast.ConversionExpression{
+				Type: ast.IntType,
+				Value: &ast.EnumMemberLiteral{
+					Enum:   state,
+					Member: &ast.Identifier{Name: "Closed"},
+					Value:  1,
+				},
+				ValueType: ast.EnumType,
+			},
 			expected: Asm{
 				AsmCodes: []AsmCode{
 					{
@@ -2569,16 +4032,36 @@ func TestCompilePrefixExpression(t *testing.T) {
 						Value:   "Push",
 					},
 					{
-						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
-						Value:   "0000000000000000",
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+						Value:   "0000000000000001",
+					},
+				},
+			},
+		},
+		// string(State.Closed)
+		{
+			expression: &ast.ConversionExpression{
+				Type: ast.StringType,
+				Value: &ast.EnumMemberLiteral{
+					Enum:   state,
+					Member: &ast.Identifier{Name: "Closed"},
+					Value:  1,
+				},
+				ValueType: ast.EnumType,
+			},
+			expected: Asm{
+				AsmCodes: []AsmCode{
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
 					},
 					{
-						RawByte: []byte{byte(opcode.NOT)},
-						Value:   "NOT",
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+						Value:   "0000000000000001",
 					},
 					{
-						RawByte: []byte{byte(opcode.NOT)},
-						Value:   "NOT",
+						RawByte: []byte{byte(opcode.IntToString)},
+						Value:   "IntToString",
 					},
 				},
 			},
@@ -2829,6 +4312,158 @@ func TestCompileIdentifier(t *testing.T) {
 	runExpressionCompileTests(t, tests)
 }
 
+func TestCompileContractCallExpression(t *testing.T) {
+	tests := []expressionCompileTestCase{
+		// call(1, "release(int)", 5)
+		{
+			expression: &ast.ContractCallExpression{
+				Address:    &ast.IntegerLiteral{Value: 1},
+				Signature:  "release(int)",
+				Arguments:  []ast.Expression{&ast.IntegerLiteral{Value: 5}},
+				ReturnType: ast.IntType,
+			},
+			expected: Asm{
+				AsmCodes: []AsmCode{
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+						Value:   "0000000000000001",
+					},
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0xf2, 0xa4, 0x3b, 0x18},
+						Value:   "00000000f2a43b18",
+					},
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x05},
+						Value:   "0000000000000005",
+					},
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+						Value:   "0000000000000001",
+					},
+					{
+						RawByte: []byte{byte(opcode.Call)},
+						Value:   "Call",
+					},
+				},
+			},
+		},
+		// call(1, "release(int) bool", 2 * 3)
+		{
+			expression: &ast.ContractCallExpression{
+				Address:   &ast.IntegerLiteral{Value: 1},
+				Signature: "release(int)",
+				Arguments: []ast.Expression{
+					&ast.InfixExpression{
+						Left:     &ast.IntegerLiteral{Value: 2},
+						Operator: ast.Asterisk,
+						Right:    &ast.IntegerLiteral{Value: 3},
+					},
+				},
+				ReturnType: ast.BoolType,
+			},
+			expected: Asm{
+				AsmCodes: []AsmCode{
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+						Value:   "0000000000000001",
+					},
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0xf2, 0xa4, 0x3b, 0x18},
+						Value:   "00000000f2a43b18",
+					},
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02},
+						Value:   "0000000000000002",
+					},
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03},
+						Value:   "0000000000000003",
+					},
+					{
+						RawByte: []byte{byte(opcode.Mul)},
+						Value:   "Mul",
+					},
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+						Value:   "0000000000000001",
+					},
+					{
+						RawByte: []byte{byte(opcode.Call)},
+						Value:   "Call",
+					},
+				},
+			},
+		},
+	}
+
+	runExpressionCompileTests(t, tests)
+}
+
+func TestCompileLoadExpression(t *testing.T) {
+	tests := []expressionCompileTestCase{
+		// load(1)
+		{
+			expression: &ast.LoadExpression{
+				Key: &ast.IntegerLiteral{Value: 1},
+			},
+			expected: Asm{
+				AsmCodes: []AsmCode{
+					{
+						RawByte: []byte{byte(opcode.Push)},
+						Value:   "Push",
+					},
+					{
+						RawByte: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+						Value:   "0000000000000001",
+					},
+					{
+						RawByte: []byte{byte(opcode.SLoad)},
+						Value:   "SLoad",
+					},
+				},
+			},
+		},
+	}
+
+	runExpressionCompileTests(t, tests)
+}
+
 func runExpressionCompileTests(t *testing.T, tests []expressionCompileTestCase) {
 	t.Helper()
 
@@ -2856,12 +4491,27 @@ func runExpressionCompileTests(t *testing.T, tests []expressionCompileTestCase)
 		case *ast.StringLiteral:
 			testFuncName = "compileStringLiteral()"
 			err = compilePrimitive(expr.Value, asm)
+		case *ast.EnumMemberLiteral:
+			testFuncName = "compileEnumMemberLiteral()"
+			err = compilePrimitive(expr.Value, asm)
 		case *ast.PrefixExpression:
 			testFuncName = "compilePrefixExpression()"
 			err = compilePrefixExpression(expr, asm, tracer)
 		case *ast.InfixExpression:
 			testFuncName = "compileInfixExpression()"
 			err = compileInfixExpression(expr, asm, tracer)
+		case *ast.ConditionalExpression:
+			testFuncName = "compileConditionalExpression()"
+			err = compileConditionalExpression(expr, asm, tracer)
+		case *ast.ConversionExpression:
+			testFuncName = "compileConversionExpression()"
+			err = compileConversionExpression(expr, asm, tracer)
+		case *ast.ContractCallExpression:
+			testFuncName = "compileContractCallExpression()"
+			err = compileContractCallExpression(expr, asm, tracer)
+		case *ast.LoadExpression:
+			testFuncName = "compileLoadExpression()"
+			err = compileLoadExpression(expr, asm, tracer)
 		case *ast.Identifier:
 			testFuncName = "compileIdentifier()"
 			err = compileIdentifier(expr, asm, tracer)
@@ -2902,6 +4552,15 @@ func runStatementCompileTests(t *testing.T, tests []statementCompileTestCase) {
 		case *ast.ReturnStatement:
 			testFuncName = "compileReturnStatement()"
 			err = compileReturnStatement(stmt, asm, tracer)
+		case *ast.RequireStatement:
+			testFuncName = "compileRequireStatement()"
+			err = compileRequireStatement(stmt, asm, tracer)
+		case *ast.StoreStatement:
+			testFuncName = "compileStoreStatement()"
+			err = compileStoreStatement(stmt, asm, tracer)
+		case *ast.EmitStatement:
+			testFuncName = "compileEmitStatement()"
+			err = compileEmitStatement(stmt, asm, tracer)
 		default:
 			t.Fatalf("%T type not support, abort.", stmt)
 			t.FailNow()
@@ -2948,3 +4607,54 @@ func compareFuncMap(funcMap1 FuncMap, funcMap2 FuncMap) bool {
 
 	return true
 }
+
+func TestCheckSelectors(t *testing.T) {
+	function := func(name string, types ...ast.DataStructure) *ast.FunctionLiteral {
+		params := make([]*ast.ParameterLiteral, 0)
+		for i, typ := range types {
+			params = append(params, &ast.ParameterLiteral{
+				Identifier: &ast.Identifier{Name: fmt.Sprintf("a%d", i)},
+				Type:       typ,
+			})
+		}
+
+		return &ast.FunctionLiteral{Name: &ast.Identifier{Name: name}, Parameters: params}
+	}
+
+	tests := []struct {
+		functions []*ast.FunctionLiteral
+		err       error
+	}{
+		{
+			functions: []*ast.FunctionLiteral{
+				function("transfer", ast.IntType),
+				function("transfer", ast.StringType),
+				function("transfer", ast.IntType, ast.IntType),
+			},
+			err: nil,
+		},
+		{
+			functions: []*ast.FunctionLiteral{
+				function("transfer", ast.IntType),
+				function("transfer", ast.IntType),
+			},
+			err: errors.New("function transfer(int) is declared more than once"),
+		},
+		{
+			// f8491() and f130736() have the same selector 62018627
+			functions: []*ast.FunctionLiteral{
+				function("f8491"),
+				function("f130736"),
+			},
+			err: errors.New("selector 62018627 of function f130736() collides with function f8491()"),
+		},
+	}
+
+	for i, test := range tests {
+		err := checkSelectors(ast.Contract{Functions: test.functions})
+
+		if (err == nil) != (test.err == nil) || (err != nil && err.Error() != test.err.Error()) {
+			t.Errorf("test[%d] - checkSelectors() error wrong.\nexpected=%v,\ngot=%v", i, test.err, err)
+		}
+	}
+}
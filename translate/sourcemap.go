@@ -0,0 +1,116 @@
+/*
+ * Copyright 2018-2019 De-labtory
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package translate
+
+import (
+	"fmt"
+	"sort"
+)
+
+// SourceMap maps the program counter of bytecode to the position in
+// source. The program counter is the index of AsmCode, which is the
+// pc of vm.
+//
+// An entry covers the codes from its PC to the PC of the next entry.
+// The codes which aren't compiled from a statement, such as the
+// function jumper, are covered by the entry of which Line is zero.
+type SourceMap struct {
+	Files   []string         `json:"files"`
+	Entries []SourceMapEntry `json:"entries"`
+}
+
+// SourceMapEntry is the position of codes from PC. File is the index
+// of Files, or -1 if the position is unknown.
+type SourceMapEntry struct {
+	PC     int `json:"pc"`
+	File   int `json:"file"`
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// SourceMap() generates the source map of the codes.
+func (a *Asm) SourceMap() SourceMap {
+	sm := SourceMap{
+		Files:   make([]string, 0),
+		Entries: make([]SourceMapEntry, 0),
+	}
+	files := make(map[string]int)
+
+	for pc, code := range a.AsmCodes {
+		entry := SourceMapEntry{PC: pc, File: -1}
+
+		if code.Pos.IsValid() {
+			file, ok := files[code.File]
+			if !ok {
+				file = len(sm.Files)
+				files[code.File] = file
+				sm.Files = append(sm.Files, code.File)
+			}
+
+			entry.File = file
+			entry.Line = code.Pos.Line
+			entry.Column = code.Pos.Column
+		}
+
+		if n := len(sm.Entries); n > 0 && sm.Entries[n-1].sameOrigin(entry) {
+			continue
+		}
+
+		sm.Entries = append(sm.Entries, entry)
+	}
+
+	return sm
+}
+
+func (e SourceMapEntry) sameOrigin(e1 SourceMapEntry) bool {
+	return e.File == e1.File && e.Line == e1.Line && e.Column == e1.Column
+}
+
+// Lookup() finds the entry which covers pc. It returns false if the
+// position of pc is unknown.
+func (s SourceMap) Lookup(pc int) (SourceMapEntry, bool) {
+	i := sort.Search(len(s.Entries), func(i int) bool {
+		return s.Entries[i].PC > pc
+	})
+
+	if i == 0 {
+		return SourceMapEntry{}, false
+	}
+
+	entry := s.Entries[i-1]
+	if entry.Line == 0 || entry.File < 0 || entry.File >= len(s.Files) {
+		return SourceMapEntry{}, false
+	}
+
+	return entry, true
+}
+
+// Position() returns the position of pc formatted as "file:line:column",
+// file is omitted if the source isn't a file. It returns an empty
+// string if the position is unknown.
+func (s SourceMap) Position(pc int) string {
+	entry, ok := s.Lookup(pc)
+	if !ok {
+		return ""
+	}
+
+	if file := s.Files[entry.File]; file != "" {
+		return fmt.Sprintf("%s:%d:%d", file, entry.Line, entry.Column)
+	}
+
+	return fmt.Sprintf("%d:%d", entry.Line, entry.Column)
+}
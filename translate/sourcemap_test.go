@@ -0,0 +1,89 @@
+/*
+ * Copyright 2018-2019 De-labtory
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package translate_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/DE-labtory/koa/ast"
+	"github.com/DE-labtory/koa/translate"
+)
+
+func TestAsm_SourceMap(t *testing.T) {
+	asm := translate.Asm{
+		AsmCodes: []translate.AsmCode{
+			{Value: "LoadFunc"},
+			{Value: "Push", File: "a.koa", Pos: ast.Pos{Line: 2, Column: 3}},
+			{Value: "00000001", File: "a.koa", Pos: ast.Pos{Line: 2, Column: 3}},
+			{Value: "Returning", File: "a.koa", Pos: ast.Pos{Line: 3, Column: 3}},
+			{Value: "Push", File: "b.koa", Pos: ast.Pos{Line: 1, Column: 5}},
+			{Value: "Returning", File: "a.koa", Pos: ast.Pos{Line: 3, Column: 3}},
+			{Value: "Revert"},
+		},
+	}
+
+	expected := translate.SourceMap{
+		Files: []string{"a.koa", "b.koa"},
+		Entries: []translate.SourceMapEntry{
+			{PC: 0, File: -1},
+			{PC: 1, File: 0, Line: 2, Column: 3},
+			{PC: 3, File: 0, Line: 3, Column: 3},
+			{PC: 4, File: 1, Line: 1, Column: 5},
+			{PC: 5, File: 0, Line: 3, Column: 3},
+			{PC: 6, File: -1},
+		},
+	}
+
+	sm := asm.SourceMap()
+	if !reflect.DeepEqual(sm, expected) {
+		t.Fatalf("wrong source map. expected=%v, got=%v", expected, sm)
+	}
+
+	tests := []struct {
+		pc       int
+		expected string
+	}{
+		{pc: -1, expected: ""},
+		{pc: 0, expected: ""},
+		{pc: 1, expected: "a.koa:2:3"},
+		{pc: 2, expected: "a.koa:2:3"},
+		{pc: 3, expected: "a.koa:3:3"},
+		{pc: 4, expected: "b.koa:1:5"},
+		{pc: 6, expected: ""},
+		{pc: 100, expected: ""},
+	}
+
+	for i, test := range tests {
+		if pos := sm.Position(test.pc); pos != test.expected {
+			t.Errorf("test[%d] - wrong position of pc %d. expected=%q, got=%q",
+				i, test.pc, test.expected, pos)
+		}
+	}
+}
+
+func TestSourceMap_Position_noFile(t *testing.T) {
+	asm := translate.Asm{
+		AsmCodes: []translate.AsmCode{
+			{Value: "Push", Pos: ast.Pos{Line: 4, Column: 9}},
+		},
+	}
+
+	if pos := asm.SourceMap().Position(0); pos != "4:9" {
+		t.Fatalf("wrong position. expected=%q, got=%q", "4:9", pos)
+	}
+}
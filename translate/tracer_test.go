@@ -36,7 +36,7 @@ func TestNewEnclosedMemEntryTable(t *testing.T) {
 	closedMemEntryTable := translate.NewEnclosedMemEntryTable(memEntryTable)
 
 	if closedMemEntryTable.Outer != memEntryTable {
-		t.Fatalf("outer is wrong. expected=%x, got=%x", memEntryTable, closedMemEntryTable.Outer)
+		t.Fatalf("outer is wrong. expected=%p, got=%p", memEntryTable, closedMemEntryTable.Outer)
 	}
 
 	if closedMemEntryTable.MemoryCounter != memEntryTable.MemoryCounter {
@@ -30,6 +30,13 @@ var opCodes = map[opcode.Type]opCode{
 	opcode.And: and{},
 	opcode.Or:  or{},
 
+	opcode.BitAnd: bitAnd{},
+	opcode.BitOr:  bitOr{},
+	opcode.BitXor: bitXor{},
+	opcode.BitNot: bitNot{},
+	opcode.Shl:    shl{},
+	opcode.Shr:    shr{},
+
 	// 0x10 range
 	opcode.LT:  lt{},
 	opcode.LTE: lte{},
@@ -51,10 +58,22 @@ var opCodes = map[opcode.Type]opCode{
 	opcode.JumpDst:   jumpDst{},
 
 	// 0x30 range
-	opcode.Jumpi: jumpi{},
-	opcode.DUP:   dup{},
-	opcode.SWAP:  swap{},
-	opcode.Exit:  exit{},
+	opcode.Jumpi:  jumpi{},
+	opcode.DUP:    dup{},
+	opcode.SWAP:   swap{},
+	opcode.Exit:   exit{},
+	opcode.Revert: revert{},
+	opcode.Call:   call{},
+	opcode.SLoad:  sload{},
+	opcode.SStore: sstore{},
+	opcode.Log:    log{},
+
+	// 0x40 range
+	opcode.IntToString:  intToString{},
+	opcode.StringToInt:  stringToInt{},
+	opcode.IntToBool:    intToBool{},
+	opcode.BoolToString: boolToString{},
+	opcode.StringToBool: stringToBool{},
 }
 
 // Converts rawByteCode to assembly code.
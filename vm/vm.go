@@ -17,9 +17,12 @@
 package vm
 
 import (
+	"bytes"
 	"encoding/binary"
 	"errors"
+	"strconv"
 
+	"github.com/DE-labtory/koa/container"
 	"github.com/DE-labtory/koa/encoding"
 	"github.com/DE-labtory/koa/opcode"
 )
@@ -34,13 +37,60 @@ const (
 
 var ErrInvalidData = errors.New("Invalid data")
 var ErrInvalidOpcode = errors.New("invalid opcode")
+var ErrNegativeShift = errors.New("negative shift count")
+var ErrInvalidConversion = errors.New("invalid type conversion")
+var ErrRevert = errors.New("execution reverted")
+var ErrNoHost = errors.New("no host to call contract")
+var ErrCallDepth = errors.New("max call depth exceeded")
+var ErrNoReturnValue = errors.New("called function returned no value")
+var ErrInvalidCount = errors.New("invalid number of items on stack")
+var ErrNoState = errors.New("no state to keep storage and logs")
+var ErrOutOfGas = errors.New("out of gas")
+
+// MaxCallDepth is the maximum number of nested calls between contracts.
+const MaxCallDepth = 64
+
+// Host provides the bytecode of contracts to call.
+type Host interface {
+	Code(address int64) ([]byte, error)
+}
+
+// State keeps the storage and logs of each contract by its address.
+type State interface {
+	Load(address int64, key int64) int64
+	Store(address int64, key int64, value int64)
+	Log(address int64, data []int64)
+}
+
+// Tracer receives each step of execution to debug contracts.
+type Tracer interface {
+	Step(step Step)
+}
+
+// Step is the state of VM before the instruction is executed. Operand
+// is the operand of Push, and Stack has the items from bottom to top.
+// GasUsed is the gas used by the call before the instruction.
+type Step struct {
+	Depth   int
+	PC      uint64
+	Op      opcode.Type
+	Operand []byte
+	Stack   []int64
+	GasUsed uint64
+}
 
 // The Execute function assemble the rawByteCode into an assembly code,
-// which in turn executes the assembly logic.
+// which in turn executes the assembly logic. rawByteCode can be a
+// container, then its code is executed.
 func Execute(rawByteCode []byte, memory *Memory, callFunc *CallFunc) (*Stack, error) {
 
 	s := newStack()
-	asm, err := disassemble(rawByteCode)
+	code, err := unpackCode(rawByteCode)
+	if err != nil {
+		return &Stack{}, err
+	}
+
+	asm, err := disassemble(code)
 	if err != nil {
 		return &Stack{}, err
 	}
@@ -51,6 +101,12 @@ func Execute(rawByteCode []byte, memory *Memory, callFunc *CallFunc) (*Stack, er
 			return &Stack{}, ErrInvalidOpcode
 		}
 
+		callFunc.trace(asm, op, s)
+
+		if err := callFunc.useGas(1); err != nil {
+			return s, err
+		}
+
 		err := op.Do(s, asm, memory, callFunc)
 		if err != nil {
 			return s, err
@@ -60,9 +116,87 @@ func Execute(rawByteCode []byte, memory *Memory, callFunc *CallFunc) (*Stack, er
 	return s, nil
 }
 
+// unpackCode returns the code of container, if rawByteCode is a
+// container. The container of other version is refused.
+func unpackCode(rawByteCode []byte) ([]byte, error) {
+	if !container.IsContainer(rawByteCode) {
+		return rawByteCode, nil
+	}
+
+	c, err := container.Unpack(rawByteCode)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Code, nil
+}
+
 type CallFunc struct {
 	Func []byte
 	Args []byte
+
+	// Host finds the contract to call with Call opcode.
+	Host Host
+
+	// State keeps the storage and logs of contracts.
+	State State
+
+	// Address is the address of running contract.
+	Address int64
+
+	// Depth is the number of calls to reach this contract.
+	Depth int
+
+	// GasLimit is the maximum gas to use, zero means no limit.
+	// Each executed instruction uses one gas, and GasUsed has
+	// the gas used by this call including the calls it makes.
+	GasLimit uint64
+	GasUsed  uint64
+
+	// Tracer receives the steps of this call and the calls it makes,
+	// it can be nil.
+	Tracer Tracer
+}
+
+// trace sends the step of op to Tracer, if it is set.
+func (cf *CallFunc) trace(a *asm, op opCode, s *Stack) {
+	if cf == nil || cf.Tracer == nil {
+		return
+	}
+
+	step := Step{
+		Depth:   cf.Depth,
+		PC:      a.pc,
+		Op:      opcode.Type(op.hex()[0]),
+		Stack:   make([]int64, len(s.items)),
+		GasUsed: cf.GasUsed,
+	}
+
+	for i, item := range s.items {
+		step.Stack[i] = int64(item)
+	}
+
+	if step.Op == opcode.Push && a.pc+1 < uint64(len(a.code)) {
+		step.Operand = a.code[a.pc+1].hex()
+	}
+
+	cf.Tracer.Step(step)
+}
+
+// useGas adds gas to GasUsed, and fails if it exceeds GasLimit.
+// When it fails, all the gas up to GasLimit is used.
+func (cf *CallFunc) useGas(gas uint64) error {
+	if cf == nil {
+		return nil
+	}
+
+	cf.GasUsed += gas
+	if cf.GasLimit > 0 && cf.GasUsed > cf.GasLimit {
+		cf.GasUsed = cf.GasLimit
+		return ErrOutOfGas
+	}
+
+	return nil
 }
 
 // function return the Func in CallFunc
@@ -112,6 +246,12 @@ type div struct{}
 type mod struct{}
 type and struct{}
 type or struct{}
+type bitAnd struct{}
+type bitOr struct{}
+type bitXor struct{}
+type bitNot struct{}
+type shl struct{}
+type shr struct{}
 
 // 0x10 range
 type lt struct{}
@@ -138,6 +278,18 @@ type jumpi struct{}
 type dup struct{}
 type swap struct{}
 type exit struct{}
+type revert struct{}
+type call struct{}
+type sload struct{}
+type sstore struct{}
+type log struct{}
+
+// 0x40 range
+type intToString struct{}
+type stringToInt struct{}
+type intToBool struct{}
+type boolToString struct{}
+type stringToBool struct{}
 
 func (add) Do(stack *Stack, _ asmReader, _ *Memory, _ *CallFunc) error {
 	y := stack.Pop()
@@ -239,6 +391,92 @@ func (or) hex() []uint8 {
 	return []uint8{uint8(opcode.Or)}
 }
 
+func (bitAnd) Do(stack *Stack, _ asmReader, _ *Memory, _ *CallFunc) error {
+	y := stack.Pop()
+	x := stack.Pop()
+
+	stack.Push(x & y)
+
+	return nil
+}
+
+func (bitAnd) hex() []uint8 {
+	return []uint8{uint8(opcode.BitAnd)}
+}
+
+func (bitOr) Do(stack *Stack, _ asmReader, _ *Memory, _ *CallFunc) error {
+	y := stack.Pop()
+	x := stack.Pop()
+
+	stack.Push(x | y)
+
+	return nil
+}
+
+func (bitOr) hex() []uint8 {
+	return []uint8{uint8(opcode.BitOr)}
+}
+
+func (bitXor) Do(stack *Stack, _ asmReader, _ *Memory, _ *CallFunc) error {
+	y := stack.Pop()
+	x := stack.Pop()
+
+	stack.Push(x ^ y)
+
+	return nil
+}
+
+func (bitXor) hex() []uint8 {
+	return []uint8{uint8(opcode.BitXor)}
+}
+
+func (bitNot) Do(stack *Stack, _ asmReader, _ *Memory, _ *CallFunc) error {
+	x := stack.Pop()
+
+	stack.Push(^x)
+
+	return nil
+}
+
+func (bitNot) hex() []uint8 {
+	return []uint8{uint8(opcode.BitNot)}
+}
+
+func (shl) Do(stack *Stack, _ asmReader, _ *Memory, _ *CallFunc) error {
+	y := stack.Pop()
+	x := stack.Pop()
+
+	if y < 0 {
+		return ErrNegativeShift
+	}
+
+	stack.Push(x << uint64(y))
+
+	return nil
+}
+
+func (shl) hex() []uint8 {
+	return []uint8{uint8(opcode.Shl)}
+}
+
+// shr is an arithmetic shift, the sign of x is preserved
+func (shr) Do(stack *Stack, _ asmReader, _ *Memory, _ *CallFunc) error {
+	y := stack.Pop()
+	x := stack.Pop()
+
+	if y < 0 {
+		return ErrNegativeShift
+	}
+
+	stack.Push(x >> uint64(y))
+
+	return nil
+}
+
+func (shr) hex() []uint8 {
+	return []uint8{uint8(opcode.Shr)}
+}
+
 func (lt) Do(stack *Stack, _ asmReader, _ *Memory, _ *CallFunc) error {
 	y, x := stack.Pop(), stack.Pop()
 
@@ -498,6 +736,252 @@ func (exit) hex() []uint8 {
 	return []uint8{uint8(opcode.Exit)}
 }
 
+func (revert) Do(_ *Stack, _ asmReader, _ *Memory, _ *CallFunc) error {
+	return ErrRevert
+}
+
+func (revert) hex() []uint8 {
+	return []uint8{uint8(opcode.Revert)}
+}
+
+func (call) Do(stack *Stack, _ asmReader, _ *Memory, callfunc *CallFunc) error {
+	// arguments are above the address and the selector
+	n, err := popCount(stack, 2)
+	if err != nil {
+		return err
+	}
+
+	args := make([]item, n)
+	for i := n - 1; i >= 0; i-- {
+		args[i] = stack.Pop()
+	}
+	selector, address := stack.Pop(), stack.Pop()
+
+	if callfunc == nil || callfunc.Host == nil {
+		return ErrNoHost
+	}
+
+	if callfunc.Depth >= MaxCallDepth {
+		return ErrCallDepth
+	}
+
+	code, err := callfunc.Host.Code(int64(address))
+	if err != nil {
+		return err
+	}
+
+	callee := &CallFunc{
+		Func:    int64ToBytes(int64(selector))[4:],
+		Args:    encodeArgs(args),
+		Host:    callfunc.Host,
+		State:   callfunc.State,
+		Address: int64(address),
+		Depth:   callfunc.Depth + 1,
+		Tracer:  callfunc.Tracer,
+	}
+
+	if callfunc.GasLimit > 0 {
+		// zero GasLimit of callee means no limit, so callee can't
+		// run when there is no gas left
+		if callfunc.GasUsed >= callfunc.GasLimit {
+			return ErrOutOfGas
+		}
+		callee.GasLimit = callfunc.GasLimit - callfunc.GasUsed
+	}
+
+	result, err := Execute(code, NewMemory(), callee)
+	if gasErr := callfunc.useGas(callee.GasUsed); gasErr != nil {
+		return gasErr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if result.Len() != 1 {
+		return ErrNoReturnValue
+	}
+
+	stack.Push(result.Pop())
+	return nil
+}
+
+// popCount pops the number of items, which are on the stack above
+// other items as many as below. It fails if the number is negative
+// or the stack doesn't have enough items.
+func popCount(stack *Stack, below int) (int, error) {
+	if stack.Len() == 0 {
+		return 0, ErrInvalidCount
+	}
+
+	n := stack.Pop()
+	if n < 0 || int64(n) > int64(stack.Len()-below) {
+		return 0, ErrInvalidCount
+	}
+
+	return int(n), nil
+}
+
+func (call) hex() []uint8 {
+	return []uint8{uint8(opcode.Call)}
+}
+
+func (sload) Do(stack *Stack, _ asmReader, _ *Memory, callfunc *CallFunc) error {
+	if callfunc == nil || callfunc.State == nil {
+		return ErrNoState
+	}
+
+	key := stack.Pop()
+	stack.Push(item(callfunc.State.Load(callfunc.Address, int64(key))))
+	return nil
+}
+
+func (sload) hex() []uint8 {
+	return []uint8{uint8(opcode.SLoad)}
+}
+
+func (sstore) Do(stack *Stack, _ asmReader, _ *Memory, callfunc *CallFunc) error {
+	if callfunc == nil || callfunc.State == nil {
+		return ErrNoState
+	}
+
+	value, key := stack.Pop(), stack.Pop()
+	callfunc.State.Store(callfunc.Address, int64(key), int64(value))
+	return nil
+}
+
+func (sstore) hex() []uint8 {
+	return []uint8{uint8(opcode.SStore)}
+}
+
+func (log) Do(stack *Stack, _ asmReader, _ *Memory, callfunc *CallFunc) error {
+	if callfunc == nil || callfunc.State == nil {
+		return ErrNoState
+	}
+
+	n, err := popCount(stack, 0)
+	if err != nil {
+		return err
+	}
+
+	data := make([]int64, n)
+	for i := n - 1; i >= 0; i-- {
+		data[i] = int64(stack.Pop())
+	}
+
+	callfunc.State.Log(callfunc.Address, data)
+	return nil
+}
+
+func (log) hex() []uint8 {
+	return []uint8{uint8(opcode.Log)}
+}
+
+func (intToString) Do(stack *Stack, _ asmReader, _ *Memory, _ *CallFunc) error {
+	x := stack.Pop()
+
+	str, err := stringToItem(strconv.FormatInt(int64(x), 10))
+	if err != nil {
+		return err
+	}
+
+	stack.Push(str)
+	return nil
+}
+
+func (intToString) hex() []uint8 {
+	return []uint8{uint8(opcode.IntToString)}
+}
+
+func (stringToInt) Do(stack *Stack, _ asmReader, _ *Memory, _ *CallFunc) error {
+	x := stack.Pop()
+
+	i, err := strconv.ParseInt(itemToString(x), 10, 64)
+	if err != nil {
+		return ErrInvalidConversion
+	}
+
+	stack.Push(item(i))
+	return nil
+}
+
+func (stringToInt) hex() []uint8 {
+	return []uint8{uint8(opcode.StringToInt)}
+}
+
+func (intToBool) Do(stack *Stack, _ asmReader, _ *Memory, _ *CallFunc) error {
+	x := stack.Pop()
+
+	if x != 0 {
+		stack.Push(item(1))
+	} else {
+		stack.Push(item(0))
+	}
+
+	return nil
+}
+
+func (intToBool) hex() []uint8 {
+	return []uint8{uint8(opcode.IntToBool)}
+}
+
+func (boolToString) Do(stack *Stack, _ asmReader, _ *Memory, _ *CallFunc) error {
+	x := stack.Pop()
+
+	var str item
+	var err error
+	if x != 0 {
+		str, err = stringToItem("true")
+	} else {
+		str, err = stringToItem("false")
+	}
+	if err != nil {
+		return err
+	}
+
+	stack.Push(str)
+	return nil
+}
+
+func (boolToString) hex() []uint8 {
+	return []uint8{uint8(opcode.BoolToString)}
+}
+
+func (stringToBool) Do(stack *Stack, _ asmReader, _ *Memory, _ *CallFunc) error {
+	x := stack.Pop()
+
+	switch itemToString(x) {
+	case "true":
+		stack.Push(item(1))
+	case "false":
+		stack.Push(item(0))
+	default:
+		return ErrInvalidConversion
+	}
+
+	return nil
+}
+
+func (stringToBool) hex() []uint8 {
+	return []uint8{uint8(opcode.StringToBool)}
+}
+
+// encodeArgs encodes items as the Args of CallFunc. Every item is
+// an 8 bytes value, so it is same as encoding with abi.
+func encodeArgs(args []item) []byte {
+	encoded := make([]byte, 0, len(args)*(PTRSIZE+SIZEPTRSIZE+8))
+	for i := range args {
+		encoded = append(encoded, int64ToBytes(int64(len(args)*PTRSIZE+i*(SIZEPTRSIZE+8)))...)
+	}
+
+	for _, arg := range args {
+		encoded = append(encoded, int64ToBytes(8)...)
+		encoded = append(encoded, int64ToBytes(int64(arg))...)
+	}
+
+	return encoded
+}
+
 func int64ToBytes(int64 int64) []byte {
 	byteSlice := make([]byte, 8)
 	binary.BigEndian.PutUint64(byteSlice, uint64(int64))
@@ -509,6 +993,23 @@ func bytesToItem(bytes []byte) item {
 	return item
 }
 
+// itemToString returns the string which item holds. Strings are
+// left-aligned in the item and padded with zero bytes.
+func itemToString(i item) string {
+	return string(bytes.TrimRight(int64ToBytes(int64(i)), "\x00"))
+}
+
+// stringToItem packs string into the item, the string
+// should not be longer than 8 bytes.
+func stringToItem(s string) (item, error) {
+	encoded, err := encoding.EncodeOperand(s)
+	if err != nil {
+		return 0, ErrInvalidConversion
+	}
+
+	return bytesToItem(encoded), nil
+}
+
 func euclidean_div(a item, b item) (item, item) {
 	var q int64
 	var r int64
@@ -20,9 +20,11 @@ import (
 	"reflect"
 
 	"bytes"
+	"errors"
 	"testing"
 
 	"github.com/DE-labtory/koa/abi"
+	"github.com/DE-labtory/koa/container"
 	"github.com/DE-labtory/koa/encoding"
 	"github.com/DE-labtory/koa/opcode"
 )
@@ -437,6 +439,144 @@ func TestOr(t *testing.T) {
 	}
 }
 
+func TestBitAnd(t *testing.T) {
+	testByteCode := makeTestByteCode(
+		uint8(opcode.Push), int64ToBytes(0xAC), // 000...10101100
+		uint8(opcode.Push), int64ToBytes(0xF0), // 000...11110000
+		uint8(opcode.BitAnd),
+	)
+	testExpected := item(0xA0) // 000...10100000
+
+	stack, err := Execute(testByteCode, nil, nil)
+	if err != nil {
+		t.Error(err)
+	}
+	result := stack.Pop()
+	if testExpected != result {
+		t.Errorf("stack.Pop() result wrong - expected=%d, got=%d", testExpected, result)
+	}
+}
+
+func TestBitOr(t *testing.T) {
+	testByteCode := makeTestByteCode(
+		uint8(opcode.Push), int64ToBytes(0xAC), // 000...10101100
+		uint8(opcode.Push), int64ToBytes(0xF0), // 000...11110000
+		uint8(opcode.BitOr),
+	)
+	testExpected := item(0xFC) // 000...11111100
+
+	stack, err := Execute(testByteCode, nil, nil)
+	if err != nil {
+		t.Error(err)
+	}
+	result := stack.Pop()
+	if testExpected != result {
+		t.Errorf("stack.Pop() result wrong - expected=%d, got=%d", testExpected, result)
+	}
+}
+
+func TestBitXor(t *testing.T) {
+	testByteCode := makeTestByteCode(
+		uint8(opcode.Push), int64ToBytes(0xAC), // 000...10101100
+		uint8(opcode.Push), int64ToBytes(0xF0), // 000...11110000
+		uint8(opcode.BitXor),
+	)
+	testExpected := item(0x5C) // 000...01011100
+
+	stack, err := Execute(testByteCode, nil, nil)
+	if err != nil {
+		t.Error(err)
+	}
+	result := stack.Pop()
+	if testExpected != result {
+		t.Errorf("stack.Pop() result wrong - expected=%d, got=%d", testExpected, result)
+	}
+}
+
+func TestBitNot(t *testing.T) {
+	testByteCode := makeTestByteCode(
+		uint8(opcode.Push), int64ToBytes(0xAC), // 000...10101100
+		uint8(opcode.BitNot),
+	)
+	testExpected := item(-0xAD) // 111...01010011
+
+	stack, err := Execute(testByteCode, nil, nil)
+	if err != nil {
+		t.Error(err)
+	}
+	result := stack.Pop()
+	if testExpected != result {
+		t.Errorf("stack.Pop() result wrong - expected=%d, got=%d", testExpected, result)
+	}
+}
+
+func TestShl(t *testing.T) {
+	testByteCode := makeTestByteCode(
+		uint8(opcode.Push), int64ToBytes(0x0F),
+		uint8(opcode.Push), int64ToBytes(4),
+		uint8(opcode.Shl),
+	)
+	testExpected := item(0xF0)
+
+	stack, err := Execute(testByteCode, nil, nil)
+	if err != nil {
+		t.Error(err)
+	}
+	result := stack.Pop()
+	if testExpected != result {
+		t.Errorf("stack.Pop() result wrong - expected=%d, got=%d", testExpected, result)
+	}
+}
+
+func TestShr(t *testing.T) {
+	testByteCode := makeTestByteCode(
+		uint8(opcode.Push), int64ToBytes(0xF0),
+		uint8(opcode.Push), int64ToBytes(4),
+		uint8(opcode.Shr),
+	)
+	testExpected := item(0x0F)
+
+	stack, err := Execute(testByteCode, nil, nil)
+	if err != nil {
+		t.Error(err)
+	}
+	result := stack.Pop()
+	if testExpected != result {
+		t.Errorf("stack.Pop() result wrong - expected=%d, got=%d", testExpected, result)
+	}
+}
+
+func TestShr_negative(t *testing.T) {
+	testByteCode := makeTestByteCode(
+		uint8(opcode.Push), int64ToBytes(-16),
+		uint8(opcode.Push), int64ToBytes(2),
+		uint8(opcode.Shr),
+	)
+	testExpected := item(-4)
+
+	stack, err := Execute(testByteCode, nil, nil)
+	if err != nil {
+		t.Error(err)
+	}
+	result := stack.Pop()
+	if testExpected != result {
+		t.Errorf("stack.Pop() result wrong - expected=%d, got=%d", testExpected, result)
+	}
+}
+
+func TestShl_invalid(t *testing.T) {
+	testByteCode := makeTestByteCode(
+		uint8(opcode.Push), int64ToBytes(1),
+		uint8(opcode.Push), int64ToBytes(-1),
+		uint8(opcode.Shl),
+	)
+
+	_, err := Execute(testByteCode, nil, nil)
+	if err != ErrNegativeShift {
+		t.Errorf("Execute() wrong error - expected=%v, got=%v", ErrNegativeShift, err)
+	}
+}
+
 func TestLT(t *testing.T) {
 	tests := []struct {
 		x      int64
@@ -950,6 +1090,456 @@ func TestExit(t *testing.T) {
 	}
 }
 
+func TestRevert(t *testing.T) {
+	testByteCode := makeTestByteCode( //  op code index
+		uint8(opcode.Push), int64ToBytes(1), // 0 , 1
+		uint8(opcode.Revert),                // 2
+		uint8(opcode.Push), int64ToBytes(2), // 3 , 4
+	)
+
+	stack, err := Execute(testByteCode, nil, nil)
+	if err != ErrRevert {
+		t.Errorf("Invalid error - expected=%v, got=%v", ErrRevert, err)
+	}
+
+	if stack.Len() != 1 {
+		t.Errorf("Invalid stack size - expected=1, got =%d", stack.Len())
+	}
+}
+
+// mockHost keeps the bytecode, storage and logs of contracts by address
+type mockHost struct {
+	codes   map[int64][]byte
+	storage map[int64]map[int64]int64
+	logs    map[int64][][]int64
+}
+
+func newMockHost(codes map[int64][]byte) *mockHost {
+	return &mockHost{
+		codes:   codes,
+		storage: make(map[int64]map[int64]int64),
+		logs:    make(map[int64][][]int64),
+	}
+}
+
+func (h *mockHost) Code(address int64) ([]byte, error) {
+	code, ok := h.codes[address]
+	if !ok {
+		return nil, errors.New("no contract")
+	}
+	return code, nil
+}
+
+func (h *mockHost) Load(address int64, key int64) int64 {
+	return h.storage[address][key]
+}
+
+func (h *mockHost) Store(address int64, key int64, value int64) {
+	if h.storage[address] == nil {
+		h.storage[address] = make(map[int64]int64)
+	}
+	h.storage[address][key] = value
+}
+
+func (h *mockHost) Log(address int64, data []int64) {
+	h.logs[address] = append(h.logs[address], data)
+}
+
+func TestCall(t *testing.T) {
+	selector, err := encoding.EncodeOperand(abi.Selector("add(int,int)"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	callTo := func(address int64, args ...int64) []byte {
+		code := makeTestByteCode(
+			uint8(opcode.Push), int64ToBytes(address),
+			uint8(opcode.Push), selector,
+		)
+		for _, arg := range args {
+			code = append(code, makeTestByteCode(uint8(opcode.Push), int64ToBytes(arg))...)
+		}
+		return append(code, makeTestByteCode(
+			uint8(opcode.Push), int64ToBytes(int64(len(args))),
+			uint8(opcode.Call),
+		)...)
+	}
+
+	host := newMockHost(map[int64][]byte{
+		// returns the sum of two arguments
+		1: makeTestByteCode(
+			uint8(opcode.Push), int64ToBytes(0),
+			uint8(opcode.LoadArgs),
+			uint8(opcode.Push), int64ToBytes(1),
+			uint8(opcode.LoadArgs),
+			uint8(opcode.Add),
+		),
+		// returns the function selector
+		2: makeTestByteCode(
+			uint8(opcode.LoadFunc),
+		),
+		3: makeTestByteCode(
+			uint8(opcode.Revert),
+		),
+		// calls itself
+		4: callTo(4),
+		// returns nothing
+		5: makeTestByteCode(
+			uint8(opcode.JumpDst),
+		),
+		// calls the contract adding arguments
+		6: makeTestByteCode(
+			callTo(1, 4, 5),
+			uint8(opcode.Push), int64ToBytes(2),
+			uint8(opcode.Mul),
+		),
+	})
+
+	tests := []struct {
+		code     []byte
+		host     Host
+		expected item
+		err      error
+	}{
+		{
+			code:     callTo(1, 3, 7),
+			host:     host,
+			expected: 10,
+		},
+		{
+			code:     callTo(2),
+			host:     host,
+			expected: bytesToItem(selector),
+		},
+		{
+			code:     callTo(6),
+			host:     host,
+			expected: 18,
+		},
+		{
+			code: callTo(3),
+			host: host,
+			err:  ErrRevert,
+		},
+		{
+			code: callTo(4),
+			host: host,
+			err:  ErrCallDepth,
+		},
+		{
+			code: callTo(5),
+			host: host,
+			err:  ErrNoReturnValue,
+		},
+		{
+			code: callTo(7),
+			host: host,
+			err:  errors.New("no contract"),
+		},
+		{
+			code: callTo(1, 3, 7),
+			host: nil,
+			err:  ErrNoHost,
+		},
+		{
+			code: makeTestByteCode(
+				uint8(opcode.Push), int64ToBytes(1),
+				uint8(opcode.Push), selector,
+				uint8(opcode.Push), int64ToBytes(-1),
+				uint8(opcode.Call),
+			),
+			host: host,
+			err:  ErrInvalidCount,
+		},
+		{
+			code: makeTestByteCode(
+				uint8(opcode.Push), int64ToBytes(1),
+				uint8(opcode.Push), selector,
+				uint8(opcode.Push), int64ToBytes(1),
+				uint8(opcode.Call),
+			),
+			host: host,
+			err:  ErrInvalidCount,
+		},
+	}
+
+	for i, test := range tests {
+		stack, err := Execute(test.code, NewMemory(), &CallFunc{Host: test.host})
+		if err != nil && (test.err == nil || err.Error() != test.err.Error()) {
+			t.Fatalf("test[%d] - Execute() returns unexpected error %v", i, err)
+		}
+
+		if test.err != nil {
+			if err == nil {
+				t.Fatalf("test[%d] - Execute() should return error %v", i, test.err)
+			}
+			continue
+		}
+
+		if stack.Len() != 1 {
+			t.Fatalf("test[%d] - Invalid stack size - expected=1, got=%d", i, stack.Len())
+		}
+
+		if item := stack.Pop(); item != test.expected {
+			t.Errorf("test[%d] - Stack item is incorrect - expected=%d, got=%d", i, test.expected, item)
+		}
+	}
+}
+
+func TestSLoad_SStore(t *testing.T) {
+	testByteCode := makeTestByteCode(
+		uint8(opcode.Push), int64ToBytes(1), // key
+		uint8(opcode.Push), int64ToBytes(42), // value
+		uint8(opcode.SStore),
+		uint8(opcode.Push), int64ToBytes(1), // key
+		uint8(opcode.SLoad),
+		uint8(opcode.Push), int64ToBytes(2), // key never stored
+		uint8(opcode.SLoad),
+	)
+
+	host := newMockHost(nil)
+	stack, err := Execute(testByteCode, nil, &CallFunc{State: host, Address: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testExpected := []item{42, 0}
+	if stack.Len() != len(testExpected) {
+		t.Fatalf("Invalid stack size - expected=%d, got=%d", len(testExpected), stack.Len())
+	}
+
+	for i, item := range stack.items {
+		if testExpected[i] != item {
+			t.Errorf("Stack item is incorrect - expected=%d, got=%d", testExpected[i], item)
+		}
+	}
+
+	if value := host.storage[7][1]; value != 42 {
+		t.Errorf("Value is not stored in the storage of contract - expected=42, got=%d", value)
+	}
+
+	if _, err := Execute(testByteCode, nil, &CallFunc{}); err != ErrNoState {
+		t.Errorf("Invalid error - expected=%v, got=%v", ErrNoState, err)
+	}
+}
+
+func TestLog(t *testing.T) {
+	testByteCode := makeTestByteCode(
+		uint8(opcode.Push), int64ToBytes(3),
+		uint8(opcode.Push), int64ToBytes(4),
+		uint8(opcode.Push), int64ToBytes(2), // the number of data
+		uint8(opcode.Log),
+		uint8(opcode.Push), int64ToBytes(0),
+		uint8(opcode.Log),
+	)
+
+	host := newMockHost(nil)
+	stack, err := Execute(testByteCode, nil, &CallFunc{State: host, Address: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stack.Len() != 0 {
+		t.Errorf("Invalid stack size - expected=0, got=%d", stack.Len())
+	}
+
+	expected := [][]int64{{3, 4}, {}}
+	if !reflect.DeepEqual(host.logs[7], expected) {
+		t.Errorf("Invalid logs - expected=%v, got=%v", expected, host.logs[7])
+	}
+
+	for _, n := range []int64{-1, 2} {
+		invalid := makeTestByteCode(
+			uint8(opcode.Push), int64ToBytes(3),
+			uint8(opcode.Push), int64ToBytes(n),
+			uint8(opcode.Log),
+		)
+
+		if _, err := Execute(invalid, nil, &CallFunc{State: host}); err != ErrInvalidCount {
+			t.Errorf("Invalid error of %d data - expected=%v, got=%v", n, ErrInvalidCount, err)
+		}
+	}
+}
+
+func TestExecute_gas(t *testing.T) {
+	selector, err := encoding.EncodeOperand(abi.Selector("double(int)"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// uses 4 gas
+	double := makeTestByteCode(
+		uint8(opcode.Push), int64ToBytes(0),
+		uint8(opcode.LoadArgs),
+		uint8(opcode.Push), int64ToBytes(2),
+		uint8(opcode.Mul),
+	)
+
+	// uses 5 gas and 4 gas of double
+	testByteCode := makeTestByteCode(
+		uint8(opcode.Push), int64ToBytes(1),
+		uint8(opcode.Push), selector,
+		uint8(opcode.Push), int64ToBytes(21),
+		uint8(opcode.Push), int64ToBytes(1),
+		uint8(opcode.Call),
+	)
+
+	tests := []struct {
+		limit    uint64
+		expected uint64
+		err      error
+	}{
+		{0, 9, nil},
+		{9, 9, nil},
+		{8, 8, ErrOutOfGas},
+		{3, 3, ErrOutOfGas},
+	}
+
+	for i, test := range tests {
+		callFunc := &CallFunc{Host: newMockHost(map[int64][]byte{1: double}), GasLimit: test.limit}
+		_, err := Execute(testByteCode, nil, callFunc)
+		if err != test.err {
+			t.Errorf("test[%d] - Invalid error - expected=%v, got=%v", i, test.err, err)
+		}
+
+		if callFunc.GasUsed != test.expected {
+			t.Errorf("test[%d] - Invalid gas used - expected=%d, got=%d", i, test.expected, callFunc.GasUsed)
+		}
+	}
+}
+
+// TestExecute_gasCall checks that Call using the last gas fails
+// without running the callee, which would have no gas limit.
+func TestExecute_gasCall(t *testing.T) {
+	selector, err := encoding.EncodeOperand(abi.Selector("loop()"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// calls itself until max call depth, uses 3 gas before Call
+	loop := makeTestByteCode(
+		uint8(opcode.Push), int64ToBytes(1),
+		uint8(opcode.Push), selector,
+		uint8(opcode.Push), int64ToBytes(0),
+		uint8(opcode.Call),
+	)
+
+	// Call of depth 0 or depth 1 uses the last gas
+	for i, limit := range []uint64{4, 8} {
+		callFunc := &CallFunc{Host: newMockHost(map[int64][]byte{1: loop}), GasLimit: limit}
+
+		if _, err := Execute(loop, nil, callFunc); err != ErrOutOfGas {
+			t.Errorf("test[%d] - Invalid error - expected=%v, got=%v", i, ErrOutOfGas, err)
+		}
+
+		if callFunc.GasUsed != limit {
+			t.Errorf("test[%d] - Invalid gas used - expected=%d, got=%d", i, limit, callFunc.GasUsed)
+		}
+	}
+}
+
+// recorder records the steps of execution.
+type recorder struct {
+	steps []Step
+}
+
+func (r *recorder) Step(step Step) {
+	r.steps = append(r.steps, step)
+}
+
+func TestExecute_trace(t *testing.T) {
+	selector, err := encoding.EncodeOperand(abi.Selector("double(int)"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sel := int64(bytesToItem(selector))
+
+	double := makeTestByteCode(
+		uint8(opcode.Push), int64ToBytes(0),
+		uint8(opcode.LoadArgs),
+		uint8(opcode.Push), int64ToBytes(2),
+		uint8(opcode.Mul),
+	)
+
+	testByteCode := makeTestByteCode(
+		uint8(opcode.Push), int64ToBytes(1),
+		uint8(opcode.Push), selector,
+		uint8(opcode.Push), int64ToBytes(21),
+		uint8(opcode.Push), int64ToBytes(1),
+		uint8(opcode.Call),
+	)
+
+	expected := []Step{
+		{Depth: 0, PC: 0, Op: opcode.Push, Operand: int64ToBytes(1), Stack: []int64{}, GasUsed: 0},
+		{Depth: 0, PC: 2, Op: opcode.Push, Operand: selector, Stack: []int64{1}, GasUsed: 1},
+		{Depth: 0, PC: 4, Op: opcode.Push, Operand: int64ToBytes(21), Stack: []int64{1, sel}, GasUsed: 2},
+		{Depth: 0, PC: 6, Op: opcode.Push, Operand: int64ToBytes(1), Stack: []int64{1, sel, 21}, GasUsed: 3},
+		{Depth: 0, PC: 8, Op: opcode.Call, Stack: []int64{1, sel, 21, 1}, GasUsed: 4},
+		{Depth: 1, PC: 0, Op: opcode.Push, Operand: int64ToBytes(0), Stack: []int64{}, GasUsed: 0},
+		{Depth: 1, PC: 2, Op: opcode.LoadArgs, Stack: []int64{0}, GasUsed: 1},
+		{Depth: 1, PC: 3, Op: opcode.Push, Operand: int64ToBytes(2), Stack: []int64{21}, GasUsed: 2},
+		{Depth: 1, PC: 5, Op: opcode.Mul, Stack: []int64{21, 2}, GasUsed: 3},
+	}
+
+	r := &recorder{}
+	callFunc := &CallFunc{Host: newMockHost(map[int64][]byte{1: double}), Tracer: r}
+	if _, err := Execute(testByteCode, nil, callFunc); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(r.steps, expected) {
+		t.Errorf("Invalid steps - expected=%v, got=%v", expected, r.steps)
+	}
+}
+
+func TestExecute_container(t *testing.T) {
+	code := makeTestByteCode(
+		uint8(opcode.Push), int64ToBytes(1),
+		uint8(opcode.Push), int64ToBytes(2),
+		uint8(opcode.Add),
+	)
+
+	packed, err := container.Pack(container.Container{Code: code})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stack, err := Execute(packed, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result := stack.Pop(); result != 3 {
+		t.Errorf("Invalid result - expected=3, got=%d", result)
+	}
+
+	// container of the other opcode version is refused
+	packed[5] = opcode.Version + 1
+	if _, err := Execute(packed, nil, nil); err != container.ErrIncompatibleOpcode {
+		t.Errorf("Invalid error - expected=%v, got=%v", container.ErrIncompatibleOpcode, err)
+	}
+}
+
+func TestEncodeArgs(t *testing.T) {
+	expected, err := abi.Encode(50, "HelloKOA", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	str, err := stringToItem("HelloKOA")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if args := encodeArgs([]item{50, str, 1}); !bytes.Equal(args, expected) {
+		t.Errorf("encodeArgs() is wrong - expected=%x, got=%x", expected, args)
+	}
+
+	if args := encodeArgs([]item{}); len(args) != 0 {
+		t.Errorf("encodeArgs() is wrong - expected empty, got=%x", args)
+	}
+}
+
 // TODO: implement test cases :-)
 func TestCallFunc_function(t *testing.T) {
 
@@ -1092,3 +1682,58 @@ func TestSWAP(t *testing.T) {
 		}
 	}
 }
+
+func stringToBytes(s string) []byte {
+	b, err := encoding.EncodeOperand(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func TestConversion(t *testing.T) {
+	tests := []struct {
+		input       []byte
+		conversion  opcode.Type
+		expected    []byte
+		expectedErr error
+	}{
+		{int64ToBytes(123), opcode.IntToString, stringToBytes("123"), nil},
+		{int64ToBytes(-45), opcode.IntToString, stringToBytes("-45"), nil},
+		{int64ToBytes(0), opcode.IntToString, stringToBytes("0"), nil},
+		{int64ToBytes(123456789), opcode.IntToString, nil, ErrInvalidConversion},
+		{stringToBytes("123"), opcode.StringToInt, int64ToBytes(123), nil},
+		{stringToBytes("-45"), opcode.StringToInt, int64ToBytes(-45), nil},
+		{stringToBytes("12a"), opcode.StringToInt, nil, ErrInvalidConversion},
+		{stringToBytes(""), opcode.StringToInt, nil, ErrInvalidConversion},
+		{int64ToBytes(0), opcode.IntToBool, int64ToBytes(0), nil},
+		{int64ToBytes(-7), opcode.IntToBool, int64ToBytes(1), nil},
+		{int64ToBytes(1), opcode.BoolToString, stringToBytes("true"), nil},
+		{int64ToBytes(0), opcode.BoolToString, stringToBytes("false"), nil},
+		{stringToBytes("true"), opcode.StringToBool, int64ToBytes(1), nil},
+		{stringToBytes("false"), opcode.StringToBool, int64ToBytes(0), nil},
+		{stringToBytes("yes"), opcode.StringToBool, nil, ErrInvalidConversion},
+	}
+
+	for i, test := range tests {
+		testByteCode := makeTestByteCode(
+			uint8(opcode.Push), test.input,
+			uint8(test.conversion),
+		)
+
+		stack, err := Execute(testByteCode, nil, nil)
+		if err != test.expectedErr {
+			t.Errorf("test[%d] - Execute() wrong error - expected=%v, got=%v", i, test.expectedErr, err)
+			continue
+		}
+
+		if err != nil {
+			continue
+		}
+
+		result := stack.Pop()
+		if expected := bytesToItem(test.expected); expected != result {
+			t.Errorf("test[%d] - stack.Pop() result wrong - expected=%d, got=%d", i, expected, result)
+		}
+	}
+}